@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/app"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/theartofdevel/logging"
+)
+
+// auditMediaCommand имя подкоманды проверки медиабиблиотеки
+const auditMediaCommand = "audit-media"
+
+// runAuditMedia проверяет медиабиблиотеку, при флаге -cleanup выполняет очистку,
+// при флаге -index переносит старые файлы в хранилище по содержимому.
+// Возвращает код завершения процесса.
+func runAuditMedia(args []string) int {
+	flags := flag.NewFlagSet(auditMediaCommand, flag.ContinueOnError)
+	cleanup := flags.Bool("cleanup", false, "перенести файлы без ссылок в карантин и удалить файлы с истекшим сроком хранения")
+	index := flags.Bool("index", false, "посчитать SHA-256 файлов, загруженных до хранения по содержимому")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.MustGetConfig()
+
+	apps := app.NewApp(cfg)
+	apps.GetLogger()
+
+	ctx := logging.ContextWithLogger(context.Background(), apps.Logger)
+
+	apps.GetStorage(ctx)
+	apps.GetMediaStore()
+	apps.GetService()
+
+	if *index {
+		files, err := apps.ServiceAdmin.IndexMedia(ctx)
+		for _, file := range files {
+			fmt.Printf("%s\t%s\n", file.Kind, file.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "index failed:", err)
+			return 1
+		}
+
+		fmt.Printf("Indexed files: %d\n", len(files))
+
+		return 0
+	}
+
+	if *cleanup {
+		results, err := apps.ServiceAdmin.CleanupMedia(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cleanup failed:", err)
+			return 1
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tFILE\tACTION\tERROR")
+		for _, result := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Kind, result.Name, result.Action, result.Error)
+		}
+		w.Flush()
+
+		return 0
+	}
+
+	audit, err := apps.ServiceAdmin.AuditMedia(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "audit failed:", err)
+		return 1
+	}
+
+	printAudit(os.Stdout, audit)
+
+	return 0
+}
+
+// printAudit выводит отчет о проверке медиабиблиотеки в виде таблиц
+func printAudit(out io.Writer, audit *admin.MediaAudit) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Orphaned files: %d\n", len(audit.Orphans))
+	for _, file := range audit.Orphans {
+		note := ""
+		if file.InGracePeriod {
+			note = "recently uploaded"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%d bytes\t%s\t%s\n", file.Kind, file.Name, file.Size, file.ModTime.Format(time.DateTime), note)
+	}
+
+	fmt.Fprintf(w, "\nMissing files: %d\n", len(audit.Missing))
+	for _, file := range audit.Missing {
+		usedBy := make([]string, 0, len(file.UsedBy))
+		for _, u := range file.UsedBy {
+			usedBy = append(usedBy, fmt.Sprintf("%s #%d %q (%s)", u.Entity, u.ID, u.Name, u.Field))
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", file.Kind, file.Name, strings.Join(usedBy, ", "))
+	}
+
+	fmt.Fprintf(w, "\nQuarantined files: %d\n", len(audit.Quarantined))
+	for _, file := range audit.Quarantined {
+		fmt.Fprintf(w, "  %s\t%s\t%d bytes\tdelete after %s\n", file.Kind, file.Name, file.Size, file.DeleteAfter.Format(time.DateTime))
+	}
+
+	w.Flush()
+}
@@ -50,8 +50,17 @@ var (
 // @securityDefinitions.apikey AdminAuth
 // @in cookie
 // @name token
+//
+// @securityDefinitions.apikey AccountAuth
+// @in header
+// @name Authorization
 
 func main() {
+	// Подкоманды запускаются вместо HTTP сервера
+	if len(os.Args) > 1 && os.Args[1] == auditMediaCommand {
+		os.Exit(runAuditMedia(os.Args[2:]))
+	}
+
 	// Инициализируем конфиг
 	cfg := config.MustGetConfig()
 
@@ -82,9 +91,22 @@ func main() {
 	// Инициализируем хранилище Redis
 	apps.GetRedis(ctx)
 
+	// Запускаем отправку уведомлений
+	apps.GetNotifier(ctx)
+
+	// Инициализируем хранилище медиафайлов
+	apps.GetMediaStore()
+
 	// Инициализируем сервис
 	apps.GetService()
 
+	// Инициализируем подпись ссылок на медиафайлы
+	apps.GetMediaSigner()
+
+	// Запускаем отправку событий каталога подписчикам webhook
+	apps.StartWebhooks(ctx)
+	apps.StartReminders(ctx)
+
 	// Инициализируем HTTP сервер
 	srv := http_server.NewServer(apps)
 	serverDone := srv.StartServer(ctx)
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,17 +17,22 @@ import (
 
 // Config содержит всю конфигурацию приложения.
 type Config struct {
-	Database    DatabaseConfig `yaml:"database"` // Конфигурация базы данных.
-	HTTPServer  HTTPServer     `yaml:"http_server"`
-	Media       Media          `yaml:"media"`
-	Docs        Docs           `yaml:"swagger"`
-	Redis       Redis          `yaml:"redis"`
-	LogLevel    string         `yaml:"log_level" env:"LOG_LEVEL" env-default:"Info"`   // Режим логирования debug, info, warn, error
-	PatchLog    string         `yaml:"patch_log" env:"PATCH_LOG" env-default:""`       // Путь к папке для логов, если не указано, то логи будут в stdout
-	PatchConfig string         `env:"PATCH_CONFIG" env-default:"./config/config.yaml"` // Путь к конфигурационному файлу.
-	Env         string         `env:"ENV" env-default:"dev"`                           //dev, prod, local
-	Debug       bool           `env:"DEBUG" env-default:"false"`                       // Режим отладки pprof
-	DebugPort   string         `env:"DEBUG_PORT" env-default:"8080"`
+	Database     DatabaseConfig `yaml:"database"` // Конфигурация базы данных.
+	HTTPServer   HTTPServer     `yaml:"http_server"`
+	Media        Media          `yaml:"media"`
+	Docs         Docs           `yaml:"swagger"`
+	Redis        Redis          `yaml:"redis"`
+	Notify       Notify         `yaml:"notify"`
+	Webhooks     Webhooks       `yaml:"webhooks"`
+	Push         Push           `yaml:"push"`
+	MediaSigning MediaSigning   `yaml:"media_signing"`
+	MediaStore   MediaStore     `yaml:"media_store"`
+	LogLevel     string         `yaml:"log_level" env:"LOG_LEVEL" env-default:"Info"`   // Режим логирования debug, info, warn, error
+	PatchLog     string         `yaml:"patch_log" env:"PATCH_LOG" env-default:""`       // Путь к папке для логов, если не указано, то логи будут в stdout
+	PatchConfig  string         `env:"PATCH_CONFIG" env-default:"./config/config.yaml"` // Путь к конфигурационному файлу.
+	Env          string         `env:"ENV" env-default:"dev"`                           //dev, prod, local
+	Debug        bool           `env:"DEBUG" env-default:"false"`                       // Режим отладки pprof
+	DebugPort    string         `env:"DEBUG_PORT" env-default:"8080"`
 }
 
 // DatabaseConfig содержит конфигурацию для работы с базой данных.
@@ -48,12 +57,40 @@ type HTTPServer struct {
 	MaxErrorCount    int           `yaml:"max_error_count" env:"HTTP_MAX_ERROR_COUNT" env-default:"5"`
 	MaxErrorDuration time.Duration `yaml:"max_error_duration" env:"HTTP_MAX_ERROR_DURATION" env-default:"1m"`
 	BanDuration      time.Duration `yaml:"ban_duration" env:"HTTP_BAN_DURATION" env-default:"5m"`
+	AppVersionCheck  bool          `yaml:"app_version_check" env:"APP_VERSION_CHECK" env-default:"false"` // Отвечать 426 клиентам с устаревшей версией из X-App-Version
 }
 
 type Media struct {
-	BaseURL     string `yaml:"base_url" env:"BASE_URL" env-default:"http://localhost:8083"`
-	VideoPatch  string `yaml:"video_patch" env:"VIDEO_PATCH" env-default:"data/video"`
-	ImagesPatch string `yaml:"images_patch" env:"IMAGES_PATCH" env-default:"data/img"`
+	BaseURL         string        `yaml:"base_url" env:"BASE_URL" env-default:"http://localhost:8083"`
+	VideoPatch      string        `yaml:"video_patch" env:"VIDEO_PATCH" env-default:"data/video"`
+	ImagesPatch     string        `yaml:"images_patch" env:"IMAGES_PATCH" env-default:"data/img"`
+	DocsPatch       string        `yaml:"docs_patch" env:"DOCS_PATCH" env-default:"data/docs"`                                                // PDF-памятки к видео и категориям
+	SubtitlesPatch  string        `yaml:"subtitles_patch" env:"SUBTITLES_PATCH" env-default:"data/subtitles"`                                 // Субтитры WebVTT к видео
+	Faststart       bool          `yaml:"faststart" env:"VIDEO_FASTSTART" env-default:"true"`                                                 // Переносить moov в начало MP4 при загрузке
+	CheckFiles      bool          `yaml:"check_files" env:"MEDIA_CHECK_FILES" env-default:"true"`                                             // Проверять наличие файлов при сохранении видео и категорий
+	QuarantinePatch string        `yaml:"quarantine_patch" env:"QUARANTINE_PATCH" env-default:"data/quarantine"`                              // Директория для файлов без ссылок перед удалением
+	QuarantineTTL   time.Duration `yaml:"quarantine_ttl" env:"QUARANTINE_TTL" env-default:"720h"`                                             // Сколько файл хранится в карантине
+	OrphanGrace     time.Duration `yaml:"orphan_grace" env:"ORPHAN_GRACE" env-default:"24h"`                                                  // Сколько новый файл может оставаться без ссылок
+	UploadsPatch    string        `yaml:"uploads_patch" env:"UPLOADS_PATCH" env-default:"data/uploads"`                                       // Незавершенные загрузки видео по протоколу tus
+	UploadTTL       time.Duration `yaml:"upload_ttl" env:"UPLOAD_TTL" env-default:"24h"`                                                      // Брошенная загрузка удаляется через это время после последней записи
+	UploadMaxSize   int64         `yaml:"upload_max_size" env:"UPLOAD_MAX_SIZE" env-default:"10737418240"`                                    // Максимальный размер видео для загрузки по частям, 10 GB
+	UploadsNode     string        `yaml:"uploads_node" env:"UPLOADS_NODE" env-default:""`                                                     // Имя экземпляра в ID загрузок, обязательно при нескольких экземплярах сервиса
+	ImageCachePatch string        `yaml:"image_cache_patch" env:"IMAGE_CACHE_PATCH" env-default:"data/img_cache"`                             // Уменьшенные копии изображений для /img/{filename}?w=&h=
+	ImageSizes      []int         `yaml:"image_sizes" env:"IMAGE_SIZES" env-separator:"," env-default:"64,128,256,320,480,640,960,1280,1920"` // Допустимые значения w и h
+	ImageMaxWidth   int           `yaml:"image_max_width" env:"IMAGE_MAX_WIDTH" env-default:"8192"`                                           // Максимальная ширина загружаемого изображения
+	ImageMaxHeight  int           `yaml:"image_max_height" env:"IMAGE_MAX_HEIGHT" env-default:"8192"`                                         // Максимальная высота загружаемого изображения
+	ImageMaxPixels  int           `yaml:"image_max_pixels" env:"IMAGE_MAX_PIXELS" env-default:"40000000"`                                     // Максимальное число пикселей, защищает от «бомб» распаковки
+}
+
+// validUploadsNode имя экземпляра попадает в адрес загрузки и в правило маршрутизации балансировщика
+var validUploadsNode = regexp.MustCompile(`^[a-z0-9-]{1,63}$`)
+
+// validate проверяет имя экземпляра для загрузок по частям: оно становится частью ID загрузки
+func (m Media) validate() error {
+	if m.UploadsNode != "" && !validUploadsNode.MatchString(m.UploadsNode) {
+		return fmt.Errorf("UPLOADS_NODE must contain only lowercase letters, digits and dashes, got %q", m.UploadsNode)
+	}
+	return nil
 }
 
 type Docs struct {
@@ -69,6 +106,107 @@ type Redis struct {
 	Enable   bool          `yaml:"enabled" env:"REDIS_ENABLED" env-default:"true"`
 }
 
+// Notify настройки уведомлений сотрудников о новой обратной связи
+type Notify struct {
+	Retries    int            `yaml:"retries" env:"NOTIFY_RETRIES" env-default:"3"`          // Количество повторов после неудачной отправки
+	RetryDelay time.Duration  `yaml:"retry_delay" env:"NOTIFY_RETRY_DELAY" env-default:"5s"` // Задержка перед первым повтором, дальше удваивается
+	Timeout    time.Duration  `yaml:"timeout" env:"NOTIFY_TIMEOUT" env-default:"10s"`        // Тайм-аут одной попытки отправки
+	QueueSize  int            `yaml:"queue_size" env:"NOTIFY_QUEUE_SIZE" env-default:"100"`  // Размер очереди каждого канала
+	Telegram   NotifyTelegram `yaml:"telegram"`
+	SMTP       NotifySMTP     `yaml:"smtp"`
+	Webhook    NotifyWebhook  `yaml:"webhook"`
+}
+
+type NotifyTelegram struct {
+	Enable  bool   `yaml:"enabled" env:"NOTIFY_TELEGRAM_ENABLED" env-default:"false"`
+	BaseURL string `yaml:"base_url" env:"NOTIFY_TELEGRAM_BASE_URL" env-default:"https://api.telegram.org"`
+	Token   string `yaml:"token" env:"NOTIFY_TELEGRAM_TOKEN" env-default:""`
+	ChatID  string `yaml:"chat_id" env:"NOTIFY_TELEGRAM_CHAT_ID" env-default:""`
+}
+
+type NotifySMTP struct {
+	Enable   bool     `yaml:"enabled" env:"NOTIFY_SMTP_ENABLED" env-default:"false"`
+	Host     string   `yaml:"host" env:"NOTIFY_SMTP_HOST" env-default:"localhost"`
+	Port     int      `yaml:"port" env:"NOTIFY_SMTP_PORT" env-default:"587"` // Порт с STARTTLS или без шифрования, неявный TLS (465) не поддерживается
+	Username string   `yaml:"username" env:"NOTIFY_SMTP_USERNAME" env-default:""`
+	Password string   `yaml:"password" env:"NOTIFY_SMTP_PASSWORD" env-default:""`
+	From     string   `yaml:"from" env:"NOTIFY_SMTP_FROM" env-default:""`
+	To       []string `yaml:"to" env:"NOTIFY_SMTP_TO" env-separator:","`
+}
+
+type NotifyWebhook struct {
+	Enable bool   `yaml:"enabled" env:"NOTIFY_WEBHOOK_ENABLED" env-default:"false"`
+	URL    string `yaml:"url" env:"NOTIFY_WEBHOOK_URL" env-default:""`
+	Token  string `yaml:"token" env:"NOTIFY_WEBHOOK_TOKEN" env-default:""` // Передается в заголовке Authorization: Bearer
+}
+
+// Webhooks настройки отправки событий каталога подписчикам
+type Webhooks struct {
+	PollInterval time.Duration `yaml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL" env-default:"5s"` // Период проверки очереди доставок
+	Timeout      time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"10s"`            // Тайм-аут одного запроса к подписчику
+	MaxAttempts  int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`    // После стольких неудачных попыток доставка помечается failed
+	RetryBase    time.Duration `yaml:"retry_base" env:"WEBHOOKS_RETRY_BASE" env-default:"30s"`      // Задержка перед первым повтором, дальше удваивается
+	BatchSize    int           `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE" env-default:"20"`       // Сколько доставок отправляется за один проход
+}
+
+// validate проверяет настройки диспетчера доставок: при нулевом размере пачки он бы опрашивал очередь
+// без паузы, а тикер с неположительным периодом не создается
+func (w Webhooks) validate() error {
+	if w.PollInterval <= 0 {
+		return fmt.Errorf("WEBHOOKS_POLL_INTERVAL must be positive, got %s", w.PollInterval)
+	}
+	if w.BatchSize <= 0 {
+		return fmt.Errorf("WEBHOOKS_BATCH_SIZE must be positive, got %d", w.BatchSize)
+	}
+	return nil
+}
+
+// Push настройки push-уведомлений с напоминаниями о занятиях
+type Push struct {
+	Provider       string        `yaml:"provider" env:"PUSH_PROVIDER" env-default:"log"`            // fcm или log — только запись в лог без отправки
+	PollInterval   time.Duration `yaml:"poll_interval" env:"PUSH_POLL_INTERVAL" env-default:"30s"`  // Период проверки наступивших напоминаний
+	Timeout        time.Duration `yaml:"timeout" env:"PUSH_TIMEOUT" env-default:"10s"`              // Тайм-аут одного запроса к push-сервису
+	BatchSize      int           `yaml:"batch_size" env:"PUSH_BATCH_SIZE" env-default:"100"`        // Сколько напоминаний отправляется за один проход
+	MaxDelay       time.Duration `yaml:"max_delay" env:"PUSH_MAX_DELAY" env-default:"1h"`           // Опоздавшее сильнее напоминание не отправляется, а переносится
+	FCMCredentials string        `yaml:"fcm_credentials" env:"PUSH_FCM_CREDENTIALS" env-default:""` // Путь к JSON ключу сервисного аккаунта Firebase
+	FCMProjectID   string        `yaml:"fcm_project_id" env:"PUSH_FCM_PROJECT_ID" env-default:""`   // По умолчанию project_id из ключа
+	FCMBaseURL     string        `yaml:"fcm_base_url" env:"PUSH_FCM_BASE_URL" env-default:"https://fcm.googleapis.com"`
+}
+
+// validate проверяет настройки рассылки напоминаний: при нулевом размере пачки планировщик бы опрашивал
+// БД без паузы, а тикер с неположительным периодом не создается
+func (p Push) validate() error {
+	if p.PollInterval <= 0 {
+		return fmt.Errorf("PUSH_POLL_INTERVAL must be positive, got %s", p.PollInterval)
+	}
+	if p.BatchSize <= 0 {
+		return fmt.Errorf("PUSH_BATCH_SIZE must be positive, got %d", p.BatchSize)
+	}
+	return nil
+}
+
+// MediaSigning подпись ссылок на /video и /img со сроком действия и привязкой к аккаунту
+type MediaSigning struct {
+	Mode string        `yaml:"mode" env:"MEDIA_SIGNING" env-default:"off"`   // off, sign — подписывать, но пускать и без подписи, enforce — требовать подпись
+	Keys string        `yaml:"keys" env:"MEDIA_SIGNING_KEYS" env-default:""` // Ключи "id:secret,id:secret", первым подписываются новые ссылки
+	TTL  time.Duration `yaml:"ttl" env:"MEDIA_SIGNING_TTL" env-default:"6h"` // Минимальный срок действия ссылки
+}
+
+// MediaStore хранилище видео, изображений и PDF-памяток
+type MediaStore struct {
+	Type             string        `yaml:"type" env:"MEDIA_STORE" env-default:"local"`                  // local — директории VIDEO_PATCH, IMAGES_PATCH и DOCS_PATCH, s3 — S3-совместимое хранилище
+	Redirect         bool          `yaml:"redirect" env:"MEDIA_STORE_REDIRECT" env-default:"false"`     // Отдавать /video, /img и /docs редиректом на временную ссылку хранилища
+	PresignTTL       time.Duration `yaml:"presign_ttl" env:"MEDIA_STORE_PRESIGN_TTL" env-default:"15m"` // Срок действия временной ссылки
+	S3Endpoint       string        `yaml:"s3_endpoint" env:"S3_ENDPOINT" env-default:""`                // Например http://minio:9000, по умолчанию AWS S3 в S3_REGION
+	S3PublicEndpoint string        `yaml:"s3_public_endpoint" env:"S3_PUBLIC_ENDPOINT" env-default:""`  // Адрес хранилища для клиентов во временных ссылках, по умолчанию S3_ENDPOINT
+	S3Region         string        `yaml:"s3_region" env:"S3_REGION" env-default:"us-east-1"`
+	S3Bucket         string        `yaml:"s3_bucket" env:"S3_BUCKET" env-default:""`
+	S3AccessKey      string        `yaml:"s3_access_key" env:"S3_ACCESS_KEY" env-default:""`
+	S3SecretKey      string        `yaml:"s3_secret_key" env:"S3_SECRET_KEY" env-default:""`
+	S3PathStyle      bool          `yaml:"s3_path_style" env:"S3_PATH_STYLE" env-default:"true"` // Адрес вида endpoint/bucket/key, нужен MinIO
+	S3Timeout        time.Duration `yaml:"s3_timeout" env:"S3_TIMEOUT" env-default:"30s"`        // Тайм-аут ожидания ответа хранилища, на передачу данных не влияет
+}
+
 var (
 	instance *Config
 	once     sync.Once
@@ -92,6 +230,15 @@ func MustGetConfig() *Config {
 		// Затем загружаем переменные окружения из YAML файла
 		_ = cleanenv.ReadConfig(instance.PatchConfig, instance)
 
+		if err = instance.Media.validate(); err != nil {
+			log.Fatal("Invalid media config", sl.Err(err))
+		}
+		if err = instance.Webhooks.validate(); err != nil {
+			log.Fatal("Invalid webhooks config", sl.Err(err))
+		}
+		if err = instance.Push.validate(); err != nil {
+			log.Fatal("Invalid push config", sl.Err(err))
+		}
 	})
 	return instance
 }
@@ -115,11 +262,28 @@ func (c *Config) LogValue() logging.Value {
 		logging.StringAttr("http_idle_timeout", formatDuration(c.HTTPServer.IdleTimeout)),
 		logging.StringAttr("http_signing_key", "REDACTED"),
 		logging.StringAttr("token_ttl", formatDuration(c.HTTPServer.TokenTTL)),
+		logging.BoolAttr("app_version_check", c.HTTPServer.AppVersionCheck),
 
 		//Media
 		logging.StringAttr("base_url", c.Media.BaseURL),
 		logging.StringAttr("video_patch", c.Media.VideoPatch),
 		logging.StringAttr("images_patch", c.Media.ImagesPatch),
+		logging.StringAttr("docs_patch", c.Media.DocsPatch),
+		logging.StringAttr("subtitles_patch", c.Media.SubtitlesPatch),
+		logging.BoolAttr("video_faststart", c.Media.Faststart),
+		logging.BoolAttr("media_check_files", c.Media.CheckFiles),
+		logging.StringAttr("quarantine_patch", c.Media.QuarantinePatch),
+		logging.StringAttr("quarantine_ttl", formatDuration(c.Media.QuarantineTTL)),
+		logging.StringAttr("orphan_grace", formatDuration(c.Media.OrphanGrace)),
+		logging.StringAttr("uploads_patch", c.Media.UploadsPatch),
+		logging.StringAttr("upload_ttl", formatDuration(c.Media.UploadTTL)),
+		logging.IntAttr("upload_max_size", int(c.Media.UploadMaxSize)),
+		logging.StringAttr("uploads_node", c.Media.UploadsNode),
+		logging.StringAttr("image_cache_patch", c.Media.ImageCachePatch),
+		logging.StringAttr("image_sizes", formatInts(c.Media.ImageSizes)),
+		logging.IntAttr("image_max_width", c.Media.ImageMaxWidth),
+		logging.IntAttr("image_max_height", c.Media.ImageMaxHeight),
+		logging.IntAttr("image_max_pixels", c.Media.ImageMaxPixels),
 
 		//Docs
 		logging.StringAttr("docs_user", c.Docs.User),
@@ -132,6 +296,61 @@ func (c *Config) LogValue() logging.Value {
 		logging.StringAttr("redis_ttl", formatDuration(c.Redis.CacheTTL)),
 		logging.BoolAttr("redis_enabled", c.Redis.Enable),
 
+		//Notify
+		logging.IntAttr("notify_retries", c.Notify.Retries),
+		logging.StringAttr("notify_retry_delay", formatDuration(c.Notify.RetryDelay)),
+		logging.StringAttr("notify_timeout", formatDuration(c.Notify.Timeout)),
+		logging.IntAttr("notify_queue_size", c.Notify.QueueSize),
+		logging.BoolAttr("notify_telegram_enabled", c.Notify.Telegram.Enable),
+		logging.StringAttr("notify_telegram_base_url", c.Notify.Telegram.BaseURL),
+		logging.StringAttr("notify_telegram_token", "REDACTED"),
+		logging.StringAttr("notify_telegram_chat_id", c.Notify.Telegram.ChatID),
+		logging.BoolAttr("notify_smtp_enabled", c.Notify.SMTP.Enable),
+		logging.StringAttr("notify_smtp_host", c.Notify.SMTP.Host),
+		logging.IntAttr("notify_smtp_port", c.Notify.SMTP.Port),
+		logging.StringAttr("notify_smtp_username", c.Notify.SMTP.Username),
+		logging.StringAttr("notify_smtp_password", "REDACTED"),
+		logging.StringAttr("notify_smtp_from", c.Notify.SMTP.From),
+		logging.StringAttr("notify_smtp_to", strings.Join(c.Notify.SMTP.To, ",")),
+		logging.BoolAttr("notify_webhook_enabled", c.Notify.Webhook.Enable),
+		logging.StringAttr("notify_webhook_url", c.Notify.Webhook.URL),
+		logging.StringAttr("notify_webhook_token", "REDACTED"),
+
+		//Webhooks
+		logging.StringAttr("webhooks_poll_interval", formatDuration(c.Webhooks.PollInterval)),
+		logging.StringAttr("webhooks_timeout", formatDuration(c.Webhooks.Timeout)),
+		logging.IntAttr("webhooks_max_attempts", c.Webhooks.MaxAttempts),
+		logging.StringAttr("webhooks_retry_base", formatDuration(c.Webhooks.RetryBase)),
+		logging.IntAttr("webhooks_batch_size", c.Webhooks.BatchSize),
+
+		//Push
+		logging.StringAttr("push_provider", c.Push.Provider),
+		logging.StringAttr("push_poll_interval", formatDuration(c.Push.PollInterval)),
+		logging.StringAttr("push_timeout", formatDuration(c.Push.Timeout)),
+		logging.IntAttr("push_batch_size", c.Push.BatchSize),
+		logging.StringAttr("push_max_delay", formatDuration(c.Push.MaxDelay)),
+		logging.StringAttr("push_fcm_credentials", c.Push.FCMCredentials),
+		logging.StringAttr("push_fcm_project_id", c.Push.FCMProjectID),
+		logging.StringAttr("push_fcm_base_url", c.Push.FCMBaseURL),
+
+		//MediaSigning
+		logging.StringAttr("media_signing", c.MediaSigning.Mode),
+		logging.StringAttr("media_signing_keys", "REDACTED"),
+		logging.StringAttr("media_signing_ttl", formatDuration(c.MediaSigning.TTL)),
+
+		//MediaStore
+		logging.StringAttr("media_store", c.MediaStore.Type),
+		logging.BoolAttr("media_store_redirect", c.MediaStore.Redirect),
+		logging.StringAttr("media_store_presign_ttl", formatDuration(c.MediaStore.PresignTTL)),
+		logging.StringAttr("s3_endpoint", c.MediaStore.S3Endpoint),
+		logging.StringAttr("s3_public_endpoint", c.MediaStore.S3PublicEndpoint),
+		logging.StringAttr("s3_region", c.MediaStore.S3Region),
+		logging.StringAttr("s3_bucket", c.MediaStore.S3Bucket),
+		logging.StringAttr("s3_access_key", c.MediaStore.S3AccessKey),
+		logging.StringAttr("s3_secret_key", "REDACTED"),
+		logging.BoolAttr("s3_path_style", c.MediaStore.S3PathStyle),
+		logging.StringAttr("s3_timeout", formatDuration(c.MediaStore.S3Timeout)),
+
 		// General
 		logging.StringAttr("log_level", c.LogLevel),
 		logging.StringAttr("patch_log", c.PatchLog),
@@ -144,3 +363,11 @@ func (c *Config) LogValue() logging.Value {
 func formatDuration(d time.Duration) string {
 	return d.String() // Используем встроенный метод String()
 }
+
+func formatInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
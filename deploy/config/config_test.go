@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhooks_Validate(t *testing.T) {
+	valid := Webhooks{PollInterval: 5 * time.Second, BatchSize: 20}
+	assert.NoError(t, valid.validate())
+
+	// Нулевой размер пачки зациклил бы диспетчер, нулевой период уронил бы time.NewTicker
+	for _, w := range []Webhooks{
+		{PollInterval: 0, BatchSize: 20},
+		{PollInterval: -time.Second, BatchSize: 20},
+		{PollInterval: 5 * time.Second, BatchSize: 0},
+		{PollInterval: 5 * time.Second, BatchSize: -1},
+	} {
+		assert.Error(t, w.validate())
+	}
+}
+
+func TestPush_Validate(t *testing.T) {
+	valid := Push{PollInterval: 30 * time.Second, BatchSize: 100}
+	assert.NoError(t, valid.validate())
+
+	for _, p := range []Push{
+		{PollInterval: 0, BatchSize: 100},
+		{PollInterval: 30 * time.Second, BatchSize: 0},
+	} {
+		assert.Error(t, p.validate())
+	}
+}
+
+func TestMedia_Validate(t *testing.T) {
+	for _, node := range []string{"", "api-1", "node7"} {
+		assert.NoError(t, Media{UploadsNode: node}.validate())
+	}
+
+	// Имя экземпляра входит в ID загрузки и в адрес запроса
+	for _, node := range []string{"Api-1", "api.1", "api/1", "api 1"} {
+		assert.Error(t, Media{UploadsNode: node}.validate())
+	}
+}
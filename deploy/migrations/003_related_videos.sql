@@ -0,0 +1,18 @@
+-- Теги видео для подбора похожих упражнений
+CREATE TABLE IF NOT EXISTS video_tags (
+    video_id INTEGER REFERENCES videos(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (video_id, tag)
+);
+
+-- Ссылки "смотреть далее", которые администратор задает вручную
+CREATE TABLE IF NOT EXISTS video_next_up (
+    video_id INTEGER REFERENCES videos(id) ON DELETE CASCADE,
+    next_video_id INTEGER REFERENCES videos(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (video_id, next_video_id),
+    CHECK (video_id <> next_video_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_tags_tag ON video_tags(tag);
+CREATE INDEX IF NOT EXISTS idx_video_next_up_video_id ON video_next_up(video_id, position);
@@ -0,0 +1,74 @@
+-- Время последнего изменения записей каталога для инкрементальной синхронизации.
+-- Мягко удаленные записи (deleted = TRUE) с обновленным updated_at служат надгробиями для клиентов.
+ALTER TABLE content_types ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+-- clock_timestamp вместо NOW, чтобы время отражало момент изменения, а не начало транзакции
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER content_types_set_updated_at
+    BEFORE INSERT OR UPDATE ON content_types
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE OR REPLACE TRIGGER categories_set_updated_at
+    BEFORE INSERT OR UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE OR REPLACE TRIGGER videos_set_updated_at
+    BEFORE INSERT OR UPDATE ON videos
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Изменение связей видео с категориями меняет видимость видео для типов контента
+CREATE OR REPLACE FUNCTION touch_video_categories() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE videos SET updated_at = clock_timestamp() WHERE id = OLD.video_id;
+    ELSE
+        UPDATE videos SET updated_at = clock_timestamp() WHERE id = NEW.video_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER video_categories_touch
+    AFTER INSERT OR DELETE ON video_categories
+    FOR EACH ROW EXECUTE FUNCTION touch_video_categories();
+
+-- Изменение связей категории с типами контента меняет видимость категории
+CREATE OR REPLACE FUNCTION touch_category_content_types() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE categories SET updated_at = clock_timestamp() WHERE id = OLD.category_id;
+    ELSE
+        UPDATE categories SET updated_at = clock_timestamp() WHERE id = NEW.category_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER category_content_types_touch
+    AFTER INSERT OR DELETE ON category_content_types
+    FOR EACH ROW EXECUTE FUNCTION touch_category_content_types();
+
+-- Видимость видео зависит от его категорий, поэтому изменение категории затрагивает и ее видео
+CREATE OR REPLACE FUNCTION touch_category_videos() RETURNS trigger AS $$
+BEGIN
+    UPDATE videos SET updated_at = clock_timestamp()
+    WHERE id IN (SELECT video_id FROM video_categories WHERE category_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER categories_touch_videos
+    AFTER UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION touch_category_videos();
+
+CREATE INDEX IF NOT EXISTS idx_content_types_updated_at ON content_types(updated_at);
+CREATE INDEX IF NOT EXISTS idx_categories_updated_at ON categories(updated_at);
+CREATE INDEX IF NOT EXISTS idx_videos_updated_at ON videos(updated_at);
@@ -0,0 +1,25 @@
+-- Вложенные категории: NULL в parent_id означает корневую категорию.
+-- Циклы проверяются в сервисе, здесь запрещена только ссылка категории на саму себя.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_parent_not_self;
+ALTER TABLE categories ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Родитель отдается клиенту, только если он доступен для того же типа контента,
+-- поэтому изменение типов категории затрагивает и ее дочерние категории
+CREATE OR REPLACE FUNCTION touch_category_content_types() RETURNS trigger AS $$
+DECLARE
+    changed_id INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_id = OLD.category_id;
+    ELSE
+        changed_id = NEW.category_id;
+    END IF;
+
+    UPDATE categories SET updated_at = clock_timestamp() WHERE id = changed_id OR parent_id = changed_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
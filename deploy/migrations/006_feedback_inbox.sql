@@ -0,0 +1,27 @@
+-- Обработка обратной связи в админке: статус, ответственный администратор, внутренние заметки
+-- и отметки о прочтении для каждого администратора.
+ALTER TABLE feedback ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'new';
+ALTER TABLE feedback ADD COLUMN IF NOT EXISTS assignee_id INTEGER REFERENCES accounts(id) ON DELETE SET NULL;
+ALTER TABLE feedback ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+ALTER TABLE feedback DROP CONSTRAINT IF EXISTS feedback_status_check;
+ALTER TABLE feedback ADD CONSTRAINT feedback_status_check CHECK (status IN ('new', 'in_progress', 'resolved'));
+
+CREATE TABLE IF NOT EXISTS feedback_notes (
+    id SERIAL PRIMARY KEY,
+    feedback_id INTEGER NOT NULL REFERENCES feedback(id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES accounts(id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS feedback_reads (
+    feedback_id INTEGER REFERENCES feedback(id) ON DELETE CASCADE,
+    account_id INTEGER REFERENCES accounts(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (feedback_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feedback_status ON feedback(status);
+CREATE INDEX IF NOT EXISTS idx_feedback_created_at ON feedback(created_at);
+CREATE INDEX IF NOT EXISTS idx_feedback_notes_feedback_id ON feedback_notes(feedback_id);
@@ -0,0 +1,22 @@
+-- Объявления для мобильного приложения: расписание на праздники, запуск новых программ.
+-- Аудитория задается типами контента, объявление без типов показывается всем.
+CREATE TABLE IF NOT EXISTS announcements (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    img_url TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP WITH TIME ZONE, -- NULL — без даты окончания
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT announcements_period_valid CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS announcement_content_types (
+    announcement_id INTEGER NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    content_type_id INTEGER NOT NULL REFERENCES content_types(id) ON DELETE CASCADE,
+    PRIMARY KEY (announcement_id, content_type_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_period ON announcements(starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_announcement_content_types_type ON announcement_content_types(content_type_id);
@@ -0,0 +1,15 @@
+-- Настройки мобильного приложения, которые администратор меняет без выпуска новой сборки.
+-- Таблица всегда содержит одну строку.
+CREATE TABLE IF NOT EXISTS app_config (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    min_version TEXT NOT NULL DEFAULT '',     -- Минимальная поддерживаемая версия, пусто — без ограничения
+    latest_version TEXT NOT NULL DEFAULT '',  -- Последняя выпущенная версия
+    force_update BOOLEAN NOT NULL DEFAULT FALSE, -- Требовать обновления до latest_version
+    support_email TEXT NOT NULL DEFAULT '',
+    support_telegram TEXT NOT NULL DEFAULT '',
+    support_phone TEXT NOT NULL DEFAULT '',
+    features JSONB NOT NULL DEFAULT '{}',     -- Флаги функций: {"pain_diary": true}
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO app_config (id) VALUES (TRUE) ON CONFLICT DO NOTHING;
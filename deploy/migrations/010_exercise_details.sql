@@ -0,0 +1,10 @@
+-- Структурированное описание упражнения: шаги, противопоказания, инвентарь,
+-- рекомендуемые подходы и повторения, уровень сложности
+ALTER TABLE videos
+    ADD COLUMN IF NOT EXISTS steps TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS contraindications TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS equipment TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS sets INTEGER NOT NULL DEFAULT 0 CHECK (sets >= 0),   -- 0 — не задано
+    ADD COLUMN IF NOT EXISTS reps INTEGER NOT NULL DEFAULT 0 CHECK (reps >= 0),   -- 0 — не задано
+    ADD COLUMN IF NOT EXISTS difficulty TEXT NOT NULL DEFAULT ''
+        CHECK (difficulty IN ('', 'beginner', 'intermediate', 'advanced'));
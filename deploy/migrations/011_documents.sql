@@ -0,0 +1,24 @@
+-- PDF-памятки для пациентов. Файл лежит в DOCS_PATCH, url хранит имя файла.
+-- Один документ можно прикрепить к нескольким видео и категориям.
+CREATE TABLE IF NOT EXISTS documents (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS video_documents (
+    video_id INTEGER NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    PRIMARY KEY (video_id, document_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_documents (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    PRIMARY KEY (category_id, document_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_documents_document ON video_documents(document_id);
+CREATE INDEX IF NOT EXISTS idx_category_documents_document ON category_documents(document_id);
@@ -0,0 +1,29 @@
+-- Оценки видео пациентами: одна оценка от аккаунта на видео, повторная заменяет предыдущую
+CREATE TABLE IF NOT EXISTS video_ratings (
+    video_id INTEGER NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    score SMALLINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (video_id, account_id),
+    CONSTRAINT video_ratings_score_valid CHECK (score BETWEEN 1 AND 5)
+);
+
+-- Комментарии пациентов к видео. Другим пациентам показываются только одобренные комментарии
+-- того типа контента, который был у автора при отправке.
+CREATE TABLE IF NOT EXISTS video_comments (
+    id SERIAL PRIMARY KEY,
+    video_id INTEGER NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    content_type_id INTEGER REFERENCES content_types(id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    moderated_by TEXT NOT NULL DEFAULT '',
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT video_comments_status_valid CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_ratings_account ON video_ratings(account_id);
+CREATE INDEX IF NOT EXISTS idx_video_comments_video_status ON video_comments(video_id, status, content_type_id);
+CREATE INDEX IF NOT EXISTS idx_video_comments_status_created ON video_comments(status, created_at);
@@ -0,0 +1,22 @@
+-- Дневник боли пациента: оценка боли 0–10 за день по области тела, заметки и выполненные видео.
+-- Тип контента автора сохраняется на момент записи, по нему строится динамика по типам.
+CREATE TABLE IF NOT EXISTS diary_entries (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    content_type_id INTEGER REFERENCES content_types(id) ON DELETE SET NULL,
+    entry_date DATE NOT NULL,
+    pain_score SMALLINT NOT NULL,
+    body_area TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT diary_entries_pain_score_valid CHECK (pain_score BETWEEN 0 AND 10)
+);
+
+CREATE TABLE IF NOT EXISTS diary_entry_videos (
+    entry_id INTEGER NOT NULL REFERENCES diary_entries(id) ON DELETE CASCADE,
+    video_id INTEGER NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    PRIMARY KEY (entry_id, video_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_diary_entries_account_date ON diary_entries(account_id, entry_date);
+CREATE INDEX IF NOT EXISTS idx_diary_entries_type_date ON diary_entries(content_type_id, entry_date);
@@ -0,0 +1,29 @@
+-- Расписания напоминаний о занятиях: дни недели ISO (1 — понедельник), местное время и часовой пояс пациента.
+-- next_run_at хранится в UTC и пересчитывается после каждого срабатывания.
+CREATE TABLE IF NOT EXISTS reminders (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    days SMALLINT[] NOT NULL,
+    local_time TEXT NOT NULL,
+    timezone TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Токены устройств для push-уведомлений. Токен принадлежит одному аккаунту:
+-- при входе другим аккаунтом на том же устройстве токен переходит к нему.
+CREATE TABLE IF NOT EXISTS device_tokens (
+    token TEXT PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    platform TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_account ON reminders(account_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_due ON reminders(next_run_at) WHERE enabled;
+CREATE INDEX IF NOT EXISTS idx_device_tokens_account ON device_tokens(account_id);
@@ -0,0 +1,15 @@
+-- Имена медиафайлов библиотек. Содержимое хранится под именем из SHA-256 (blob),
+-- несколько имен с одинаковым содержимым указывают на один blob.
+CREATE TABLE IF NOT EXISTS media_files (
+    library TEXT NOT NULL,
+    name TEXT NOT NULL,
+    sha256 TEXT NOT NULL,
+    blob TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (library, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_files_sha256 ON media_files(library, sha256);
+CREATE INDEX IF NOT EXISTS idx_media_files_blob ON media_files(library, blob);
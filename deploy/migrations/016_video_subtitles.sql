@@ -0,0 +1,11 @@
+-- Субтитры WebVTT к видео, по одной дорожке на язык. Файл лежит в SUBTITLES_PATCH, url хранит имя файла.
+CREATE TABLE IF NOT EXISTS video_subtitles (
+    id SERIAL PRIMARY KEY,
+    video_id INTEGER NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    label TEXT NOT NULL,
+    url TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (video_id, language)
+);
@@ -0,0 +1,492 @@
+package mediastore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// contentTmpSuffix суффикс временной копии данных, которые нельзя прочитать повторно
+const contentTmpSuffix = ".content.tmp"
+
+// File имя файла библиотеки и содержимое, на которое оно указывает
+type File struct {
+	Name    string
+	Digest  string // SHA-256 содержимого в hex
+	Blob    string // имя содержимого в хранилище
+	Size    int64
+	ModTime time.Time
+}
+
+// Index таблица имен файлов библиотек. Отсутствующее имя — ErrNotFound.
+type Index interface {
+	GetMediaFile(ctx context.Context, library, name string) (*File, error)
+	ListMediaFiles(ctx context.Context, library string) ([]File, error)
+	// SaveMediaFile сохраняет имя и возвращает содержимое, на которое оно указывало раньше, или пустую строку
+	SaveMediaFile(ctx context.Context, library string, file File) (string, error)
+	// CreateMediaFile добавляет новое имя. Занятое имя не перезаписывается: ErrExists.
+	CreateMediaFile(ctx context.Context, library string, file File) error
+	// DeleteMediaFile удаляет имя и возвращает содержимое, на которое оно указывало
+	DeleteMediaFile(ctx context.Context, library, name string) (string, error)
+	// CountMediaBlobRefs возвращает количество имен, указывающих на содержимое
+	CountMediaBlobRefs(ctx context.Context, library, blob string) (int, error)
+	// ListMediaFilesByDigest возвращает имена с указанным SHA-256
+	ListMediaFilesByDigest(ctx context.Context, library, digest string) ([]File, error)
+	// LockMediaBlob блокирует содержимое для всех экземпляров сервиса до вызова unlock
+	LockMediaBlob(ctx context.Context, library, blob string) (unlock func(), err error)
+}
+
+// Content хранит содержимое файлов в blobs под именем <sha256><расширение>, а имена файлов библиотеки
+// ведет в Index. Одинаковые файлы под разными именами хранятся один раз, содержимое удаляется,
+// когда на него не остается ни одного имени.
+//
+// Файлы, загруженные в blobs до хранения по содержимому, отдаются по своему имени без контрольной суммы,
+// пока их не перенесет Adopt или повторная загрузка.
+//
+// Новое имя ставится на содержимое и содержимое удаляется только под LockMediaBlob, поэтому экземпляр,
+// удаляющий последнее имя, не может удалить содержимое, на которое другой экземпляр в этот момент ставит имя.
+type Content struct {
+	blobs   MediaStore
+	index   Index
+	library string
+	tmpDir  string
+}
+
+// presigningContent Content поверх хранилища, которое выдает временные ссылки
+type presigningContent struct {
+	*Content
+	presigner Presigner
+}
+
+// NewContent создает хранилище по содержимому поверх blobs. library — имя библиотеки в Index,
+// tmpDir — директория для временной копии данных, которые нельзя прочитать дважды.
+func NewContent(blobs MediaStore, index Index, library, tmpDir string) MediaStore {
+	c := &Content{blobs: blobs, index: index, library: library, tmpDir: tmpDir}
+
+	if presigner, ok := blobs.(Presigner); ok {
+		return &presigningContent{Content: c, presigner: presigner}
+	}
+
+	return c
+}
+
+// Save считает SHA-256 содержимого и ставит на него имя. Содержимое загружается в blobs,
+// только если такого там еще нет. Содержимое, на которое имя указывало раньше, удаляется,
+// если на него больше нет имен.
+func (c *Content) Save(ctx context.Context, name string, r io.Reader, _ int64) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+
+	// Такое имя совпало бы с содержимым другого файла
+	if isBlobName(name) {
+		return ErrInvalidName
+	}
+
+	src, cleanup, err := c.seekable(r)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	start, err := src.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to get read position: %w", err)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, src)
+	if err != nil {
+		return fmt.Errorf("failed to hash file: %w", err)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	blob := blobName(digest, name)
+
+	if err = c.saveBlob(ctx, blob, src, start, size); err != nil {
+		return err
+	}
+
+	prev, err := c.link(ctx, File{
+		Name:   name,
+		Digest: digest,
+		Blob:   blob,
+		Size:   size,
+	}, src, start)
+	if err != nil {
+		return err
+	}
+
+	if prev != "" && prev != blob {
+		c.release(ctx, prev)
+	}
+
+	// Копия под тем же именем, загруженная до хранения по содержимому, больше не нужна
+	if err = c.blobs.Delete(ctx, name); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to delete previous file: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Content) Open(ctx context.Context, name string) (io.ReadSeekCloser, *Object, error) {
+	file, err := c.lookup(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if file == nil {
+		return c.blobs.Open(ctx, name)
+	}
+
+	rc, obj, err := c.blobs.Open(ctx, file.Blob)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rc, fileObject(file, obj.Size), nil
+}
+
+func (c *Content) Stat(ctx context.Context, name string) (*Object, error) {
+	file, err := c.lookup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if file == nil {
+		return c.blobs.Stat(ctx, name)
+	}
+
+	obj, err := c.blobs.Stat(ctx, file.Blob)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileObject(file, obj.Size), nil
+}
+
+// List возвращает имена из Index и файлы, загруженные до хранения по содержимому
+func (c *Content) List(ctx context.Context) ([]Object, error) {
+	files, err := c.index.ListMediaFiles(ctx, c.library)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file names: %w", err)
+	}
+
+	blobs, err := c.blobs.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Object, 0, len(files))
+	indexed := make(map[string]bool, len(files))
+	for i := range files {
+		indexed[files[i].Name] = true
+		result = append(result, *fileObject(&files[i], files[i].Size))
+	}
+
+	for _, obj := range blobs {
+		if isBlobName(obj.Name) || indexed[obj.Name] {
+			continue
+		}
+		result = append(result, obj)
+	}
+
+	return result, nil
+}
+
+// Delete удаляет имя, а содержимое — если на него больше нет имен
+func (c *Content) Delete(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+
+	if isBlobName(name) {
+		return ErrNotFound
+	}
+
+	blob, err := c.index.DeleteMediaFile(ctx, c.library, name)
+	if errors.Is(err, ErrNotFound) {
+		return c.blobs.Delete(ctx, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete file name: %w", err)
+	}
+
+	c.release(ctx, blob)
+
+	return nil
+}
+
+// Link ставит имя dst на содержимое src без копирования и повторного хеширования данных.
+// Имя добавляется в Index только если его там нет, поэтому параллельная загрузка или переименование
+// в dst не перезаписывается: возвращается ErrExists.
+func (c *Content) Link(ctx context.Context, src, dst string) error {
+	if err := checkName(dst); err != nil {
+		return err
+	}
+
+	if isBlobName(dst) {
+		return ErrInvalidName
+	}
+
+	file, err := c.lookup(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	// Файл, загруженный до хранения по содержимому, сначала переносится под имя из SHA-256
+	if file == nil {
+		if err = c.adopt(ctx, src); err != nil {
+			return err
+		}
+		if file, err = c.lookup(ctx, src); err != nil {
+			return err
+		}
+		if file == nil {
+			return ErrNotFound
+		}
+	}
+
+	// Имя может быть занято файлом, загруженным до хранения по содержимому. Новые такие файлы не появляются,
+	// поэтому проверка до записи здесь достаточна.
+	if _, err = c.blobs.Stat(ctx, dst); err == nil {
+		return ErrExists
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	unlock, err := c.index.LockMediaBlob(ctx, c.library, file.Blob)
+	if err != nil {
+		return fmt.Errorf("failed to lock media blob: %w", err)
+	}
+	defer unlock()
+
+	// До блокировки src могли удалить вместе с последним указывавшим на содержимое именем
+	if _, err = c.blobs.Stat(ctx, file.Blob); err != nil {
+		return err
+	}
+
+	linked := *file
+	linked.Name = dst
+	if err = c.index.CreateMediaFile(ctx, c.library, linked); err != nil {
+		if errors.Is(err, ErrExists) {
+			return err
+		}
+		return fmt.Errorf("failed to create file name: %w", err)
+	}
+
+	return nil
+}
+
+// FindByDigest возвращает имена файлов с указанным SHA-256 по таблице имен, не перебирая библиотеку
+func (c *Content) FindByDigest(ctx context.Context, digest string) ([]Object, error) {
+	files, err := c.index.ListMediaFilesByDigest(ctx, c.library, digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files by digest: %w", err)
+	}
+
+	result := make([]Object, 0, len(files))
+	for i := range files {
+		result = append(result, *fileObject(&files[i], files[i].Size))
+	}
+
+	return result, nil
+}
+
+// Adopt переносит файлы, загруженные до хранения по содержимому, под имена из SHA-256
+// и возвращает имена перенесенных файлов
+func (c *Content) Adopt(ctx context.Context) ([]string, error) {
+	objects, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	adopted := make([]string, 0)
+	for _, obj := range objects {
+		if obj.Digest != "" {
+			continue
+		}
+
+		if err = c.adopt(ctx, obj.Name); err != nil {
+			return adopted, fmt.Errorf("failed to adopt %s: %w", obj.Name, err)
+		}
+
+		adopted = append(adopted, obj.Name)
+	}
+
+	return adopted, nil
+}
+
+func (c *Content) adopt(ctx context.Context, name string) error {
+	file, obj, err := c.blobs.Open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.Save(ctx, name, file, obj.Size)
+}
+
+func (p *presigningContent) PresignGet(ctx context.Context, name string, ttl time.Duration) (string, error) {
+	file, err := p.lookup(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	if file == nil {
+		return p.presigner.PresignGet(ctx, name, ttl)
+	}
+
+	return p.presigner.PresignGet(ctx, file.Blob, ttl)
+}
+
+// lookup ищет имя в Index. nil без ошибки — файл, загруженный до хранения по содержимому.
+func (c *Content) lookup(ctx context.Context, name string) (*File, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	if isBlobName(name) {
+		return nil, ErrNotFound
+	}
+
+	file, err := c.index.GetMediaFile(ctx, c.library, name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file name: %w", err)
+	}
+
+	return file, nil
+}
+
+// saveBlob загружает содержимое, если его еще нет в blobs
+func (c *Content) saveBlob(ctx context.Context, blob string, src io.ReadSeeker, start, size int64) error {
+	_, err := c.blobs.Stat(ctx, blob)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if _, err = src.Seek(start, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind file: %w", err)
+	}
+
+	return c.blobs.Save(ctx, blob, src, size)
+}
+
+// link ставит имя на содержимое под блокировкой и возвращает содержимое, на которое имя указывало раньше
+func (c *Content) link(ctx context.Context, file File, src io.ReadSeeker, start int64) (string, error) {
+	unlock, err := c.index.LockMediaBlob(ctx, c.library, file.Blob)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock media blob: %w", err)
+	}
+	defer unlock()
+
+	// Пока содержимое загружалось, его могли удалить вместе с последним указывавшим на него именем
+	if err = c.saveBlob(ctx, file.Blob, src, start, file.Size); err != nil {
+		return "", err
+	}
+
+	prev, err := c.index.SaveMediaFile(ctx, c.library, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to save file name: %w", err)
+	}
+
+	return prev, nil
+}
+
+// release удаляет содержимое, на которое больше нет имен. Ошибка не возвращается:
+// имя уже указывает на новое содержимое, а лишняя копия не мешает отдаче файлов.
+func (c *Content) release(ctx context.Context, blob string) {
+	unlock, err := c.index.LockMediaBlob(ctx, c.library, blob)
+	if err != nil {
+		logging.L(ctx).Warn("failed to lock media blob", "library", c.library, "blob", blob, sl.Err(err))
+		return
+	}
+	defer unlock()
+
+	refs, err := c.index.CountMediaBlobRefs(ctx, c.library, blob)
+	if err != nil {
+		logging.L(ctx).Warn("failed to count media blob references", "library", c.library, "blob", blob, sl.Err(err))
+		return
+	}
+
+	if refs > 0 {
+		return
+	}
+
+	if err = c.blobs.Delete(ctx, blob); err != nil && !errors.Is(err, ErrNotFound) {
+		logging.L(ctx).Warn("failed to delete media blob", "library", c.library, "blob", blob, sl.Err(err))
+	}
+}
+
+// seekable возвращает r, если его можно перечитать, иначе временную копию в tmpDir
+func (c *Content) seekable(r io.Reader) (io.ReadSeeker, func(), error) {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return rs, func() {}, nil
+	}
+
+	if err := os.MkdirAll(c.tmpDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.tmpDir, "*"+contentTmpSuffix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	if _, err = io.Copy(tmp, r); err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	return tmp, cleanup, nil
+}
+
+func fileObject(file *File, size int64) *Object {
+	return &Object{
+		Name:    file.Name,
+		Size:    size,
+		ModTime: file.ModTime,
+		Digest:  file.Digest,
+	}
+}
+
+// blobName имя содержимого: SHA-256 и расширение файла, по которому хранилище определяет Content-Type
+func blobName(digest, name string) string {
+	return digest + strings.ToLower(filepath.Ext(name))
+}
+
+// isBlobName проверяет, что name — имя содержимого, а не файла библиотеки
+func isBlobName(name string) bool {
+	digest, _, _ := strings.Cut(name, ".")
+	if len(digest) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(digest)
+
+	return err == nil
+}
@@ -0,0 +1,287 @@
+package mediastore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memIndex таблица имен файлов в памяти
+type memIndex map[string]File
+
+func (m memIndex) GetMediaFile(_ context.Context, library, name string) (*File, error) {
+	file, ok := m[library+"/"+name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &file, nil
+}
+
+func (m memIndex) ListMediaFiles(_ context.Context, library string) ([]File, error) {
+	var files []File
+	for key, file := range m {
+		if strings.HasPrefix(key, library+"/") {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+func (m memIndex) SaveMediaFile(_ context.Context, library string, file File) (string, error) {
+	prev := m[library+"/"+file.Name].Blob
+	file.ModTime = time.Now()
+	m[library+"/"+file.Name] = file
+	return prev, nil
+}
+
+func (m memIndex) CreateMediaFile(_ context.Context, library string, file File) error {
+	if _, ok := m[library+"/"+file.Name]; ok {
+		return ErrExists
+	}
+	file.ModTime = time.Now()
+	m[library+"/"+file.Name] = file
+	return nil
+}
+
+func (m memIndex) DeleteMediaFile(_ context.Context, library, name string) (string, error) {
+	file, ok := m[library+"/"+name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	delete(m, library+"/"+name)
+	return file.Blob, nil
+}
+
+func (m memIndex) CountMediaBlobRefs(_ context.Context, library, blob string) (int, error) {
+	refs := 0
+	for key, file := range m {
+		if strings.HasPrefix(key, library+"/") && file.Blob == blob {
+			refs++
+		}
+	}
+	return refs, nil
+}
+
+func (m memIndex) ListMediaFilesByDigest(_ context.Context, library, digest string) ([]File, error) {
+	files, _ := m.ListMediaFiles(context.Background(), library)
+	result := make([]File, 0)
+	for _, file := range files {
+		if file.Digest == digest {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+func (m memIndex) LockMediaBlob(context.Context, string, string) (func(), error) {
+	return func() {}, nil
+}
+
+// lockingIndex проверяет, что имя ставится на содержимое и содержимое освобождается только под блокировкой
+type lockingIndex struct {
+	memIndex
+	t    *testing.T
+	held map[string]bool
+	// beforeLock вызывается перед блокировкой, как если бы в это время работал другой экземпляр
+	beforeLock func(blob string)
+}
+
+func (l *lockingIndex) LockMediaBlob(_ context.Context, library, blob string) (func(), error) {
+	if l.beforeLock != nil {
+		l.beforeLock(blob)
+	}
+	require.False(l.t, l.held[library+"/"+blob], "blob %s is already locked", blob)
+	l.held[library+"/"+blob] = true
+	return func() { delete(l.held, library+"/"+blob) }, nil
+}
+
+func (l *lockingIndex) SaveMediaFile(ctx context.Context, library string, file File) (string, error) {
+	assert.True(l.t, l.held[library+"/"+file.Blob], "name %s is linked without lock", file.Name)
+	return l.memIndex.SaveMediaFile(ctx, library, file)
+}
+
+func (l *lockingIndex) CreateMediaFile(ctx context.Context, library string, file File) error {
+	assert.True(l.t, l.held[library+"/"+file.Blob], "name %s is linked without lock", file.Name)
+	return l.memIndex.CreateMediaFile(ctx, library, file)
+}
+
+func (l *lockingIndex) CountMediaBlobRefs(ctx context.Context, library, blob string) (int, error) {
+	assert.True(l.t, l.held[library+"/"+blob], "blob %s is released without lock", blob)
+	return l.memIndex.CountMediaBlobRefs(ctx, library, blob)
+}
+
+const (
+	// SHA-256 строк "first" и "second"
+	firstDigest  = "a7937b64b8caa58f03721bb6bacf5c78cb235febe0e70b1b84cd99541461a08e"
+	secondDigest = "16367aacb67a4a017c8da8ab95682ccb390863780f7114dda0a0e0c55644c7c4"
+)
+
+func TestContent(t *testing.T) {
+	ctx := context.Background()
+	blobs := NewLocal(t.TempDir())
+	store := NewContent(blobs, memIndex{}, "video", t.TempDir())
+
+	// Одинаковое содержимое под двумя именами хранится один раз
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("first"), 5))
+	require.NoError(t, store.Save(ctx, "b.MP4", io.LimitReader(strings.NewReader("first"), 5), 5))
+	assertBlobs(t, blobs, firstDigest+".mp4")
+
+	obj, err := store.Stat(ctx, "b.MP4")
+	require.NoError(t, err)
+	assert.Equal(t, firstDigest, obj.Digest)
+	assert.Equal(t, int64(5), obj.Size)
+
+	// Новое содержимое под тем же именем не затрагивает другое имя
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("second"), 6))
+	assertBlobs(t, blobs, secondDigest+".mp4", firstDigest+".mp4")
+
+	file, obj, err := store.Open(ctx, "a.mp4")
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	file.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+	assert.Equal(t, secondDigest, obj.Digest)
+
+	// Содержимое удаляется вместе с последним именем
+	require.NoError(t, store.Delete(ctx, "b.MP4"))
+	assertBlobs(t, blobs, secondDigest+".mp4")
+	assert.ErrorIs(t, store.Delete(ctx, "b.MP4"), ErrNotFound)
+
+	// Содержимое недоступно напрямую по имени из SHA-256
+	_, err = store.Stat(ctx, secondDigest+".mp4")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.Save(ctx, secondDigest+".mp4", strings.NewReader("x"), 1), ErrInvalidName)
+}
+
+func TestContent_Adopt(t *testing.T) {
+	ctx := context.Background()
+	blobs := NewLocal(t.TempDir())
+	store := NewContent(blobs, memIndex{}, "video", t.TempDir())
+
+	// Файл, загруженный до хранения по содержимому, отдается по имени без контрольной суммы
+	require.NoError(t, os.WriteFile(filepath.Join(blobs.Dir(), "old.mp4"), []byte("first"), 0644))
+	require.NoError(t, store.Save(ctx, "new.mp4", strings.NewReader("second"), 6))
+
+	files, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "new.mp4", files[0].Name)
+	assert.Equal(t, "old.mp4", files[1].Name)
+	assert.Empty(t, files[1].Digest)
+
+	adopted, err := store.(*Content).Adopt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old.mp4"}, adopted)
+	assertBlobs(t, blobs, secondDigest+".mp4", firstDigest+".mp4")
+
+	obj, err := store.Stat(ctx, "old.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, firstDigest, obj.Digest)
+}
+
+func TestContent_Lock(t *testing.T) {
+	ctx := context.Background()
+	blobs := NewLocal(t.TempDir())
+	index := &lockingIndex{memIndex: memIndex{}, t: t, held: map[string]bool{}}
+	store := NewContent(blobs, index, "video", t.TempDir())
+
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("first"), 5))
+
+	// Пока содержимое загружалось, другой экземпляр удалил последнее имя и содержимое.
+	// Под блокировкой содержимое загружается заново, и новое имя не остается без файла.
+	index.beforeLock = func(blob string) {
+		if blob == firstDigest+".mp4" {
+			require.NoError(t, blobs.Delete(ctx, blob))
+		}
+	}
+	require.NoError(t, store.Save(ctx, "b.mp4", strings.NewReader("first"), 5))
+	index.beforeLock = nil
+	assertBlobs(t, blobs, firstDigest+".mp4")
+
+	// Замена и удаление освобождают содержимое под блокировкой
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("second"), 6))
+	require.NoError(t, store.Delete(ctx, "b.mp4"))
+	assertBlobs(t, blobs, secondDigest+".mp4")
+	assert.Empty(t, index.held)
+}
+
+func TestContent_Link(t *testing.T) {
+	ctx := context.Background()
+	blobs := NewLocal(t.TempDir())
+	index := &lockingIndex{memIndex: memIndex{}, t: t, held: map[string]bool{}}
+	store := NewContent(blobs, index, "video", t.TempDir())
+
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("first"), 5))
+	require.NoError(t, store.Save(ctx, "b.mp4", strings.NewReader("second"), 6))
+
+	// Новое имя ставится на то же содержимое, данные не копируются
+	require.NoError(t, store.(Linker).Link(ctx, "a.mp4", "c.mp4"))
+	assertBlobs(t, blobs, secondDigest+".mp4", firstDigest+".mp4")
+
+	obj, err := store.Stat(ctx, "c.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, firstDigest, obj.Digest)
+
+	// Занятое имя не перезаписывается
+	assert.ErrorIs(t, store.(Linker).Link(ctx, "a.mp4", "b.mp4"), ErrExists)
+	obj, err = store.Stat(ctx, "b.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, secondDigest, obj.Digest)
+
+	// Имя файла, загруженного до хранения по содержимому, тоже занято
+	require.NoError(t, os.WriteFile(filepath.Join(blobs.Dir(), "old.mp4"), []byte("old"), 0644))
+	assert.ErrorIs(t, store.(Linker).Link(ctx, "a.mp4", "old.mp4"), ErrExists)
+
+	// Такой файл переносится под имя из SHA-256, и на него ставится новое имя
+	require.NoError(t, store.(Linker).Link(ctx, "old.mp4", "old-1.mp4"))
+	first, err := store.Stat(ctx, "old.mp4")
+	require.NoError(t, err)
+	second, err := store.Stat(ctx, "old-1.mp4")
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.Digest)
+	assert.Equal(t, first.Digest, second.Digest)
+
+	assert.ErrorIs(t, store.(Linker).Link(ctx, "missing.mp4", "d.mp4"), ErrNotFound)
+	assert.Empty(t, index.held)
+}
+
+func TestContent_FindByDigest(t *testing.T) {
+	ctx := context.Background()
+	store := NewContent(NewLocal(t.TempDir()), memIndex{}, "video", t.TempDir())
+
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("first"), 5))
+	require.NoError(t, store.Save(ctx, "b.mp4", strings.NewReader("first"), 5))
+	require.NoError(t, store.Save(ctx, "c.mp4", strings.NewReader("second"), 6))
+
+	// Дубликаты ищутся по таблице имен
+	objects, err := store.(DigestFinder).FindByDigest(ctx, firstDigest)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "a.mp4", objects[0].Name)
+	assert.Equal(t, "b.mp4", objects[1].Name)
+}
+
+func assertBlobs(t *testing.T, blobs *Local, expected ...string) {
+	t.Helper()
+
+	objects, err := blobs.List(context.Background())
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		names = append(names, obj.Name)
+	}
+	sort.Strings(names)
+
+	assert.Equal(t, expected, names)
+}
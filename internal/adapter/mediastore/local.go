@@ -0,0 +1,193 @@
+package mediastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// saveTmpSuffix суффикс файла, который еще записывается
+const saveTmpSuffix = ".save.tmp"
+
+// Local хранит файлы в директории на диске
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) *Local {
+	return &Local{dir: filepath.Clean(dir)}
+}
+
+// Dir возвращает директорию хранилища
+func (l *Local) Dir() string {
+	return l.dir
+}
+
+// Save записывает файл во временный файл рядом и переименовывает его,
+// чтобы файл не отдавался клиентам недописанным
+func (l *Local) Save(_ context.Context, name string, r io.Reader, _ int64) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(l.dir, name+".*"+saveTmpSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// CreateTemp создает файл с правами 0600, а файлы библиотеки читает веб-сервер
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
+
+// Link создает жесткую ссылку dst на файл src. os.Link не перезаписывает существующий файл,
+// поэтому занятое имя не может быть занято повторно между проверкой и записью.
+func (l *Local) Link(_ context.Context, src, dst string) error {
+	srcPath, err := l.path(src)
+	if err != nil {
+		return err
+	}
+
+	dstPath, err := l.path(dst)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Link(srcPath, dstPath); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return ErrExists
+		}
+		return notFound(err)
+	}
+
+	return nil
+}
+
+func (l *Local) Open(_ context.Context, name string) (io.ReadSeekCloser, *Object, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, notFound(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, nil, ErrNotFound
+	}
+
+	return file, &Object{Name: name, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (l *Local) Stat(_ context.Context, name string) (*Object, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, ErrNotFound
+	}
+
+	return &Object{Name: name, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// List возвращает файлы директории без поддиректорий и недописанных файлов. Отсутствующая директория считается пустой.
+func (l *Local) List(ctx context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []Object
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !entry.Type().IsRegular() || strings.HasSuffix(entry.Name(), saveTmpSuffix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		result = append(result, Object{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	return result, nil
+}
+
+func (l *Local) Delete(_ context.Context, name string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil {
+		return notFound(err)
+	}
+
+	return nil
+}
+
+func (l *Local) path(name string) (string, error) {
+	if err := checkName(name); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(l.dir, name), nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,77 @@
+package mediastore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(filepath.Join(t.TempDir(), "video"))
+
+	// Отсутствующая директория считается пустой и создается при первой записи
+	files, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("0123456789"), 10))
+	require.NoError(t, os.Mkdir(filepath.Join(store.Dir(), "nested"), 0755))
+
+	file, obj, err := store.Open(ctx, "a.mp4")
+	require.NoError(t, err)
+	defer file.Close()
+	assert.Equal(t, int64(10), obj.Size)
+
+	_, err = file.Seek(4, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "456789", string(data))
+
+	files, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "a.mp4", files[0].Name)
+
+	require.NoError(t, store.Delete(ctx, "a.mp4"))
+	_, err = store.Stat(ctx, "a.mp4")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "a.mp4"), ErrNotFound)
+}
+
+func TestLocal_Link(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocal(t.TempDir())
+
+	require.NoError(t, store.Save(ctx, "a.mp4", strings.NewReader("first"), 5))
+	require.NoError(t, store.Save(ctx, "b.mp4", strings.NewReader("second"), 6))
+
+	require.NoError(t, store.Link(ctx, "a.mp4", "c.mp4"))
+	obj, err := store.Stat(ctx, "c.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), obj.Size)
+
+	// Занятое имя не перезаписывается
+	assert.ErrorIs(t, store.Link(ctx, "a.mp4", "b.mp4"), ErrExists)
+	obj, err = store.Stat(ctx, "b.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), obj.Size)
+
+	assert.ErrorIs(t, store.Link(ctx, "missing.mp4", "d.mp4"), ErrNotFound)
+}
+
+func TestLocal_InvalidName(t *testing.T) {
+	store := NewLocal(t.TempDir())
+
+	for _, name := range []string{"", "..", "../secret.txt", `..\secret.txt`, "dir/a.mp4"} {
+		_, _, err := store.Open(context.Background(), name)
+		assert.ErrorIs(t, err, ErrInvalidName, name)
+	}
+}
@@ -0,0 +1,94 @@
+// Package mediastore хранит медиафайлы библиотеки: видео, изображения и PDF-памятки.
+//
+// Одна MediaStore соответствует одной библиотеке. Локальная реализация работает с директорией
+// на диске, S3 — с префиксом в бакете S3-совместимого хранилища (AWS S3, MinIO).
+// Content поверх любой из них хранит содержимое по SHA-256, а имена файлов — в таблице БД.
+package mediastore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("media file not found")
+	ErrExists      = errors.New("media file already exists")
+	ErrInvalidName = errors.New("invalid media file name")
+)
+
+// Object описание файла в хранилище
+type Object struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	Digest  string // SHA-256 содержимого в hex, заполняет только Content
+}
+
+// ETag возвращает сильный ETag файла: SHA-256 содержимого, если он известен, иначе размер и время изменения
+func (o *Object) ETag() string {
+	if o.Digest != "" {
+		return `"` + o.Digest + `"`
+	}
+
+	return fmt.Sprintf(`"%x-%x"`, o.Size, o.ModTime.UnixNano())
+}
+
+// DigestHeader возвращает значение заголовка Digest (RFC 3230) или пустую строку, если SHA-256 неизвестен
+func (o *Object) DigestHeader() string {
+	sum, err := hex.DecodeString(o.Digest)
+	if o.Digest == "" || err != nil {
+		return ""
+	}
+
+	return "sha-256=" + base64.StdEncoding.EncodeToString(sum)
+}
+
+// MediaStore хранилище файлов одной библиотеки
+type MediaStore interface {
+	// Save записывает файл целиком, существующий файл с тем же именем заменяется. size — размер данных в r.
+	Save(ctx context.Context, name string, r io.Reader, size int64) error
+	// Open открывает файл на чтение. Seek позволяет отдавать файл по частям (Range).
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, *Object, error)
+	Stat(ctx context.Context, name string) (*Object, error)
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// Presigner хранилище, которое умеет выдавать временные ссылки для скачивания файла напрямую
+type Presigner interface {
+	PresignGet(ctx context.Context, name string, ttl time.Duration) (string, error)
+}
+
+// Linker хранилище, которое ставит новое имя на существующий файл, не копируя данные
+type Linker interface {
+	// Link создает имя dst с содержимым src. Занятое имя не перезаписывается: ErrExists.
+	Link(ctx context.Context, src, dst string) error
+}
+
+// DigestFinder хранилище, которое находит файлы по SHA-256 содержимого без перебора всей библиотеки
+type DigestFinder interface {
+	FindByDigest(ctx context.Context, digest string) ([]Object, error)
+}
+
+// Stores хранилища всех библиотек
+type Stores struct {
+	Video     MediaStore
+	Images    MediaStore
+	Docs      MediaStore
+	Subtitles MediaStore
+}
+
+// checkName пропускает только имя файла без директорий
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return ErrInvalidName
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package mediastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	// s3PartSize размер части при загрузке больших файлов, S3 ограничивает одиночный PUT 5 GB
+	s3PartSize = 64 << 20
+	// maxPresignTTL максимальный срок временной ссылки в Signature V4
+	maxPresignTTL = 7 * 24 * time.Hour
+)
+
+// S3Client доступ к бакету S3-совместимого хранилища через minio-go. Тело запроса не хэшируется
+// (UNSIGNED-PAYLOAD), поэтому файлы передаются потоком без повторного чтения.
+type S3Client struct {
+	client   *minio.Client
+	public   *minio.Client // Подписывает временные ссылки адресом S3_PUBLIC_ENDPOINT, запросов не выполняет
+	bucket   string
+	partSize uint64
+}
+
+// NewS3Client проверяет настройки доступа к бакету
+func NewS3Client(cfg config.MediaStore) (*S3Client, error) {
+	if cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, errors.New("S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required")
+	}
+
+	rawEndpoint := cfg.S3Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + cfg.S3Region + ".amazonaws.com"
+	}
+
+	// Тайм-аут ограничивает ожидание ответа, а не всю передачу: видео может скачиваться дольше
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = cfg.S3Timeout
+	transport.DialContext = (&net.Dialer{Timeout: cfg.S3Timeout}).DialContext
+
+	client, err := newMinioClient(cfg, rawEndpoint, transport)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT: %w", err)
+	}
+
+	public := client
+	if cfg.S3PublicEndpoint != "" {
+		if public, err = newMinioClient(cfg, cfg.S3PublicEndpoint, transport); err != nil {
+			return nil, fmt.Errorf("invalid S3_PUBLIC_ENDPOINT: %w", err)
+		}
+	}
+
+	return &S3Client{
+		client:   client,
+		public:   public,
+		bucket:   cfg.S3Bucket,
+		partSize: s3PartSize,
+	}, nil
+}
+
+// newMinioClient создает клиент для адреса хранилища. Регион задается явно, чтобы клиент
+// не запрашивал расположение бакета перед первым запросом и при подписи ссылок.
+func newMinioClient(cfg config.MediaStore, rawEndpoint string, transport http.RoundTripper) (*minio.Client, error) {
+	u, err := url.Parse(strings.TrimRight(rawEndpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("endpoint must be an http or https URL")
+	}
+	if u.Path != "" || u.RawQuery != "" {
+		return nil, errors.New("endpoint must not contain a path")
+	}
+
+	lookup := minio.BucketLookupDNS
+	if cfg.S3PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	return minio.New(u.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure:       u.Scheme == "https",
+		Region:       cfg.S3Region,
+		BucketLookup: lookup,
+		Transport:    transport,
+	})
+}
+
+// Store возвращает хранилище библиотеки с ключами вида prefix/имя_файла
+func (c *S3Client) Store(prefix string) *S3 {
+	return &S3{client: c, prefix: strings.Trim(prefix, "/") + "/"}
+}
+
+// S3 хранит файлы одной библиотеки под префиксом в бакете
+type S3 struct {
+	client *S3Client
+	prefix string
+}
+
+// Save загружает файл одним запросом, а файлы больше части — по частям (multipart upload).
+// При ошибке загрузка по частям отменяется, чтобы в бакете не оставались оплачиваемые части.
+func (s *S3) Save(ctx context.Context, name string, r io.Reader, size int64) error {
+	key, err := s.key(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.client.PutObject(ctx, s.client.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType:          contentType(name),
+		PartSize:             s.client.partSize,
+		DisableContentSha256: true,
+	})
+	if err != nil {
+		return fmt.Errorf("s3 put %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Open открывает объект на чтение. Данные запрашиваются при первом чтении после Seek,
+// поэтому http.ServeContent отдает Range без скачивания всего файла.
+func (s *S3) Open(ctx context.Context, name string) (io.ReadSeekCloser, *Object, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := s.client.client.GetObject(ctx, s.client.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, s3Error("get", key, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, s3Error("get", key, err)
+	}
+
+	return file, &Object{Name: name, Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+func (s *S3) Stat(ctx context.Context, name string) (*Object, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.client.StatObject(ctx, s.client.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s3Error("head", key, err)
+	}
+
+	return &Object{Name: name, Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+// List возвращает файлы под префиксом библиотеки без вложенных «директорий»
+func (s *S3) List(ctx context.Context) ([]Object, error) {
+	var result []Object
+
+	for info := range s.client.client.ListObjects(ctx, s.client.bucket, minio.ListObjectsOptions{Prefix: s.prefix}) {
+		if info.Err != nil {
+			return nil, s3Error("list", s.prefix, info.Err)
+		}
+
+		name := strings.TrimPrefix(info.Key, s.prefix)
+		if checkName(name) != nil {
+			continue
+		}
+		result = append(result, Object{Name: name, Size: info.Size, ModTime: info.LastModified})
+	}
+
+	return result, nil
+}
+
+func (s *S3) Delete(ctx context.Context, name string) error {
+	// DELETE в S3 успешен и для несуществующего ключа, поэтому отсутствие файла проверяется заранее
+	if _, err := s.Stat(ctx, name); err != nil {
+		return err
+	}
+
+	key := s.prefix + name
+	if err := s.client.client.RemoveObject(ctx, s.client.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return s3Error("delete", key, err)
+	}
+
+	return nil
+}
+
+// PresignGet возвращает временную ссылку на скачивание файла по адресу S3_PUBLIC_ENDPOINT
+func (s *S3) PresignGet(ctx context.Context, name string, ttl time.Duration) (string, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return "", err
+	}
+
+	if ttl > maxPresignTTL {
+		ttl = maxPresignTTL
+	}
+
+	link, err := s.client.public.PresignedGetObject(ctx, s.client.bucket, key, ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("s3 presign %s: %w", key, err)
+	}
+
+	return link.String(), nil
+}
+
+func (s *S3) key(name string) (string, error) {
+	if err := checkName(name); err != nil {
+		return "", err
+	}
+
+	return s.prefix + name, nil
+}
+
+// s3Error возвращает отсутствие ключа как ErrNotFound, остальные ошибки — с операцией и ключом
+func s3Error(op, key string, err error) error {
+	if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+		return ErrNotFound
+	}
+
+	return fmt.Errorf("s3 %s %s: %w", op, key, err)
+}
+
+// contentType определяет тип файла по расширению, чтобы хранилище отдавало его по временной ссылке
+func contentType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+
+	switch strings.ToLower(path.Ext(name)) {
+	case ".mp4":
+		return "video/mp4"
+	case ".mov":
+		return "video/quicktime"
+	case ".webm":
+		return "video/webm"
+	}
+
+	return "application/octet-stream"
+}
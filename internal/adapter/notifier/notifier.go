@@ -0,0 +1,231 @@
+// Package notifier отправляет уведомления сотрудникам через внешние каналы: Telegram, email и webhook.
+// Каждый канал обрабатывает свою очередь в отдельной горутине, поэтому медленный канал
+// не задерживает ни HTTP ответ, ни другие каналы.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// EventFeedback событие о новом сообщении обратной связи
+const EventFeedback = "feedback.created"
+
+// maxTextLength ограничение длины текста уведомления, у Telegram предел 4096 символов
+const maxTextLength = 4000
+
+// Message уведомление, которое каждый канал оформляет по-своему
+type Message struct {
+	Event   string
+	Subject string
+	Text    string
+	Data    any // Данные события для webhook
+}
+
+// Channel канал доставки уведомлений
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// permanentError ошибка, после которой повтор отправки не поможет
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent помечает ошибку как неповторяемую
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// statusError преобразует неуспешный HTTP статус в ошибку. Ошибки клиента, кроме 429, не повторяются.
+func statusError(resp *http.Response) error {
+	err := fmt.Errorf("unexpected status %s", resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return permanent(err)
+	}
+	return err
+}
+
+// Notifier распределяет уведомления по очередям каналов и доставляет их с повторами
+type Notifier struct {
+	cfg     config.Notify
+	baseURL string
+	queues  map[Channel]chan Message
+}
+
+// New создает Notifier с каналами, включенными в конфигурации. Без включенных каналов уведомления не отправляются.
+func New(cfg *config.Config) *Notifier {
+	channels := make([]Channel, 0, 3)
+
+	if cfg.Notify.Telegram.Enable {
+		channels = append(channels, NewTelegram(cfg.Notify.Telegram))
+	}
+	if cfg.Notify.SMTP.Enable {
+		channels = append(channels, NewSMTP(cfg.Notify.SMTP))
+	}
+	if cfg.Notify.Webhook.Enable {
+		channels = append(channels, NewWebhook(cfg.Notify.Webhook))
+	}
+
+	return NewWithChannels(cfg.Notify, cfg.Media.BaseURL, channels...)
+}
+
+// NewWithChannels создает Notifier с переданными каналами
+func NewWithChannels(cfg config.Notify, baseURL string, channels ...Channel) *Notifier {
+	queueSize := cfg.QueueSize
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	n := &Notifier{
+		cfg:     cfg,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		queues:  make(map[Channel]chan Message, len(channels)),
+	}
+
+	for _, channel := range channels {
+		n.queues[channel] = make(chan Message, queueSize)
+	}
+
+	return n
+}
+
+// Start запускает обработку очередей до отмены ctx. Неотправленные к этому моменту уведомления теряются.
+func (n *Notifier) Start(ctx context.Context) {
+	for channel, queue := range n.queues {
+		go n.run(ctx, channel, queue)
+	}
+}
+
+// NotifyFeedback ставит уведомление о новой обратной связи в очереди всех каналов без ожидания отправки
+func (n *Notifier) NotifyFeedback(ctx context.Context, feedback *api.Feedback) {
+	n.Notify(ctx, Message{
+		Event:   EventFeedback,
+		Subject: fmt.Sprintf("Новое сообщение обратной связи #%d", feedback.ID),
+		Text:    n.feedbackText(feedback),
+		Data: map[string]any{
+			"id":       feedback.ID,
+			"name":     feedback.Name,
+			"email":    feedback.Email,
+			"telegram": feedback.Telegram,
+			"message":  feedback.Message,
+		},
+	})
+}
+
+// Notify ставит уведомление в очереди всех каналов. Если очередь канала заполнена, уведомление для него отбрасывается.
+func (n *Notifier) Notify(ctx context.Context, msg Message) {
+	const op = "notifier.Notify"
+
+	for channel, queue := range n.queues {
+		select {
+		case queue <- msg:
+		default:
+			logging.L(ctx).Warn("notification queue is full, message dropped", "op", op, "channel", channel.Name(), "event", msg.Event)
+		}
+	}
+}
+
+func (n *Notifier) run(ctx context.Context, channel Channel, queue chan Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			if dropped := len(queue); dropped > 0 {
+				logging.L(ctx).Warn("notifier stopped with pending messages", "channel", channel.Name(), "dropped", dropped)
+			}
+			return
+		case msg := <-queue:
+			n.deliver(ctx, channel, msg)
+		}
+	}
+}
+
+// deliver отправляет уведомление, повторяя попытки с удваивающейся задержкой
+func (n *Notifier) deliver(ctx context.Context, channel Channel, msg Message) {
+	const op = "notifier.deliver"
+
+	delay := n.cfg.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		err := n.send(ctx, channel, msg)
+		if err == nil {
+			logging.L(ctx).Debug("notification sent", "op", op, "channel", channel.Name(), "event", msg.Event, "attempt", attempt+1)
+			return
+		}
+
+		var permanentErr *permanentError
+		if errors.As(err, &permanentErr) || attempt >= n.cfg.Retries || ctx.Err() != nil {
+			logging.L(ctx).Error("failed to send notification", "op", op, "channel", channel.Name(), "event", msg.Event, "attempts", attempt+1, sl.Err(err))
+			return
+		}
+
+		logging.L(ctx).Warn("failed to send notification, retrying", "op", op, "channel", channel.Name(), "event", msg.Event, "attempt", attempt+1, "retry_in", delay.String(), sl.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, channel Channel, msg Message) error {
+	if n.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.cfg.Timeout)
+		defer cancel()
+	}
+
+	return channel.Send(ctx, msg)
+}
+
+func (n *Notifier) feedbackText(feedback *api.Feedback) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Новое сообщение обратной связи #%d\n", feedback.ID)
+	if feedback.Name != "" {
+		fmt.Fprintf(&b, "Имя: %s\n", feedback.Name)
+	}
+	if feedback.Email != "" {
+		fmt.Fprintf(&b, "Email: %s\n", feedback.Email)
+	}
+	if feedback.Telegram != "" {
+		fmt.Fprintf(&b, "Telegram: %s\n", feedback.Telegram)
+	}
+
+	b.WriteString("\n")
+	b.WriteString(truncate(feedback.Message, maxTextLength-utf8.RuneCountInString(b.String())-len(n.baseURL)-20))
+
+	if n.baseURL != "" {
+		fmt.Fprintf(&b, "\n\n%s/admin/web", n.baseURL)
+	}
+
+	return b.String()
+}
+
+// truncate обрезает строку до limit символов
+func truncate(s string, limit int) string {
+	if limit < 0 {
+		limit = 0
+	}
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "…"
+}
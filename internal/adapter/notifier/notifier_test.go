@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theartofdevel/logging"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithCancel(logging.ContextWithLogger(context.Background(), logdiscart.NewDiscardLogger()))
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func testConfig() config.Notify {
+	return config.Notify{
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+		Timeout:    time.Second,
+		QueueSize:  10,
+	}
+}
+
+func TestNotifier_WebhookRetriesUntilSuccess(t *testing.T) {
+	var calls atomic.Int32
+	received := make(chan webhookPayload, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		// Первая попытка завершается ошибкой сервера, вторая успешна
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var payload webhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+	}))
+	defer srv.Close()
+
+	n := NewWithChannels(testConfig(), "https://example.com", NewWebhook(config.NotifyWebhook{URL: srv.URL, Token: "secret"}))
+	ctx := testContext(t)
+	n.Start(ctx)
+
+	n.NotifyFeedback(ctx, &api.Feedback{ID: 7, Name: "Иван", Email: "ivan@example.com", Message: "Болит колено"})
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, EventFeedback, payload.Event)
+		assert.Contains(t, payload.Text, "Болит колено")
+		assert.Contains(t, payload.Text, "https://example.com/admin/web")
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestNotifier_TelegramClientErrorIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	done := make(chan struct{}, 3)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bottoken/sendMessage", r.URL.Path)
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		done <- struct{}{}
+	}))
+	defer srv.Close()
+
+	n := NewWithChannels(testConfig(), "", NewTelegram(config.NotifyTelegram{BaseURL: srv.URL, Token: "token", ChatID: "1"}))
+	ctx := testContext(t)
+	n.Start(ctx)
+
+	n.NotifyFeedback(ctx, &api.Feedback{ID: 1, Telegram: "@user", Message: "Спасибо"})
+
+	<-done
+	// Даем время на возможные повторы
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNotifier_FullQueueDoesNotBlock(t *testing.T) {
+	blocked := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-blocked
+	}))
+	defer srv.Close()
+	defer close(blocked)
+
+	cfg := testConfig()
+	cfg.QueueSize = 1
+	n := NewWithChannels(cfg, "", NewWebhook(config.NotifyWebhook{URL: srv.URL}))
+	ctx := testContext(t)
+	n.Start(ctx)
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		n.NotifyFeedback(ctx, &api.Feedback{ID: int64(i), Message: "test"})
+	}
+
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "абв", truncate("абв", 3))
+	assert.Equal(t, "аб…", truncate("абвг", 2))
+	assert.Equal(t, "…", truncate("абв", -1))
+}
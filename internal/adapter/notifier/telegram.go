@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+)
+
+// Telegram отправляет уведомления в чат через Telegram Bot API
+type Telegram struct {
+	baseURL string
+	token   string
+	chatID  string
+	client  *http.Client
+}
+
+func NewTelegram(cfg config.NotifyTelegram) *Telegram {
+	return &Telegram{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		token:   cfg.Token,
+		chatID:  cfg.ChatID,
+		client:  &http.Client{},
+	}
+}
+
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+func (t *Telegram) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  t.chatID,
+		"text":                     msg.Text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return permanent(fmt.Errorf("failed to encode message: %w", err))
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", t.baseURL, t.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Ошибка содержит URL с токеном бота, поэтому в лог попадает только ее причина
+		return fmt.Errorf("telegram request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp)
+	}
+
+	var res struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !res.OK {
+		return permanent(fmt.Errorf("telegram error: %s", res.Description))
+	}
+
+	return nil
+}
+
+// unwrapURLError убирает из ошибки http клиента URL запроса
+func unwrapURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+)
+
+// Webhook отправляет уведомления POST запросом с JSON на произвольный URL
+type Webhook struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// webhookPayload тело запроса webhook
+type webhookPayload struct {
+	Event   string    `json:"event"`
+	Subject string    `json:"subject"`
+	Text    string    `json:"text"`
+	Data    any       `json:"data,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+func NewWebhook(cfg config.NotifyWebhook) *Webhook {
+	return &Webhook{
+		url:    cfg.URL,
+		token:  cfg.Token,
+		client: &http.Client{},
+	}
+}
+
+func (h *Webhook) Name() string {
+	return "webhook"
+}
+
+func (h *Webhook) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:   msg.Event,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		Data:    msg.Data,
+		SentAt:  time.Now(),
+	})
+	if err != nil {
+		return permanent(fmt.Errorf("failed to encode payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+const (
+	fcmScope        = "https://www.googleapis.com/auth/firebase.messaging"
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+
+	// tokenRefreshMargin запас, с которым токен доступа обновляется до истечения
+	tokenRefreshMargin = time.Minute
+)
+
+// serviceAccount поля JSON ключа сервисного аккаунта Google, нужные для получения токена доступа
+type serviceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// FCM отправляет уведомления через Firebase Cloud Messaging HTTP v1.
+// Токен доступа OAuth 2.0 получается по ключу сервисного аккаунта и кэшируется до истечения.
+type FCM struct {
+	client    *http.Client
+	baseURL   string
+	projectID string
+	account   serviceAccount
+	key       *rsa.PrivateKey
+	now       func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCM читает ключ сервисного аккаунта из cfg.FCMCredentials
+func NewFCM(cfg config.Push) (*FCM, error) {
+	data, err := os.ReadFile(cfg.FCMCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+
+	var account serviceAccount
+	if err = json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("FCM credentials must contain client_email and private_key")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FCM private key: %w", err)
+	}
+
+	if account.TokenURI == "" {
+		account.TokenURI = defaultTokenURI
+	}
+
+	projectID := cfg.FCMProjectID
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" {
+		return nil, errors.New("FCM project ID is not set")
+	}
+
+	return &FCM{
+		client:    &http.Client{Timeout: cfg.Timeout},
+		baseURL:   strings.TrimRight(cfg.FCMBaseURL, "/"),
+		projectID: projectID,
+		account:   account,
+		key:       key,
+		now:       time.Now,
+	}, nil
+}
+
+// fcmRequest тело запроса messages:send
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      fcmAndroid        `json:"android"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmAndroid struct {
+	Priority string `json:"priority"`
+}
+
+// fcmError тело ответа FCM с ошибкой
+type fcmError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			Type      string `json:"@type"`
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send отправляет уведомление на устройство. Если FCM больше не принимает токен устройства,
+// возвращает ошибку, оборачивающую api.ErrPushTokenInvalid.
+func (f *FCM) Send(ctx context.Context, msg *api.PushMessage) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        msg.Token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+		Android:      fcmAndroid{Priority: "high"},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to encode FCM message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", f.baseURL, url.PathEscape(f.projectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("FCM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// Токен доступа могли отозвать, следующая отправка получит новый
+		f.resetToken()
+	}
+
+	var fcmErr fcmError
+	_ = json.Unmarshal(respBody, &fcmErr)
+
+	if resp.StatusCode == http.StatusNotFound || fcmErr.Error.Status == "NOT_FOUND" {
+		return fmt.Errorf("%w: %s", api.ErrPushTokenInvalid, fcmErr.Error.Message)
+	}
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return fmt.Errorf("%w: %s", api.ErrPushTokenInvalid, fcmErr.Error.Message)
+		}
+	}
+
+	return fmt.Errorf("FCM unexpected status %s: %s", resp.Status, fcmErr.Error.Message)
+}
+
+// token возвращает действующий токен доступа, при необходимости получая новый
+func (f *FCM) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.accessToken != "" && f.now().Add(tokenRefreshMargin).Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	now := f.now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   f.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if f.account.PrivateKeyID != "" {
+		assertion.Header["kid"] = f.account.PrivateKeyID
+	}
+
+	signed, err := assertion.SignedString(f.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign FCM assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return "", fmt.Errorf("token request unexpected status %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("token response without access_token")
+	}
+
+	f.accessToken = token.AccessToken
+	f.expiresAt = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	return f.accessToken, nil
+}
+
+func (f *FCM) resetToken() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.accessToken = ""
+}
@@ -0,0 +1,114 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFCM поднимает сервер, который отвечает и на обмен токена, и на messages:send
+func newTestFCM(t *testing.T, send http.HandlerFunc) (*FCM, *int32) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var tokenRequests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+		// Сервер Google проверяет подпись открытым ключом сервисного аккаунта
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "push@example.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, fcmScope, claims["scope"])
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "access", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v1/projects/bodybalance/messages:send", send)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	creds, err := json.Marshal(serviceAccount{
+		ProjectID:    "bodybalance",
+		PrivateKeyID: "kid-1",
+		PrivateKey:   string(keyPEM),
+		ClientEmail:  "push@example.iam.gserviceaccount.com",
+		TokenURI:     srv.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(path, creds, 0o600))
+
+	f, err := NewFCM(config.Push{
+		Timeout:        time.Second,
+		FCMCredentials: path,
+		FCMBaseURL:     srv.URL,
+	})
+	require.NoError(t, err)
+
+	return f, &tokenRequests
+}
+
+func TestFCM_Send(t *testing.T) {
+	f, tokenRequests := newTestFCM(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+
+		var req fcmRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "device", req.Message.Token)
+		assert.Equal(t, "BodyBalance", req.Message.Notification.Title)
+		assert.Equal(t, "7", req.Message.Data["reminder_id"])
+
+		_, _ = w.Write([]byte(`{"name":"projects/bodybalance/messages/1"}`))
+	})
+
+	msg := &api.PushMessage{
+		Token: "device",
+		Title: "BodyBalance",
+		Body:  "Пора выполнить упражнения",
+		Data:  map[string]string{"reminder_id": "7"},
+	}
+
+	require.NoError(t, f.Send(context.Background(), msg))
+	require.NoError(t, f.Send(context.Background(), msg))
+
+	// Токен доступа кэшируется между отправками
+	assert.Equal(t, int32(1), atomic.LoadInt32(tokenRequests))
+}
+
+func TestFCM_SendUnregistered(t *testing.T) {
+	f, _ := newTestFCM(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND",
+			"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+	})
+
+	err := f.Send(context.Background(), &api.PushMessage{Token: "stale"})
+	assert.ErrorIs(t, err, api.ErrPushTokenInvalid)
+}
@@ -0,0 +1,38 @@
+// Package push отправляет push-уведомления на устройства пациентов.
+// FCM отправляет через Firebase Cloud Messaging HTTP v1, LogSender только пишет уведомление в лог
+// и подходит для локального запуска и тестов.
+package push
+
+import (
+	"context"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/theartofdevel/logging"
+)
+
+// LogSender пишет push-уведомления в лог вместо отправки
+type LogSender struct{}
+
+// NewLogSender создает LogSender
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// Send пишет уведомление в лог и всегда завершается успешно
+func (s *LogSender) Send(ctx context.Context, msg *api.PushMessage) error {
+	logging.L(ctx).Info("push message",
+		"token", maskToken(msg.Token),
+		"title", msg.Title,
+		"body", msg.Body,
+	)
+	return nil
+}
+
+// maskToken оставляет в логе только конец токена устройства
+func maskToken(token string) string {
+	const visible = 6
+	if len(token) <= visible {
+		return "***"
+	}
+	return "***" + token[len(token)-visible:]
+}
@@ -0,0 +1,204 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// AddAnnouncement добавляет объявление вместе с аудиторией
+func (s *Storage) AddAnnouncement(ctx context.Context, req *admin.Announcement) (int64, error) {
+	const op = "storage.postgres.AddAnnouncement"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO announcements (title, body, img_url, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, req.Title, req.Body, req.ImgURL, req.StartsAt, req.EndsAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = insertAnnouncementTypes(ctx, tx, id, req.ContentType); err != nil {
+		if errors.Is(err, admin.ErrTypeNotFound) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetAnnouncement возвращает объявление по ID
+func (s *Storage) GetAnnouncement(ctx context.Context, id int64) (*admin.Announcement, error) {
+	const op = "storage.postgres.GetAnnouncement"
+
+	announcements, err := s.queryAnnouncements(ctx, `WHERE a.id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(announcements) == 0 {
+		return nil, admin.ErrAnnouncementNotFound
+	}
+
+	return &announcements[0], nil
+}
+
+// GetAnnouncements возвращает все объявления, начинающиеся позже первыми
+func (s *Storage) GetAnnouncements(ctx context.Context) ([]admin.Announcement, error) {
+	const op = "storage.postgres.GetAnnouncements"
+
+	announcements, err := s.queryAnnouncements(ctx, ``)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return announcements, nil
+}
+
+// UpdateAnnouncement обновляет объявление и заменяет аудиторию
+func (s *Storage) UpdateAnnouncement(ctx context.Context, req *admin.Announcement) error {
+	const op = "storage.postgres.UpdateAnnouncement"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE announcements
+		SET title = $1, body = $2, img_url = $3, starts_at = $4, ends_at = $5, updated_at = NOW()
+		WHERE id = $6
+	`, req.Title, req.Body, req.ImgURL, req.StartsAt, req.EndsAt, req.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrAnnouncementNotFound
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM announcement_content_types WHERE announcement_id = $1`, req.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = insertAnnouncementTypes(ctx, tx, req.ID, req.ContentType); err != nil {
+		if errors.Is(err, admin.ErrTypeNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteAnnouncement удаляет объявление
+func (s *Storage) DeleteAnnouncement(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeleteAnnouncement"
+
+	commandTag, err := s.db.Exec(ctx, `DELETE FROM announcements WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrAnnouncementNotFound
+	}
+
+	return nil
+}
+
+// queryAnnouncements выбирает объявления по условию where вместе с типами контента аудитории
+func (s *Storage) queryAnnouncements(ctx context.Context, where string, args ...any) ([]admin.Announcement, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT a.id, a.title, a.body, a.img_url, a.starts_at, a.ends_at, a.created_at, a.updated_at,
+		       COALESCE(array_agg(ct.id ORDER BY ct.id) FILTER (WHERE ct.id IS NOT NULL), '{}'),
+		       COALESCE(array_agg(ct.name ORDER BY ct.id) FILTER (WHERE ct.id IS NOT NULL), '{}')
+		FROM announcements a
+		LEFT JOIN announcement_content_types act ON act.announcement_id = a.id
+		LEFT JOIN content_types ct ON ct.id = act.content_type_id
+		`+where+`
+		GROUP BY a.id
+		ORDER BY a.starts_at DESC, a.id DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := make([]admin.Announcement, 0)
+	for rows.Next() {
+		var a admin.Announcement
+		var typeIDs []int64
+		var typeNames []string
+
+		if err = rows.Scan(
+			&a.ID,
+			&a.Title,
+			&a.Body,
+			&a.ImgURL,
+			&a.StartsAt,
+			&a.EndsAt,
+			&a.CreatedAt,
+			&a.UpdatedAt,
+			&typeIDs,
+			&typeNames,
+		); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		a.ContentType = make([]admin.ContentType, len(typeIDs))
+		for i := range typeIDs {
+			a.ContentType[i] = admin.ContentType{ID: typeIDs[i], Name: typeNames[i]}
+		}
+
+		announcements = append(announcements, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return announcements, nil
+}
+
+func insertAnnouncementTypes(ctx context.Context, tx pgx.Tx, id int64, types []admin.ContentType) error {
+	for _, contentType := range types {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO announcement_content_types (announcement_id, content_type_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, id, contentType.ID)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return admin.ErrTypeNotFound
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// GetAppConfig возвращает настройки мобильного приложения
+func (s *Storage) GetAppConfig(ctx context.Context) (*admin.AppConfig, error) {
+	const op = "storage.postgres.GetAppConfig"
+
+	var cfg admin.AppConfig
+	err := s.db.QueryRow(ctx, `
+		SELECT min_version, latest_version, force_update, support_email, support_telegram, support_phone,
+		       features, updated_at
+		FROM app_config
+		WHERE id
+	`).Scan(
+		&cfg.MinVersion,
+		&cfg.LatestVersion,
+		&cfg.ForceUpdate,
+		&cfg.SupportEmail,
+		&cfg.SupportTelegram,
+		&cfg.SupportPhone,
+		&cfg.Features,
+		&cfg.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &cfg, nil
+}
+
+// UpdateAppConfig сохраняет настройки мобильного приложения
+func (s *Storage) UpdateAppConfig(ctx context.Context, cfg *admin.AppConfig) error {
+	const op = "storage.postgres.UpdateAppConfig"
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO app_config (id, min_version, latest_version, force_update, support_email, support_telegram,
+		                        support_phone, features, updated_at)
+		VALUES (TRUE, $1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (id) DO UPDATE
+		SET min_version = EXCLUDED.min_version,
+		    latest_version = EXCLUDED.latest_version,
+		    force_update = EXCLUDED.force_update,
+		    support_email = EXCLUDED.support_email,
+		    support_telegram = EXCLUDED.support_telegram,
+		    support_phone = EXCLUDED.support_phone,
+		    features = EXCLUDED.features,
+		    updated_at = NOW()
+	`, cfg.MinVersion, cfg.LatestVersion, cfg.ForceUpdate, cfg.SupportEmail, cfg.SupportTelegram,
+		cfg.SupportPhone, cfg.Features)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,730 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/slicesx"
+)
+
+// ExportCatalog выгружает типы контента, категории и видео со всеми связями
+func (s *Storage) ExportCatalog(ctx context.Context) (*admin.CatalogBundle, error) {
+	const op = "storage.postgres.ExportCatalog"
+
+	// RepeatableRead гарантирует, что все запросы видят один снимок данных
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	bundle := &admin.CatalogBundle{
+		Version:      admin.CatalogBundleVersion,
+		ExportedAt:   time.Now().UTC(),
+		ContentTypes: make([]admin.CatalogContentType, 0),
+		Categories:   make([]admin.CatalogCategory, 0),
+		Videos:       make([]admin.CatalogVideo, 0),
+	}
+
+	typeNames, err := queryStrings(ctx, tx, `
+		SELECT name
+		FROM content_types
+		WHERE deleted IS NOT TRUE
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query content types: %w", op, err)
+	}
+
+	for _, name := range typeNames {
+		bundle.ContentTypes = append(bundle.ContentTypes, admin.CatalogContentType{Name: name})
+	}
+
+	bundle.Categories, err = exportCategories(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	bundle.Videos, err = exportVideos(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: transaction commit failed: %w", op, err)
+	}
+
+	return bundle, nil
+}
+
+// ImportCatalog создает или обновляет сущности выгрузки по естественным ключам в одной транзакции.
+// При dryRun транзакция откатывается, а возвращаемая разница показывает, что было бы изменено.
+func (s *Storage) ImportCatalog(ctx context.Context, bundle *admin.CatalogBundle, dryRun bool) (*admin.ImportDiff, error) {
+	const op = "storage.postgres.ImportCatalog"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	diff := &admin.ImportDiff{DryRun: dryRun}
+
+	for _, contentType := range bundle.ContentTypes {
+		if err = importContentType(ctx, tx, contentType, &diff.ContentTypes); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, category := range bundle.Categories {
+		if err = importCategory(ctx, tx, category, &diff.Categories); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Родители назначаются после создания всех категорий, так как могут быть из этой же выгрузки
+	if err = importCategoryParents(ctx, tx, bundle.Categories); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Ссылки "смотреть далее" сохраняются после всех видео, так как могут указывать на видео из этой же выгрузки
+	nextUp := make(map[int64][]string)
+	for _, video := range bundle.Videos {
+		videoID, changed, err := importVideo(ctx, tx, video, &diff.Videos)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if changed {
+			nextUp[videoID] = video.NextUp
+		}
+	}
+
+	for videoID, urls := range nextUp {
+		nextIDs := make([]int64, 0, len(urls))
+		for _, url := range urls {
+			nextID, err := lookupVideoID(ctx, tx, url)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			nextIDs = append(nextIDs, nextID)
+		}
+
+		if err = saveVideoNextUp(ctx, tx, videoID, nextIDs); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if dryRun {
+		return diff, nil
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: transaction commit failed: %w", op, err)
+	}
+
+	return diff, nil
+}
+
+func exportCategories(ctx context.Context, tx pgx.Tx) ([]admin.CatalogCategory, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT c.id, c.name, COALESCE(c.img_url, ''), COALESCE(p.name, '')
+		FROM categories c
+		LEFT JOIN categories p ON p.id = c.parent_id AND p.deleted IS NOT TRUE
+		WHERE c.deleted IS NOT TRUE
+		ORDER BY c.name, c.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+
+	categories := make([]admin.CatalogCategory, 0)
+	index := make(map[int64]int)
+
+	for rows.Next() {
+		var id int64
+		category := admin.CatalogCategory{ContentTypes: make([]string, 0)}
+
+		if err = rows.Scan(&id, &category.Name, &category.ImgURL, &category.Parent); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+
+		index[id] = len(categories)
+		categories = append(categories, category)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("category rows error: %w", err)
+	}
+
+	links, err := queryLinks(ctx, tx, `
+		SELECT cct.category_id, ct.name
+		FROM category_content_types cct
+		INNER JOIN content_types ct ON ct.id = cct.content_type_id
+		WHERE ct.deleted IS NOT TRUE
+		ORDER BY ct.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category content types: %w", err)
+	}
+
+	for id, names := range links {
+		if i, exists := index[id]; exists {
+			categories[i].ContentTypes = names
+		}
+	}
+
+	return categories, nil
+}
+
+func exportVideos(ctx context.Context, tx pgx.Tx) ([]admin.CatalogVideo, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, url, name, COALESCE(description, ''), COALESCE(img_url, ''),
+		       steps, contraindications, equipment, sets, reps, difficulty
+		FROM videos
+		WHERE deleted IS NOT TRUE
+		ORDER BY url, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query videos: %w", err)
+	}
+
+	videos := make([]admin.CatalogVideo, 0)
+	index := make(map[int64]int)
+
+	for rows.Next() {
+		var id int64
+		video := admin.CatalogVideo{
+			Categories: make([]string, 0),
+			Tags:       make([]string, 0),
+			NextUp:     make([]string, 0),
+		}
+
+		err = rows.Scan(
+			&id,
+			&video.URL,
+			&video.Name,
+			&video.Description,
+			&video.ImgURL,
+			&video.Details.Steps,
+			&video.Details.Contraindications,
+			&video.Details.Equipment,
+			&video.Details.Sets,
+			&video.Details.Reps,
+			&video.Details.Difficulty,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan video: %w", err)
+		}
+
+		index[id] = len(videos)
+		videos = append(videos, video)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("video rows error: %w", err)
+	}
+
+	categories, err := queryLinks(ctx, tx, `
+		SELECT vc.video_id, c.name
+		FROM video_categories vc
+		INNER JOIN categories c ON c.id = vc.category_id
+		WHERE c.deleted IS NOT TRUE
+		ORDER BY c.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video categories: %w", err)
+	}
+
+	tags, err := queryLinks(ctx, tx, `
+		SELECT video_id, tag
+		FROM video_tags
+		ORDER BY tag
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video tags: %w", err)
+	}
+
+	nextUp, err := queryLinks(ctx, tx, `
+		SELECT n.video_id, v.url
+		FROM video_next_up n
+		INNER JOIN videos v ON v.id = n.next_video_id
+		WHERE v.deleted IS NOT TRUE
+		ORDER BY n.position
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query next up links: %w", err)
+	}
+
+	for id, i := range index {
+		if names, exists := categories[id]; exists {
+			videos[i].Categories = names
+		}
+		if names, exists := tags[id]; exists {
+			videos[i].Tags = names
+		}
+		if urls, exists := nextUp[id]; exists {
+			videos[i].NextUp = urls
+		}
+	}
+
+	return videos, nil
+}
+
+func importContentType(ctx context.Context, tx pgx.Tx, contentType admin.CatalogContentType, changes *admin.ImportChanges) error {
+	var id int64
+	err := tx.QueryRow(ctx, `
+		SELECT id
+		FROM content_types
+		WHERE name = $1 AND deleted IS NOT TRUE
+		ORDER BY id
+		LIMIT 1
+	`, contentType.Name).Scan(&id)
+
+	switch {
+	case err == nil:
+		changes.Unchanged = append(changes.Unchanged, contentType.Name)
+		return nil
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("failed to find content type %q: %w", contentType.Name, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO content_types (name, deleted)
+		VALUES ($1, FALSE)
+	`, contentType.Name)
+	if err != nil {
+		return fmt.Errorf("failed to insert content type %q: %w", contentType.Name, err)
+	}
+
+	changes.Created = append(changes.Created, contentType.Name)
+
+	return nil
+}
+
+func importCategory(ctx context.Context, tx pgx.Tx, category admin.CatalogCategory, changes *admin.ImportChanges) error {
+	typeIDs := make([]int64, 0, len(category.ContentTypes))
+	for _, name := range category.ContentTypes {
+		var typeID int64
+		err := tx.QueryRow(ctx, `
+			SELECT id
+			FROM content_types
+			WHERE name = $1 AND deleted IS NOT TRUE
+			ORDER BY id
+			LIMIT 1
+		`, name).Scan(&typeID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: content type %q of category %q", admin.ErrCatalogUnknownReference, name, category.Name)
+			}
+			return fmt.Errorf("failed to find content type %q: %w", name, err)
+		}
+		typeIDs = append(typeIDs, typeID)
+	}
+
+	var id int64
+	var imgURL, parent string
+	err := tx.QueryRow(ctx, `
+		SELECT c.id, COALESCE(c.img_url, ''), COALESCE(p.name, '')
+		FROM categories c
+		LEFT JOIN categories p ON p.id = c.parent_id AND p.deleted IS NOT TRUE
+		WHERE c.name = $1 AND c.deleted IS NOT TRUE
+		ORDER BY c.id
+		LIMIT 1
+	`, category.Name).Scan(&id, &imgURL, &parent)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = tx.QueryRow(ctx, `
+			INSERT INTO categories (name, img_url, deleted)
+			VALUES ($1, $2, FALSE)
+			RETURNING id
+		`, category.Name, category.ImgURL).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to insert category %q: %w", category.Name, err)
+		}
+
+		changes.AddCreated(category.Name, id)
+	case err != nil:
+		return fmt.Errorf("failed to find category %q: %w", category.Name, err)
+	default:
+		currentTypes, err := queryStrings(ctx, tx, `
+			SELECT ct.name
+			FROM category_content_types cct
+			INNER JOIN content_types ct ON ct.id = cct.content_type_id
+			WHERE cct.category_id = $1 AND ct.deleted IS NOT TRUE
+		`, id)
+		if err != nil {
+			return fmt.Errorf("failed to query content types of category %q: %w", category.Name, err)
+		}
+
+		if imgURL == category.ImgURL && parent == category.Parent && sameSet(currentTypes, category.ContentTypes) {
+			changes.Unchanged = append(changes.Unchanged, category.Name)
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE categories
+			SET img_url = $1
+			WHERE id = $2
+		`, category.ImgURL, id)
+		if err != nil {
+			return fmt.Errorf("failed to update category %q: %w", category.Name, err)
+		}
+
+		changes.AddUpdated(category.Name, id)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM category_content_types
+		WHERE category_id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete content types of category %q: %w", category.Name, err)
+	}
+
+	for _, typeID := range typeIDs {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO category_content_types (category_id, content_type_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, id, typeID)
+		if err != nil {
+			return fmt.Errorf("failed to add content type to category %q: %w", category.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// importCategoryParents назначает родителей категориям выгрузки и проверяет, что дерево категорий не содержит циклов
+func importCategoryParents(ctx context.Context, tx pgx.Tx, categories []admin.CatalogCategory) error {
+	if err := lockCategoryTree(ctx, tx); err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		var parentID *int64
+		if category.Parent != "" {
+			id, err := lookupCategoryID(ctx, tx, category.Parent)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("%w: parent %q of category %q", admin.ErrCatalogUnknownReference, category.Parent, category.Name)
+				}
+				return fmt.Errorf("failed to find category %q: %w", category.Parent, err)
+			}
+			parentID = &id
+		}
+
+		id, err := lookupCategoryID(ctx, tx, category.Name)
+		if err != nil {
+			return fmt.Errorf("failed to find category %q: %w", category.Name, err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE categories
+			SET parent_id = $1
+			WHERE id = $2 AND parent_id IS DISTINCT FROM $1
+		`, parentID, id)
+		if err != nil {
+			return fmt.Errorf("failed to set parent of category %q: %w", category.Name, err)
+		}
+	}
+
+	var cycle bool
+	err := tx.QueryRow(ctx, `
+		WITH RECURSIVE chain(start_id, id) AS (
+			SELECT id, parent_id
+			FROM categories
+			WHERE parent_id IS NOT NULL AND deleted IS NOT TRUE
+			UNION
+			SELECT chain.start_id, c.parent_id
+			FROM chain
+			JOIN categories c ON c.id = chain.id
+			WHERE c.parent_id IS NOT NULL
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE start_id = id)
+	`).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("failed to check category tree: %w", err)
+	}
+
+	if cycle {
+		return fmt.Errorf("%w: categories are nested in each other", admin.ErrCatalogInvalid)
+	}
+
+	return nil
+}
+
+func lookupCategoryID(ctx context.Context, tx pgx.Tx, name string) (int64, error) {
+	var id int64
+	err := tx.QueryRow(ctx, `
+		SELECT id
+		FROM categories
+		WHERE name = $1 AND deleted IS NOT TRUE
+		ORDER BY id
+		LIMIT 1
+	`, name).Scan(&id)
+
+	return id, err
+}
+
+// importVideo создает или обновляет видео без ссылок "смотреть далее".
+// Возвращает ID видео и признак того, что ссылки нужно перезаписать.
+func importVideo(ctx context.Context, tx pgx.Tx, video admin.CatalogVideo, changes *admin.ImportChanges) (int64, bool, error) {
+	categoryIDs := make([]int64, 0, len(video.Categories))
+	for _, name := range video.Categories {
+		var categoryID int64
+		err := tx.QueryRow(ctx, `
+			SELECT id
+			FROM categories
+			WHERE name = $1 AND deleted IS NOT TRUE
+			ORDER BY id
+			LIMIT 1
+		`, name).Scan(&categoryID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, false, fmt.Errorf("%w: category %q of video %q", admin.ErrCatalogUnknownReference, name, video.URL)
+			}
+			return 0, false, fmt.Errorf("failed to find category %q: %w", name, err)
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+
+	var id int64
+	var name, description, imgURL string
+	var details admin.ExerciseDetails
+	err := tx.QueryRow(ctx, `
+		SELECT id, name, COALESCE(description, ''), COALESCE(img_url, ''),
+		       steps, contraindications, equipment, sets, reps, difficulty
+		FROM videos
+		WHERE url = $1 AND deleted IS NOT TRUE
+		ORDER BY id
+		LIMIT 1
+	`, video.URL).Scan(
+		&id,
+		&name,
+		&description,
+		&imgURL,
+		&details.Steps,
+		&details.Contraindications,
+		&details.Equipment,
+		&details.Sets,
+		&details.Reps,
+		&details.Difficulty,
+	)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = tx.QueryRow(ctx, `
+			INSERT INTO videos (url, name, description, img_url,
+			                    steps, contraindications, equipment, sets, reps, difficulty, deleted)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, FALSE)
+			RETURNING id
+		`,
+			video.URL,
+			video.Name,
+			video.Description,
+			video.ImgURL,
+			slicesx.NonNil(video.Details.Steps),
+			slicesx.NonNil(video.Details.Contraindications),
+			slicesx.NonNil(video.Details.Equipment),
+			video.Details.Sets,
+			video.Details.Reps,
+			video.Details.Difficulty,
+		).Scan(&id)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to insert video %q: %w", video.URL, err)
+		}
+
+		changes.AddCreated(video.URL, id)
+	case err != nil:
+		return 0, false, fmt.Errorf("failed to find video %q: %w", video.URL, err)
+	default:
+		changed, err := videoChanged(ctx, tx, id, video)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if !changed && name == video.Name && description == video.Description && imgURL == video.ImgURL &&
+			sameDetails(details, video.Details) {
+			changes.Unchanged = append(changes.Unchanged, video.URL)
+			return id, false, nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE videos
+			SET name = $1, description = $2, img_url = $3,
+			    steps = $4, contraindications = $5, equipment = $6, sets = $7, reps = $8, difficulty = $9
+			WHERE id = $10
+		`,
+			video.Name,
+			video.Description,
+			video.ImgURL,
+			slicesx.NonNil(video.Details.Steps),
+			slicesx.NonNil(video.Details.Contraindications),
+			slicesx.NonNil(video.Details.Equipment),
+			video.Details.Sets,
+			video.Details.Reps,
+			video.Details.Difficulty,
+			id,
+		)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to update video %q: %w", video.URL, err)
+		}
+
+		changes.AddUpdated(video.URL, id)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM video_categories
+		WHERE video_id = $1
+	`, id)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to delete categories of video %q: %w", video.URL, err)
+	}
+
+	for _, categoryID := range categoryIDs {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO video_categories (video_id, category_id)
+			VALUES ($1, $2)
+			ON CONFLICT (video_id, category_id) DO NOTHING
+		`, id, categoryID)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to add category to video %q: %w", video.URL, err)
+		}
+	}
+
+	if err = saveVideoTags(ctx, tx, id, video.Tags); err != nil {
+		return 0, false, fmt.Errorf("video %q: %w", video.URL, err)
+	}
+
+	return id, true, nil
+}
+
+// videoChanged сравнивает связи видео в БД со связями из выгрузки
+func videoChanged(ctx context.Context, tx pgx.Tx, id int64, video admin.CatalogVideo) (bool, error) {
+	categories, err := queryStrings(ctx, tx, `
+		SELECT c.name
+		FROM video_categories vc
+		INNER JOIN categories c ON c.id = vc.category_id
+		WHERE vc.video_id = $1 AND c.deleted IS NOT TRUE
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to query categories of video %q: %w", video.URL, err)
+	}
+
+	tags, err := queryStrings(ctx, tx, `
+		SELECT tag
+		FROM video_tags
+		WHERE video_id = $1
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to query tags of video %q: %w", video.URL, err)
+	}
+
+	nextUp, err := queryStrings(ctx, tx, `
+		SELECT v.url
+		FROM video_next_up n
+		INNER JOIN videos v ON v.id = n.next_video_id
+		WHERE n.video_id = $1 AND v.deleted IS NOT TRUE
+		ORDER BY n.position
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to query next up links of video %q: %w", video.URL, err)
+	}
+
+	return !sameSet(categories, video.Categories) ||
+		!sameSet(tags, video.Tags) ||
+		!slices.Equal(nextUp, video.NextUp), nil
+}
+
+// sameDetails сравнивает описание упражнения, порядок элементов списков важен
+func sameDetails(a, b admin.ExerciseDetails) bool {
+	return slices.Equal(a.Steps, b.Steps) &&
+		slices.Equal(a.Contraindications, b.Contraindications) &&
+		slices.Equal(a.Equipment, b.Equipment) &&
+		a.Sets == b.Sets &&
+		a.Reps == b.Reps &&
+		a.Difficulty == b.Difficulty
+}
+
+func lookupVideoID(ctx context.Context, tx pgx.Tx, url string) (int64, error) {
+	var id int64
+	err := tx.QueryRow(ctx, `
+		SELECT id
+		FROM videos
+		WHERE url = $1 AND deleted IS NOT TRUE
+		ORDER BY id
+		LIMIT 1
+	`, url).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: next up video %q", admin.ErrCatalogUnknownReference, url)
+		}
+		return 0, fmt.Errorf("failed to find video %q: %w", url, err)
+	}
+
+	return id, nil
+}
+
+// queryStrings выполняет запрос, возвращающий одну текстовую колонку
+func queryStrings(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, rows.Err()
+}
+
+// queryLinks выполняет запрос, возвращающий пары (id, значение), и группирует значения по id
+func queryLinks(ctx context.Context, tx pgx.Tx, query string) (map[int64][]string, error) {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64][]string)
+	for rows.Next() {
+		var id int64
+		var value string
+		if err = rows.Scan(&id, &value); err != nil {
+			return nil, err
+		}
+		result[id] = append(result[id], value)
+	}
+
+	return result, rows.Err()
+}
+
+func sameSet(a, b []string) bool {
+	a = slices.Clone(a)
+	b = slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
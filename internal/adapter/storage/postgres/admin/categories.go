@@ -23,18 +23,23 @@ func (s *Storage) AddCategory(ctx context.Context, req *admin.Category) (*admin.
 		}
 	}()
 
+	if err = checkCategoryParent(ctx, tx, 0, req.ParentID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Добавляем категорию
 	var category admin.Category
 	var createdAt time.Time
 
 	err = tx.QueryRow(ctx, `
-		INSERT INTO categories (name, img_url, deleted)
-		VALUES ($1, $2, FALSE)
-		RETURNING id, name, img_url, created_at
-	`, req.Name, req.ImgURL).Scan(
+		INSERT INTO categories (name, img_url, parent_id, deleted)
+		VALUES ($1, $2, NULLIF($3, 0), FALSE)
+		RETURNING id, name, img_url, COALESCE(parent_id, 0), created_at
+	`, req.Name, req.ImgURL, req.ParentID).Scan(
 		&category.ID,
 		&category.Name,
 		&category.ImgURL,
+		&category.ParentID,
 		&createdAt,
 	)
 
@@ -95,13 +100,14 @@ func (s *Storage) GetCategory(ctx context.Context, id int64) (*admin.Category, e
 	var createdAt time.Time
 
 	err := s.db.QueryRow(ctx, `
-		SELECT id, name, img_url, created_at
+		SELECT id, name, img_url, COALESCE(parent_id, 0), created_at
 		FROM categories
 		WHERE id = $1 AND deleted IS NOT TRUE
 	`, id).Scan(
 		&category.ID,
 		&category.Name,
 		&category.ImgURL,
+		&category.ParentID,
 		&createdAt,
 	)
 
@@ -148,7 +154,7 @@ func (s *Storage) GetCategories(ctx context.Context) ([]admin.Category, error) {
 
 	// Сначала получаем все категории
 	rows, err := s.db.Query(ctx, `
-		SELECT id, name, img_url, created_at
+		SELECT id, name, img_url, COALESCE(parent_id, 0), created_at
 		FROM categories
 		WHERE deleted IS NOT TRUE
 		ORDER BY id
@@ -167,6 +173,7 @@ func (s *Storage) GetCategories(ctx context.Context) ([]admin.Category, error) {
 			&category.ID,
 			&category.Name,
 			&category.ImgURL,
+			&category.ParentID,
 			&createdAt,
 		); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -225,12 +232,18 @@ func (s *Storage) UpdateCategory(ctx context.Context, id int64, req *admin.Categ
 		}
 	}()
 
+	// Проверка в той же транзакции под блокировкой дерева: встречные переносы A в B и B в A
+	// выполняются по очереди, и второй видит результат первого
+	if err = checkCategoryParent(ctx, tx, id, req.ParentID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Обновляем основную информацию о категории
 	commandTag, err := tx.Exec(ctx, `
 		UPDATE categories
-		SET name = $1, img_url = $2
-		WHERE id = $3 AND deleted IS NOT TRUE
-	`, req.Name, req.ImgURL, id)
+		SET name = $1, img_url = $2, parent_id = NULLIF($3, 0)
+		WHERE id = $4 AND deleted IS NOT TRUE
+	`, req.Name, req.ImgURL, req.ParentID, id)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -267,7 +280,8 @@ func (s *Storage) UpdateCategory(ctx context.Context, id int64, req *admin.Categ
 	return nil
 }
 
-// DeleteCategory помечает категорию как удаленную и удаляет её связи с типами контента и видео
+// DeleteCategory помечает категорию как удаленную и удаляет её связи с типами контента и видео.
+// Дочерние категории переносятся к родителю удаляемой категории.
 func (s *Storage) DeleteCategory(ctx context.Context, id int64) error {
 	const op = "storage.postgres.DeleteCategory"
 
@@ -281,6 +295,11 @@ func (s *Storage) DeleteCategory(ctx context.Context, id int64) error {
 		}
 	}()
 
+	// Подкатегории переносятся к родителю, и новая категория не должна попасть к удаляемой
+	if err = lockCategoryTree(ctx, tx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	_, err = tx.Exec(ctx, `
 		DELETE FROM category_content_types
 		WHERE category_id = $1
@@ -297,6 +316,15 @@ func (s *Storage) DeleteCategory(ctx context.Context, id int64) error {
 		return fmt.Errorf("%s: failed to delete video relations: %w", op, err)
 	}
 
+	_, err = tx.Exec(ctx, `
+		UPDATE categories
+		SET parent_id = (SELECT parent_id FROM categories WHERE id = $1)
+		WHERE parent_id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to move subcategories: %w", op, err)
+	}
+
 	commandTag, err := tx.Exec(ctx, `
 		UPDATE categories
 		SET deleted = TRUE
@@ -316,3 +344,54 @@ func (s *Storage) DeleteCategory(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// lockCategoryTree берет транзакционную advisory-блокировку на дерево категорий. Ее берут все изменения
+// родителей, поэтому проверка на циклы видит дерево, которое не поменяется до конца транзакции.
+func lockCategoryTree(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('categories/tree', 0))`)
+	if err != nil {
+		return fmt.Errorf("failed to lock category tree: %w", err)
+	}
+
+	return nil
+}
+
+// checkCategoryParent блокирует дерево категорий и проверяет, что родитель существует, а категория id
+// не попадает в собственное поддерево. Для новой категории id равен 0.
+// UNION отбрасывает повторы, поэтому запрос завершается даже на испорченных данных с циклом.
+func checkCategoryParent(ctx context.Context, tx pgx.Tx, id, parentID int64) error {
+	if err := lockCategoryTree(ctx, tx); err != nil {
+		return err
+	}
+
+	if parentID == 0 {
+		return nil
+	}
+
+	var found, cycle bool
+	err := tx.QueryRow(ctx, `
+		WITH RECURSIVE chain(id, parent_id) AS (
+			SELECT id, parent_id
+			FROM categories
+			WHERE id = $1 AND deleted IS NOT TRUE
+			UNION
+			SELECT c.id, c.parent_id
+			FROM categories c
+			JOIN chain ON c.id = chain.parent_id
+		)
+		SELECT COUNT(*) > 0, COALESCE(bool_or(id = $2), FALSE)
+		FROM chain
+	`, parentID, id).Scan(&found, &cycle)
+	if err != nil {
+		return fmt.Errorf("failed to check parent category: %w", err)
+	}
+
+	switch {
+	case !found:
+		return admin.ErrParentNotFound
+	case cycle:
+		return admin.ErrCategoryCycle
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// GetUserDiary возвращает записи дневника пациента за период по возрастанию даты вместе с отмеченными видео
+func (s *Storage) GetUserDiary(ctx context.Context, userID int64, filter *admin.DiaryFilter) ([]admin.DiaryEntry, error) {
+	const op = "storage.postgres.GetUserDiary"
+
+	var exists bool
+	err := s.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1 AND deleted IS NOT TRUE)
+	`, userID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to check user: %w", op, err)
+	}
+	if !exists {
+		return nil, admin.ErrUserNotFound
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT d.id, d.entry_date, d.pain_score, d.body_area, d.notes,
+		       COALESCE(d.content_type_id, 0), COALESCE(ct.name, ''), d.created_at
+		FROM diary_entries d
+		LEFT JOIN content_types ct ON ct.id = d.content_type_id
+		WHERE d.account_id = $1
+		  AND ($2::date IS NULL OR d.entry_date >= $2)
+		  AND ($3::date IS NULL OR d.entry_date <= $3)
+		ORDER BY d.entry_date, d.created_at, d.id
+	`, userID, nullDate(filter.From), nullDate(filter.To))
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := make([]admin.DiaryEntry, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var e admin.DiaryEntry
+		if err = rows.Scan(
+			&e.ID,
+			&e.Date,
+			&e.PainScore,
+			&e.BodyArea,
+			&e.Notes,
+			&e.ContentTypeID,
+			&e.ContentTypeName,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		e.Videos = make([]admin.DiaryVideo, 0)
+
+		index[e.ID] = len(entries)
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+
+	videoRows, err := s.db.Query(ctx, `
+		SELECT dv.entry_id, v.id, v.name
+		FROM diary_entry_videos dv
+		JOIN videos v ON v.id = dv.video_id
+		WHERE dv.entry_id = ANY($1) AND v.deleted IS NOT TRUE
+		ORDER BY v.name, v.id
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get videos: %w", op, err)
+	}
+	defer videoRows.Close()
+
+	for videoRows.Next() {
+		var entryID int64
+		var video admin.DiaryVideo
+		if err = videoRows.Scan(&entryID, &video.ID, &video.Name); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan video: %w", op, err)
+		}
+
+		if i, ok := index[entryID]; ok {
+			entries[i].Videos = append(entries[i].Videos, video)
+		}
+	}
+
+	if err = videoRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: video rows error: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// GetDiaryTrend возвращает среднюю оценку боли пациентов типа контента по периодам
+func (s *Storage) GetDiaryTrend(ctx context.Context, filter *admin.DiaryTrendFilter) ([]admin.DiaryTrendPoint, error) {
+	const op = "storage.postgres.GetDiaryTrend"
+
+	var typeID int64
+	err := s.db.QueryRow(ctx, `
+		SELECT id FROM content_types WHERE id = $1 AND deleted IS NOT TRUE
+	`, filter.ContentTypeID).Scan(&typeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, admin.ErrTypeNotFound
+		}
+		return nil, fmt.Errorf("%s: failed to check content type: %w", op, err)
+	}
+
+	rows, err := s.db.Query(ctx, `
+		WITH per_patient AS (
+		    SELECT date_trunc($2, d.entry_date::timestamp)::date AS period_start,
+		           d.account_id,
+		           AVG(d.pain_score) AS avg_score,
+		           COUNT(*) AS entries
+		    FROM diary_entries d
+		    JOIN accounts a ON a.id = d.account_id
+		    WHERE d.content_type_id = $1
+		      AND d.entry_date BETWEEN $3 AND $4
+		      AND a.deleted IS NOT TRUE
+		    GROUP BY 1, 2
+		)
+		SELECT period_start, AVG(avg_score)::float8, SUM(entries)::int, COUNT(*)::int
+		FROM per_patient
+		GROUP BY period_start
+		ORDER BY period_start
+	`, typeID, filter.Period, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	points := make([]admin.DiaryTrendPoint, 0)
+	for rows.Next() {
+		var p admin.DiaryTrendPoint
+		if err = rows.Scan(&p.PeriodStart, &p.AvgScore, &p.Entries, &p.Patients); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		points = append(points, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return points, nil
+}
+
+// nullDate превращает нулевую дату в NULL для необязательных границ периода
+func nullDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,217 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// AddDocument добавляет документ и прикрепляет его к видео и категориям
+func (s *Storage) AddDocument(ctx context.Context, req *admin.Document) (int64, error) {
+	const op = "storage.postgres.AddDocument"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO documents (title, url)
+		VALUES ($1, $2)
+		RETURNING id
+	`, req.Title, req.URL).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = saveDocumentLinks(ctx, tx, id, req); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetDocument возвращает документ по ID
+func (s *Storage) GetDocument(ctx context.Context, id int64) (*admin.Document, error) {
+	const op = "storage.postgres.GetDocument"
+
+	documents, err := s.queryDocuments(ctx, `WHERE d.id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(documents) == 0 {
+		return nil, admin.ErrDocumentNotFound
+	}
+
+	return &documents[0], nil
+}
+
+// GetDocuments возвращает все документы
+func (s *Storage) GetDocuments(ctx context.Context) ([]admin.Document, error) {
+	const op = "storage.postgres.GetDocuments"
+
+	documents, err := s.queryDocuments(ctx, ``)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return documents, nil
+}
+
+// UpdateDocument обновляет документ и заменяет список видео и категорий
+func (s *Storage) UpdateDocument(ctx context.Context, req *admin.Document) error {
+	const op = "storage.postgres.UpdateDocument"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE documents
+		SET title = $1, url = $2, updated_at = NOW()
+		WHERE id = $3
+	`, req.Title, req.URL, req.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrDocumentNotFound
+	}
+
+	if err = saveDocumentLinks(ctx, tx, req.ID, req); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteDocument удаляет документ, связи с видео и категориями удаляются каскадно
+func (s *Storage) DeleteDocument(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeleteDocument"
+
+	commandTag, err := s.db.Exec(ctx, `DELETE FROM documents WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrDocumentNotFound
+	}
+
+	return nil
+}
+
+// queryDocuments выбирает документы по условию where вместе с ID не удаленных видео и категорий
+func (s *Storage) queryDocuments(ctx context.Context, where string, args ...any) ([]admin.Document, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT d.id, d.title, d.url, d.created_at, d.updated_at,
+		       COALESCE((
+		           SELECT array_agg(vd.video_id ORDER BY vd.video_id)
+		           FROM video_documents vd
+		           JOIN videos v ON v.id = vd.video_id
+		           WHERE vd.document_id = d.id AND v.deleted IS NOT TRUE
+		       ), '{}'),
+		       COALESCE((
+		           SELECT array_agg(cd.category_id ORDER BY cd.category_id)
+		           FROM category_documents cd
+		           JOIN categories c ON c.id = cd.category_id
+		           WHERE cd.document_id = d.id AND c.deleted IS NOT TRUE
+		       ), '{}')
+		FROM documents d
+		`+where+`
+		ORDER BY d.title, d.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	documents := make([]admin.Document, 0)
+	for rows.Next() {
+		var d admin.Document
+		if err = rows.Scan(
+			&d.ID,
+			&d.Title,
+			&d.URL,
+			&d.CreatedAt,
+			&d.UpdatedAt,
+			&d.VideoIDs,
+			&d.CategoryIDs,
+		); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		documents = append(documents, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return documents, nil
+}
+
+// saveDocumentLinks заменяет видео и категории документа в рамках транзакции.
+// Удаленные видео и категории считаются не найденными.
+func saveDocumentLinks(ctx context.Context, tx pgx.Tx, id int64, req *admin.Document) error {
+	const op = "storage.postgres.saveDocumentLinks"
+
+	if _, err := tx.Exec(ctx, `DELETE FROM video_documents WHERE document_id = $1`, id); err != nil {
+		return fmt.Errorf("%s: failed to delete video links: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM category_documents WHERE document_id = $1`, id); err != nil {
+		return fmt.Errorf("%s: failed to delete category links: %w", op, err)
+	}
+
+	for _, videoID := range req.VideoIDs {
+		commandTag, err := tx.Exec(ctx, `
+			INSERT INTO video_documents (video_id, document_id)
+			SELECT id, $2
+			FROM videos
+			WHERE id = $1 AND deleted IS NOT TRUE
+			ON CONFLICT DO NOTHING
+		`, videoID, id)
+		if err != nil {
+			return fmt.Errorf("%s: failed to attach to video %d: %w", op, videoID, err)
+		}
+
+		if commandTag.RowsAffected() == 0 {
+			return admin.ErrVideoNotFound
+		}
+	}
+
+	for _, categoryID := range req.CategoryIDs {
+		commandTag, err := tx.Exec(ctx, `
+			INSERT INTO category_documents (category_id, document_id)
+			SELECT id, $2
+			FROM categories
+			WHERE id = $1 AND deleted IS NOT TRUE
+			ON CONFLICT DO NOTHING
+		`, categoryID, id)
+		if err != nil {
+			return fmt.Errorf("%s: failed to attach to category %d: %w", op, categoryID, err)
+		}
+
+		if commandTag.RowsAffected() == 0 {
+			return admin.ErrCategoryNotFound
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// likeEscaper экранирует спецсимволы шаблона ILIKE в поисковом запросе
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListFeedback возвращает страницу обратной связи по фильтру, новые сообщения первыми.
+// Признак прочтения и счетчики непрочитанного считаются для администратора username.
+func (s *Storage) ListFeedback(ctx context.Context, username string, filter *admin.FeedbackFilter) (*admin.FeedbackList, error) {
+	const op = "storage.postgres.ListFeedback"
+
+	pattern := ""
+	if filter.Query != "" {
+		pattern = "%" + likeEscaper.Replace(filter.Query) + "%"
+	}
+
+	where := `
+		WHERE ($1::text = '' OR f.status = $1)
+		  AND ($2::bigint = 0 OR f.assignee_id = $2)
+		  AND ($3::text = '' OR f.username ILIKE $3 OR f.email ILIKE $3 OR f.telegram ILIKE $3 OR f.message ILIKE $3)
+	`
+
+	list := &admin.FeedbackList{
+		Items: make([]admin.Feedback, 0),
+		Unread: map[string]int{
+			admin.FeedbackStatusNew:        0,
+			admin.FeedbackStatusInProgress: 0,
+			admin.FeedbackStatusResolved:   0,
+		},
+	}
+
+	err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM feedback f`+where,
+		filter.Status, filter.AssigneeID, pattern,
+	).Scan(&list.Total)
+	if err != nil {
+		return nil, fmt.Errorf("%s: count query failed: %w", op, err)
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT f.id, COALESCE(f.username, ''), COALESCE(f.email, ''), COALESCE(f.telegram, ''),
+		       f.message, f.status, COALESCE(f.assignee_id, 0), COALESCE(a.username, ''),
+		       EXISTS (
+		           SELECT 1
+		           FROM feedback_reads r
+		           JOIN accounts me ON me.id = r.account_id
+		           WHERE r.feedback_id = f.id AND me.username = $4
+		       ),
+		       f.created_at, f.updated_at
+		FROM feedback f
+		LEFT JOIN accounts a ON a.id = f.assignee_id
+	`+where+`
+		ORDER BY f.created_at DESC, f.id DESC
+		LIMIT $5 OFFSET $6
+	`, filter.Status, filter.AssigneeID, pattern, username, filter.Limit, (filter.Page-1)*filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var feedback admin.Feedback
+		if err = rows.Scan(
+			&feedback.ID,
+			&feedback.Username,
+			&feedback.Email,
+			&feedback.Telegram,
+			&feedback.Message,
+			&feedback.Status,
+			&feedback.AssigneeID,
+			&feedback.AssigneeName,
+			&feedback.Read,
+			&feedback.CreatedAt,
+			&feedback.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		list.Items = append(list.Items, feedback)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	unreadRows, err := s.db.Query(ctx, `
+		SELECT f.status, COUNT(*)
+		FROM feedback f
+		WHERE NOT EXISTS (
+		    SELECT 1
+		    FROM feedback_reads r
+		    JOIN accounts me ON me.id = r.account_id
+		    WHERE r.feedback_id = f.id AND me.username = $1
+		)
+		GROUP BY f.status
+	`, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: unread query failed: %w", op, err)
+	}
+	defer unreadRows.Close()
+
+	for unreadRows.Next() {
+		var status string
+		var count int
+		if err = unreadRows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("%s: unread scan failed: %w", op, err)
+		}
+		list.Unread[status] = count
+	}
+
+	if err = unreadRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: unread rows error: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// GetFeedback возвращает сообщение обратной связи с заметками
+func (s *Storage) GetFeedback(ctx context.Context, id int64, username string) (*admin.Feedback, error) {
+	const op = "storage.postgres.GetFeedback"
+
+	var feedback admin.Feedback
+	err := s.db.QueryRow(ctx, `
+		SELECT f.id, COALESCE(f.username, ''), COALESCE(f.email, ''), COALESCE(f.telegram, ''),
+		       f.message, f.status, COALESCE(f.assignee_id, 0), COALESCE(a.username, ''),
+		       EXISTS (
+		           SELECT 1
+		           FROM feedback_reads r
+		           JOIN accounts me ON me.id = r.account_id
+		           WHERE r.feedback_id = f.id AND me.username = $2
+		       ),
+		       f.created_at, f.updated_at
+		FROM feedback f
+		LEFT JOIN accounts a ON a.id = f.assignee_id
+		WHERE f.id = $1
+	`, id, username).Scan(
+		&feedback.ID,
+		&feedback.Username,
+		&feedback.Email,
+		&feedback.Telegram,
+		&feedback.Message,
+		&feedback.Status,
+		&feedback.AssigneeID,
+		&feedback.AssigneeName,
+		&feedback.Read,
+		&feedback.CreatedAt,
+		&feedback.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, admin.ErrFeedbackNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT n.id, COALESCE(a.username, ''), n.text, n.created_at
+		FROM feedback_notes n
+		LEFT JOIN accounts a ON a.id = n.author_id
+		WHERE n.feedback_id = $1
+		ORDER BY n.created_at, n.id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: notes query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	feedback.Notes = make([]admin.FeedbackNote, 0)
+	for rows.Next() {
+		var note admin.FeedbackNote
+		if err = rows.Scan(&note.ID, &note.Author, &note.Text, &note.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: notes scan failed: %w", op, err)
+		}
+		feedback.Notes = append(feedback.Notes, note)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: notes rows error: %w", op, err)
+	}
+
+	return &feedback, nil
+}
+
+// MarkFeedbackRead отмечает сообщение прочитанным администратором username
+func (s *Storage) MarkFeedbackRead(ctx context.Context, id int64, username string) error {
+	const op = "storage.postgres.MarkFeedbackRead"
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO feedback_reads (feedback_id, account_id)
+		SELECT $1, id
+		FROM accounts
+		WHERE username = $2 AND deleted IS NOT TRUE
+		ON CONFLICT DO NOTHING
+	`, id, username)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateFeedbackStatus меняет статус обработки сообщения
+func (s *Storage) UpdateFeedbackStatus(ctx context.Context, id int64, status string) error {
+	const op = "storage.postgres.UpdateFeedbackStatus"
+
+	commandTag, err := s.db.Exec(ctx, `
+		UPDATE feedback
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`, status, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrFeedbackNotFound
+	}
+
+	return nil
+}
+
+// AssignFeedback назначает ответственного администратора, assigneeID = 0 снимает назначение
+func (s *Storage) AssignFeedback(ctx context.Context, id, assigneeID int64) error {
+	const op = "storage.postgres.AssignFeedback"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if assigneeID != 0 {
+		var isAdmin bool
+		err = tx.QueryRow(ctx, `
+			SELECT admin IS TRUE
+			FROM accounts
+			WHERE id = $1 AND deleted IS NOT TRUE
+		`, assigneeID).Scan(&isAdmin)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && !isAdmin) {
+			return admin.ErrFeedbackAssigneeNotAdmin
+		}
+		if err != nil {
+			return fmt.Errorf("%s: failed to get assignee: %w", op, err)
+		}
+	}
+
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE feedback
+		SET assignee_id = NULLIF($1, 0), updated_at = NOW()
+		WHERE id = $2
+	`, assigneeID, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrFeedbackNotFound
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: transaction commit failed: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddFeedbackNote добавляет внутреннюю заметку от имени администратора username
+func (s *Storage) AddFeedbackNote(ctx context.Context, id int64, username, text string) (*admin.FeedbackNote, error) {
+	const op = "storage.postgres.AddFeedbackNote"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	note := admin.FeedbackNote{Author: username, Text: text}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO feedback_notes (feedback_id, author_id, text)
+		VALUES ($1, (SELECT id FROM accounts WHERE username = $2 AND deleted IS NOT TRUE), $3)
+		RETURNING id, created_at
+	`, id, username, text).Scan(&note.ID, &note.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, admin.ErrFeedbackNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE feedback
+		SET updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: transaction commit failed: %w", op, err)
+	}
+
+	return &note, nil
+}
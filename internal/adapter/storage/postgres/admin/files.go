@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// GetVideoFileUsage возвращает видео, ссылающиеся на видеофайлы, сгруппированные по имени файла
+func (s *Storage) GetVideoFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error) {
+	const op = "storage.postgres.GetVideoFileUsage"
+
+	usage, err := s.queryFileUsage(ctx, `
+		SELECT url, 'video', id, name, 'url'
+		FROM videos
+		WHERE deleted IS NOT TRUE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// GetImageFileUsage возвращает видео и категории, ссылающиеся на изображения, сгруппированные по имени файла
+func (s *Storage) GetImageFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error) {
+	const op = "storage.postgres.GetImageFileUsage"
+
+	usage, err := s.queryFileUsage(ctx, `
+		SELECT img_url, 'video', id, name, 'img_url'
+		FROM videos
+		WHERE deleted IS NOT TRUE AND img_url IS NOT NULL
+		UNION ALL
+		SELECT img_url, 'category', id, name, 'img_url'
+		FROM categories
+		WHERE deleted IS NOT TRUE AND img_url IS NOT NULL
+		UNION ALL
+		SELECT img_url, 'announcement', id, title, 'img_url'
+		FROM announcements
+		WHERE img_url <> ''
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// GetDocumentFileUsage возвращает документы, ссылающиеся на PDF-файлы, сгруппированные по имени файла
+func (s *Storage) GetDocumentFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error) {
+	const op = "storage.postgres.GetDocumentFileUsage"
+
+	usage, err := s.queryFileUsage(ctx, `
+		SELECT url, 'document', id, title, 'url'
+		FROM documents
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// GetSubtitleFileUsage возвращает видео, ссылающиеся на файлы субтитров, сгруппированные по имени файла
+func (s *Storage) GetSubtitleFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error) {
+	const op = "storage.postgres.GetSubtitleFileUsage"
+
+	usage, err := s.queryFileUsage(ctx, `
+		SELECT s.url, 'video', v.id, v.name, 'subtitles'
+		FROM video_subtitles s
+		JOIN videos v ON v.id = s.video_id
+		WHERE v.deleted IS NOT TRUE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+func (s *Storage) queryFileUsage(ctx context.Context, query string) (map[string][]admin.FileUsage, error) {
+	rows, err := s.db.Query(ctx, query+` ORDER BY 1, 2, 3`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make(map[string][]admin.FileUsage)
+	for rows.Next() {
+		var file string
+		var u admin.FileUsage
+
+		if err = rows.Scan(&file, &u.Entity, &u.ID, &u.Name, &u.Field); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		usage[file] = append(usage[file], u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return usage, nil
+}
+
+// RenameVideoFile заменяет имя видеофайла в ссылках видео и возвращает обновленные видео
+func (s *Storage) RenameVideoFile(ctx context.Context, oldName, newName string) ([]admin.FileUsage, error) {
+	const op = "storage.postgres.RenameVideoFile"
+
+	usage, err := s.renameFileReferences(ctx, oldName, newName, `
+		UPDATE videos SET url = $2
+		WHERE url = $1
+		RETURNING 'video', id, name, 'url', deleted IS TRUE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// RenameImageFile заменяет имя изображения в ссылках видео, категорий и объявлений в одной транзакции
+// и возвращает обновленные записи
+func (s *Storage) RenameImageFile(ctx context.Context, oldName, newName string) ([]admin.FileUsage, error) {
+	const op = "storage.postgres.RenameImageFile"
+
+	usage, err := s.renameFileReferences(ctx, oldName, newName, `
+		UPDATE videos SET img_url = $2
+		WHERE img_url = $1
+		RETURNING 'video', id, name, 'img_url', deleted IS TRUE
+	`, `
+		UPDATE categories SET img_url = $2
+		WHERE img_url = $1
+		RETURNING 'category', id, name, 'img_url', deleted IS TRUE
+	`, `
+		UPDATE announcements SET img_url = $2
+		WHERE img_url = $1
+		RETURNING 'announcement', id, title, 'img_url', FALSE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// renameFileReferences выполняет запросы переименования в одной транзакции. Мягко удаленные записи
+// тоже обновляются, чтобы после восстановления ссылались на существующий файл, но в результат не попадают.
+func (s *Storage) renameFileReferences(ctx context.Context, oldName, newName string, queries ...string) ([]admin.FileUsage, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	usage := make([]admin.FileUsage, 0)
+	for _, query := range queries {
+		rows, err := tx.Query(ctx, query, oldName, newName)
+		if err != nil {
+			return nil, fmt.Errorf("query failed: %w", err)
+		}
+
+		for rows.Next() {
+			var u admin.FileUsage
+			var deleted bool
+
+			if err = rows.Scan(&u.Entity, &u.ID, &u.Name, &u.Field, &deleted); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan failed: %w", err)
+			}
+
+			if !deleted {
+				usage = append(usage, u)
+			}
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows error: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return usage, nil
+}
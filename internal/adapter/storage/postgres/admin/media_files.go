@@ -0,0 +1,200 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+)
+
+// GetMediaFile возвращает имя файла библиотеки и содержимое, на которое оно указывает
+func (s *Storage) GetMediaFile(ctx context.Context, library, name string) (*mediastore.File, error) {
+	const op = "storage.postgres.GetMediaFile"
+
+	var file mediastore.File
+	err := s.db.QueryRow(ctx, `
+		SELECT name, sha256, blob, size, updated_at
+		FROM media_files
+		WHERE library = $1 AND name = $2
+	`, library, name).Scan(&file.Name, &file.Digest, &file.Blob, &file.Size, &file.ModTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, mediastore.ErrNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &file, nil
+}
+
+// ListMediaFiles возвращает все имена файлов библиотеки
+func (s *Storage) ListMediaFiles(ctx context.Context, library string) ([]mediastore.File, error) {
+	const op = "storage.postgres.ListMediaFiles"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT name, sha256, blob, size, updated_at
+		FROM media_files
+		WHERE library = $1
+		ORDER BY name
+	`, library)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	files := make([]mediastore.File, 0)
+	for rows.Next() {
+		var file mediastore.File
+		if err = rows.Scan(&file.Name, &file.Digest, &file.Blob, &file.Size, &file.ModTime); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		files = append(files, file)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return files, nil
+}
+
+// ListMediaFilesByDigest возвращает имена файлов библиотеки с указанным SHA-256
+func (s *Storage) ListMediaFilesByDigest(ctx context.Context, library, digest string) ([]mediastore.File, error) {
+	const op = "storage.postgres.ListMediaFilesByDigest"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT name, sha256, blob, size, updated_at
+		FROM media_files
+		WHERE library = $1 AND sha256 = $2
+		ORDER BY name
+	`, library, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	files := make([]mediastore.File, 0)
+	for rows.Next() {
+		var file mediastore.File
+		if err = rows.Scan(&file.Name, &file.Digest, &file.Blob, &file.Size, &file.ModTime); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		files = append(files, file)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return files, nil
+}
+
+// SaveMediaFile сохраняет имя файла и возвращает содержимое, на которое оно указывало раньше,
+// или пустую строку для нового имени
+func (s *Storage) SaveMediaFile(ctx context.Context, library string, file mediastore.File) (string, error) {
+	const op = "storage.postgres.SaveMediaFile"
+
+	var prev string
+	err := s.db.QueryRow(ctx, `
+		WITH prev AS (
+			SELECT blob FROM media_files
+			WHERE library = $1 AND name = $2
+			FOR UPDATE
+		)
+		INSERT INTO media_files (library, name, sha256, blob, size)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (library, name) DO UPDATE
+		SET sha256 = EXCLUDED.sha256,
+		    blob = EXCLUDED.blob,
+		    size = EXCLUDED.size,
+		    updated_at = NOW()
+		RETURNING COALESCE((SELECT blob FROM prev), '')
+	`, library, file.Name, file.Digest, file.Blob, file.Size).Scan(&prev)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prev, nil
+}
+
+// CreateMediaFile добавляет новое имя файла. Занятое имя не перезаписывается: возвращается mediastore.ErrExists.
+func (s *Storage) CreateMediaFile(ctx context.Context, library string, file mediastore.File) error {
+	const op = "storage.postgres.CreateMediaFile"
+
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO media_files (library, name, sha256, blob, size)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (library, name) DO NOTHING
+	`, library, file.Name, file.Digest, file.Blob, file.Size)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return mediastore.ErrExists
+	}
+
+	return nil
+}
+
+// DeleteMediaFile удаляет имя файла и возвращает содержимое, на которое оно указывало
+func (s *Storage) DeleteMediaFile(ctx context.Context, library, name string) (string, error) {
+	const op = "storage.postgres.DeleteMediaFile"
+
+	var blob string
+	err := s.db.QueryRow(ctx, `
+		DELETE FROM media_files
+		WHERE library = $1 AND name = $2
+		RETURNING blob
+	`, library, name).Scan(&blob)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", mediastore.ErrNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blob, nil
+}
+
+// CountMediaBlobRefs возвращает количество имен, указывающих на содержимое
+func (s *Storage) CountMediaBlobRefs(ctx context.Context, library, blob string) (int, error) {
+	const op = "storage.postgres.CountMediaBlobRefs"
+
+	var refs int
+	err := s.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM media_files
+		WHERE library = $1 AND blob = $2
+	`, library, blob).Scan(&refs)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refs, nil
+}
+
+// LockMediaBlob берет транзакционную advisory-блокировку на содержимое библиотеки. Блокировка общая
+// для всех экземпляров сервиса и держится до вызова unlock, а при обрыве соединения снимается сама.
+func (s *Storage) LockMediaBlob(ctx context.Context, library, blob string) (func(), error) {
+	const op = "storage.postgres.LockMediaBlob"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		SELECT pg_advisory_xact_lock(hashtextextended($1 || '/' || $2, 0))
+	`, library, blob)
+	if err != nil {
+		tx.Rollback(context.Background())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Контекст запроса к этому моменту может быть отменен, а блокировку нужно снять в любом случае
+	return func() {
+		tx.Rollback(context.Background())
+	}, nil
+}
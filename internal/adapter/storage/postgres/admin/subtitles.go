@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// GetVideoSubtitles возвращает дорожки субтитров видео
+func (s *Storage) GetVideoSubtitles(ctx context.Context, videoID int64) ([]admin.Subtitle, error) {
+	const op = "storage.postgres.GetVideoSubtitles"
+
+	var exists bool
+	err := s.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM videos WHERE id = $1 AND deleted IS NOT TRUE)
+	`, videoID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, admin.ErrVideoNotFound
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, video_id, language, label, url, created_at, updated_at
+		FROM video_subtitles
+		WHERE video_id = $1
+		ORDER BY language
+	`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	subtitles := make([]admin.Subtitle, 0)
+	for rows.Next() {
+		var sub admin.Subtitle
+		if err = rows.Scan(&sub.ID, &sub.VideoID, &sub.Language, &sub.Label, &sub.URL, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		subtitles = append(subtitles, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return subtitles, nil
+}
+
+// SaveVideoSubtitle добавляет дорожку субтитров или заменяет дорожку видео на том же языке.
+// Удаленное видео считается не найденным.
+func (s *Storage) SaveVideoSubtitle(ctx context.Context, sub *admin.Subtitle) error {
+	const op = "storage.postgres.SaveVideoSubtitle"
+
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO video_subtitles (video_id, language, label, url)
+		SELECT id, $2, $3, $4
+		FROM videos
+		WHERE id = $1 AND deleted IS NOT TRUE
+		ON CONFLICT (video_id, language) DO UPDATE
+		SET label = EXCLUDED.label,
+		    url = EXCLUDED.url,
+		    updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`, sub.VideoID, sub.Language, sub.Label, sub.URL).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return admin.ErrVideoNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteVideoSubtitle удаляет дорожку субтитров и возвращает имя ее файла
+func (s *Storage) DeleteVideoSubtitle(ctx context.Context, videoID int64, language string) (string, error) {
+	const op = "storage.postgres.DeleteVideoSubtitle"
+
+	var url string
+	err := s.db.QueryRow(ctx, `
+		DELETE FROM video_subtitles
+		WHERE video_id = $1 AND language = $2
+		RETURNING url
+	`, videoID, language).Scan(&url)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", admin.ErrSubtitleNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return url, nil
+}
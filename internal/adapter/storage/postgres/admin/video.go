@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/slicesx"
 )
 
 // AddVideo добавляет новое видео в БД
@@ -24,14 +25,21 @@ func (s *Storage) AddVideo(ctx context.Context, video *admin.Video) (int64, erro
 	// 1. Вставляем видео
 	var videoID int64
 	err = tx.QueryRow(ctx, `
-        INSERT INTO videos (url, name, description, img_url, deleted)
-        VALUES ($1, $2, $3, $4, FALSE)
+        INSERT INTO videos (url, name, description, img_url,
+                            steps, contraindications, equipment, sets, reps, difficulty, deleted)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, FALSE)
         RETURNING id
     `,
 		video.URL,
 		video.Name,
 		video.Description,
 		video.ImgURL,
+		slicesx.NonNil(video.Details.Steps),
+		slicesx.NonNil(video.Details.Contraindications),
+		slicesx.NonNil(video.Details.Equipment),
+		video.Details.Sets,
+		video.Details.Reps,
+		video.Details.Difficulty,
 	).Scan(&videoID)
 
 	if err != nil {
@@ -57,6 +65,18 @@ func (s *Storage) AddVideo(ctx context.Context, video *admin.Video) (int64, erro
 		}
 	}
 
+	// 3. Сохраняем теги и ссылки "смотреть далее"
+	if err = saveVideoTags(ctx, tx, videoID, video.Tags); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = saveVideoNextUp(ctx, tx, videoID, video.NextVideoIDs); err != nil {
+		if errors.Is(err, admin.ErrVideoNextUpNotFound) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return 0, fmt.Errorf("%s: transaction commit failed: %w", op, err)
 	}
@@ -80,7 +100,8 @@ func (s *Storage) GetVideo(ctx context.Context, id int64) (*admin.Video, error)
 
 	// Сначала получаем данные видео
 	videoQuery := `
-        SELECT id, url, name, description, img_url, created_at
+        SELECT id, url, name, description, img_url,
+               steps, contraindications, equipment, sets, reps, difficulty, created_at
         FROM videos
         WHERE id = $1 AND deleted IS NOT TRUE
     `
@@ -94,6 +115,12 @@ func (s *Storage) GetVideo(ctx context.Context, id int64) (*admin.Video, error)
 		&video.Name,
 		&video.Description,
 		&video.ImgURL,
+		&video.Details.Steps,
+		&video.Details.Contraindications,
+		&video.Details.Equipment,
+		&video.Details.Sets,
+		&video.Details.Reps,
+		&video.Details.Difficulty,
 		&createdAt,
 	)
 	if err != nil {
@@ -143,6 +170,14 @@ func (s *Storage) GetVideo(ctx context.Context, id int64) (*admin.Video, error)
 		return nil, fmt.Errorf("%s: category rows error: %w", op, err)
 	}
 
+	if err = loadVideoLinks(ctx, tx, map[int64]*admin.Video{video.ID: &video}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = loadVideoRating(ctx, tx, &video); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Коммитим read-only транзакцию
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
@@ -167,7 +202,8 @@ func (s *Storage) GetVideos(ctx context.Context) ([]admin.Video, error) {
 
 	// Сначала получаем все видео
 	videoQuery := `
-        SELECT id, url, name, description, img_url, created_at
+        SELECT id, url, name, description, img_url,
+               steps, contraindications, equipment, sets, reps, difficulty, created_at
         FROM videos
         WHERE deleted IS NOT TRUE
         ORDER BY created_at DESC, id
@@ -192,6 +228,12 @@ func (s *Storage) GetVideos(ctx context.Context) ([]admin.Video, error) {
 			&video.Name,
 			&video.Description,
 			&video.ImgURL,
+			&video.Details.Steps,
+			&video.Details.Contraindications,
+			&video.Details.Equipment,
+			&video.Details.Sets,
+			&video.Details.Reps,
+			&video.Details.Difficulty,
 			&createdAt,
 		)
 		if err != nil {
@@ -263,6 +305,10 @@ func (s *Storage) GetVideos(ctx context.Context) ([]admin.Video, error) {
 		return nil, fmt.Errorf("%s: category rows error: %w", op, err)
 	}
 
+	if err = loadVideoLinks(ctx, tx, videoMap); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Коммитим read-only транзакцию
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
@@ -285,8 +331,9 @@ func (s *Storage) UpdateVideo(ctx context.Context, video *admin.Video) error {
 	// 1. Обновляем основные данные видео
 	updateVideoQuery := `
 		UPDATE videos
-		SET url = $1, name = $2, description = $3, img_url = $4
-		WHERE id = $5 AND deleted IS NOT TRUE
+		SET url = $1, name = $2, description = $3, img_url = $4,
+		    steps = $5, contraindications = $6, equipment = $7, sets = $8, reps = $9, difficulty = $10
+		WHERE id = $11 AND deleted IS NOT TRUE
 	`
 
 	commandTag, err := tx.Exec(ctx, updateVideoQuery,
@@ -294,6 +341,12 @@ func (s *Storage) UpdateVideo(ctx context.Context, video *admin.Video) error {
 		video.Name,
 		video.Description,
 		video.ImgURL,
+		slicesx.NonNil(video.Details.Steps),
+		slicesx.NonNil(video.Details.Contraindications),
+		slicesx.NonNil(video.Details.Equipment),
+		video.Details.Sets,
+		video.Details.Reps,
+		video.Details.Difficulty,
 		video.ID,
 	)
 	if err != nil {
@@ -370,7 +423,19 @@ func (s *Storage) UpdateVideo(ctx context.Context, video *admin.Video) error {
 		}
 	}
 
-	// 6. Коммитим транзакцию
+	// 6. Обновляем теги и ссылки "смотреть далее"
+	if err = saveVideoTags(ctx, tx, video.ID, video.Tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = saveVideoNextUp(ctx, tx, video.ID, video.NextVideoIDs); err != nil {
+		if errors.Is(err, admin.ErrVideoNextUpNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 7. Коммитим транзакцию
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
 	}
@@ -400,6 +465,14 @@ func (s *Storage) DeleteVideo(ctx context.Context, id int64) error {
 		return fmt.Errorf("%s: failed to delete category relations: %w", op, err)
 	}
 
+	_, err = tx.Exec(ctx, `
+		DELETE FROM video_next_up
+		WHERE video_id = $1 OR next_video_id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete next up links: %w", op, err)
+	}
+
 	commandTag, err := tx.Exec(ctx, `
 		UPDATE videos
 		SET deleted = TRUE
@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// GetVideoComments возвращает комментарии с указанным статусом. Очередь на модерации
+// отдается от старых к новым, остальные комментарии — от новых к старым.
+func (s *Storage) GetVideoComments(ctx context.Context, status string) ([]admin.VideoComment, error) {
+	const op = "storage.postgres.GetVideoComments"
+
+	order := "c.created_at DESC, c.id DESC"
+	if status == admin.CommentStatusPending {
+		order = "c.created_at, c.id"
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT c.id, c.video_id, v.name, a.username,
+		       COALESCE(c.content_type_id, 0), COALESCE(ct.name, ''),
+		       c.text, c.status, c.moderated_by, c.moderated_at, c.created_at
+		FROM video_comments c
+		JOIN videos v ON v.id = c.video_id
+		JOIN accounts a ON a.id = c.account_id
+		LEFT JOIN content_types ct ON ct.id = c.content_type_id
+		WHERE c.status = $1 AND v.deleted IS NOT TRUE AND a.deleted IS NOT TRUE
+		ORDER BY `+order, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	comments := make([]admin.VideoComment, 0)
+	for rows.Next() {
+		var c admin.VideoComment
+		if err = rows.Scan(
+			&c.ID,
+			&c.VideoID,
+			&c.VideoName,
+			&c.Username,
+			&c.ContentTypeID,
+			&c.ContentTypeName,
+			&c.Text,
+			&c.Status,
+			&c.ModeratedBy,
+			&c.ModeratedAt,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		comments = append(comments, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return comments, nil
+}
+
+// UpdateVideoCommentStatus меняет статус комментария и запоминает, кто и когда его модерировал
+func (s *Storage) UpdateVideoCommentStatus(ctx context.Context, id int64, status, moderator string) error {
+	const op = "storage.postgres.UpdateVideoCommentStatus"
+
+	commandTag, err := s.db.Exec(ctx, `
+		UPDATE video_comments
+		SET status = $1, moderated_by = $2, moderated_at = NOW()
+		WHERE id = $3
+	`, status, moderator, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// loadVideoRating заполняет итог оценок видео в рамках транзакции
+func loadVideoRating(ctx context.Context, tx pgx.Tx, video *admin.Video) error {
+	rows, err := tx.Query(ctx, `
+		SELECT score, COUNT(*)
+		FROM video_ratings
+		WHERE video_id = $1
+		GROUP BY score
+	`, video.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get rating: %w", err)
+	}
+	defer rows.Close()
+
+	var total int
+	for rows.Next() {
+		var score, count int
+		if err = rows.Scan(&score, &count); err != nil {
+			return fmt.Errorf("failed to scan rating: %w", err)
+		}
+		if score < 1 || score > 5 {
+			continue
+		}
+
+		video.Rating.Distribution[score-1] = count
+		video.Rating.Count += count
+		total += score * count
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rating rows error: %w", err)
+	}
+
+	if video.Rating.Count > 0 {
+		video.Rating.Average = float64(total) / float64(video.Rating.Count)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+// saveVideoTags заменяет теги видео в рамках транзакции
+func saveVideoTags(ctx context.Context, tx pgx.Tx, videoID int64, tags []string) error {
+	_, err := tx.Exec(ctx, `
+		DELETE FROM video_tags
+		WHERE video_id = $1
+	`, videoID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO video_tags (video_id, tag)
+			VALUES ($1, $2)
+			ON CONFLICT (video_id, tag) DO NOTHING
+		`, videoID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to add tag %q: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// saveVideoNextUp заменяет ссылки "смотреть далее" в рамках транзакции
+func saveVideoNextUp(ctx context.Context, tx pgx.Tx, videoID int64, nextIDs []int64) error {
+	_, err := tx.Exec(ctx, `
+		DELETE FROM video_next_up
+		WHERE video_id = $1
+	`, videoID)
+	if err != nil {
+		return fmt.Errorf("failed to delete next up links: %w", err)
+	}
+
+	for position, nextID := range nextIDs {
+		commandTag, err := tx.Exec(ctx, `
+			INSERT INTO video_next_up (video_id, next_video_id, position)
+			SELECT $1, id, $3
+			FROM videos
+			WHERE id = $2 AND deleted IS NOT TRUE
+			ON CONFLICT (video_id, next_video_id) DO NOTHING
+		`, videoID, nextID, position)
+		if err != nil {
+			return fmt.Errorf("failed to add next up video %d: %w", nextID, err)
+		}
+
+		if commandTag.RowsAffected() == 0 {
+			return admin.ErrVideoNextUpNotFound
+		}
+	}
+
+	return nil
+}
+
+// loadVideoLinks заполняет теги и ссылки "смотреть далее" для переданных видео
+func loadVideoLinks(ctx context.Context, tx pgx.Tx, videoMap map[int64]*admin.Video) error {
+	videoIDs := make([]int64, 0, len(videoMap))
+	for id, video := range videoMap {
+		video.Tags = make([]string, 0)
+		video.NextVideoIDs = make([]int64, 0)
+		videoIDs = append(videoIDs, id)
+	}
+
+	tagRows, err := tx.Query(ctx, `
+		SELECT video_id, tag
+		FROM video_tags
+		WHERE video_id = ANY($1)
+		ORDER BY video_id, tag
+	`, videoIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var videoID int64
+		var tag string
+
+		if err = tagRows.Scan(&videoID, &tag); err != nil {
+			return fmt.Errorf("failed to scan tag: %w", err)
+		}
+
+		if video, exists := videoMap[videoID]; exists {
+			video.Tags = append(video.Tags, tag)
+		}
+	}
+
+	if err = tagRows.Err(); err != nil {
+		return fmt.Errorf("tag rows error: %w", err)
+	}
+
+	nextRows, err := tx.Query(ctx, `
+		SELECT n.video_id, n.next_video_id
+		FROM video_next_up n
+		INNER JOIN videos v ON v.id = n.next_video_id
+		WHERE n.video_id = ANY($1) AND v.deleted IS NOT TRUE
+		ORDER BY n.video_id, n.position
+	`, videoIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query next up links: %w", err)
+	}
+	defer nextRows.Close()
+
+	for nextRows.Next() {
+		var videoID, nextID int64
+
+		if err = nextRows.Scan(&videoID, &nextID); err != nil {
+			return fmt.Errorf("failed to scan next up link: %w", err)
+		}
+
+		if video, exists := videoMap[videoID]; exists {
+			video.NextVideoIDs = append(video.NextVideoIDs, nextID)
+		}
+	}
+
+	if err = nextRows.Err(); err != nil {
+		return fmt.Errorf("next up rows error: %w", err)
+	}
+
+	return nil
+}
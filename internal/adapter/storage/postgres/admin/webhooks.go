@@ -0,0 +1,296 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+const webhookColumns = `id, url, secret, events, description, active, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*admin.Webhook, error) {
+	var webhook admin.Webhook
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.Events,
+		&webhook.Description,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// AddWebhook добавляет подписку на события
+func (s *Storage) AddWebhook(ctx context.Context, req *admin.Webhook) (*admin.Webhook, error) {
+	const op = "storage.postgres.AddWebhook"
+
+	webhook, err := scanWebhook(s.db.QueryRow(ctx, `
+		INSERT INTO webhooks (url, secret, events, description, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+webhookColumns,
+		req.URL, req.Secret, req.Events, req.Description, req.Active,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+// GetWebhook возвращает подписку по ID
+func (s *Storage) GetWebhook(ctx context.Context, id int64) (*admin.Webhook, error) {
+	const op = "storage.postgres.GetWebhook"
+
+	webhook, err := scanWebhook(s.db.QueryRow(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, admin.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks возвращает все подписки
+func (s *Storage) GetWebhooks(ctx context.Context) ([]admin.Webhook, error) {
+	const op = "storage.postgres.GetWebhooks"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]admin.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook обновляет подписку
+func (s *Storage) UpdateWebhook(ctx context.Context, req *admin.Webhook) error {
+	const op = "storage.postgres.UpdateWebhook"
+
+	commandTag, err := s.db.Exec(ctx, `
+		UPDATE webhooks
+		SET url = $1, secret = $2, events = $3, description = $4, active = $5, updated_at = NOW()
+		WHERE id = $6
+	`, req.URL, req.Secret, req.Events, req.Description, req.Active, req.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставки
+func (s *Storage) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeleteWebhook"
+
+	commandTag, err := s.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return admin.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueWebhookEvent создает доставки события для всех активных подписок на него
+func (s *Storage) EnqueueWebhookEvent(ctx context.Context, event string, payload []byte) (int64, error) {
+	const op = "storage.postgres.EnqueueWebhookEvent"
+
+	commandTag, err := s.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $1::text, $2::jsonb
+		FROM webhooks
+		WHERE active AND (cardinality(events) = 0 OR $1::text = ANY(events))
+	`, event, payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// ClaimWebhookDeliveries выбирает доставки, время отправки которых наступило, и откладывает их на lease,
+// чтобы другой экземпляр сервиса не отправил их одновременно
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]admin.WebhookDelivery, error) {
+	const op = "storage.postgres.ClaimWebhookDeliveries"
+
+	rows, err := s.db.Query(ctx, `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + $2::bigint * INTERVAL '1 millisecond'
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.attempts, w.url, w.secret
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]admin.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery admin.WebhookDelivery
+		if err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&delivery.Payload,
+			&delivery.Attempts,
+			&delivery.URL,
+			&delivery.Secret,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// SaveWebhookAttempt сохраняет результат попытки отправки
+func (s *Storage) SaveWebhookAttempt(ctx context.Context, id int64, attempt *admin.WebhookAttempt) error {
+	const op = "storage.postgres.SaveWebhookAttempt"
+
+	_, err := s.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+		    status = $1,
+		    last_status_code = $2,
+		    last_error = $3,
+		    next_attempt_at = $4,
+		    delivered_at = CASE WHEN $1 = 'success' THEN NOW() END
+		WHERE id = $5
+	`, attempt.Status, attempt.StatusCode, attempt.Error, attempt.NextAttemptAt, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries возвращает последние доставки подписки, новые первыми
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, webhookID int64, limit int) ([]admin.WebhookDelivery, error) {
+	const op = "storage.postgres.GetWebhookDeliveries"
+
+	var exists bool
+	if err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1)`, webhookID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, admin.ErrWebhookNotFound
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, webhook_id, event, payload, status, attempts, last_status_code, last_error,
+		       next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]admin.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery admin.WebhookDelivery
+		if err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhook создает новую доставку с тем же событием и телом. Исходная запись остается в журнале.
+func (s *Storage) RedeliverWebhook(ctx context.Context, deliveryID int64) (int64, error) {
+	const op = "storage.postgres.RedeliverWebhook"
+
+	var id int64
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT webhook_id, event, payload
+		FROM webhook_deliveries
+		WHERE id = $1
+		RETURNING id
+	`, deliveryID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, admin.ErrWebhookDeliveryNotFound
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// GetAnnouncements возвращает объявления для типа контента, которые еще не закончились,
+// включая запланированные. Объявления без аудитории показываются всем типам.
+func (s *Storage) GetAnnouncements(ctx context.Context, typeID int64) ([]api.Announcement, error) {
+	const op = "storage.postgres.GetAnnouncements"
+
+	err := s.chekType(ctx, typeID, op)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT a.id, a.title, a.body, a.img_url, a.starts_at, a.ends_at
+		FROM announcements a
+		WHERE (a.ends_at IS NULL OR a.ends_at > NOW())
+		  AND (
+		      NOT EXISTS (SELECT 1 FROM announcement_content_types act WHERE act.announcement_id = a.id)
+		      OR EXISTS (
+		          SELECT 1
+		          FROM announcement_content_types act
+		          WHERE act.announcement_id = a.id AND act.content_type_id = $1
+		      )
+		  )
+		ORDER BY a.starts_at DESC, a.id DESC
+	`, typeID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	announcements := make([]api.Announcement, 0)
+	for rows.Next() {
+		var a api.Announcement
+		if err = rows.Scan(&a.ID, &a.Title, &a.Body, &a.ImgURL, &a.StartsAt, &a.EndsAt); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		a.ImgURL = s.constructFullImgURL(a.ImgURL)
+
+		announcements = append(announcements, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return announcements, nil
+}
@@ -52,6 +52,29 @@ func (s *Storage) CheckAccount(ctx context.Context, account *api.Account) (*api.
 	return account, nil
 }
 
+// CheckAccountPassword возвращает тип контента аккаунта, если пароль совпадает с заданным администратором.
+// Аккаунт без пароля, как и неверный пароль, возвращает ErrAccountNotFound.
+func (s *Storage) CheckAccountPassword(ctx context.Context, account *api.Account, passwordHash string) (*api.Account, error) {
+	const op = "storage.postgres.CheckAccountPassword"
+
+	query := `
+        SELECT a.content_type_id, ct.name
+        FROM accounts a
+        JOIN content_types ct ON a.content_type_id = ct.id
+        WHERE a.username = $1 AND a.password = $2 AND a.deleted IS NOT TRUE
+    `
+
+	err := s.db.QueryRow(ctx, query, account.Username, passwordHash).Scan(&account.ContentType.ID, &account.ContentType.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrAccountNotFound)
+		}
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+
+	return account, nil
+}
+
 // GetCategories возвращает все категории для указанного типа контента
 func (s *Storage) GetCategories(ctx context.Context, TypeID int64) ([]api.Category, error) {
 	const op = "storage.postgres.GetCategories"
@@ -62,7 +85,12 @@ func (s *Storage) GetCategories(ctx context.Context, TypeID int64) ([]api.Catego
 	}
 
 	query := `
-        SELECT c.id, c.name, c.img_url
+        SELECT c.id, c.name, c.img_url,
+            CASE WHEN EXISTS (
+                SELECT 1
+                FROM category_content_types pct
+                WHERE pct.category_id = c.parent_id AND pct.content_type_id = ct.id
+            ) THEN c.parent_id ELSE 0 END
         FROM categories c
         JOIN category_content_types cct ON c.id = cct.category_id
         JOIN content_types ct ON cct.content_type_id = ct.id
@@ -80,7 +108,7 @@ func (s *Storage) GetCategories(ctx context.Context, TypeID int64) ([]api.Catego
 
 	for rows.Next() {
 		var category api.Category
-		if err := rows.Scan(&category.ID, &category.Name, &category.ImgURL); err != nil {
+		if err := rows.Scan(&category.ID, &category.Name, &category.ImgURL, &category.ParentID); err != nil {
 			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
 		}
 		category.ImgURL = s.constructFullImgURL(category.ImgURL)
@@ -97,6 +125,20 @@ func (s *Storage) GetCategories(ctx context.Context, TypeID int64) ([]api.Catego
 			op, storage.ErrNoCategoriesFound, TypeID)
 	}
 
+	categoryIDs := make([]int64, len(categories))
+	for i := range categories {
+		categoryIDs[i] = categories[i].ID
+	}
+
+	documents, err := s.getCategoryDocuments(ctx, categoryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range categories {
+		categories[i].Documents = documents[categories[i].ID]
+	}
+
 	return categories, nil
 }
 
@@ -104,7 +146,8 @@ func (s *Storage) GetVideo(ctx context.Context, videoID int64) (*api.Video, erro
 	const op = "storage.postgres.GetVideo"
 
 	query := `
-        SELECT v.id, v.url, v.name, v.description, c.name as category, v.img_url
+        SELECT v.id, v.url, v.name, v.description, c.name as category, v.img_url,
+               v.steps, v.contraindications, v.equipment, v.sets, v.reps, v.difficulty
         FROM videos v
         JOIN video_categories vc ON v.id = vc.video_id
         JOIN categories c ON vc.category_id = c.id
@@ -121,6 +164,12 @@ func (s *Storage) GetVideo(ctx context.Context, videoID int64) (*api.Video, erro
 		&video.Description,
 		&video.Category.Name,
 		&video.ImgURL,
+		&video.Details.Steps,
+		&video.Details.Contraindications,
+		&video.Details.Equipment,
+		&video.Details.Sets,
+		&video.Details.Reps,
+		&video.Details.Difficulty,
 	)
 
 	if err != nil {
@@ -135,9 +184,121 @@ func (s *Storage) GetVideo(ctx context.Context, videoID int64) (*api.Video, erro
 
 	video.ImgURL = s.constructFullImgURL(video.ImgURL)
 
+	documents, err := s.getVideoDocuments(ctx, []int64{video.ID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	video.Documents = documents[video.ID]
+
+	subtitles, err := s.getVideoSubtitles(ctx, []int64{video.ID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	video.Subtitles = subtitles[video.ID]
+
 	return &video, nil
 }
 
+// GetRelatedCandidates возвращает видео, которые можно показать после просмотра videoID: ссылки
+// "смотреть далее", заданные администратором, и видео того же типа контента с общими категориями или тегами.
+// Если typeID равен 0, учитываются все типы контента исходного видео. Порядок выдачи определяет сервис.
+func (s *Storage) GetRelatedCandidates(ctx context.Context, videoID, typeID int64) ([]api.RelatedCandidate, error) {
+	const op = "storage.postgres.GetRelatedCandidates"
+
+	var videoExists bool
+	err := s.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM videos WHERE id = $1 AND deleted IS NOT TRUE)`,
+		videoID,
+	).Scan(&videoExists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: video check failed: %w", op, err)
+	}
+
+	if !videoExists {
+		return nil, fmt.Errorf("%s: %w: video with id '%d' not found",
+			op, storage.ErrVideoNotFound, videoID)
+	}
+
+	if typeID != 0 {
+		if err = s.chekType(ctx, typeID, op); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+        WITH src_categories AS (
+            SELECT vc.category_id
+            FROM video_categories vc
+            JOIN categories c ON c.id = vc.category_id
+            WHERE vc.video_id = $1 AND c.deleted IS NOT TRUE
+        ),
+        src_tags AS (
+            SELECT tag FROM video_tags WHERE video_id = $1
+        ),
+        src_types AS (
+            SELECT DISTINCT cct.content_type_id
+            FROM category_content_types cct
+            WHERE cct.category_id IN (SELECT category_id FROM src_categories)
+              AND ($2::bigint = 0 OR cct.content_type_id = $2)
+        ),
+        candidates AS (
+            SELECT v.id, v.url, v.name, v.description, v.img_url, v.created_at,
+                (SELECT n.position FROM video_next_up n
+                 WHERE n.video_id = $1 AND n.next_video_id = v.id) AS next_position,
+                (SELECT COUNT(*) FROM video_categories vc
+                 WHERE vc.video_id = v.id AND vc.category_id IN (SELECT category_id FROM src_categories)) AS shared_categories,
+                (SELECT COUNT(*) FROM video_tags vt
+                 WHERE vt.video_id = v.id AND vt.tag IN (SELECT tag FROM src_tags)) AS shared_tags,
+                (SELECT c.name FROM video_categories vc
+                 JOIN categories c ON c.id = vc.category_id
+                 WHERE vc.video_id = v.id AND c.deleted IS NOT TRUE
+                 ORDER BY vc.category_id IN (SELECT category_id FROM src_categories) DESC, c.name
+                 LIMIT 1) AS category
+            FROM videos v
+            WHERE v.id <> $1 AND v.deleted IS NOT TRUE
+              AND EXISTS (
+                  SELECT 1
+                  FROM video_categories vc
+                  JOIN categories c ON c.id = vc.category_id
+                  JOIN category_content_types cct ON cct.category_id = vc.category_id
+                  WHERE vc.video_id = v.id AND c.deleted IS NOT TRUE
+                    AND cct.content_type_id IN (SELECT content_type_id FROM src_types)
+              )
+        )
+        SELECT id, url, name, description, COALESCE(category, ''), img_url,
+               COALESCE(next_position, -1), shared_categories, shared_tags, created_at
+        FROM candidates
+        WHERE next_position IS NOT NULL OR shared_categories > 0 OR shared_tags > 0
+        ORDER BY id
+    `
+
+	rows, err := s.db.Query(ctx, query, videoID, typeID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	candidates := make([]api.RelatedCandidate, 0)
+	for rows.Next() {
+		var c api.RelatedCandidate
+		v := &c.Video
+		err = rows.Scan(&v.ID, &v.URL, &v.Name, &v.Description, &v.Category.Name, &v.ImgURL,
+			&c.NextUpPosition, &c.SharedCategories, &c.SharedTags, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		v.URL = s.constructFullMediaURL(v.URL)
+		v.ImgURL = s.constructFullImgURL(v.ImgURL)
+		candidates = append(candidates, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return candidates, nil
+}
+
 func (s *Storage) GetVideosByCategoryAndType(ctx context.Context, TypeID, CatID int64) ([]api.Video, error) {
 	const op = "storage.postgres.GetVideosByCategoryAndType"
 
@@ -188,6 +349,20 @@ func (s *Storage) GetVideosByCategoryAndType(ctx context.Context, TypeID, CatID
 			op, storage.ErrVideoNotFound, TypeID, CatID)
 	}
 
+	videoIDs := make([]int64, len(videos))
+	for i := range videos {
+		videoIDs[i] = videos[i].ID
+	}
+
+	subtitles, err := s.getVideoSubtitles(ctx, videoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range videos {
+		videos[i].Subtitles = subtitles[videos[i].ID]
+	}
+
 	return videos, nil
 }
 
@@ -200,13 +375,12 @@ func (s *Storage) Feedback(ctx context.Context, feedback *api.Feedback) error {
 		RETURNING id
 	`
 
-	var id int
 	err := s.db.QueryRow(ctx, query,
 		feedback.Name,
 		feedback.Email,
 		feedback.Telegram,
 		feedback.Message,
-	).Scan(&id)
+	).Scan(&feedback.ID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
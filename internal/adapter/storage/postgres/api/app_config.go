@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// GetAppConfig возвращает настройки мобильного приложения
+func (s *Storage) GetAppConfig(ctx context.Context) (*api.AppConfig, error) {
+	const op = "storage.postgres.GetAppConfig"
+
+	var cfg api.AppConfig
+	err := s.db.QueryRow(ctx, `
+		SELECT min_version, latest_version, force_update, support_email, support_telegram, support_phone, features
+		FROM app_config
+		WHERE id
+	`).Scan(
+		&cfg.MinVersion,
+		&cfg.LatestVersion,
+		&cfg.ForceUpdate,
+		&cfg.SupportEmail,
+		&cfg.SupportTelegram,
+		&cfg.SupportPhone,
+		&cfg.Features,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+
+	return &cfg, nil
+}
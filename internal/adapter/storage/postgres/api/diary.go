@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// AddDiaryEntry сохраняет запись дневника вместе с текущим типом контента пациента и выполненными видео.
+// Удаленные видео считаются не найденными.
+func (s *Storage) AddDiaryEntry(ctx context.Context, entry *api.DiaryEntry) error {
+	const op = "storage.postgres.AddDiaryEntry"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO diary_entries (account_id, content_type_id, entry_date, pain_score, body_area, notes)
+		SELECT a.id, a.content_type_id, $2, $3, $4, $5
+		FROM accounts a
+		WHERE a.username = $1 AND a.deleted IS NOT TRUE
+		RETURNING id, created_at
+	`, entry.Username, entry.Date, entry.PainScore, entry.BodyArea, entry.Notes).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrAccountNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, videoID := range entry.VideoIDs {
+		commandTag, err := tx.Exec(ctx, `
+			INSERT INTO diary_entry_videos (entry_id, video_id)
+			SELECT $1, id
+			FROM videos
+			WHERE id = $2 AND deleted IS NOT TRUE
+		`, entry.ID, videoID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to attach video %d: %w", op, videoID, err)
+		}
+
+		if commandTag.RowsAffected() == 0 {
+			return fmt.Errorf("%s: %w: video %d", op, storage.ErrVideoNotFound, videoID)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetDiaryEntries возвращает записи дневника пациента за период, новые сначала. Нулевая дата не ограничивает период.
+func (s *Storage) GetDiaryEntries(ctx context.Context, username string, from, to time.Time) ([]api.DiaryEntry, error) {
+	const op = "storage.postgres.GetDiaryEntries"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT d.id, d.entry_date, d.pain_score, d.body_area, d.notes, d.created_at,
+		       COALESCE((
+		           SELECT array_agg(dv.video_id ORDER BY dv.video_id)
+		           FROM diary_entry_videos dv
+		           JOIN videos v ON v.id = dv.video_id
+		           WHERE dv.entry_id = d.id AND v.deleted IS NOT TRUE
+		       ), '{}')
+		FROM diary_entries d
+		JOIN accounts a ON a.id = d.account_id
+		WHERE a.username = $1 AND a.deleted IS NOT TRUE
+		  AND ($2::date IS NULL OR d.entry_date >= $2)
+		  AND ($3::date IS NULL OR d.entry_date <= $3)
+		ORDER BY d.entry_date DESC, d.created_at DESC, d.id DESC
+	`, username, nullDate(from), nullDate(to))
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := make([]api.DiaryEntry, 0)
+	for rows.Next() {
+		e := api.DiaryEntry{Username: username}
+		if err = rows.Scan(&e.ID, &e.Date, &e.PainScore, &e.BodyArea, &e.Notes, &e.CreatedAt, &e.VideoIDs); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// DeleteDiaryEntry удаляет запись дневника, принадлежащую пациенту
+func (s *Storage) DeleteDiaryEntry(ctx context.Context, username string, id int64) error {
+	const op = "storage.postgres.DeleteDiaryEntry"
+
+	commandTag, err := s.db.Exec(ctx, `
+		DELETE FROM diary_entries d
+		USING accounts a
+		WHERE d.id = $1 AND a.id = d.account_id AND a.username = $2
+	`, id, username)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return api.ErrDiaryEntryNotFound
+	}
+
+	return nil
+}
+
+// nullDate превращает нулевую дату в NULL для необязательных границ периода
+func nullDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
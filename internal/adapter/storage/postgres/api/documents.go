@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// getVideoDocuments возвращает документы, прикрепленные к видео, сгруппированные по ID видео
+func (s *Storage) getVideoDocuments(ctx context.Context, videoIDs []int64) (map[int64][]api.Document, error) {
+	return s.queryDocuments(ctx, `
+		SELECT vd.video_id, d.id, d.title, d.url
+		FROM video_documents vd
+		JOIN documents d ON d.id = vd.document_id
+		WHERE vd.video_id = ANY($1)
+		ORDER BY vd.video_id, d.title, d.id
+	`, videoIDs)
+}
+
+// getCategoryDocuments возвращает документы, прикрепленные к категориям, сгруппированные по ID категории
+func (s *Storage) getCategoryDocuments(ctx context.Context, categoryIDs []int64) (map[int64][]api.Document, error) {
+	return s.queryDocuments(ctx, `
+		SELECT cd.category_id, d.id, d.title, d.url
+		FROM category_documents cd
+		JOIN documents d ON d.id = cd.document_id
+		WHERE cd.category_id = ANY($1)
+		ORDER BY cd.category_id, d.title, d.id
+	`, categoryIDs)
+}
+
+func (s *Storage) queryDocuments(ctx context.Context, query string, ids []int64) (map[int64][]api.Document, error) {
+	rows, err := s.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("documents query failed: %w", err)
+	}
+	defer rows.Close()
+
+	documents := make(map[int64][]api.Document)
+	for rows.Next() {
+		var ownerID int64
+		var document api.Document
+
+		if err = rows.Scan(&ownerID, &document.ID, &document.Title, &document.URL); err != nil {
+			return nil, fmt.Errorf("documents scan failed: %w", err)
+		}
+
+		document.URL = s.constructFullDocURL(document.URL)
+		documents[ownerID] = append(documents[ownerID], document)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("documents rows error: %w", err)
+	}
+
+	return documents, nil
+}
+
+func (s *Storage) constructFullDocURL(relativePath string) string {
+	if relativePath == "" {
+		return ""
+	}
+
+	baseURL := strings.TrimRight(s.cfg.Media.BaseURL, "/")
+	docPath := strings.TrimLeft(relativePath, "/")
+
+	return fmt.Sprintf("%s/docs/%s", baseURL, docPath)
+}
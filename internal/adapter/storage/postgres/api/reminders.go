@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// GetReminders возвращает напоминания пациента
+func (s *Storage) GetReminders(ctx context.Context, username string) ([]api.Reminder, error) {
+	const op = "storage.postgres.GetReminders"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT r.id, r.days, r.local_time, r.timezone, r.message, r.enabled, r.next_run_at, r.created_at, r.updated_at
+		FROM reminders r
+		JOIN accounts a ON a.id = r.account_id
+		WHERE a.username = $1 AND a.deleted IS NOT TRUE
+		ORDER BY r.local_time, r.id
+	`, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	reminders := make([]api.Reminder, 0)
+	for rows.Next() {
+		r := api.Reminder{Username: username}
+		if err = rows.Scan(&r.ID, &r.Days, &r.Time, &r.Timezone, &r.Message, &r.Enabled, &r.NextRunAt, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		reminders = append(reminders, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return reminders, nil
+}
+
+// AddReminder сохраняет напоминание, если у пациента их меньше maxPerAccount
+func (s *Storage) AddReminder(ctx context.Context, reminder *api.Reminder, maxPerAccount int) error {
+	const op = "storage.postgres.AddReminder"
+
+	rows, err := s.db.Query(ctx, `
+		INSERT INTO reminders (account_id, days, local_time, timezone, message, enabled, next_run_at)
+		SELECT a.id, $2, $3, $4, $5, $6, $7
+		FROM accounts a
+		WHERE a.username = $1 AND a.deleted IS NOT TRUE
+		  AND (SELECT COUNT(*) FROM reminders r WHERE r.account_id = a.id) < $8
+		RETURNING id, created_at, updated_at
+	`, reminder.Username, reminder.Days, reminder.Time, reminder.Timezone, reminder.Message, reminder.Enabled,
+		reminder.NextRunAt, maxPerAccount)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return api.ErrTooManyReminders
+	}
+
+	if err = rows.Scan(&reminder.ID, &reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: scan failed: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateReminder заменяет расписание напоминания пациента
+func (s *Storage) UpdateReminder(ctx context.Context, reminder *api.Reminder) error {
+	const op = "storage.postgres.UpdateReminder"
+
+	rows, err := s.db.Query(ctx, `
+		UPDATE reminders r
+		SET days = $3, local_time = $4, timezone = $5, message = $6, enabled = $7, next_run_at = $8, updated_at = NOW()
+		FROM accounts a
+		WHERE r.id = $1 AND a.id = r.account_id AND a.username = $2
+		RETURNING r.created_at, r.updated_at
+	`, reminder.ID, reminder.Username, reminder.Days, reminder.Time, reminder.Timezone, reminder.Message,
+		reminder.Enabled, reminder.NextRunAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return api.ErrReminderNotFound
+	}
+
+	if err = rows.Scan(&reminder.CreatedAt, &reminder.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: scan failed: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteReminder удаляет напоминание пациента
+func (s *Storage) DeleteReminder(ctx context.Context, username string, id int64) error {
+	const op = "storage.postgres.DeleteReminder"
+
+	commandTag, err := s.db.Exec(ctx, `
+		DELETE FROM reminders r
+		USING accounts a
+		WHERE r.id = $1 AND a.id = r.account_id AND a.username = $2
+	`, id, username)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return api.ErrReminderNotFound
+	}
+
+	return nil
+}
+
+// ClaimDueReminders выбирает включенные напоминания, время которых наступило, и откладывает их на lease,
+// чтобы другой экземпляр сервиса не отправил их одновременно
+func (s *Storage) ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]api.DueReminder, error) {
+	const op = "storage.postgres.ClaimDueReminders"
+
+	rows, err := s.db.Query(ctx, `
+		WITH due AS (
+			SELECT r.id, r.next_run_at
+			FROM reminders r
+			JOIN accounts a ON a.id = r.account_id
+			WHERE r.enabled AND r.next_run_at <= NOW() AND a.deleted IS NOT TRUE
+			ORDER BY r.next_run_at
+			LIMIT $1
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE reminders r
+		SET next_run_at = NOW() + $2::bigint * INTERVAL '1 millisecond'
+		FROM due, accounts a
+		WHERE r.id = due.id AND a.id = r.account_id
+		RETURNING r.id, a.username, r.days, r.local_time, r.timezone, r.message, due.next_run_at, r.next_run_at,
+		          COALESCE((
+		              SELECT array_agg(t.token ORDER BY t.token)
+		              FROM device_tokens t
+		              WHERE t.account_id = r.account_id
+		          ), '{}')
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	reminders := make([]api.DueReminder, 0)
+	for rows.Next() {
+		r := api.DueReminder{Reminder: api.Reminder{Enabled: true}}
+		if err = rows.Scan(
+			&r.ID,
+			&r.Username,
+			&r.Days,
+			&r.Time,
+			&r.Timezone,
+			&r.Message,
+			&r.DueAt,
+			&r.LeaseUntil,
+			&r.Tokens,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reminders = append(reminders, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reminders, nil
+}
+
+// ExtendReminderLease продлевает скрытие напоминания от других экземпляров сервиса на lease и возвращает
+// новое время окончания. Продлевается только аренда leasedUntil: если пациент изменил напоминание или его забрал
+// другой экземпляр, next_run_at уже другой и возвращается api.ErrReminderLeaseLost.
+func (s *Storage) ExtendReminderLease(ctx context.Context, id int64, leasedUntil time.Time, lease time.Duration) (time.Time, error) {
+	const op = "storage.postgres.ExtendReminderLease"
+
+	var until time.Time
+	err := s.db.QueryRow(ctx, `
+		UPDATE reminders
+		SET next_run_at = NOW() + $3::bigint * INTERVAL '1 millisecond'
+		WHERE id = $1 AND enabled AND next_run_at = $2
+		RETURNING next_run_at
+	`, id, leasedUntil, lease.Milliseconds()).Scan(&until)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, api.ErrReminderLeaseLost
+		}
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return until, nil
+}
+
+// RescheduleReminder сохраняет следующее срабатывание напоминания. nil nextRunAt выключает напоминание,
+// nil sentAt оставляет время последней отправки без изменений. Расписание меняется, только пока next_run_at
+// равен аренде leasedUntil: изменение, которое пациент сделал во время отправки, не перезаписывается.
+func (s *Storage) RescheduleReminder(ctx context.Context, id int64, leasedUntil time.Time, nextRunAt, sentAt *time.Time) error {
+	const op = "storage.postgres.RescheduleReminder"
+
+	_, err := s.db.Exec(ctx, `
+		UPDATE reminders
+		SET next_run_at = CASE WHEN next_run_at = $2 THEN $3 ELSE next_run_at END,
+		    enabled = CASE WHEN next_run_at = $2 THEN enabled AND $3::timestamptz IS NOT NULL ELSE enabled END,
+		    last_sent_at = COALESCE($4, last_sent_at)
+		WHERE id = $1
+	`, id, leasedUntil, nextRunAt, sentAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveDevice регистрирует токен устройства пациента. Токен, зарегистрированный другим аккаунтом, переходит к пациенту.
+func (s *Storage) SaveDevice(ctx context.Context, device *api.Device) error {
+	const op = "storage.postgres.SaveDevice"
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO device_tokens (token, account_id, platform)
+		SELECT $1, a.id, $3
+		FROM accounts a
+		WHERE a.username = $2 AND a.deleted IS NOT TRUE
+		ON CONFLICT (token) DO UPDATE
+		SET account_id = EXCLUDED.account_id, platform = EXCLUDED.platform, updated_at = NOW()
+	`, device.Token, device.Username, device.Platform)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteDevice удаляет токен устройства пациента
+func (s *Storage) DeleteDevice(ctx context.Context, username, token string) error {
+	const op = "storage.postgres.DeleteDevice"
+
+	commandTag, err := s.db.Exec(ctx, `
+		DELETE FROM device_tokens t
+		USING accounts a
+		WHERE t.token = $1 AND a.id = t.account_id AND a.username = $2
+	`, token, username)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return api.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+// DeleteDeviceToken удаляет токен, который push-сервис больше не принимает
+func (s *Storage) DeleteDeviceToken(ctx context.Context, token string) error {
+	const op = "storage.postgres.DeleteDeviceToken"
+
+	if _, err := s.db.Exec(ctx, `DELETE FROM device_tokens WHERE token = $1`, token); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// getVideoSubtitles возвращает дорожки субтитров видео, сгруппированные по ID видео
+func (s *Storage) getVideoSubtitles(ctx context.Context, videoIDs []int64) (map[int64][]api.Subtitle, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT video_id, language, label, url
+		FROM video_subtitles
+		WHERE video_id = ANY($1)
+		ORDER BY video_id, language
+	`, videoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("subtitles query failed: %w", err)
+	}
+	defer rows.Close()
+
+	subtitles := make(map[int64][]api.Subtitle)
+	for rows.Next() {
+		var videoID int64
+		var sub api.Subtitle
+
+		if err = rows.Scan(&videoID, &sub.Language, &sub.Label, &sub.URL); err != nil {
+			return nil, fmt.Errorf("subtitles scan failed: %w", err)
+		}
+
+		sub.URL = s.constructFullSubtitlesURL(sub.URL)
+		subtitles[videoID] = append(subtitles[videoID], sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("subtitles rows error: %w", err)
+	}
+
+	return subtitles, nil
+}
+
+func (s *Storage) constructFullSubtitlesURL(relativePath string) string {
+	if relativePath == "" {
+		return ""
+	}
+
+	baseURL := strings.TrimRight(s.cfg.Media.BaseURL, "/")
+	subtitlesPath := strings.TrimLeft(relativePath, "/")
+
+	return fmt.Sprintf("%s/subtitles/%s", baseURL, subtitlesPath)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// GetSyncChanges возвращает категории и видео типа контента, измененные после since.
+// Записи, которые удалены или перестали относиться к типу, попадают в списки удаленных.
+// Если since нулевой, возвращаются все доступные записи без списков удаленных.
+func (s *Storage) GetSyncChanges(ctx context.Context, typeID int64, since time.Time) (*api.SyncChanges, error) {
+	const op = "storage.postgres.GetSyncChanges"
+
+	err := s.chekType(ctx, typeID, op)
+	if err != nil {
+		return nil, err
+	}
+
+	// RepeatableRead гарантирует, что категории и видео соответствуют одному снимку данных
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	full := since.IsZero()
+
+	changes := &api.SyncChanges{
+		Full:              full,
+		Categories:        make([]api.Category, 0),
+		Videos:            make([]api.SyncVideo, 0),
+		DeletedCategories: make([]int64, 0),
+		DeletedVideos:     make([]int64, 0),
+	}
+
+	if err = tx.QueryRow(ctx, `SELECT clock_timestamp()`).Scan(&changes.Until); err != nil {
+		return nil, fmt.Errorf("%s: failed to get current time: %w", op, err)
+	}
+
+	categoryRows, err := tx.Query(ctx, `
+		SELECT c.id, c.name, COALESCE(c.img_url, ''),
+			CASE WHEN EXISTS (
+				SELECT 1
+				FROM category_content_types pct
+				WHERE pct.category_id = c.parent_id AND pct.content_type_id = $1
+			) THEN c.parent_id ELSE 0 END,
+			c.deleted IS NOT TRUE AND EXISTS (
+				SELECT 1
+				FROM category_content_types cct
+				WHERE cct.category_id = c.id AND cct.content_type_id = $1
+			) AS visible
+		FROM categories c
+		WHERE c.updated_at > $2
+		ORDER BY c.id
+	`, typeID, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: categories query failed: %w", op, err)
+	}
+	defer categoryRows.Close()
+
+	for categoryRows.Next() {
+		var category api.Category
+		var visible bool
+
+		if err = categoryRows.Scan(&category.ID, &category.Name, &category.ImgURL, &category.ParentID, &visible); err != nil {
+			return nil, fmt.Errorf("%s: category scan failed: %w", op, err)
+		}
+
+		switch {
+		case visible:
+			category.ImgURL = s.constructFullImgURL(category.ImgURL)
+			changes.Categories = append(changes.Categories, category)
+		case !full:
+			changes.DeletedCategories = append(changes.DeletedCategories, category.ID)
+		}
+	}
+
+	if err = categoryRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: category rows error: %w", op, err)
+	}
+
+	videoRows, err := tx.Query(ctx, `
+		SELECT v.id, v.url, v.name, COALESCE(v.description, ''), COALESCE(v.img_url, ''), v.deleted IS TRUE,
+			COALESCE(array_agg(c.id ORDER BY c.id) FILTER (WHERE c.id IS NOT NULL), '{}')
+		FROM videos v
+		LEFT JOIN video_categories vc ON vc.video_id = v.id
+		LEFT JOIN categories c ON c.id = vc.category_id
+			AND c.deleted IS NOT TRUE
+			AND EXISTS (
+				SELECT 1
+				FROM category_content_types cct
+				WHERE cct.category_id = c.id AND cct.content_type_id = $1
+			)
+		WHERE v.updated_at > $2
+		GROUP BY v.id
+		ORDER BY v.id
+	`, typeID, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: videos query failed: %w", op, err)
+	}
+	defer videoRows.Close()
+
+	for videoRows.Next() {
+		var video api.SyncVideo
+		var deleted bool
+
+		err = videoRows.Scan(
+			&video.ID,
+			&video.URL,
+			&video.Name,
+			&video.Description,
+			&video.ImgURL,
+			&deleted,
+			&video.CategoryIDs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: video scan failed: %w", op, err)
+		}
+
+		switch {
+		case !deleted && len(video.CategoryIDs) > 0:
+			video.URL = s.constructFullMediaURL(video.URL)
+			video.ImgURL = s.constructFullImgURL(video.ImgURL)
+			changes.Videos = append(changes.Videos, video)
+		case !full:
+			changes.DeletedVideos = append(changes.DeletedVideos, video.ID)
+		}
+	}
+
+	if err = videoRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: video rows error: %w", op, err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// videoAvailableToAccount условие, что видео $1 не удалено и входит в неудаленную категорию
+// типа контента аккаунта a. Используется в запросах, где аккаунт выбран под алиасом a.
+const videoAvailableToAccount = `
+	EXISTS (
+	    SELECT 1
+	    FROM videos v
+	    JOIN video_categories vc ON vc.video_id = v.id
+	    JOIN categories c ON c.id = vc.category_id
+	    JOIN category_content_types cct ON cct.category_id = c.id
+	    WHERE v.id = $1
+	      AND v.deleted IS NOT TRUE
+	      AND c.deleted IS NOT TRUE
+	      AND cct.content_type_id = a.content_type_id
+	)`
+
+// RateVideo сохраняет оценку видео от аккаунта, повторная оценка заменяет предыдущую.
+// Возвращает ErrVideoNotFound, если видео недоступно типу контента аккаунта.
+func (s *Storage) RateVideo(ctx context.Context, rating *api.VideoRating) error {
+	const op = "storage.postgres.RateVideo"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `
+		INSERT INTO video_ratings (video_id, account_id, score)
+		SELECT $1, a.id, $3
+		FROM accounts a
+		WHERE a.username = $2 AND a.deleted IS NOT TRUE AND `+videoAvailableToAccount+`
+		ON CONFLICT (video_id, account_id) DO UPDATE
+		SET score = EXCLUDED.score, updated_at = NOW()
+	`, rating.VideoID, rating.Username, rating.Score)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w: video %d", op, storage.ErrVideoNotFound, rating.VideoID)
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(AVG(score), 0)::float8, COUNT(*)
+		FROM video_ratings
+		WHERE video_id = $1
+	`, rating.VideoID).Scan(&rating.Average, &rating.Count)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get average: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddVideoComment сохраняет комментарий на модерацию вместе с текущим типом контента автора.
+// Возвращает ErrVideoNotFound, если видео недоступно типу контента аккаунта.
+func (s *Storage) AddVideoComment(ctx context.Context, comment *api.VideoComment) error {
+	const op = "storage.postgres.AddVideoComment"
+
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO video_comments (video_id, account_id, content_type_id, text)
+		SELECT $1, a.id, a.content_type_id, $3
+		FROM accounts a
+		WHERE a.username = $2 AND a.deleted IS NOT TRUE AND `+videoAvailableToAccount+`
+		RETURNING id, status, created_at
+	`, comment.VideoID, comment.Username, comment.Text).Scan(&comment.ID, &comment.Status, &comment.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w: video %d", op, storage.ErrVideoNotFound, comment.VideoID)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	comment.Own = true
+
+	return nil
+}
+
+// GetVideoComments возвращает одобренные комментарии к видео от пациентов того же типа контента,
+// что и у аккаунта username, и все комментарии самого аккаунта, от старых к новым
+func (s *Storage) GetVideoComments(ctx context.Context, videoID int64, username string) ([]api.VideoComment, error) {
+	const op = "storage.postgres.GetVideoComments"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT c.id, c.video_id, c.text, c.status, c.created_at, a.username = $2
+		FROM video_comments c
+		JOIN accounts a ON a.id = c.account_id
+		JOIN accounts me ON me.username = $2 AND me.deleted IS NOT TRUE
+		WHERE c.video_id = $1
+		  AND a.deleted IS NOT TRUE
+		  AND (
+		      c.account_id = me.id
+		      OR (c.status = 'approved' AND c.content_type_id = me.content_type_id)
+		  )
+		ORDER BY c.created_at, c.id
+	`, videoID, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query failed: %w", op, err)
+	}
+	defer rows.Close()
+
+	comments := make([]api.VideoComment, 0)
+	for rows.Next() {
+		var c api.VideoComment
+		if err = rows.Scan(&c.ID, &c.VideoID, &c.Text, &c.Status, &c.CreatedAt, &c.Own); err != nil {
+			return nil, fmt.Errorf("%s: scan failed: %w", op, err)
+		}
+		comments = append(comments, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return comments, nil
+}
@@ -201,6 +201,131 @@ func (s *Storage) SetVideosByCategoryAndType(ctx context.Context, typeID, catID
 	return nil
 }
 
+// GetRelatedVideos получает похожие видео из кэша redis
+func (s *Storage) GetRelatedVideos(ctx context.Context, videoID, typeID int64) ([]api.Video, error) {
+	const op = "storage.redis.GetRelatedVideos"
+
+	cacheKey := fmt.Sprintf("video:%d:related:%d", videoID, typeID)
+	data, err := s.rdb.Get(ctx, cacheKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: failed to get from redis: %w", op, err)
+	}
+
+	videos := make([]api.Video, 0)
+	if err := json.Unmarshal(data, &videos); err != nil {
+		return nil, fmt.Errorf("%s: failed to unmarshal videos: %w", op, err)
+	}
+
+	if len(videos) > 0 {
+		videos[0].DataSource = metrics.SourceRedis
+	}
+
+	return videos, nil
+}
+
+// SetRelatedVideos сохраняет похожие видео в кэш redis
+func (s *Storage) SetRelatedVideos(ctx context.Context, videoID, typeID int64, videos []api.Video) error {
+	const op = "storage.redis.SetRelatedVideos"
+
+	cacheKey := fmt.Sprintf("video:%d:related:%d", videoID, typeID)
+	data, err := json.Marshal(videos)
+	if err != nil {
+		return fmt.Errorf("%s: failed to marshal videos: %w", op, err)
+	}
+
+	if err := s.rdb.Set(ctx, cacheKey, data, s.cfg.Redis.CacheTTL).Err(); err != nil {
+		return fmt.Errorf("%s: failed to set redis key: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetAnnouncements получает объявления типа контента из кэша redis
+func (s *Storage) GetAnnouncements(ctx context.Context, typeID int64) ([]api.Announcement, error) {
+	const op = "storage.redis.GetAnnouncements"
+
+	cacheKey := fmt.Sprintf("announcements:%d", typeID)
+	data, err := s.rdb.Get(ctx, cacheKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: failed to get from redis: %w", op, err)
+	}
+
+	announcements := make([]api.Announcement, 0)
+	if err = json.Unmarshal(data, &announcements); err != nil {
+		return nil, fmt.Errorf("%s: failed to unmarshal announcements: %w", op, err)
+	}
+
+	if len(announcements) > 0 {
+		announcements[0].DataSource = metrics.SourceRedis
+	}
+
+	return announcements, nil
+}
+
+// SetAnnouncements сохраняет объявления типа контента в кэш redis
+func (s *Storage) SetAnnouncements(ctx context.Context, typeID int64, announcements []api.Announcement) error {
+	const op = "storage.redis.SetAnnouncements"
+
+	cacheKey := fmt.Sprintf("announcements:%d", typeID)
+	data, err := json.Marshal(announcements)
+	if err != nil {
+		return fmt.Errorf("%s: failed to marshal announcements: %w", op, err)
+	}
+
+	if err = s.rdb.Set(ctx, cacheKey, data, s.cfg.Redis.CacheTTL).Err(); err != nil {
+		return fmt.Errorf("%s: failed to set redis key: %w", op, err)
+	}
+
+	return nil
+}
+
+// appConfigCacheKey ключ настроек мобильного приложения
+const appConfigCacheKey = "app_config"
+
+// GetAppConfig получает настройки мобильного приложения из кэша redis
+func (s *Storage) GetAppConfig(ctx context.Context) (*api.AppConfig, error) {
+	const op = "storage.redis.GetAppConfig"
+
+	data, err := s.rdb.Get(ctx, appConfigCacheKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: failed to get from redis: %w", op, err)
+	}
+
+	var cfg api.AppConfig
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: failed to unmarshal app config: %w", op, err)
+	}
+
+	cfg.DataSource = metrics.SourceRedis
+
+	return &cfg, nil
+}
+
+// SetAppConfig сохраняет настройки мобильного приложения в кэш redis
+func (s *Storage) SetAppConfig(ctx context.Context, cfg *api.AppConfig) error {
+	const op = "storage.redis.SetAppConfig"
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("%s: failed to marshal app config: %w", op, err)
+	}
+
+	if err = s.rdb.Set(ctx, appConfigCacheKey, data, s.cfg.Redis.CacheTTL).Err(); err != nil {
+		return fmt.Errorf("%s: failed to set redis key: %w", op, err)
+	}
+
+	return nil
+}
+
 // InvalidateCacheByPattern удаляет все ключи из кэша redis, соответствующие указанному шаблону
 func (s *Storage) InvalidateCacheByPattern(ctx context.Context, pattern string) error {
 	const op = "storage.redis.InvalidateCacheByPattern"
@@ -242,6 +367,11 @@ func (s *Storage) InvalidateVideosCache(ctx context.Context) error {
 	return s.InvalidateCacheByPattern(ctx, "videos:*")
 }
 
+// InvalidateVideoCache удаляет кэш отдельных видео video:<id> вместе с похожими видео video:<id>:related:*
+func (s *Storage) InvalidateVideoCache(ctx context.Context) error {
+	return s.InvalidateCacheByPattern(ctx, "video:*")
+}
+
 // InvalidateCategoriesCache удаляет весь кэш категорий
 func (s *Storage) InvalidateCategoriesCache(ctx context.Context) error {
 	return s.InvalidateCacheByPattern(ctx, "categories:*")
@@ -252,6 +382,16 @@ func (s *Storage) InvalidateAccountsCache(ctx context.Context) error {
 	return s.InvalidateCacheByPattern(ctx, "account:*")
 }
 
+// InvalidateAnnouncementsCache удаляет весь кэш объявлений
+func (s *Storage) InvalidateAnnouncementsCache(ctx context.Context) error {
+	return s.InvalidateCacheByPattern(ctx, "announcements:*")
+}
+
+// InvalidateAppConfigCache удаляет настройки мобильного приложения из кэша
+func (s *Storage) InvalidateAppConfigCache(ctx context.Context) error {
+	return s.rdb.Del(ctx, appConfigCacheKey).Err()
+}
+
 func (s *Storage) HealthCheck(ctx context.Context) error {
 	const op = "storage.redis.HealthCheck"
 
@@ -0,0 +1,81 @@
+// Package webhook отправляет события каталога подписчикам, подписывая тело запроса HMAC-SHA256.
+//
+// Подпись передается в заголовке X-BodyBalance-Signature в виде "sha256=<hex>" и считается
+// от строки "<X-BodyBalance-Timestamp>.<тело запроса>" с секретом подписки.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+)
+
+const (
+	HeaderSignature = "X-BodyBalance-Signature"
+	HeaderTimestamp = "X-BodyBalance-Timestamp"
+	HeaderEvent     = "X-BodyBalance-Event"
+	HeaderDelivery  = "X-BodyBalance-Delivery"
+)
+
+// Sender отправляет доставки событий по HTTP
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender создает Sender с тайм-аутом одного запроса timeout
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+// Send отправляет доставку и возвращает HTTP статус ответа. Статус вне 2xx возвращается вместе с ошибкой.
+func (s *Sender) Send(ctx context.Context, delivery *admin.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Дочитываем тело, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает hex подпись HMAC-SHA256 строки "<timestamp>.<body>"
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
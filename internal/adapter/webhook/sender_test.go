@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_SignsPayload(t *testing.T) {
+	payload := []byte(`{"event":"video.created","data":{"id":1}}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, payload, body)
+
+		// Подписчик проверяет подпись тем же секретом
+		assert.Equal(t, "1700000000", r.Header.Get(HeaderTimestamp))
+		assert.Equal(t, "sha256="+Sign("secret", "1700000000", body), r.Header.Get(HeaderSignature))
+		assert.Equal(t, admin.EventVideoCreated, r.Header.Get(HeaderEvent))
+		assert.Equal(t, "42", r.Header.Get(HeaderDelivery))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewSender(time.Second)
+	s.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	status, err := s.Send(context.Background(), &admin.WebhookDelivery{
+		ID:      42,
+		Event:   admin.EventVideoCreated,
+		Payload: payload,
+		URL:     srv.URL,
+		Secret:  "secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+}
+
+func TestSender_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), &admin.WebhookDelivery{
+		ID:      1,
+		Payload: []byte(`{}`),
+		URL:     srv.URL,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, status)
+}
+
+func TestSign(t *testing.T) {
+	// Подпись зависит и от времени, и от тела
+	assert.NotEqual(t, Sign("secret", "1", []byte("a")), Sign("secret", "2", []byte("a")))
+	assert.NotEqual(t, Sign("secret", "1", []byte("a")), Sign("secret", "1", []byte("b")))
+	assert.Len(t, Sign("secret", "1", []byte("a")), 64)
+}
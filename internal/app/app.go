@@ -7,12 +7,18 @@ import (
 	"os"
 
 	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/adapter/notifier"
+	"github.com/langowen/bodybalance-backend/internal/adapter/push"
 	"github.com/langowen/bodybalance-backend/internal/adapter/storage/postgres"
 	"github.com/langowen/bodybalance-backend/internal/adapter/storage/redis"
+	"github.com/langowen/bodybalance-backend/internal/adapter/webhook"
+	entities "github.com/langowen/bodybalance-backend/internal/entities/admin"
 	"github.com/langowen/bodybalance-backend/internal/service/admin"
 	"github.com/langowen/bodybalance-backend/internal/service/api"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logpretty"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/langowen/bodybalance-backend/pkg/lib/mediasign"
 	"github.com/theartofdevel/logging"
 )
 
@@ -21,8 +27,11 @@ type App struct {
 	Logger       *logging.Logger
 	Storage      *postgres.Storage
 	Redis        *redis.Storage
+	Notifier     *notifier.Notifier
 	ServiceApi   *api.ServiceApi
 	ServiceAdmin *admin.ServiceAdmin
+	MediaSigner  *mediasign.Signer // nil при MEDIA_SIGNING=off
+	Media        mediastore.Stores
 }
 
 func NewApp(cfg *config.Config) *App {
@@ -62,18 +71,109 @@ func (a *App) GetRedis(ctx context.Context) {
 	}
 }
 
+// GetNotifier запускает отправку уведомлений сотрудникам до отмены ctx
+func (a *App) GetNotifier(ctx context.Context) {
+	n := notifier.New(a.Cfg)
+	n.Start(ctx)
+
+	a.Notifier = n
+}
+
+// GetMediaSigner создает подпись ссылок на медиафайлы, если она включена в MEDIA_SIGNING
+func (a *App) GetMediaSigner() {
+	switch a.Cfg.MediaSigning.Mode {
+	case mediasign.ModeOff:
+		return
+	case mediasign.ModeSign, mediasign.ModeEnforce:
+	default:
+		log.Fatalln("Unknown MEDIA_SIGNING mode", a.Cfg.MediaSigning.Mode)
+	}
+
+	keys, err := mediasign.ParseKeys(a.Cfg.MediaSigning.Keys)
+	if err != nil {
+		log.Fatalln("Failed to parse MEDIA_SIGNING_KEYS", sl.Err(err))
+	}
+
+	signer, err := mediasign.New(keys, a.Cfg.MediaSigning.TTL)
+	if err != nil {
+		log.Fatalln("Failed to initialize media signer", sl.Err(err))
+	}
+
+	a.MediaSigner = signer
+}
+
+// GetMediaStore выбирает хранилище медиафайлов по MEDIA_STORE. Содержимое хранится по SHA-256,
+// имена файлов — в PostgreSQL, поэтому GetStorage должен быть вызван раньше.
+func (a *App) GetMediaStore() {
+	var video, images, docs, subtitles mediastore.MediaStore
+
+	switch a.Cfg.MediaStore.Type {
+	case "local":
+		video = mediastore.NewLocal(a.Cfg.Media.VideoPatch)
+		images = mediastore.NewLocal(a.Cfg.Media.ImagesPatch)
+		docs = mediastore.NewLocal(a.Cfg.Media.DocsPatch)
+		subtitles = mediastore.NewLocal(a.Cfg.Media.SubtitlesPatch)
+	case "s3":
+		client, err := mediastore.NewS3Client(a.Cfg.MediaStore)
+		if err != nil {
+			log.Fatalln("Failed to initialize S3 media store", sl.Err(err))
+		}
+
+		video = client.Store("video")
+		images = client.Store("img")
+		docs = client.Store("docs")
+		subtitles = client.Store("subtitles")
+	default:
+		log.Fatalln("Unknown MEDIA_STORE type", a.Cfg.MediaStore.Type)
+	}
+
+	tmpDir := a.Cfg.Media.UploadsPatch
+	a.Media = mediastore.Stores{
+		Video:     mediastore.NewContent(video, a.Storage.Admin, entities.MediaKindVideo, tmpDir),
+		Images:    mediastore.NewContent(images, a.Storage.Admin, entities.MediaKindImage, tmpDir),
+		Docs:      mediastore.NewContent(docs, a.Storage.Admin, entities.MediaKindDocument, tmpDir),
+		Subtitles: mediastore.NewContent(subtitles, a.Storage.Admin, entities.MediaKindSubtitle, tmpDir),
+	}
+}
+
 func (a *App) GetService() {
-	serviceApi := api.NewServiceApi(a.Cfg, a.Storage.Api, a.Redis)
+	serviceApi := api.NewServiceApi(a.Cfg, a.Storage.Api, a.Redis, a.Notifier, newPushSender(a.Cfg))
 	serviceAdmin := admin.NewServiceAdmin(
 		a.Cfg,
 		a.Storage.Admin,
 		a.Redis,
+		webhook.NewSender(a.Cfg.Webhooks.Timeout),
+		a.Media,
 	)
 
 	a.ServiceApi = serviceApi
 	a.ServiceAdmin = serviceAdmin
 }
 
+// StartWebhooks запускает отправку событий каталога подписчикам до отмены ctx
+func (a *App) StartWebhooks(ctx context.Context) {
+	go a.ServiceAdmin.RunWebhookDispatcher(ctx)
+}
+
+// StartReminders запускает отправку напоминаний о занятиях до отмены ctx
+func (a *App) StartReminders(ctx context.Context) {
+	go a.ServiceApi.RunReminderScheduler(ctx)
+}
+
+// newPushSender выбирает отправку push-уведомлений по cfg.Push.Provider
+func newPushSender(cfg *config.Config) api.PushSender {
+	switch cfg.Push.Provider {
+	case "fcm":
+		sender, err := push.NewFCM(cfg.Push)
+		if err != nil {
+			log.Fatalln("Failed to initialize FCM push sender", sl.Err(err))
+		}
+		return sender
+	default:
+		return push.NewLogSender()
+	}
+}
+
 func newLogger(cfg *config.Config) *logging.Logger {
 	var logger *logging.Logger
 
@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAnnouncementNotFound      = errors.New("announcement not found")
+	ErrAnnouncementEmptyTitle    = errors.New("announcement title cannot be empty")
+	ErrAnnouncementInvalidPeriod = errors.New("announcement end must be after its start")
+)
+
+// Announcement объявление для пользователей мобильного приложения
+type Announcement struct {
+	ID          int64
+	Title       string
+	Body        string
+	ImgURL      string        // Необязательное изображение
+	ContentType []ContentType // Аудитория, пустой список — все типы контента
+	StartsAt    time.Time
+	EndsAt      *time.Time // nil — объявление не снимается автоматически
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Active проверяет, что объявление показывается в момент now
+func (a *Announcement) Active(now time.Time) bool {
+	return !a.StartsAt.After(now) && (a.EndsAt == nil || a.EndsAt.After(now))
+}
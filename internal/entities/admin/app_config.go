@@ -0,0 +1,24 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAppConfigInvalidVersion = errors.New("invalid app version, expected format 1.2.3")
+	ErrAppConfigVersionOrder   = errors.New("minimum version cannot be greater than latest version")
+	ErrAppConfigInvalidFeature = errors.New("invalid feature name")
+)
+
+// AppConfig настройки мобильного приложения, которые отдаются клиентам в /v1/config
+type AppConfig struct {
+	MinVersion      string // Минимальная поддерживаемая версия, пусто — без ограничения
+	LatestVersion   string // Последняя выпущенная версия
+	ForceUpdate     bool   // Требовать обновления до LatestVersion, а не только до MinVersion
+	SupportEmail    string
+	SupportTelegram string
+	SupportPhone    string
+	Features        map[string]bool // Флаги функций приложения
+	UpdatedAt       time.Time
+}
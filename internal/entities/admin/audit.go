@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrMediaAuditFailed   = errors.New("failed to audit media library")
+	ErrMediaCleanupFailed = errors.New("failed to clean up media library")
+	ErrMediaIndexFailed   = errors.New("failed to index media library")
+)
+
+// Виды медиафайлов в библиотеке
+const (
+	MediaKindVideo    = "video"
+	MediaKindImage    = "image"
+	MediaKindDocument = "document"
+	MediaKindSubtitle = "subtitles"
+)
+
+// Действия очистки медиабиблиотеки
+const (
+	CleanupQuarantined = "quarantined" // Файл без ссылок перенесен в карантин
+	CleanupRestored    = "restored"    // На файл из карантина снова ссылаются, он возвращен в библиотеку
+	CleanupDeleted     = "deleted"     // Срок хранения в карантине истек, файл удален
+	CleanupFailed      = "failed"      // Ошибка, файл оставлен на месте
+)
+
+// MediaAudit результат сравнения ссылок в БД с файлами на диске
+type MediaAudit struct {
+	Orphans     []OrphanFile
+	Missing     []MissingFile
+	Quarantined []QuarantinedFile
+}
+
+// OrphanFile файл, на который не ссылается ни одно видео или категория
+type OrphanFile struct {
+	Kind          string
+	Name          string
+	Size          int64
+	ModTime       time.Time
+	InGracePeriod bool // Файл загружен недавно и еще не переносится в карантин
+}
+
+// MissingFile файл, на который ссылаются видео или категории, но которого нет на диске
+type MissingFile struct {
+	Kind   string
+	Name   string
+	UsedBy []FileUsage
+}
+
+// QuarantinedFile файл в карантине, ожидающий удаления
+type QuarantinedFile struct {
+	Kind          string
+	Name          string
+	Size          int64
+	QuarantinedAt time.Time
+	DeleteAfter   time.Time
+}
+
+type CleanupResult struct {
+	Kind   string
+	Name   string
+	Action string
+	Error  string
+}
+
+// IndexedFile файл, загруженный до хранения по содержимому и перенесенный под имя из SHA-256
+type IndexedFile struct {
+	Kind string
+	Name string
+}
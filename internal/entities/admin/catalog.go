@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+// CatalogBundleVersion текущая версия формата выгрузки каталога
+const CatalogBundleVersion = 1
+
+var (
+	ErrCatalogVersion          = errors.New("unsupported catalog bundle version")
+	ErrCatalogInvalid          = errors.New("invalid catalog bundle")
+	ErrCatalogUnknownReference = errors.New("catalog bundle references unknown entity")
+	ErrCatalogExportFailed     = errors.New("failed to export catalog")
+	ErrCatalogImportFailed     = errors.New("failed to import catalog")
+)
+
+// CatalogBundle выгрузка каталога, в которой связи задаются естественными ключами:
+// типы контента и категории по имени, видео по имени файла
+type CatalogBundle struct {
+	Version      int
+	ExportedAt   time.Time
+	ContentTypes []CatalogContentType
+	Categories   []CatalogCategory
+	Videos       []CatalogVideo
+	Media        *CatalogMedia
+}
+
+type CatalogContentType struct {
+	Name string
+}
+
+type CatalogCategory struct {
+	Name         string
+	ImgURL       string
+	ContentTypes []string
+	Parent       string // Имя родительской категории, пустое для корневой
+}
+
+type CatalogVideo struct {
+	URL         string
+	Name        string
+	Description string
+	ImgURL      string
+	Categories  []string
+	Tags        []string
+	NextUp      []string // Имена файлов видео "смотреть далее"
+	Details     ExerciseDetails
+}
+
+// CatalogMedia имена медиафайлов, на которые ссылается каталог
+type CatalogMedia struct {
+	Videos []string
+	Images []string
+}
+
+// ImportDiff результат импорта каталога или его пробного запуска
+type ImportDiff struct {
+	DryRun       bool
+	ContentTypes ImportChanges
+	Categories   ImportChanges
+	Videos       ImportChanges
+	MissingMedia []string
+}
+
+type ImportChanges struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+	IDs       map[string]int64 // ID созданных и обновленных сущностей по ключу из Created и Updated
+}
+
+// track запоминает ID созданной или обновленной сущности
+func (c *ImportChanges) track(key string, id int64) {
+	if c.IDs == nil {
+		c.IDs = make(map[string]int64)
+	}
+	c.IDs[key] = id
+}
+
+// AddCreated отмечает сущность созданной
+func (c *ImportChanges) AddCreated(key string, id int64) {
+	c.Created = append(c.Created, key)
+	c.track(key, id)
+}
+
+// AddUpdated отмечает сущность обновленной
+func (c *ImportChanges) AddUpdated(key string, id int64) {
+	c.Updated = append(c.Updated, key)
+	c.track(key, id)
+}
@@ -9,12 +9,16 @@ var (
 	ErrInvalidImgFormat  = errors.New("invalid file format in image URL")
 	ErrSuspiciousContent = errors.New("suspicious pattern in image URL")
 	ErrCategoryNotFound  = errors.New("category not found")
+	ErrImgNotFound       = errors.New("image file not found")
+	ErrParentNotFound    = errors.New("parent category not found")
+	ErrCategoryCycle     = errors.New("category cannot be nested in itself or its subcategory")
 )
 
 type Category struct {
 	ID          int64
 	Name        string
 	ImgURL      string
+	ParentID    int64 // 0 — корневая категория
 	ContentType []ContentType
 	CreatedAt   string
 }
@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDiaryInvalidRange  = errors.New("invalid diary date range")
+	ErrDiaryInvalidPeriod = errors.New("invalid diary trend period")
+)
+
+// Периоды группировки динамики дневника боли
+const (
+	DiaryPeriodDay   = "day"
+	DiaryPeriodWeek  = "week"
+	DiaryPeriodMonth = "month"
+)
+
+// DiaryDateLayout формат дат дневника в запросах
+const DiaryDateLayout = "2006-01-02"
+
+// DiaryEntry запись дневника боли пациента
+type DiaryEntry struct {
+	ID              int64
+	Date            time.Time
+	PainScore       int
+	BodyArea        string
+	Notes           string
+	Videos          []DiaryVideo
+	ContentTypeID   int64 // Тип контента пациента на момент записи, 0 если тип удален
+	ContentTypeName string
+	CreatedAt       time.Time
+}
+
+// DiaryVideo видео, отмеченное пациентом в записи дневника
+type DiaryVideo struct {
+	ID   int64
+	Name string
+}
+
+// DiaryFilter период выборки записей дневника, нулевая дата — без ограничения
+type DiaryFilter struct {
+	From time.Time
+	To   time.Time
+}
+
+// DiaryTrendFilter параметры динамики дневника боли по типу контента
+type DiaryTrendFilter struct {
+	ContentTypeID int64
+	From          time.Time
+	To            time.Time
+	Period        string
+}
+
+// DiaryTrendPoint усредненная оценка боли за период. Сначала оценки усредняются по каждому пациенту,
+// затем по пациентам, чтобы частые записи одного пациента не перевешивали остальных.
+type DiaryTrendPoint struct {
+	PeriodStart time.Time
+	AvgScore    float64
+	Entries     int
+	Patients    int
+}
@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDocumentNotFound          = errors.New("document not found")
+	ErrDocumentEmptyTitle        = errors.New("document title cannot be empty")
+	ErrDocumentEmptyURL          = errors.New("document file cannot be empty")
+	ErrDocumentURLPattern        = errors.New("invalid file format in document URL")
+	ErrDocumentSuspiciousPattern = errors.New("suspicious pattern in document URL")
+	ErrDocumentFileNotFound      = errors.New("document file not found")
+	ErrDocumentInvalidLink       = errors.New("invalid document video or category ID")
+)
+
+// Document PDF-памятка, прикрепленная к видео и категориям
+type Document struct {
+	ID          int64
+	Title       string
+	URL         string  // Имя файла в DOCS_PATCH
+	VideoIDs    []int64 // Видео, к которым прикреплен документ
+	CategoryIDs []int64 // Категории, к которым прикреплен документ
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrFeedbackNotFound         = errors.New("feedback not found")
+	ErrFeedbackInvalidStatus    = errors.New("invalid feedback status")
+	ErrFeedbackEmptyNote        = errors.New("note cannot be empty")
+	ErrFeedbackNoteTooLong      = errors.New("note is too long")
+	ErrFeedbackAssigneeNotAdmin = errors.New("feedback can be assigned only to an admin")
+)
+
+// Статусы обработки обратной связи
+const (
+	FeedbackStatusNew        = "new"
+	FeedbackStatusInProgress = "in_progress"
+	FeedbackStatusResolved   = "resolved"
+)
+
+// Ограничения постраничного вывода обратной связи
+const (
+	FeedbackDefaultLimit = 20
+	FeedbackMaxLimit     = 100
+)
+
+type Feedback struct {
+	ID           int64
+	Username     string
+	Email        string
+	Telegram     string
+	Message      string
+	Status       string
+	AssigneeID   int64 // 0 — не назначено
+	AssigneeName string
+	Read         bool // Прочитано текущим администратором
+	Notes        []FeedbackNote
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// FeedbackNote внутренняя заметка администратора, пользователю не показывается
+type FeedbackNote struct {
+	ID        int64
+	Author    string
+	Text      string
+	CreatedAt time.Time
+}
+
+// FeedbackFilter параметры поиска и постраничного вывода обратной связи
+type FeedbackFilter struct {
+	Query      string // Подстрока в имени, email, telegram или тексте сообщения
+	Status     string
+	AssigneeID int64
+	Page       int // Нумерация с 1
+	Limit      int
+}
+
+// FeedbackList страница обратной связи и счетчики непрочитанного для текущего администратора
+type FeedbackList struct {
+	Items  []Feedback
+	Total  int
+	Unread map[string]int // Непрочитанные по статусам
+}
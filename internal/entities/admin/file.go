@@ -11,10 +11,57 @@ var (
 	ErrFailedToReadFile     = errors.New("failed to read file")
 	ErrInvalidFileName      = errors.New("invalid file name")
 	ErrFileNotFound         = errors.New("file not found")
+	ErrFileAlreadyExists    = errors.New("file already exists")
+	ErrFileInUse            = errors.New("file is referenced by videos, categories or announcements")
+	ErrFileDeleteFailed     = errors.New("failed to delete file")
+	ErrFileRenameFailed     = errors.New("failed to rename file")
+	ErrImageInvalid         = errors.New("image is damaged or cannot be made safe")
+	ErrImageTooLarge        = errors.New("image dimensions exceed limits")
+	ErrImageExtension       = errors.New("image extension does not match its content")
+)
+
+// Статусы оптимизации faststart для видеофайлов
+const (
+	FaststartOptimized   = "optimized"         // moov перенесен в начало файла
+	FaststartAlreadyDone = "already_optimized" // moov уже находится перед mdat
+	FaststartSkipped     = "skipped"           // формат файла не поддерживается
+	FaststartFailed      = "failed"            // ошибка, оригинал оставлен без изменений
+)
+
+// Сущности, которые могут ссылаться на медиафайлы
+const (
+	UsageVideo        = "video"
+	UsageCategory     = "category"
+	UsageAnnouncement = "announcement"
+	UsageDocument     = "document"
 )
 
 type File struct {
-	Name    string
-	Size    int64
-	ModTime time.Time
+	Name     string
+	Size     int64
+	ModTime  time.Time
+	Checksum string // SHA-256 содержимого в hex, пусто для файлов, загруженных до хранения по содержимому
+	UsedBy   []FileUsage
+}
+
+// SavedFile результат загрузки файла в библиотеку
+type SavedFile struct {
+	Name       string
+	Checksum   string   // SHA-256 содержимого в hex
+	Replaced   bool     // под этим именем раньше было другое содержимое
+	Duplicates []string // другие имена файлов с тем же содержимым
+}
+
+// FileUsage ссылка на медиафайл из видео, категории, объявления или документа
+type FileUsage struct {
+	Entity string // video, category, announcement или document
+	ID     int64
+	Name   string
+	Field  string // Поле, в котором указан файл: url или img_url
+}
+
+type FaststartResult struct {
+	Name   string
+	Status string
+	Error  string
 }
@@ -0,0 +1,24 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSubtitleNotFound        = errors.New("subtitle track not found")
+	ErrSubtitleInvalidLanguage = errors.New("invalid subtitle language")
+	ErrSubtitleInvalidLabel    = errors.New("subtitle label is too long")
+	ErrSubtitleInvalidFile     = errors.New("invalid webvtt file")
+)
+
+// Subtitle дорожка субтитров WebVTT к видео на одном языке
+type Subtitle struct {
+	ID        int64
+	VideoID   int64
+	Language  string // Код языка BCP 47, например ru или en-US
+	Label     string // Название дорожки для меню плеера
+	URL       string // Имя файла в SUBTITLES_PATCH
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadInvalidLength  = errors.New("invalid upload length")
+	ErrUploadTooLarge       = errors.New("upload exceeds maximum size")
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	ErrUploadLocked         = errors.New("upload is being written by another request")
+	ErrUploadWrongNode      = errors.New("upload belongs to another instance")
+)
+
+// Upload загрузка видео по частям по протоколу tus.
+// Принятые байты лежат в UploadsPatch, после получения последнего байта файл проверяется и переносится в библиотеку видео.
+// UploadsPatch и блокировки загрузок локальны для экземпляра, поэтому ID начинается с имени экземпляра
+// из UPLOADS_NODE, и балансировщик направляет по нему запросы на тот экземпляр, где загрузка создана.
+type Upload struct {
+	ID        string
+	Filename  string
+	Length    int64 // Полный размер файла из Upload-Length
+	Offset    int64 // Сколько байт уже принято
+	ExpiresAt time.Time
+	Completed bool
+}
@@ -3,28 +3,59 @@ package admin
 import "errors"
 
 var (
-	ErrVideoNotFound             = errors.New("video not found")
-	ErrVideoInvalidID            = errors.New("invalid video ID")
-	ErrVideoInvalidURL           = errors.New("invalid video URL")
-	ErrVideoInvalidName          = errors.New("video name cannot be empty")
-	ErrVideoInvalidImgURL        = errors.New("video image URL cannot be empty")
-	ErrVideoInvalidCategory      = errors.New("at least one category must be selected")
-	ErrVideoURLPattern           = errors.New("invalid file format in video URL")
-	ErrVideoSuspiciousPattern    = errors.New("suspicious pattern in video URL")
-	ErrVideoImgPattern           = errors.New("invalid file format in video image URL")
-	ErrVideoImgSuspiciousPattern = errors.New("suspicious pattern in video image URL")
-	ErrVideoSaveFailed           = errors.New("failed to save video")
-	ErrFailedGetVideo            = errors.New("failed to get video")
-	ErrVideoDeleteFailed         = errors.New("failed to delete video")
-	ErrVideoUpdateFailed         = errors.New("failed to update video")
+	ErrVideoNotFound                 = errors.New("video not found")
+	ErrVideoInvalidID                = errors.New("invalid video ID")
+	ErrVideoInvalidURL               = errors.New("invalid video URL")
+	ErrVideoInvalidName              = errors.New("video name cannot be empty")
+	ErrVideoInvalidImgURL            = errors.New("video image URL cannot be empty")
+	ErrVideoInvalidCategory          = errors.New("at least one category must be selected")
+	ErrVideoURLPattern               = errors.New("invalid file format in video URL")
+	ErrVideoSuspiciousPattern        = errors.New("suspicious pattern in video URL")
+	ErrVideoImgPattern               = errors.New("invalid file format in video image URL")
+	ErrVideoImgSuspiciousPattern     = errors.New("suspicious pattern in video image URL")
+	ErrVideoSaveFailed               = errors.New("failed to save video")
+	ErrFailedGetVideo                = errors.New("failed to get video")
+	ErrVideoDeleteFailed             = errors.New("failed to delete video")
+	ErrVideoUpdateFailed             = errors.New("failed to update video")
+	ErrVideoInvalidTag               = errors.New("invalid video tag")
+	ErrVideoNextUpInvalid            = errors.New("video cannot be next up for itself")
+	ErrVideoNextUpNotFound           = errors.New("next up video not found")
+	ErrVideoFileNotFound             = errors.New("video file not found")
+	ErrVideoImgNotFound              = errors.New("video image file not found")
+	ErrVideoInvalidSteps             = errors.New("invalid video steps")
+	ErrVideoInvalidContraindications = errors.New("invalid video contraindications")
+	ErrVideoInvalidEquipment         = errors.New("invalid video equipment")
+	ErrVideoInvalidDosage            = errors.New("invalid video sets or reps")
+	ErrVideoInvalidDifficulty        = errors.New("invalid video difficulty")
+)
+
+// Уровни сложности упражнения, пустая строка означает, что уровень не задан
+const (
+	DifficultyBeginner     = "beginner"
+	DifficultyIntermediate = "intermediate"
+	DifficultyAdvanced     = "advanced"
 )
 
 type Video struct {
-	ID          int64
-	URL         string
-	Name        string
-	Description string
-	ImgURL      string
-	Categories  []Category
-	DateCreated string
+	ID           int64
+	URL          string
+	Name         string
+	Description  string
+	ImgURL       string
+	Categories   []Category
+	Tags         []string
+	NextVideoIDs []int64 // Видео "смотреть далее" в порядке показа
+	Details      ExerciseDetails
+	Rating       VideoRating // Заполняется только при получении одного видео
+	DateCreated  string
+}
+
+// ExerciseDetails структурированное описание упражнения для специалистов
+type ExerciseDetails struct {
+	Steps             []string // Шаги выполнения по порядку
+	Contraindications []string
+	Equipment         []string
+	Sets              int // Рекомендуемое число подходов, 0 если не задано
+	Reps              int // Рекомендуемое число повторений в подходе, 0 если не задано
+	Difficulty        string
 }
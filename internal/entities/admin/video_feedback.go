@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrCommentInvalidStatus = errors.New("invalid comment status")
+)
+
+// Статусы модерации комментариев
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+)
+
+// VideoRating итог оценок видео пациентами
+type VideoRating struct {
+	Average      float64
+	Count        int
+	Distribution [5]int // Количество оценок от 1 до 5
+}
+
+// VideoComment комментарий пациента в очереди модерации
+type VideoComment struct {
+	ID              int64
+	VideoID         int64
+	VideoName       string
+	Username        string
+	ContentTypeID   int64 // 0, если тип контента удален
+	ContentTypeName string
+	Text            string
+	Status          string
+	ModeratedBy     string
+	ModeratedAt     *time.Time
+	CreatedAt       time.Time
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookInvalidURL       = errors.New("webhook URL must be an absolute http or https URL")
+	ErrWebhookInvalidEvent     = errors.New("unknown webhook event")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// События каталога, на которые можно подписаться
+const (
+	EventVideoCreated    = "video.created"
+	EventVideoUpdated    = "video.updated"
+	EventVideoDeleted    = "video.deleted"
+	EventCategoryCreated = "category.created"
+	EventCategoryUpdated = "category.updated"
+	EventCategoryDeleted = "category.deleted"
+)
+
+// WebhookEvents все события, на которые можно подписаться
+var WebhookEvents = []string{
+	EventVideoCreated,
+	EventVideoUpdated,
+	EventVideoDeleted,
+	EventCategoryCreated,
+	EventCategoryUpdated,
+	EventCategoryDeleted,
+}
+
+// Статусы доставки события
+const (
+	DeliveryPending = "pending" // Ожидает первой отправки или повтора
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed" // Попытки исчерпаны
+)
+
+// Webhook подписка внешней системы на события каталога
+type Webhook struct {
+	ID          int64
+	URL         string
+	Secret      string // Ключ HMAC подписи тела запроса
+	Events      []string
+	Description string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WebhookDelivery попытка доставить событие подписчику
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	Event          string
+	Payload        []byte // JSON тело запроса
+	Status         string
+	Attempts       int
+	LastStatusCode int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+
+	// Заполняются при выборке доставок для отправки
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt результат одной попытки отправки
+type WebhookAttempt struct {
+	StatusCode    int
+	Error         string
+	Status        string
+	NextAttemptAt time.Time
+}
@@ -4,6 +4,7 @@ import "errors"
 
 var (
 	ErrEmptyUsername      = errors.New("username cannot be empty")
+	ErrEmptyPassword      = errors.New("password cannot be empty")
 	ErrStorageServerError = errors.New("storage server error")
 	ErrRedisError         = errors.New("redis server error")
 )
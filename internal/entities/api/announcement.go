@@ -0,0 +1,18 @@
+package api
+
+import "time"
+
+type Announcement struct {
+	ID         int64
+	Title      string
+	Body       string
+	ImgURL     string
+	StartsAt   time.Time
+	EndsAt     *time.Time // nil — без даты окончания
+	DataSource string
+}
+
+// Active проверяет, что объявление показывается в момент now
+func (a *Announcement) Active(now time.Time) bool {
+	return !a.StartsAt.After(now) && (a.EndsAt == nil || a.EndsAt.After(now))
+}
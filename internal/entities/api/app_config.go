@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/langowen/bodybalance-backend/pkg/lib/appversion"
+)
+
+var ErrInvalidAppVersion = errors.New("invalid app version")
+
+type AppConfig struct {
+	MinVersion      string
+	LatestVersion   string
+	ForceUpdate     bool
+	SupportEmail    string
+	SupportTelegram string
+	SupportPhone    string
+	Features        map[string]bool
+	DataSource      string
+}
+
+// UpgradeRequired проверяет, что версия клиента больше не поддерживается: она ниже минимальной
+// или, при включенном ForceUpdate, ниже последней. Некорректные версии в настройках не учитываются.
+func (c *AppConfig) UpgradeRequired(version appversion.Version) bool {
+	if minVersion, err := appversion.Parse(c.MinVersion); err == nil && version.Less(minVersion) {
+		return true
+	}
+
+	if c.ForceUpdate {
+		if latestVersion, err := appversion.Parse(c.LatestVersion); err == nil && version.Less(latestVersion) {
+			return true
+		}
+	}
+
+	return false
+}
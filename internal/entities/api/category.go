@@ -6,10 +6,13 @@ type Category struct {
 	ID         int64
 	Name       string
 	ImgURL     string
+	ParentID   int64 // 0 — корневая категория или родитель недоступен для типа контента
+	Documents  []Document
 	DataSource string
 }
 
 var (
 	ErrEmptyCategoryID = errors.New("category ID cannot be empty")
 	ErrCategoryInvalid = errors.New("invalid category ID")
+	ErrParentInvalid   = errors.New("invalid parent category ID")
 )
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidPainScore     = errors.New("pain score must be between 0 and 10")
+	ErrInvalidDiaryDate     = errors.New("invalid diary date")
+	ErrInvalidDiaryRange    = errors.New("invalid diary date range")
+	ErrDiaryBodyAreaTooLong = errors.New("body area is too long")
+	ErrDiaryNotesTooLong    = errors.New("diary notes are too long")
+	ErrDiaryInvalidVideo    = errors.New("invalid diary video ID")
+	ErrDiaryEntryNotFound   = errors.New("diary entry not found")
+	ErrInvalidDiaryEntryID  = errors.New("invalid diary entry ID")
+)
+
+// DiaryDateLayout формат даты записи дневника
+const DiaryDateLayout = "2006-01-02"
+
+// DiaryEntry запись дневника боли пациента за день
+type DiaryEntry struct {
+	ID        int64 // Заполняется после сохранения
+	Username  string
+	Date      time.Time
+	PainScore int // От 0 (нет боли) до 10
+	BodyArea  string
+	Notes     string
+	VideoIDs  []int64 // Видео, выполненные в этот день
+	CreatedAt time.Time
+}
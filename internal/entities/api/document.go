@@ -0,0 +1,8 @@
+package api
+
+// Document PDF-памятка, прикрепленная к видео или категории
+type Document struct {
+	ID    int64
+	Title string
+	URL   string
+}
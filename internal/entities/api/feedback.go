@@ -3,6 +3,7 @@ package api
 import "errors"
 
 type Feedback struct {
+	ID       int64 // Заполняется после сохранения
 	Name     string
 	Email    string
 	Telegram string
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrReminderNotFound        = errors.New("reminder not found")
+	ErrInvalidReminderID       = errors.New("invalid reminder ID")
+	ErrInvalidReminderDays     = errors.New("invalid reminder days")
+	ErrInvalidReminderTime     = errors.New("invalid reminder time")
+	ErrInvalidReminderTimezone = errors.New("invalid reminder timezone")
+	ErrReminderMessageTooLong  = errors.New("reminder message is too long")
+	ErrTooManyReminders        = errors.New("too many reminders")
+	ErrEmptyDeviceToken        = errors.New("device token cannot be empty")
+	ErrInvalidDevicePlatform   = errors.New("invalid device platform")
+	ErrDeviceNotFound          = errors.New("device token not found")
+	ErrPushTokenInvalid        = errors.New("push token is no longer valid")
+	ErrReminderLeaseLost       = errors.New("reminder was changed or claimed by another instance")
+)
+
+// Платформы устройств для push-уведомлений
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+)
+
+// Reminder расписание напоминаний пациенту о занятиях
+type Reminder struct {
+	ID        int64
+	Username  string
+	Days      []int  // ISO дни недели: 1 — понедельник, 7 — воскресенье
+	Time      string // Местное время "HH:MM"
+	Timezone  string // Часовой пояс IANA, например Europe/Moscow
+	Message   string // Пустое сообщение заменяется текстом по умолчанию
+	Enabled   bool
+	NextRunAt *time.Time // nil у выключенного напоминания
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DueReminder напоминание, время которого наступило, с токенами устройств пациента
+type DueReminder struct {
+	Reminder
+	DueAt      time.Time // Время срабатывания по расписанию
+	LeaseUntil time.Time // До этого времени напоминание скрыто от других экземпляров сервиса
+	Tokens     []string
+}
+
+// Device устройство пациента для push-уведомлений
+type Device struct {
+	Token    string
+	Username string
+	Platform string
+}
+
+// PushMessage push-уведомление на одно устройство
+type PushMessage struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string
+}
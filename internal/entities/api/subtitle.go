@@ -0,0 +1,8 @@
+package api
+
+// Subtitle дорожка субтитров WebVTT к видео
+type Subtitle struct {
+	Language string
+	Label    string
+	URL      string
+}
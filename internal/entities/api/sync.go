@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+	"time"
+)
+
+// SyncChanges изменения каталога для одного типа контента с момента курсора
+type SyncChanges struct {
+	Cursor            string    // Курсор для следующего запроса
+	Full              bool      // Полная выгрузка, клиент должен заменить локальные данные
+	Until             time.Time // Момент, на который собраны изменения
+	Categories        []Category
+	Videos            []SyncVideo
+	DeletedCategories []int64 // Категории, которые удалены или больше не доступны для типа
+	DeletedVideos     []int64 // Видео, которые удалены или больше не доступны для типа
+}
+
+// SyncVideo видео с ID всех его категорий, доступных для типа контента
+type SyncVideo struct {
+	ID          int64
+	URL         string
+	Name        string
+	Description string
+	ImgURL      string
+	CategoryIDs []int64
+}
+
+var (
+	ErrSyncCursorInvalid = errors.New("invalid sync cursor")
+)
@@ -1,6 +1,9 @@
 package api
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type Video struct {
 	ID          int64
@@ -9,9 +12,31 @@ type Video struct {
 	Description string
 	Category    Category
 	ImgURL      string
+	Details     ExerciseDetails
+	Documents   []Document
+	Subtitles   []Subtitle
 	DataSource  string
 }
 
+// RelatedCandidate видео, которое можно показать после просмотра исходного, и признаки для сортировки
+type RelatedCandidate struct {
+	Video            Video
+	NextUpPosition   int // Место в ссылках "смотреть далее", -1 — ссылки нет
+	SharedCategories int
+	SharedTags       int
+	CreatedAt        time.Time
+}
+
+// ExerciseDetails структурированное описание упражнения
+type ExerciseDetails struct {
+	Steps             []string
+	Contraindications []string
+	Equipment         []string
+	Sets              int // 0 если не задано
+	Reps              int // 0 если не задано
+	Difficulty        string
+}
+
 var (
 	ErrEmptyVideoID   = errors.New("video ID cannot be empty")
 	ErrInvalidVideoID = errors.New("invalid video ID")
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidScore   = errors.New("score must be between 1 and 5")
+	ErrEmptyComment   = errors.New("comment cannot be empty")
+	ErrCommentTooLong = errors.New("comment is too long")
+)
+
+// VideoRating оценка видео пациентом и итог по всем оценкам видео
+type VideoRating struct {
+	VideoID  int64
+	Username string
+	Score    int
+	Average  float64 // Заполняется после сохранения
+	Count    int
+}
+
+// VideoComment комментарий пациента к видео. Имя автора наружу не отдается:
+// по нему можно войти в приложение.
+type VideoComment struct {
+	ID        int64
+	VideoID   int64
+	Username  string
+	Text      string
+	Status    string // pending, approved или rejected
+	Own       bool   // Комментарий текущего пациента
+	CreatedAt time.Time
+}
@@ -63,9 +63,33 @@ func (h *Handler) Router(r chi.Router) chi.Router {
 		r.Route("/files", func(r chi.Router) {
 			r.Post("/video", h.uploadVideoHandler)
 			r.Get("/video", h.listVideoFilesHandler)
+			r.Post("/video/faststart", h.optimizeVideoFilesHandler)
+			r.Put("/video/{filename}", h.replaceVideoFileHandler)
+			r.Delete("/video/{filename}", h.deleteVideoFileHandler)
+			r.Post("/video/{filename}/rename", h.renameVideoFileHandler)
 			r.Post("/img", h.uploadImageHandler)
 			r.Get("/img", h.listImageFilesHandler)
+			r.Put("/img/{filename}", h.replaceImageFileHandler)
+			r.Delete("/img/{filename}", h.deleteImageFileHandler)
+			r.Post("/img/{filename}/rename", h.renameImageFileHandler)
+			r.Post("/docs", h.uploadDocumentHandler)
+			r.Get("/docs", h.listDocumentFilesHandler)
+			r.Get("/audit", h.auditMediaHandler)
+			r.Post("/audit/cleanup", h.cleanupMediaHandler)
+
+			// Загрузка видео по частям по протоколу tus 1.0
+			r.Route("/uploads", func(r chi.Router) {
+				r.Options("/", h.tusOptionsHandler)
+				r.Post("/", h.createUploadHandler)
+				r.Head("/{id}", h.headUploadHandler)
+				r.Patch("/{id}", h.patchUploadHandler)
+				r.Delete("/{id}", h.deleteUploadHandler)
+			})
 		})
+		// API для выгрузки и загрузки каталога
+		r.Get("/export", h.exportCatalog)
+		r.Post("/import", h.importCatalog)
+
 		// API для работы с видео
 		r.Route("/video", func(r chi.Router) {
 			r.Post("/", h.addVideo)
@@ -73,6 +97,9 @@ func (h *Handler) Router(r chi.Router) chi.Router {
 			r.Get("/", h.getVideos)
 			r.Put("/{id}", h.updateVideo)
 			r.Delete("/{id}", h.deleteVideo)
+			r.Get("/{id}/subtitles", h.getVideoSubtitles)
+			r.Post("/{id}/subtitles", h.uploadVideoSubtitle)
+			r.Delete("/{id}/subtitles/{language}", h.deleteVideoSubtitle)
 		})
 
 		// API для работы с типами
@@ -91,6 +118,58 @@ func (h *Handler) Router(r chi.Router) chi.Router {
 			r.Get("/", h.getUsers)
 			r.Put("/{id}", h.updateUser)
 			r.Delete("/{id}", h.deleteUser)
+			r.Get("/{id}/diary", h.getUserDiary)
+		})
+
+		// API для динамики дневника боли
+		r.Get("/diary/trend", h.getDiaryTrend)
+
+		// API для работы с обратной связью
+		r.Route("/feedback", func(r chi.Router) {
+			r.Get("/", h.listFeedback)
+			r.Get("/{id}", h.getFeedback)
+			r.Put("/{id}/status", h.updateFeedbackStatus)
+			r.Put("/{id}/assignee", h.assignFeedback)
+			r.Post("/{id}/notes", h.addFeedbackNote)
+		})
+
+		// API для работы с объявлениями
+		r.Route("/announcements", func(r chi.Router) {
+			r.Post("/", h.addAnnouncement)
+			r.Get("/", h.getAnnouncements)
+			r.Get("/{id}", h.getAnnouncement)
+			r.Put("/{id}", h.updateAnnouncement)
+			r.Delete("/{id}", h.deleteAnnouncement)
+		})
+
+		// API для работы с PDF-памятками
+		r.Route("/documents", func(r chi.Router) {
+			r.Post("/", h.addDocument)
+			r.Get("/", h.getDocuments)
+			r.Get("/{id}", h.getDocument)
+			r.Put("/{id}", h.updateDocument)
+			r.Delete("/{id}", h.deleteDocument)
+		})
+
+		// API для модерации комментариев пациентов к видео
+		r.Route("/comments", func(r chi.Router) {
+			r.Get("/", h.getVideoComments)
+			r.Put("/{id}/status", h.moderateVideoComment)
+		})
+
+		// API для настроек мобильного приложения
+		r.Get("/config", h.getAppConfig)
+		r.Put("/config", h.updateAppConfig)
+
+		// API для работы с подписками на события каталога
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/", h.addWebhook)
+			r.Get("/", h.getWebhooks)
+			r.Get("/{id}", h.getWebhook)
+			r.Put("/{id}", h.updateWebhook)
+			r.Delete("/{id}", h.deleteWebhook)
+			r.Get("/{id}/deliveries", h.getWebhookDeliveries)
+			r.Post("/deliveries/{id}/redeliver", h.redeliverWebhook)
 		})
 
 		// API для работы с категориями
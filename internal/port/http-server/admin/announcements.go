@@ -0,0 +1,296 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Создать объявление
+// @Description Создает объявление для мобильного приложения. Без типов контента объявление показывается всем.
+// @Tags Admin Announcements
+// @Accept json
+// @Produce json
+// @Param input body dto.AnnouncementRequest true "Данные объявления"
+// @Success 201 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/announcements [post]
+func (h *Handler) addAnnouncement(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.addAnnouncement"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	var req dto.AnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	id, err := h.service.AddAnnouncement(ctx, announcementFromDTO(0, &req))
+	if err != nil {
+		if respondAnnouncementValidationError(w, err) {
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to add announcement")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, dto.SuccessResponse{
+		ID:      id,
+		Message: "Announcement added successfully",
+	})
+}
+
+// @Summary Получить объявление
+// @Description Возвращает объявление по ID
+// @Tags Admin Announcements
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 200 {object} dto.AnnouncementResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/announcements/{id} [get]
+func (h *Handler) getAnnouncement(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getAnnouncement"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid announcement ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid announcement ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	announcement, err := h.service.GetAnnouncement(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrAnnouncementNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Announcement not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get announcement")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, announcementToDTO(announcement, time.Now()))
+}
+
+// @Summary Получить объявления
+// @Description Возвращает все объявления, включая запланированные и завершенные
+// @Tags Admin Announcements
+// @Produce json
+// @Success 200 {array} dto.AnnouncementResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/announcements [get]
+func (h *Handler) getAnnouncements(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getAnnouncements"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	announcements, err := h.service.GetAnnouncements(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get announcements")
+		return
+	}
+
+	now := time.Now()
+	res := make([]dto.AnnouncementResponse, len(announcements))
+	for i := range announcements {
+		res[i] = announcementToDTO(&announcements[i], now)
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Изменить объявление
+// @Description Обновляет объявление и его аудиторию
+// @Tags Admin Announcements
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Param input body dto.AnnouncementRequest true "Данные объявления"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/announcements/{id} [put]
+func (h *Handler) updateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.updateAnnouncement"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid announcement ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid announcement ID")
+		return
+	}
+
+	var req dto.AnnouncementRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.UpdateAnnouncement(ctx, announcementFromDTO(id, &req))
+	if err != nil {
+		if respondAnnouncementValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, admin.ErrAnnouncementNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Announcement not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to update announcement")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Announcement updated successfully",
+	})
+}
+
+// @Summary Удалить объявление
+// @Description Удаляет объявление
+// @Tags Admin Announcements
+// @Produce json
+// @Param id path int true "ID объявления"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/announcements/{id} [delete]
+func (h *Handler) deleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.deleteAnnouncement"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid announcement ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid announcement ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrAnnouncementNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Announcement not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to delete announcement")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Announcement deleted successfully",
+	})
+}
+
+// respondAnnouncementValidationError отвечает 400 на ошибки проверки объявления и возвращает true, если ответ отправлен
+func respondAnnouncementValidationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, admin.ErrAnnouncementEmptyTitle):
+		dto.RespondWithError(w, http.StatusBadRequest, "Введите заголовок объявления")
+	case errors.Is(err, admin.ErrAnnouncementInvalidPeriod):
+		dto.RespondWithError(w, http.StatusBadRequest, "Окончание показа должно быть позже начала")
+	case errors.Is(err, admin.ErrInvalidImgFormat):
+		dto.RespondWithError(w, http.StatusBadRequest, "Недопустимый формат имени файла изображения")
+	case errors.Is(err, admin.ErrSuspiciousContent):
+		dto.RespondWithError(w, http.StatusBadRequest, "Подозрительный контент в URL изображения")
+	case errors.Is(err, admin.ErrImgNotFound):
+		dto.RespondWithError(w, http.StatusBadRequest, "Изображение не найдено на сервере, сначала загрузите его")
+	case errors.Is(err, admin.ErrTypeNotFound):
+		dto.RespondWithError(w, http.StatusBadRequest, "Тип контента не найден")
+	default:
+		return false
+	}
+	return true
+}
+
+func announcementFromDTO(id int64, req *dto.AnnouncementRequest) *admin.Announcement {
+	announcement := &admin.Announcement{
+		ID:          id,
+		Title:       req.Title,
+		Body:        req.Body,
+		ImgURL:      req.ImgURL,
+		EndsAt:      req.EndsAt,
+		ContentType: make([]admin.ContentType, len(req.TypeIDs)),
+	}
+
+	if req.StartsAt != nil {
+		announcement.StartsAt = *req.StartsAt
+	}
+
+	for i, typeID := range req.TypeIDs {
+		announcement.ContentType[i].ID = typeID
+	}
+
+	return announcement
+}
+
+func announcementToDTO(announcement *admin.Announcement, now time.Time) dto.AnnouncementResponse {
+	res := dto.AnnouncementResponse{
+		ID:        announcement.ID,
+		Title:     announcement.Title,
+		Body:      announcement.Body,
+		ImgURL:    announcement.ImgURL,
+		Types:     make([]dto.TypeResponse, len(announcement.ContentType)),
+		StartsAt:  announcement.StartsAt,
+		EndsAt:    announcement.EndsAt,
+		Active:    announcement.Active(now),
+		CreatedAt: announcement.CreatedAt,
+		UpdatedAt: announcement.UpdatedAt,
+	}
+
+	for i, contentType := range announcement.ContentType {
+		res.Types[i] = dto.TypeResponse{ID: contentType.ID, Name: contentType.Name}
+	}
+
+	return res
+}
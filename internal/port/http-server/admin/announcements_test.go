@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnouncementFromDTO(t *testing.T) {
+	startsAt := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	announcement := announcementFromDTO(5, &dto.AnnouncementRequest{
+		Title:    "График работы",
+		TypeIDs:  []int64{1, 2},
+		StartsAt: &startsAt,
+	})
+
+	assert.Equal(t, int64(5), announcement.ID)
+	assert.Equal(t, startsAt, announcement.StartsAt)
+	assert.Nil(t, announcement.EndsAt)
+	assert.Equal(t, []admin.ContentType{{ID: 1}, {ID: 2}}, announcement.ContentType)
+
+	// Без начала показа время подставляет сервис
+	announcement = announcementFromDTO(0, &dto.AnnouncementRequest{Title: "Новая программа"})
+	assert.True(t, announcement.StartsAt.IsZero())
+	assert.Empty(t, announcement.ContentType)
+}
+
+func TestAnnouncementToDTO_Active(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		startsAt time.Time
+		endsAt   *time.Time
+		active   bool
+	}{
+		{name: "без окончания", startsAt: past, active: true},
+		{name: "в периоде", startsAt: past, endsAt: &future, active: true},
+		{name: "запланировано", startsAt: future, active: false},
+		{name: "завершено", startsAt: past.Add(-time.Hour), endsAt: &past, active: false},
+		{name: "окончание ровно сейчас", startsAt: past, endsAt: &now, active: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := announcementToDTO(&admin.Announcement{StartsAt: tt.startsAt, EndsAt: tt.endsAt}, now)
+			assert.Equal(t, tt.active, res.Active)
+			// Пустая аудитория отдается как [], а не null
+			assert.NotNil(t, res.Types)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Получить настройки приложения
+// @Description Возвращает настройки мобильного приложения, которые отдаются клиентам в /v1/config
+// @Tags Admin App Config
+// @Produce json
+// @Success 200 {object} dto.AppConfigResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/config [get]
+func (h *Handler) getAppConfig(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getAppConfig"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	cfg, err := h.service.GetAppConfig(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get app config")
+		return
+	}
+
+	features := cfg.Features
+	if features == nil {
+		features = map[string]bool{}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.AppConfigResponse{
+		AppConfigRequest: dto.AppConfigRequest{
+			MinVersion:      cfg.MinVersion,
+			LatestVersion:   cfg.LatestVersion,
+			ForceUpdate:     cfg.ForceUpdate,
+			SupportEmail:    cfg.SupportEmail,
+			SupportTelegram: cfg.SupportTelegram,
+			SupportPhone:    cfg.SupportPhone,
+			Features:        features,
+		},
+		UpdatedAt: cfg.UpdatedAt,
+	})
+}
+
+// @Summary Изменить настройки приложения
+// @Description Сохраняет настройки мобильного приложения целиком. Клиенты с версией ниже min_version, а при force_update ниже latest_version, получают 426 Upgrade Required, если включен APP_VERSION_CHECK.
+// @Tags Admin App Config
+// @Accept json
+// @Produce json
+// @Param input body dto.AppConfigRequest true "Настройки приложения"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/config [put]
+func (h *Handler) updateAppConfig(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.updateAppConfig"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	var req dto.AppConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err := h.service.UpdateAppConfig(ctx, &admin.AppConfig{
+		MinVersion:      req.MinVersion,
+		LatestVersion:   req.LatestVersion,
+		ForceUpdate:     req.ForceUpdate,
+		SupportEmail:    req.SupportEmail,
+		SupportTelegram: req.SupportTelegram,
+		SupportPhone:    req.SupportPhone,
+		Features:        req.Features,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppConfigInvalidVersion):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный формат версии", "version must look like 1.2.3")
+			return
+		case errors.Is(err, admin.ErrAppConfigVersionOrder):
+			dto.RespondWithError(w, http.StatusBadRequest, "Минимальная версия не может быть больше последней")
+			return
+		case errors.Is(err, admin.ErrAppConfigInvalidFeature):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверное имя флага функции", "feature names must match ^[a-z][a-z0-9_]{0,49}$")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to update app config")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		Message: "App config updated successfully",
+	})
+}
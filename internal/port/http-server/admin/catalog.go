@@ -0,0 +1,413 @@
+package admin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/langowen/bodybalance-backend/pkg/lib/slicesx"
+	"github.com/theartofdevel/logging"
+)
+
+const (
+	maxCatalogSize = 20 << 20 // 20 MB
+
+	// catalogCSVSeparator разделитель элементов списков внутри ячейки CSV
+	catalogCSVSeparator = "|"
+)
+
+// catalogCSVHeader колонки CSV выгрузки видео
+var catalogCSVHeader = []string{
+	"url", "name", "description", "img_url", "categories", "tags", "next_up",
+	"steps", "contraindications", "equipment", "sets", "reps", "difficulty",
+}
+
+// @Summary Выгрузить каталог
+// @Description Выгружает типы контента, категории и видео со связями. В формате csv выгружаются только видео.
+// @Tags Admin Catalog
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Формат выгрузки: json или csv" default(json)
+// @Param include_media query bool false "Добавить список используемых медиафайлов"
+// @Success 200 {object} dto.CatalogBundle
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/export [get]
+func (h *Handler) exportCatalog(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.exportCatalog"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверный формат выгрузки", "format must be json or csv")
+		return
+	}
+
+	includeMedia := r.URL.Query().Get("include_media") == "true"
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	bundle, err := h.service.ExportCatalog(ctx, includeMedia)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to export catalog")
+		return
+	}
+
+	filename := "catalog-" + bundle.ExportedAt.Format("20060102-150405")
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+		w.WriteHeader(http.StatusOK)
+
+		if err = writeCatalogCSV(w, bundle.Videos); err != nil {
+			logger.Error("failed to write catalog csv", sl.Err(err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+	dto.RespondWithJSON(w, http.StatusOK, catalogToDTO(bundle))
+}
+
+// @Summary Загрузить каталог
+// @Description Проверяет выгрузку и создает или обновляет сущности по именам в одной транзакции. CSV содержит только видео, категории должны существовать.
+// @Tags Admin Catalog
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "Формат выгрузки: json или csv" default(json)
+// @Param dry_run query bool false "Только показать изменения без сохранения"
+// @Param input body dto.CatalogBundle true "Выгрузка каталога"
+// @Success 200 {object} dto.ImportDiffResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/import [post]
+func (h *Handler) importCatalog(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.importCatalog"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		format = "csv"
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCatalogSize)
+
+	var bundle *admin.CatalogBundle
+
+	switch format {
+	case "", "json":
+		var req dto.CatalogBundle
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error("failed to decode request body", sl.Err(err))
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+			return
+		}
+		bundle = catalogFromDTO(&req)
+	case "csv":
+		videos, err := readCatalogCSV(r.Body)
+		if err != nil {
+			logger.Error("failed to parse catalog csv", sl.Err(err))
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный формат CSV", err.Error())
+			return
+		}
+		bundle = &admin.CatalogBundle{
+			Version: admin.CatalogBundleVersion,
+			Videos:  videos,
+		}
+	default:
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверный формат выгрузки", "format must be json or csv")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	diff, err := h.service.ImportCatalog(ctx, bundle, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrCatalogVersion):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неподдерживаемая версия выгрузки", err.Error())
+			return
+		case errors.Is(err, admin.ErrCatalogInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Ошибка в данных выгрузки", err.Error())
+			return
+		case errors.Is(err, admin.ErrCatalogUnknownReference):
+			dto.RespondWithError(w, http.StatusBadRequest, "Выгрузка ссылается на несуществующие данные", err.Error())
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to import catalog")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.ImportDiffResponse{
+		DryRun:       diff.DryRun,
+		ContentTypes: importChangesToDTO(diff.ContentTypes),
+		Categories:   importChangesToDTO(diff.Categories),
+		Videos:       importChangesToDTO(diff.Videos),
+		MissingMedia: slicesx.NonNil(diff.MissingMedia),
+	})
+}
+
+func catalogToDTO(bundle *admin.CatalogBundle) dto.CatalogBundle {
+	res := dto.CatalogBundle{
+		Version:      bundle.Version,
+		ExportedAt:   bundle.ExportedAt,
+		ContentTypes: make([]dto.CatalogContentType, len(bundle.ContentTypes)),
+		Categories:   make([]dto.CatalogCategory, len(bundle.Categories)),
+		Videos:       make([]dto.CatalogVideo, len(bundle.Videos)),
+	}
+
+	for i, contentType := range bundle.ContentTypes {
+		res.ContentTypes[i] = dto.CatalogContentType{Name: contentType.Name}
+	}
+
+	for i, category := range bundle.Categories {
+		res.Categories[i] = dto.CatalogCategory{
+			Name:         category.Name,
+			ImgURL:       category.ImgURL,
+			ContentTypes: slicesx.NonNil(category.ContentTypes),
+			Parent:       category.Parent,
+		}
+	}
+
+	for i, video := range bundle.Videos {
+		res.Videos[i] = dto.CatalogVideo{
+			URL:               video.URL,
+			Name:              video.Name,
+			Description:       video.Description,
+			ImgURL:            video.ImgURL,
+			Categories:        slicesx.NonNil(video.Categories),
+			Tags:              slicesx.NonNil(video.Tags),
+			NextUp:            slicesx.NonNil(video.NextUp),
+			Steps:             slicesx.NonNil(video.Details.Steps),
+			Contraindications: slicesx.NonNil(video.Details.Contraindications),
+			Equipment:         slicesx.NonNil(video.Details.Equipment),
+			Sets:              video.Details.Sets,
+			Reps:              video.Details.Reps,
+			Difficulty:        video.Details.Difficulty,
+		}
+	}
+
+	if bundle.Media != nil {
+		res.Media = &dto.CatalogMedia{
+			Videos: slicesx.NonNil(bundle.Media.Videos),
+			Images: slicesx.NonNil(bundle.Media.Images),
+		}
+	}
+
+	return res
+}
+
+func catalogFromDTO(req *dto.CatalogBundle) *admin.CatalogBundle {
+	bundle := &admin.CatalogBundle{
+		Version:      req.Version,
+		ExportedAt:   req.ExportedAt,
+		ContentTypes: make([]admin.CatalogContentType, len(req.ContentTypes)),
+		Categories:   make([]admin.CatalogCategory, len(req.Categories)),
+		Videos:       make([]admin.CatalogVideo, len(req.Videos)),
+	}
+
+	for i, contentType := range req.ContentTypes {
+		bundle.ContentTypes[i] = admin.CatalogContentType{Name: contentType.Name}
+	}
+
+	for i, category := range req.Categories {
+		bundle.Categories[i] = admin.CatalogCategory{
+			Name:         category.Name,
+			ImgURL:       category.ImgURL,
+			ContentTypes: category.ContentTypes,
+			Parent:       category.Parent,
+		}
+	}
+
+	for i, video := range req.Videos {
+		bundle.Videos[i] = admin.CatalogVideo{
+			URL:         video.URL,
+			Name:        video.Name,
+			Description: video.Description,
+			ImgURL:      video.ImgURL,
+			Categories:  video.Categories,
+			Tags:        video.Tags,
+			NextUp:      video.NextUp,
+			Details: admin.ExerciseDetails{
+				Steps:             video.Steps,
+				Contraindications: video.Contraindications,
+				Equipment:         video.Equipment,
+				Sets:              video.Sets,
+				Reps:              video.Reps,
+				Difficulty:        video.Difficulty,
+			},
+		}
+	}
+
+	if req.Media != nil {
+		bundle.Media = &admin.CatalogMedia{
+			Videos: req.Media.Videos,
+			Images: req.Media.Images,
+		}
+	}
+
+	return bundle
+}
+
+func importChangesToDTO(changes admin.ImportChanges) dto.ImportChangesResponse {
+	return dto.ImportChangesResponse{
+		Created:   slicesx.NonNil(changes.Created),
+		Updated:   slicesx.NonNil(changes.Updated),
+		Unchanged: slicesx.NonNil(changes.Unchanged),
+	}
+}
+
+// writeCatalogCSV записывает видео в CSV, списки объединяются через catalogCSVSeparator
+func writeCatalogCSV(w io.Writer, videos []admin.CatalogVideo) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(catalogCSVHeader); err != nil {
+		return err
+	}
+
+	for _, video := range videos {
+		record := []string{
+			video.URL,
+			video.Name,
+			video.Description,
+			video.ImgURL,
+			strings.Join(video.Categories, catalogCSVSeparator),
+			strings.Join(video.Tags, catalogCSVSeparator),
+			strings.Join(video.NextUp, catalogCSVSeparator),
+			strings.Join(video.Details.Steps, catalogCSVSeparator),
+			strings.Join(video.Details.Contraindications, catalogCSVSeparator),
+			strings.Join(video.Details.Equipment, catalogCSVSeparator),
+			strconv.Itoa(video.Details.Sets),
+			strconv.Itoa(video.Details.Reps),
+			video.Details.Difficulty,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// readCatalogCSV читает видео из CSV. Колонки определяются по заголовку, их порядок не важен.
+func readCatalogCSV(r io.Reader) ([]admin.CatalogVideo, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{"url", "name", "categories"} {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	videos := make([]admin.CatalogVideo, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			if i, exists := columns[column]; exists {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		list := func(column string) []string {
+			result := make([]string, 0)
+			for _, item := range strings.Split(value(column), catalogCSVSeparator) {
+				if item = strings.TrimSpace(item); item != "" {
+					result = append(result, item)
+				}
+			}
+			return result
+		}
+
+		number := func(column string) (int, error) {
+			if value(column) == "" {
+				return 0, nil
+			}
+			n, err := strconv.Atoi(value(column))
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid %s %q", line, column, value(column))
+			}
+			return n, nil
+		}
+
+		sets, err := number("sets")
+		if err != nil {
+			return nil, err
+		}
+
+		reps, err := number("reps")
+		if err != nil {
+			return nil, err
+		}
+
+		video := admin.CatalogVideo{
+			URL:         value("url"),
+			Name:        value("name"),
+			Description: value("description"),
+			ImgURL:      value("img_url"),
+			Categories:  list("categories"),
+			Tags:        list("tags"),
+			NextUp:      list("next_up"),
+			Details: admin.ExerciseDetails{
+				Steps:             list("steps"),
+				Contraindications: list("contraindications"),
+				Equipment:         list("equipment"),
+				Sets:              sets,
+				Reps:              reps,
+				Difficulty:        value("difficulty"),
+			},
+		}
+
+		if video.URL == "" {
+			return nil, fmt.Errorf("line %d: empty url", line)
+		}
+
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
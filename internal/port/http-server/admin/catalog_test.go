@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogCSV_RoundTrip(t *testing.T) {
+	videos := []admin.CatalogVideo{
+		{
+			URL:         "warmup.mp4",
+			Name:        "Разминка, часть 1",
+			Description: "Описание с \"кавычками\"",
+			ImgURL:      "warmup.jpg",
+			Categories:  []string{"Спина", "Шея"},
+			Tags:        []string{"утро"},
+			NextUp:      []string{"stretch.mp4"},
+			Details: admin.ExerciseDetails{
+				Steps:             []string{"Встаньте прямо", "Поднимите руки"},
+				Contraindications: []string{"Острая боль в спине"},
+				Equipment:         []string{},
+				Sets:              3,
+				Reps:              12,
+				Difficulty:        admin.DifficultyBeginner,
+			},
+		},
+		{
+			URL:        "stretch.mp4",
+			Name:       "Растяжка",
+			ImgURL:     "stretch.jpg",
+			Categories: []string{"Спина"},
+			Tags:       []string{},
+			NextUp:     []string{},
+			Details: admin.ExerciseDetails{
+				Steps:             []string{},
+				Contraindications: []string{},
+				Equipment:         []string{},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCatalogCSV(&buf, videos))
+
+	// Читаем обратно и сравниваем
+	got, err := readCatalogCSV(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, videos, got)
+}
+
+func TestReadCatalogCSV_ColumnOrder(t *testing.T) {
+	// Колонки могут идти в любом порядке, необязательные можно опустить
+	data := "categories,name,url\nСпина | Шея,Разминка,warmup.mp4\n"
+
+	got, err := readCatalogCSV(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	assert.Equal(t, "warmup.mp4", got[0].URL)
+	assert.Equal(t, "Разминка", got[0].Name)
+	assert.Equal(t, []string{"Спина", "Шея"}, got[0].Categories)
+	assert.Empty(t, got[0].Tags)
+	assert.Zero(t, got[0].Details.Sets)
+}
+
+func TestReadCatalogCSV_Errors(t *testing.T) {
+	// Нет обязательной колонки
+	_, err := readCatalogCSV(strings.NewReader("url,name\nwarmup.mp4,Разминка\n"))
+	assert.Error(t, err)
+
+	// Пустое имя файла
+	_, err = readCatalogCSV(strings.NewReader("url,name,categories\n,Разминка,Спина\n"))
+	assert.Error(t, err)
+
+	// Число подходов не число
+	_, err = readCatalogCSV(strings.NewReader("url,name,categories,sets\nwarmup.mp4,Разминка,Спина,три\n"))
+	assert.Error(t, err)
+}
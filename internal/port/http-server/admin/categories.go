@@ -44,6 +44,7 @@ func (h *Handler) addCategory(w http.ResponseWriter, r *http.Request) {
 	catReq := admin.Category{
 		Name:        req.Name,
 		ImgURL:      req.ImgURL,
+		ParentID:    req.ParentID,
 		ContentType: make([]admin.ContentType, len(req.TypeIDs)),
 	}
 
@@ -69,6 +70,15 @@ func (h *Handler) addCategory(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, admin.ErrSuspiciousContent):
 			dto.RespondWithError(w, http.StatusBadRequest, "Подозрительный контент в URL превью")
 			return
+		case errors.Is(err, admin.ErrImgNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Изображение превью не найдено на сервере, сначала загрузите его")
+			return
+		case errors.Is(err, admin.ErrParentNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Родительская категория не найдена")
+			return
+		case errors.Is(err, admin.ErrCategoryCycle):
+			dto.RespondWithError(w, http.StatusBadRequest, "Категорию нельзя вложить в саму себя или в её подкатегорию")
+			return
 		default:
 			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to add category")
 			return
@@ -79,6 +89,7 @@ func (h *Handler) addCategory(w http.ResponseWriter, r *http.Request) {
 		ID:          category.ID,
 		Name:        category.Name,
 		ImgURL:      category.ImgURL,
+		ParentID:    category.ParentID,
 		Types:       make([]dto.TypeResponse, len(category.ContentType)),
 		DateCreated: category.CreatedAt,
 	}
@@ -137,6 +148,7 @@ func (h *Handler) getCategory(w http.ResponseWriter, r *http.Request) {
 		ID:          category.ID,
 		Name:        category.Name,
 		ImgURL:      category.ImgURL,
+		ParentID:    category.ParentID,
 		Types:       make([]dto.TypeResponse, len(category.ContentType)),
 		DateCreated: category.CreatedAt,
 	}
@@ -180,6 +192,7 @@ func (h *Handler) getCategories(w http.ResponseWriter, r *http.Request) {
 			ID:          category.ID,
 			Name:        category.Name,
 			ImgURL:      category.ImgURL,
+			ParentID:    category.ParentID,
 			Types:       make([]dto.TypeResponse, len(category.ContentType)),
 			DateCreated: category.CreatedAt,
 		}
@@ -236,6 +249,7 @@ func (h *Handler) updateCategory(w http.ResponseWriter, r *http.Request) {
 		ID:          id,
 		Name:        req.Name,
 		ImgURL:      req.ImgURL,
+		ParentID:    req.ParentID,
 		ContentType: make([]admin.ContentType, len(req.TypeIDs)),
 	}
 	for i, typeID := range req.TypeIDs {
@@ -260,6 +274,15 @@ func (h *Handler) updateCategory(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, admin.ErrSuspiciousContent):
 			dto.RespondWithError(w, http.StatusBadRequest, "Подозрительный контент в URL превью")
 			return
+		case errors.Is(err, admin.ErrImgNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Изображение превью не найдено на сервере, сначала загрузите его")
+			return
+		case errors.Is(err, admin.ErrParentNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Родительская категория не найдена")
+			return
+		case errors.Is(err, admin.ErrCategoryCycle):
+			dto.RespondWithError(w, http.StatusBadRequest, "Категорию нельзя вложить в саму себя или в её подкатегорию")
+			return
 		case errors.Is(err, admin.ErrCategoryNotFound):
 			dto.RespondWithError(w, http.StatusNotFound, "Category not found")
 			return
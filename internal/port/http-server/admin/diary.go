@@ -0,0 +1,209 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Получить дневник пациента
+// @Description Возвращает записи дневника боли пациента по возрастанию даты с выполненными видео
+// @Tags Admin Diary
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param from query string false "Начало периода, YYYY-MM-DD"
+// @Param to query string false "Конец периода включительно, YYYY-MM-DD"
+// @Success 200 {array} dto.DiaryEntryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/users/{id}/diary [get]
+func (h *Handler) getUserDiary(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getUserDiary"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid user ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	filter, err := parseDiaryFilter(r.URL.Query())
+	if err != nil {
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверные параметры запроса", err.Error())
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	entries, err := h.service.GetUserDiary(ctx, id, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrUserInvalidID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		case errors.Is(err, admin.ErrDiaryInvalidRange):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный период", "from must not be after to")
+			return
+		case errors.Is(err, admin.ErrUserNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "User not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get diary")
+			return
+		}
+	}
+
+	res := make([]dto.DiaryEntryResponse, len(entries))
+	for i, e := range entries {
+		res[i] = dto.DiaryEntryResponse{
+			ID:              e.ID,
+			Date:            e.Date.Format(admin.DiaryDateLayout),
+			PainScore:       e.PainScore,
+			BodyArea:        e.BodyArea,
+			Notes:           e.Notes,
+			Videos:          make([]dto.DiaryVideoResponse, len(e.Videos)),
+			ContentTypeID:   e.ContentTypeID,
+			ContentTypeName: e.ContentTypeName,
+			CreatedAt:       e.CreatedAt,
+		}
+		for j, v := range e.Videos {
+			res[i].Videos[j] = dto.DiaryVideoResponse{ID: v.ID, Name: v.Name}
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Получить динамику дневника боли
+// @Description Возвращает среднюю оценку боли пациентов типа контента по дням, неделям или месяцам. Оценки сначала усредняются по каждому пациенту. По умолчанию — по неделям за последние 90 дней, не больше двух лет за запрос.
+// @Tags Admin Diary
+// @Produce json
+// @Param type query int true "ID типа контента"
+// @Param period query string false "Группировка: day, week или month"
+// @Param from query string false "Начало периода, YYYY-MM-DD"
+// @Param to query string false "Конец периода включительно, YYYY-MM-DD"
+// @Success 200 {object} dto.DiaryTrendResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/diary/trend [get]
+func (h *Handler) getDiaryTrend(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getDiaryTrend"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	filter, err := parseDiaryTrendFilter(r.URL.Query())
+	if err != nil {
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверные параметры запроса", err.Error())
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	points, err := h.service.GetDiaryTrend(ctx, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrTypeInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный тип контента")
+			return
+		case errors.Is(err, admin.ErrDiaryInvalidPeriod):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверная группировка", "period must be day, week or month")
+			return
+		case errors.Is(err, admin.ErrDiaryInvalidRange):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный период", "from must not be after to, range is limited to two years")
+			return
+		case errors.Is(err, admin.ErrTypeNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Content type not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get diary trend")
+			return
+		}
+	}
+
+	res := dto.DiaryTrendResponse{
+		ContentTypeID: filter.ContentTypeID,
+		Period:        filter.Period,
+		From:          filter.From.Format(admin.DiaryDateLayout),
+		To:            filter.To.Format(admin.DiaryDateLayout),
+		Points:        make([]dto.DiaryTrendPointResponse, len(points)),
+	}
+	for i, p := range points {
+		res.Points[i] = dto.DiaryTrendPointResponse{
+			PeriodStart: p.PeriodStart.Format(admin.DiaryDateLayout),
+			AvgScore:    p.AvgScore,
+			Entries:     p.Entries,
+			Patients:    p.Patients,
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func parseDiaryFilter(query url.Values) (*admin.DiaryFilter, error) {
+	from, err := parseDiaryDate(query, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parseDiaryDate(query, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	return &admin.DiaryFilter{From: from, To: to}, nil
+}
+
+func parseDiaryTrendFilter(query url.Values) (*admin.DiaryTrendFilter, error) {
+	typeID, err := strconv.ParseInt(query.Get("type"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("type must be a content type ID")
+	}
+
+	period, err := parseDiaryFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &admin.DiaryTrendFilter{
+		ContentTypeID: typeID,
+		From:          period.From,
+		To:            period.To,
+		Period:        query.Get("period"),
+	}, nil
+}
+
+// parseDiaryDate разбирает необязательную дату YYYY-MM-DD, пустое значение дает нулевую дату
+func parseDiaryDate(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(admin.DiaryDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date in YYYY-MM-DD format", name)
+	}
+	return t, nil
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiaryTrendFilter(t *testing.T) {
+	query := url.Values{
+		"type":   {"2"},
+		"period": {"month"},
+		"from":   {"2024-01-01"},
+		"to":     {"2024-03-31"},
+	}
+
+	filter, err := parseDiaryTrendFilter(query)
+	require.NoError(t, err)
+	assert.Equal(t, &admin.DiaryTrendFilter{
+		ContentTypeID: 2,
+		Period:        "month",
+		From:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	}, filter)
+}
+
+func TestParseDiaryTrendFilter_Defaults(t *testing.T) {
+	filter, err := parseDiaryTrendFilter(url.Values{"type": {"1"}})
+	require.NoError(t, err)
+
+	// Период и группировку по умолчанию подставляет сервис
+	assert.Equal(t, &admin.DiaryTrendFilter{ContentTypeID: 1}, filter)
+}
+
+func TestParseDiaryTrendFilter_Invalid(t *testing.T) {
+	for _, query := range []url.Values{
+		{},
+		{"type": {"abc"}},
+		{"type": {"1"}, "from": {"01.02.2024"}},
+		{"type": {"1"}, "to": {"2024-02-30"}},
+	} {
+		_, err := parseDiaryTrendFilter(query)
+		assert.Error(t, err, query.Encode())
+	}
+}
@@ -0,0 +1,278 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Создать документ
+// @Description Создает PDF-памятку по загруженному файлу и прикрепляет ее к видео и категориям
+// @Tags Admin Documents
+// @Accept json
+// @Produce json
+// @Param input body dto.DocumentRequest true "Данные документа"
+// @Success 201 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/documents [post]
+func (h *Handler) addDocument(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.addDocument"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	var req dto.DocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	id, err := h.service.AddDocument(ctx, documentFromDTO(0, &req))
+	if err != nil {
+		if respondDocumentValidationError(w, err) {
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to add document")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, dto.SuccessResponse{
+		ID:      id,
+		Message: "Document added successfully",
+	})
+}
+
+// @Summary Получить документ
+// @Description Возвращает PDF-памятку по ID
+// @Tags Admin Documents
+// @Produce json
+// @Param id path int true "ID документа"
+// @Success 200 {object} dto.DocumentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/documents/{id} [get]
+func (h *Handler) getDocument(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getDocument"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid document ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	document, err := h.service.GetDocument(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrDocumentNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Document not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get document")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, documentToDTO(document))
+}
+
+// @Summary Получить документы
+// @Description Возвращает все PDF-памятки с видео и категориями, к которым они прикреплены
+// @Tags Admin Documents
+// @Produce json
+// @Success 200 {array} dto.DocumentResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/documents [get]
+func (h *Handler) getDocuments(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getDocuments"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	documents, err := h.service.GetDocuments(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get documents")
+		return
+	}
+
+	res := make([]dto.DocumentResponse, len(documents))
+	for i := range documents {
+		res[i] = documentToDTO(&documents[i])
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Изменить документ
+// @Description Обновляет PDF-памятку и заменяет список видео и категорий
+// @Tags Admin Documents
+// @Accept json
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body dto.DocumentRequest true "Данные документа"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/documents/{id} [put]
+func (h *Handler) updateDocument(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.updateDocument"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid document ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+
+	var req dto.DocumentRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.UpdateDocument(ctx, documentFromDTO(id, &req))
+	if err != nil {
+		if respondDocumentValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, admin.ErrDocumentNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Document not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to update document")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Document updated successfully",
+	})
+}
+
+// @Summary Удалить документ
+// @Description Удаляет PDF-памятку и ее связи с видео и категориями. Файл остается на диске.
+// @Tags Admin Documents
+// @Produce json
+// @Param id path int true "ID документа"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/documents/{id} [delete]
+func (h *Handler) deleteDocument(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.deleteDocument"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid document ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.DeleteDocument(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrDocumentNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Document not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to delete document")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Document deleted successfully",
+	})
+}
+
+// respondDocumentValidationError отвечает 400 на ошибки проверки документа и возвращает true, если ответ отправлен
+func respondDocumentValidationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, admin.ErrDocumentEmptyTitle):
+		dto.RespondWithError(w, http.StatusBadRequest, "Введите название документа")
+	case errors.Is(err, admin.ErrDocumentEmptyURL):
+		dto.RespondWithError(w, http.StatusBadRequest, "Выберите PDF-файл документа")
+	case errors.Is(err, admin.ErrDocumentURLPattern):
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверный формат имени файла, нужен файл .pdf")
+	case errors.Is(err, admin.ErrDocumentSuspiciousPattern):
+		dto.RespondWithError(w, http.StatusBadRequest, "Подозрительный URL документа")
+	case errors.Is(err, admin.ErrDocumentFileNotFound):
+		dto.RespondWithError(w, http.StatusBadRequest, "PDF-файл не найден на сервере, сначала загрузите его")
+	case errors.Is(err, admin.ErrDocumentInvalidLink):
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверный список видео или категорий")
+	case errors.Is(err, admin.ErrVideoNotFound):
+		dto.RespondWithError(w, http.StatusBadRequest, "One or more videos not found")
+	case errors.Is(err, admin.ErrCategoryNotFound):
+		dto.RespondWithError(w, http.StatusBadRequest, "One or more categories not found")
+	default:
+		return false
+	}
+	return true
+}
+
+func documentFromDTO(id int64, req *dto.DocumentRequest) *admin.Document {
+	return &admin.Document{
+		ID:          id,
+		Title:       req.Title,
+		URL:         req.URL,
+		VideoIDs:    req.VideoIDs,
+		CategoryIDs: req.CategoryIDs,
+	}
+}
+
+func documentToDTO(document *admin.Document) dto.DocumentResponse {
+	return dto.DocumentResponse{
+		ID:          document.ID,
+		Title:       document.Title,
+		URL:         document.URL,
+		VideoIDs:    document.VideoIDs,
+		CategoryIDs: document.CategoryIDs,
+		CreatedAt:   document.CreatedAt,
+		UpdatedAt:   document.UpdatedAt,
+	}
+}
@@ -11,31 +11,219 @@ import (
 // VideoResponse представляет ответ с данными видео
 // swagger:model videoResponse
 type VideoResponse struct {
-	ID          int64              `json:"id"`          // ID видео; example: 1
-	URL         string             `json:"url"`         // URL видеофайла; example: https://example.com/video.mp4
-	Name        string             `json:"name"`        // Название видео; example: Утренняя йога
-	Description string             `json:"description"` // Описание видео; example: 30-минутный комплекс утренних упражнений
-	ImgURL      string             `json:"img_url"`     // URL превью изображения; example: https://example.com/preview.jpg
-	Categories  []CategoryResponse `json:"categories"`  // Список категорий видео
-	DateCreated string             `json:"created_at"`  // Дата создания; example: 02.01.2006
+	ID                int64                `json:"id"`                // ID видео; example: 1
+	URL               string               `json:"url"`               // URL видеофайла; example: https://example.com/video.mp4
+	Name              string               `json:"name"`              // Название видео; example: Утренняя йога
+	Description       string               `json:"description"`       // Описание видео; example: 30-минутный комплекс утренних упражнений
+	ImgURL            string               `json:"img_url"`           // URL превью изображения; example: https://example.com/preview.jpg
+	Categories        []CategoryResponse   `json:"categories"`        // Список категорий видео
+	Tags              []string             `json:"tags"`              // Теги видео; example: ["колено", "растяжка"]
+	NextVideoIDs      []int64              `json:"next_video_ids"`    // ID видео "смотреть далее" в порядке показа; example: [4, 7]
+	Steps             []string             `json:"steps"`             // Шаги выполнения по порядку; example: ["Лягте на спину", "Согните колени"]
+	Contraindications []string             `json:"contraindications"` // Противопоказания; example: ["Острая боль в пояснице"]
+	Equipment         []string             `json:"equipment"`         // Необходимый инвентарь; example: ["Коврик"]
+	Sets              int                  `json:"sets"`              // Рекомендуемое число подходов, 0 если не задано; example: 3
+	Reps              int                  `json:"reps"`              // Рекомендуемое число повторений, 0 если не задано; example: 12
+	Difficulty        string               `json:"difficulty"`        // Уровень сложности: beginner, intermediate, advanced или пусто; example: beginner
+	Rating            *VideoRatingResponse `json:"rating,omitempty"`  // Оценки пациентов, только в ответе на запрос одного видео
+	DateCreated       string               `json:"created_at"`        // Дата создания; example: 02.01.2006
+}
+
+// VideoRatingResponse представляет итог оценок видео пациентами
+// swagger:model videoRatingResponse
+type VideoRatingResponse struct {
+	Average      float64 `json:"average"`      // Средняя оценка, 0 если оценок нет; example: 4.5
+	Count        int     `json:"count"`        // Количество оценок; example: 12
+	Distribution []int   `json:"distribution"` // Количество оценок от 1 до 5; example: [0, 1, 1, 3, 7]
 }
 
 // VideoRequest представляет запрос для создания/обновления видео
 // swagger:model videoRequest
 type VideoRequest struct {
-	URL         string  `json:"url"`          // URL видеофайла; required: true; example: https://example.com/video.mp4
-	Name        string  `json:"name"`         // Название видео; required: true; example: Утренняя йога
-	Description string  `json:"description"`  // Описание видео; example: 30-минутный комплекс утренних упражнений
-	ImgURL      string  `json:"img_url"`      // URL превью изображения; example: https://example.com/preview.jpg
-	CategoryIDs []int64 `json:"category_ids"` // Список ID категорий; example: [1, 2, 3]
+	URL               string   `json:"url"`               // URL видеофайла; required: true; example: https://example.com/video.mp4
+	Name              string   `json:"name"`              // Название видео; required: true; example: Утренняя йога
+	Description       string   `json:"description"`       // Описание видео; example: 30-минутный комплекс утренних упражнений
+	ImgURL            string   `json:"img_url"`           // URL превью изображения; example: https://example.com/preview.jpg
+	CategoryIDs       []int64  `json:"category_ids"`      // Список ID категорий; example: [1, 2, 3]
+	Tags              []string `json:"tags"`              // Теги видео для подбора похожих; example: ["колено", "растяжка"]
+	NextVideoIDs      []int64  `json:"next_video_ids"`    // ID видео "смотреть далее" в порядке показа; example: [4, 7]
+	Steps             []string `json:"steps"`             // Шаги выполнения по порядку, до 30; example: ["Лягте на спину", "Согните колени"]
+	Contraindications []string `json:"contraindications"` // Противопоказания, до 20; example: ["Острая боль в пояснице"]
+	Equipment         []string `json:"equipment"`         // Необходимый инвентарь, до 20; example: ["Коврик"]
+	Sets              int      `json:"sets"`              // Рекомендуемое число подходов от 0 до 20, 0 если не задано; example: 3
+	Reps              int      `json:"reps"`              // Рекомендуемое число повторений от 0 до 100, 0 если не задано; example: 12
+	Difficulty        string   `json:"difficulty"`        // Уровень сложности: beginner, intermediate, advanced или пусто; example: beginner
 }
 
 // FileInfoResponse представляет информацию о файле
 // swagger:model fileInfo
 type FileInfoResponse struct {
-	Name    string    `json:"name"`     // Имя файла; example: video.mp4
-	Size    int64     `json:"size"`     // Размер файла в байтах; example: 1024000
-	ModTime time.Time `json:"mod_time"` // Время последнего изменения; example: 2023-01-01T12:00:00Z
+	Name       string              `json:"name"`        // Имя файла; example: video.mp4
+	Size       int64               `json:"size"`        // Размер файла в байтах; example: 1024000
+	ModTime    time.Time           `json:"mod_time"`    // Время последнего изменения; example: 2023-01-01T12:00:00Z
+	Checksum   string              `json:"sha256"`      // SHA-256 содержимого, пусто для файлов, загруженных до хранения по содержимому; example: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	UsageCount int                 `json:"usage_count"` // Количество ссылок на файл; example: 2
+	UsedBy     []FileUsageResponse `json:"used_by"`     // Видео и категории, использующие файл
+}
+
+// UploadResponse результат загрузки файла
+// swagger:model upload
+type UploadResponse struct {
+	Message    string   `json:"message"`    // Сообщение об успехе; example: Video neck.mp4 uploaded successfully
+	Checksum   string   `json:"sha256"`     // SHA-256 содержимого; example: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	Replaced   bool     `json:"replaced"`   // Под этим именем раньше был файл с другим содержимым
+	Duplicates []string `json:"duplicates"` // Другие файлы библиотеки с тем же содержимым
+}
+
+// RenameFileRequest новое имя файла
+// swagger:model renameFileRequest
+type RenameFileRequest struct {
+	Name string `json:"name"` // Новое имя файла; example: neck_stretch.mp4
+}
+
+// RenameFileResponse результат переименования файла
+// swagger:model renameFile
+type RenameFileResponse struct {
+	Message string              `json:"message"` // Сообщение об успехе; example: File neck.mp4 renamed to neck_stretch.mp4
+	Updated []FileUsageResponse `json:"updated"` // Видео, категории и объявления, в которых заменена ссылка
+}
+
+// FileInUseResponse ошибка удаления файла, на который есть ссылки
+// swagger:model fileInUse
+type FileInUseResponse struct {
+	Error  string              `json:"error"`   // Текст ошибки; example: File is in use
+	UsedBy []FileUsageResponse `json:"used_by"` // Видео, категории и объявления, использующие файл
+}
+
+// FileUsageResponse представляет ссылку на файл из видео или категории
+// swagger:model fileUsage
+type FileUsageResponse struct {
+	Entity string `json:"entity"` // Тип сущности: video, category, announcement или document; example: video
+	ID     int64  `json:"id"`     // ID сущности; example: 1
+	Name   string `json:"name"`   // Название сущности; example: Утренняя йога
+	Field  string `json:"field"`  // Поле со ссылкой: url или img_url; example: img_url
+}
+
+// FaststartResultResponse представляет результат оптимизации видеофайла
+// swagger:model faststartResult
+type FaststartResultResponse struct {
+	Name   string `json:"name"`            // Имя файла; example: video.mp4
+	Status string `json:"status"`          // Статус: optimized, already_optimized, skipped, failed; example: optimized
+	Error  string `json:"error,omitempty"` // Текст ошибки для статуса failed
+}
+
+// CatalogBundle представляет выгрузку каталога, связи задаются именами
+// swagger:model catalogBundle
+type CatalogBundle struct {
+	Version      int                  `json:"version"`         // Версия формата выгрузки; example: 1
+	ExportedAt   time.Time            `json:"exported_at"`     // Время выгрузки; example: 2023-01-01T12:00:00Z
+	ContentTypes []CatalogContentType `json:"content_types"`   // Типы контента
+	Categories   []CatalogCategory    `json:"categories"`      // Категории
+	Videos       []CatalogVideo       `json:"videos"`          // Видео
+	Media        *CatalogMedia        `json:"media,omitempty"` // Используемые медиафайлы
+}
+
+// CatalogContentType представляет тип контента в выгрузке
+// swagger:model catalogContentType
+type CatalogContentType struct {
+	Name string `json:"name"` // Название типа; example: Йога
+}
+
+// CatalogCategory представляет категорию в выгрузке
+// swagger:model catalogCategory
+type CatalogCategory struct {
+	Name         string   `json:"name"`          // Название категории; example: Спина
+	ImgURL       string   `json:"img_url"`       // Имя файла изображения; example: back.jpg
+	ContentTypes []string `json:"content_types"` // Названия типов контента
+	Parent       string   `json:"parent"`        // Название родительской категории, пустое для корневой; example: Колено
+}
+
+// CatalogVideo представляет видео в выгрузке
+// swagger:model catalogVideo
+type CatalogVideo struct {
+	URL               string   `json:"url"`               // Имя видеофайла; example: video.mp4
+	Name              string   `json:"name"`              // Название видео; example: Разминка
+	Description       string   `json:"description"`       // Описание видео
+	ImgURL            string   `json:"img_url"`           // Имя файла превью; example: preview.jpg
+	Categories        []string `json:"categories"`        // Названия категорий
+	Tags              []string `json:"tags"`              // Теги
+	NextUp            []string `json:"next_up"`           // Имена видеофайлов "смотреть далее"
+	Steps             []string `json:"steps"`             // Шаги выполнения по порядку; example: ["Лягте на спину", "Согните колени"]
+	Contraindications []string `json:"contraindications"` // Противопоказания; example: ["Острая боль в пояснице"]
+	Equipment         []string `json:"equipment"`         // Необходимый инвентарь; example: ["Коврик"]
+	Sets              int      `json:"sets"`              // Рекомендуемое число подходов, 0 если не задано; example: 3
+	Reps              int      `json:"reps"`              // Рекомендуемое число повторений, 0 если не задано; example: 12
+	Difficulty        string   `json:"difficulty"`        // Уровень сложности: beginner, intermediate, advanced или пусто; example: beginner
+}
+
+// CatalogMedia представляет список медиафайлов выгрузки
+// swagger:model catalogMedia
+type CatalogMedia struct {
+	Videos []string `json:"videos"` // Видеофайлы
+	Images []string `json:"images"` // Изображения
+}
+
+// ImportDiffResponse представляет результат импорта каталога
+// swagger:model importDiff
+type ImportDiffResponse struct {
+	DryRun       bool                  `json:"dry_run"`       // Изменения не сохранены
+	ContentTypes ImportChangesResponse `json:"content_types"` // Изменения типов контента
+	Categories   ImportChangesResponse `json:"categories"`    // Изменения категорий
+	Videos       ImportChangesResponse `json:"videos"`        // Изменения видео
+	MissingMedia []string              `json:"missing_media"` // Файлы выгрузки, отсутствующие на сервере
+}
+
+// ImportChangesResponse представляет изменения одного вида сущностей
+// swagger:model importChanges
+type ImportChangesResponse struct {
+	Created   []string `json:"created"`   // Созданные
+	Updated   []string `json:"updated"`   // Обновленные
+	Unchanged []string `json:"unchanged"` // Без изменений
+}
+
+// MediaAuditResponse представляет результат проверки медиабиблиотеки
+// swagger:model mediaAudit
+type MediaAuditResponse struct {
+	Orphans     []OrphanFileResponse      `json:"orphans"`     // Файлы без ссылок
+	Missing     []MissingFileResponse     `json:"missing"`     // Ссылки на отсутствующие файлы
+	Quarantined []QuarantinedFileResponse `json:"quarantined"` // Файлы в карантине
+}
+
+// OrphanFileResponse представляет файл, на который ничего не ссылается
+// swagger:model orphanFile
+type OrphanFileResponse struct {
+	Kind          string    `json:"kind"`            // Вид файла: video, image, document или subtitles; example: video
+	Name          string    `json:"name"`            // Имя файла; example: old.mp4
+	Size          int64     `json:"size"`            // Размер файла в байтах; example: 1024000
+	ModTime       time.Time `json:"mod_time"`        // Время последнего изменения; example: 2023-01-01T12:00:00Z
+	InGracePeriod bool      `json:"in_grace_period"` // Файл загружен недавно и не будет перенесен в карантин
+}
+
+// MissingFileResponse представляет ссылку на отсутствующий файл
+// swagger:model missingFile
+type MissingFileResponse struct {
+	Kind   string              `json:"kind"`    // Вид файла: video, image, document или subtitles; example: image
+	Name   string              `json:"name"`    // Имя файла; example: preview.jpg
+	UsedBy []FileUsageResponse `json:"used_by"` // Видео и категории, ссылающиеся на файл
+}
+
+// QuarantinedFileResponse представляет файл в карантине
+// swagger:model quarantinedFile
+type QuarantinedFileResponse struct {
+	Kind          string    `json:"kind"`           // Вид файла: video, image, document или subtitles; example: video
+	Name          string    `json:"name"`           // Имя файла; example: old.mp4
+	Size          int64     `json:"size"`           // Размер файла в байтах; example: 1024000
+	QuarantinedAt time.Time `json:"quarantined_at"` // Время переноса в карантин; example: 2023-01-01T12:00:00Z
+	DeleteAfter   time.Time `json:"delete_after"`   // Время, после которого файл будет удален; example: 2023-01-31T12:00:00Z
+}
+
+// CleanupResultResponse представляет действие над файлом при очистке медиабиблиотеки
+// swagger:model cleanupResult
+type CleanupResultResponse struct {
+	Kind   string `json:"kind"`            // Вид файла: video, image, document или subtitles; example: video
+	Name   string `json:"name"`            // Имя файла; example: old.mp4
+	Action string `json:"action"`          // Действие: quarantined, restored, deleted, failed; example: quarantined
+	Error  string `json:"error,omitempty"` // Текст ошибки для действия failed
 }
 
 // TypeRequest представляет запрос для создания/обновления типа
@@ -55,9 +243,10 @@ type TypeResponse struct {
 // CategoryRequest представляет запрос для создания/обновления категории
 // swagger:model categoryRequest
 type CategoryRequest struct {
-	Name    string  `json:"name"`     // Название категории; required: true; example: Утренние практики
-	ImgURL  string  `json:"img_url"`  // URL изображения категории; example: https://example.com/category.jpg
-	TypeIDs []int64 `json:"type_ids"` // Список ID типов; required: true; example: [1, 2]
+	Name     string  `json:"name"`      // Название категории; required: true; example: Утренние практики
+	ImgURL   string  `json:"img_url"`   // URL изображения категории; example: https://example.com/category.jpg
+	TypeIDs  []int64 `json:"type_ids"`  // Список ID типов; required: true; example: [1, 2]
+	ParentID int64   `json:"parent_id"` // ID родительской категории, 0 для корневой; example: 3
 }
 
 // CategoryResponse представляет ответ с данными категории
@@ -66,6 +255,7 @@ type CategoryResponse struct {
 	ID          int64          `json:"id"`                     // ID категории; example: 1
 	Name        string         `json:"name"`                   // Название категории; example: Утренние практики
 	ImgURL      string         `json:"img_url,omitempty"`      // URL изображения категории; example: https://example.com/category.jpg
+	ParentID    int64          `json:"parent_id"`              // ID родительской категории, 0 для корневой; example: 3
 	Types       []TypeResponse `json:"types,omitempty"`        // Список типов категории
 	DateCreated string         `json:"date_created,omitempty"` // Дата создания; example: 02.01.2006
 }
@@ -90,6 +280,239 @@ type UserResponse struct {
 	DateCreated   string `json:"date_created"`      // Дата создания; example: 02.01.2006
 }
 
+// FeedbackListResponse представляет страницу обратной связи
+// swagger:model feedbackListResponse
+type FeedbackListResponse struct {
+	Items  []FeedbackResponse `json:"items"`  // Сообщения на странице
+	Total  int                `json:"total"`  // Количество сообщений по фильтру; example: 42
+	Page   int                `json:"page"`   // Номер страницы; example: 1
+	Limit  int                `json:"limit"`  // Размер страницы; example: 20
+	Unread map[string]int     `json:"unread"` // Непрочитанные текущим администратором по статусам; example: {"new": 3, "in_progress": 0, "resolved": 0}
+}
+
+// FeedbackResponse представляет сообщение обратной связи
+// swagger:model feedbackResponse
+type FeedbackResponse struct {
+	ID           int64                  `json:"id"`                      // ID сообщения; example: 1
+	Username     string                 `json:"username"`                // Имя пользователя; example: Иван
+	Email        string                 `json:"email,omitempty"`         // Email пользователя; example: user@example.com
+	Telegram     string                 `json:"telegram,omitempty"`      // Telegram пользователя; example: @user
+	Message      string                 `json:"message"`                 // Текст сообщения
+	Status       string                 `json:"status"`                  // Статус: new, in_progress или resolved; example: new
+	AssigneeID   int64                  `json:"assignee_id"`             // ID ответственного администратора, 0 если не назначен; example: 1
+	AssigneeName string                 `json:"assignee_name,omitempty"` // Имя ответственного администратора; example: admin
+	Read         bool                   `json:"read"`                    // Прочитано текущим администратором
+	Notes        []FeedbackNoteResponse `json:"notes,omitempty"`         // Внутренние заметки, только при просмотре сообщения
+	CreatedAt    time.Time              `json:"created_at"`              // Время отправки; example: 2023-01-01T12:00:00Z
+	UpdatedAt    time.Time              `json:"updated_at"`              // Время последнего изменения; example: 2023-01-01T12:00:00Z
+}
+
+// FeedbackNoteResponse представляет внутреннюю заметку к обратной связи
+// swagger:model feedbackNoteResponse
+type FeedbackNoteResponse struct {
+	ID        int64     `json:"id"`         // ID заметки; example: 1
+	Author    string    `json:"author"`     // Имя администратора; example: admin
+	Text      string    `json:"text"`       // Текст заметки; example: Перезвонить в понедельник
+	CreatedAt time.Time `json:"created_at"` // Время создания; example: 2023-01-01T12:00:00Z
+}
+
+// FeedbackStatusRequest представляет запрос на изменение статуса обратной связи
+// swagger:model feedbackStatusRequest
+type FeedbackStatusRequest struct {
+	Status string `json:"status"` // Статус: new, in_progress или resolved; required: true; example: in_progress
+}
+
+// FeedbackAssigneeRequest представляет запрос на назначение ответственного администратора
+// swagger:model feedbackAssigneeRequest
+type FeedbackAssigneeRequest struct {
+	AssigneeID int64 `json:"assignee_id"` // ID администратора, 0 снимает назначение; example: 1
+}
+
+// FeedbackNoteRequest представляет запрос на добавление внутренней заметки
+// swagger:model feedbackNoteRequest
+type FeedbackNoteRequest struct {
+	Text string `json:"text"` // Текст заметки; required: true; example: Перезвонить в понедельник
+}
+
+// AnnouncementRequest представляет запрос на создание или изменение объявления
+// swagger:model announcementRequest
+type AnnouncementRequest struct {
+	Title    string     `json:"title"`     // Заголовок; required: true; example: График работы в праздники
+	Body     string     `json:"body"`      // Текст объявления
+	ImgURL   string     `json:"img_url"`   // Имя файла изображения, необязательно; example: holidays.jpg
+	TypeIDs  []int64    `json:"type_ids"`  // Аудитория по типам контента, пустой список — все типы; example: [1, 2]
+	StartsAt *time.Time `json:"starts_at"` // Начало показа, по умолчанию сейчас; example: 2023-12-25T00:00:00Z
+	EndsAt   *time.Time `json:"ends_at"`   // Окончание показа, пусто — без окончания; example: 2024-01-09T00:00:00Z
+}
+
+// AnnouncementResponse представляет объявление
+// swagger:model announcementResponse
+type AnnouncementResponse struct {
+	ID        int64          `json:"id"`                // ID объявления; example: 1
+	Title     string         `json:"title"`             // Заголовок; example: График работы в праздники
+	Body      string         `json:"body"`              // Текст объявления
+	ImgURL    string         `json:"img_url,omitempty"` // Имя файла изображения; example: holidays.jpg
+	Types     []TypeResponse `json:"types"`             // Аудитория, пустой список — все типы
+	StartsAt  time.Time      `json:"starts_at"`         // Начало показа; example: 2023-12-25T00:00:00Z
+	EndsAt    *time.Time     `json:"ends_at,omitempty"` // Окончание показа; example: 2024-01-09T00:00:00Z
+	Active    bool           `json:"active"`            // Показывается сейчас; example: true
+	CreatedAt time.Time      `json:"created_at"`        // Время создания; example: 2023-01-01T12:00:00Z
+	UpdatedAt time.Time      `json:"updated_at"`        // Время последнего изменения; example: 2023-01-01T12:00:00Z
+}
+
+// DocumentRequest представляет запрос на создание или изменение PDF-памятки
+// swagger:model documentRequest
+type DocumentRequest struct {
+	Title       string  `json:"title"`        // Название; required: true; example: Упражнения для колена
+	URL         string  `json:"url"`          // Имя загруженного PDF-файла; required: true; example: knee_exercises.pdf
+	VideoIDs    []int64 `json:"video_ids"`    // Видео, к которым прикреплен документ; example: [1, 4]
+	CategoryIDs []int64 `json:"category_ids"` // Категории, к которым прикреплен документ; example: [2]
+}
+
+// DocumentResponse представляет PDF-памятку
+// swagger:model documentResponse
+type DocumentResponse struct {
+	ID          int64     `json:"id"`           // ID документа; example: 1
+	Title       string    `json:"title"`        // Название; example: Упражнения для колена
+	URL         string    `json:"url"`          // Имя PDF-файла; example: knee_exercises.pdf
+	VideoIDs    []int64   `json:"video_ids"`    // Видео, к которым прикреплен документ; example: [1, 4]
+	CategoryIDs []int64   `json:"category_ids"` // Категории, к которым прикреплен документ; example: [2]
+	CreatedAt   time.Time `json:"created_at"`   // Время создания; example: 2023-01-01T12:00:00Z
+	UpdatedAt   time.Time `json:"updated_at"`   // Время последнего изменения; example: 2023-01-01T12:00:00Z
+}
+
+// SubtitleResponse представляет дорожку субтитров WebVTT к видео
+// swagger:model subtitleResponse
+type SubtitleResponse struct {
+	ID        int64     `json:"id"`         // ID дорожки; example: 1
+	VideoID   int64     `json:"video_id"`   // ID видео; example: 4
+	Language  string    `json:"language"`   // Код языка BCP 47; example: ru
+	Label     string    `json:"label"`      // Название дорожки в плеере; example: Русский
+	URL       string    `json:"url"`        // Имя файла субтитров; example: video4_ru.vtt
+	CreatedAt time.Time `json:"created_at"` // Время создания; example: 2023-01-01T12:00:00Z
+	UpdatedAt time.Time `json:"updated_at"` // Время последнего изменения; example: 2023-01-01T12:00:00Z
+}
+
+// VideoCommentResponse представляет комментарий пациента к видео
+// swagger:model videoCommentResponse
+type VideoCommentResponse struct {
+	ID              int64      `json:"id"`                // ID комментария; example: 1
+	VideoID         int64      `json:"video_id"`          // ID видео; example: 3
+	VideoName       string     `json:"video_name"`        // Название видео; example: Утренняя йога
+	Username        string     `json:"username"`          // Автор; example: patient1
+	ContentTypeID   int64      `json:"content_type_id"`   // Тип контента автора на момент отправки, 0 если тип удален; example: 1
+	ContentTypeName string     `json:"content_type_name"` // Название типа контента; example: Колено
+	Text            string     `json:"text"`              // Текст комментария; example: Не понял, как ставить стопу
+	Status          string     `json:"status"`            // Статус: pending, approved или rejected; example: pending
+	ModeratedBy     string     `json:"moderated_by"`      // Администратор, изменивший статус; example: admin
+	ModeratedAt     *time.Time `json:"moderated_at"`      // Время модерации; example: 2023-01-01T12:00:00Z
+	CreatedAt       time.Time  `json:"created_at"`        // Время отправки; example: 2023-01-01T12:00:00Z
+}
+
+// VideoCommentStatusRequest представляет запрос на модерацию комментария
+// swagger:model videoCommentStatusRequest
+type VideoCommentStatusRequest struct {
+	Status string `json:"status"` // Статус: approved, rejected или pending; required: true; example: approved
+}
+
+// DiaryEntryResponse представляет запись дневника боли пациента
+// swagger:model diaryEntryResponse
+type DiaryEntryResponse struct {
+	ID              int64                `json:"id"`                // ID записи; example: 1
+	Date            string               `json:"date"`              // Дата записи; example: 2024-03-01
+	PainScore       int                  `json:"pain_score"`        // Оценка боли от 0 до 10; example: 4
+	BodyArea        string               `json:"body_area"`         // Область тела; example: колено
+	Notes           string               `json:"notes"`             // Заметки пациента; example: Болит после лестницы
+	Videos          []DiaryVideoResponse `json:"videos"`            // Выполненные в этот день видео
+	ContentTypeID   int64                `json:"content_type_id"`   // Тип контента на момент записи, 0 если тип удален; example: 1
+	ContentTypeName string               `json:"content_type_name"` // Название типа контента; example: Колено
+	CreatedAt       time.Time            `json:"created_at"`        // Время отправки записи; example: 2024-03-01T20:00:00Z
+}
+
+// DiaryVideoResponse представляет видео в записи дневника
+// swagger:model diaryVideoResponse
+type DiaryVideoResponse struct {
+	ID   int64  `json:"id"`   // ID видео; example: 3
+	Name string `json:"name"` // Название видео; example: Утренняя йога
+}
+
+// DiaryTrendResponse представляет динамику боли по типу контента
+// swagger:model diaryTrendResponse
+type DiaryTrendResponse struct {
+	ContentTypeID int64                     `json:"content_type_id"` // ID типа контента; example: 1
+	Period        string                    `json:"period"`          // Группировка: day, week или month; example: week
+	From          string                    `json:"from"`            // Начало периода; example: 2024-01-01
+	To            string                    `json:"to"`              // Конец периода; example: 2024-03-31
+	Points        []DiaryTrendPointResponse `json:"points"`          // Точки динамики по возрастанию даты
+}
+
+// DiaryTrendPointResponse представляет среднюю оценку боли за период
+// swagger:model diaryTrendPointResponse
+type DiaryTrendPointResponse struct {
+	PeriodStart string  `json:"period_start"` // Начало периода; example: 2024-02-26
+	AvgScore    float64 `json:"avg_score"`    // Средняя по пациентам оценка боли; example: 4.2
+	Entries     int     `json:"entries"`      // Количество записей; example: 37
+	Patients    int     `json:"patients"`     // Количество пациентов с записями; example: 9
+}
+
+// AppConfigRequest представляет настройки мобильного приложения
+// swagger:model appConfigRequest
+type AppConfigRequest struct {
+	MinVersion      string          `json:"min_version"`      // Минимальная поддерживаемая версия, пусто — без ограничения; example: 1.4.0
+	LatestVersion   string          `json:"latest_version"`   // Последняя выпущенная версия; example: 1.6.2
+	ForceUpdate     bool            `json:"force_update"`     // Требовать обновления до latest_version; example: false
+	SupportEmail    string          `json:"support_email"`    // Email поддержки; example: support@example.com
+	SupportTelegram string          `json:"support_telegram"` // Telegram поддержки; example: @bodybalance
+	SupportPhone    string          `json:"support_phone"`    // Телефон поддержки; example: +7 900 000-00-00
+	Features        map[string]bool `json:"features"`         // Флаги функций: латиница в нижнем регистре, цифры и _; example: {"pain_diary": true}
+}
+
+// AppConfigResponse представляет сохраненные настройки мобильного приложения
+// swagger:model appConfigResponse
+type AppConfigResponse struct {
+	AppConfigRequest
+	UpdatedAt time.Time `json:"updated_at"` // Время последнего изменения; example: 2023-01-01T12:00:00Z
+}
+
+// WebhookRequest представляет запрос на создание или изменение подписки на события
+// swagger:model webhookRequest
+type WebhookRequest struct {
+	URL         string   `json:"url"`         // URL для POST запросов с событиями; required: true; example: https://example.com/hooks/bodybalance
+	Secret      string   `json:"secret"`      // Ключ HMAC подписи, пустой генерируется при создании и не меняется при изменении
+	Events      []string `json:"events"`      // События подписки, пустой список означает все события; example: ["video.created","video.updated"]
+	Description string   `json:"description"` // Описание подписки; example: Синхронизация с сайтом
+	Active      bool     `json:"active"`      // Подписка включена; example: true
+}
+
+// WebhookResponse представляет подписку на события
+// swagger:model webhookResponse
+type WebhookResponse struct {
+	ID          int64     `json:"id"`               // ID подписки; example: 1
+	URL         string    `json:"url"`              // URL подписчика; example: https://example.com/hooks/bodybalance
+	Secret      string    `json:"secret,omitempty"` // Ключ HMAC подписи, только при создании и просмотре подписки
+	Events      []string  `json:"events"`           // События подписки, пустой список означает все события; example: ["video.created"]
+	Description string    `json:"description"`      // Описание подписки; example: Синхронизация с сайтом
+	Active      bool      `json:"active"`           // Подписка включена; example: true
+	CreatedAt   time.Time `json:"created_at"`       // Время создания; example: 2023-01-01T12:00:00Z
+	UpdatedAt   time.Time `json:"updated_at"`       // Время последнего изменения; example: 2023-01-01T12:00:00Z
+}
+
+// WebhookDeliveryResponse представляет запись журнала доставки события
+// swagger:model webhookDeliveryResponse
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`                           // ID доставки; example: 1
+	WebhookID      int64           `json:"webhook_id"`                   // ID подписки; example: 1
+	Event          string          `json:"event"`                        // Событие; example: video.created
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"` // Тело запроса
+	Status         string          `json:"status"`                       // Статус: pending, success или failed; example: success
+	Attempts       int             `json:"attempts"`                     // Количество попыток; example: 1
+	LastStatusCode int             `json:"last_status_code,omitempty"`   // HTTP статус последней попытки; example: 200
+	LastError      string          `json:"last_error,omitempty"`         // Ошибка последней попытки
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`    // Время следующей попытки, только для pending
+	CreatedAt      time.Time       `json:"created_at"`                   // Время события; example: 2023-01-01T12:00:00Z
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`       // Время успешной доставки
+}
+
 // SignInRequest представляет запрос на аутентификацию
 // swagger:parameters signInRequest
 type SignInRequest struct {
@@ -0,0 +1,362 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Получить обратную связь
+// @Description Возвращает страницу сообщений обратной связи, новые первыми, и счетчики непрочитанного текущим администратором
+// @Tags Admin Feedback
+// @Produce json
+// @Param q query string false "Поиск по имени, email, telegram и тексту сообщения"
+// @Param status query string false "Статус: new, in_progress или resolved"
+// @Param assignee_id query int false "ID ответственного администратора"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Размер страницы, не больше 100" default(20)
+// @Success 200 {object} dto.FeedbackListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/feedback [get]
+func (h *Handler) listFeedback(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.listFeedback"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	filter, err := parseFeedbackFilter(r.URL.Query())
+	if err != nil {
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверные параметры запроса", err.Error())
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	list, err := h.service.ListFeedback(ctx, adminUsername(r.Context()), filter)
+	if err != nil {
+		if errors.Is(err, admin.ErrFeedbackInvalidStatus) {
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный статус", "status must be new, in_progress or resolved")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get feedback")
+		return
+	}
+
+	res := dto.FeedbackListResponse{
+		Items:  make([]dto.FeedbackResponse, len(list.Items)),
+		Total:  list.Total,
+		Page:   filter.Page,
+		Limit:  filter.Limit,
+		Unread: list.Unread,
+	}
+
+	for i := range list.Items {
+		res.Items[i] = feedbackToDTO(&list.Items[i])
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Получить сообщение обратной связи
+// @Description Возвращает сообщение с внутренними заметками и отмечает его прочитанным текущим администратором
+// @Tags Admin Feedback
+// @Produce json
+// @Param id path int true "ID сообщения"
+// @Success 200 {object} dto.FeedbackResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/feedback/{id} [get]
+func (h *Handler) getFeedback(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getFeedback"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid feedback ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid feedback ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	feedback, err := h.service.GetFeedback(ctx, id, adminUsername(r.Context()))
+	if err != nil {
+		if errors.Is(err, admin.ErrFeedbackNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Feedback not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get feedback")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, feedbackToDTO(feedback))
+}
+
+// @Summary Изменить статус обратной связи
+// @Description Меняет статус обработки сообщения
+// @Tags Admin Feedback
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сообщения"
+// @Param input body dto.FeedbackStatusRequest true "Новый статус"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/feedback/{id}/status [put]
+func (h *Handler) updateFeedbackStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.updateFeedbackStatus"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid feedback ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid feedback ID")
+		return
+	}
+
+	var req dto.FeedbackStatusRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.UpdateFeedbackStatus(ctx, id, req.Status)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrFeedbackInvalidStatus):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный статус", "status must be new, in_progress or resolved")
+			return
+		case errors.Is(err, admin.ErrFeedbackNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Feedback not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to update feedback status")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Feedback status updated successfully",
+	})
+}
+
+// @Summary Назначить ответственного
+// @Description Назначает сообщению ответственного администратора, assignee_id = 0 снимает назначение
+// @Tags Admin Feedback
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сообщения"
+// @Param input body dto.FeedbackAssigneeRequest true "Ответственный администратор"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/feedback/{id}/assignee [put]
+func (h *Handler) assignFeedback(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.assignFeedback"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid feedback ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid feedback ID")
+		return
+	}
+
+	var req dto.FeedbackAssigneeRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.AssignFeedback(ctx, id, req.AssigneeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrFeedbackAssigneeNotAdmin):
+			dto.RespondWithError(w, http.StatusBadRequest, "Ответственным можно назначить только администратора")
+			return
+		case errors.Is(err, admin.ErrFeedbackNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Feedback not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to assign feedback")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Feedback assigned successfully",
+	})
+}
+
+// @Summary Добавить заметку
+// @Description Добавляет к сообщению внутреннюю заметку от имени текущего администратора
+// @Tags Admin Feedback
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сообщения"
+// @Param input body dto.FeedbackNoteRequest true "Заметка"
+// @Success 201 {object} dto.FeedbackNoteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/feedback/{id}/notes [post]
+func (h *Handler) addFeedbackNote(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.addFeedbackNote"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid feedback ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid feedback ID")
+		return
+	}
+
+	var req dto.FeedbackNoteRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	note, err := h.service.AddFeedbackNote(ctx, id, adminUsername(r.Context()), req.Text)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrFeedbackEmptyNote):
+			dto.RespondWithError(w, http.StatusBadRequest, "Введите текст заметки")
+			return
+		case errors.Is(err, admin.ErrFeedbackNoteTooLong):
+			dto.RespondWithError(w, http.StatusBadRequest, "Слишком длинная заметка")
+			return
+		case errors.Is(err, admin.ErrFeedbackNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Feedback not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to add note")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, feedbackNoteToDTO(note))
+}
+
+// parseFeedbackFilter читает параметры поиска и страницы. Пустые значения заменяются значениями по умолчанию в сервисе.
+func parseFeedbackFilter(query url.Values) (*admin.FeedbackFilter, error) {
+	filter := &admin.FeedbackFilter{
+		Query:  query.Get("q"),
+		Status: query.Get("status"),
+	}
+
+	parseInt := func(name string) (int64, error) {
+		value := query.Get(name)
+		if value == "" {
+			return 0, nil
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s must be a non-negative integer", name)
+		}
+		return n, nil
+	}
+
+	page, err := parseInt("page")
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := parseInt("limit")
+	if err != nil {
+		return nil, err
+	}
+
+	filter.AssigneeID, err = parseInt("assignee_id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Page = int(page)
+	filter.Limit = int(limit)
+
+	return filter, nil
+}
+
+func feedbackToDTO(feedback *admin.Feedback) dto.FeedbackResponse {
+	res := dto.FeedbackResponse{
+		ID:           feedback.ID,
+		Username:     feedback.Username,
+		Email:        feedback.Email,
+		Telegram:     feedback.Telegram,
+		Message:      feedback.Message,
+		Status:       feedback.Status,
+		AssigneeID:   feedback.AssigneeID,
+		AssigneeName: feedback.AssigneeName,
+		Read:         feedback.Read,
+		CreatedAt:    feedback.CreatedAt,
+		UpdatedAt:    feedback.UpdatedAt,
+	}
+
+	if feedback.Notes != nil {
+		res.Notes = make([]dto.FeedbackNoteResponse, len(feedback.Notes))
+		for i := range feedback.Notes {
+			res.Notes[i] = feedbackNoteToDTO(&feedback.Notes[i])
+		}
+	}
+
+	return res
+}
+
+func feedbackNoteToDTO(note *admin.FeedbackNote) dto.FeedbackNoteResponse {
+	return dto.FeedbackNoteResponse{
+		ID:        note.ID,
+		Author:    note.Author,
+		Text:      note.Text,
+		CreatedAt: note.CreatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeedbackFilter(t *testing.T) {
+	query := url.Values{
+		"q":           {"колено"},
+		"status":      {"in_progress"},
+		"assignee_id": {"3"},
+		"page":        {"2"},
+		"limit":       {"50"},
+	}
+
+	filter, err := parseFeedbackFilter(query)
+	require.NoError(t, err)
+	assert.Equal(t, &admin.FeedbackFilter{
+		Query:      "колено",
+		Status:     "in_progress",
+		AssigneeID: 3,
+		Page:       2,
+		Limit:      50,
+	}, filter)
+}
+
+func TestParseFeedbackFilter_Defaults(t *testing.T) {
+	filter, err := parseFeedbackFilter(url.Values{})
+	require.NoError(t, err)
+
+	// Значения по умолчанию подставляет сервис
+	assert.Equal(t, &admin.FeedbackFilter{}, filter)
+}
+
+func TestParseFeedbackFilter_Invalid(t *testing.T) {
+	for _, query := range []url.Values{
+		{"page": {"abc"}},
+		{"limit": {"-1"}},
+		{"assignee_id": {"1.5"}},
+	} {
+		_, err := parseFeedbackFilter(query)
+		assert.Error(t, err, query.Encode())
+	}
+}
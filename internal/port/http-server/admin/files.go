@@ -9,20 +9,23 @@ import (
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
 	"github.com/theartofdevel/logging"
 	"net/http"
+	"strings"
 )
 
 const (
 	maxUploadSize      = 500 << 20 // 500 MB
 	maxImageUploadSize = 20 << 20  // 20 MB
+	maxDocUploadSize   = 20 << 20  // 20 MB
+	maxSubtitlesSize   = 2 << 20   // 2 MB
 )
 
 // @Summary Загрузить видеофайл
-// @Description Загружает видеофайл на сервер (макс. 500MB)
+// @Description Загружает видеофайл на сервер (макс. 500MB). Возвращает SHA-256 и другие файлы с тем же содержимым.
 // @Tags Admin Files
 // @Accept multipart/form-data
 // @Produce json
 // @Param video formData file true "Видеофайл для загрузки"
-// @Success 200 {object} dto.SuccessResponse "Видео успешно загружено"
+// @Success 200 {object} dto.UploadResponse "Видео успешно загружено"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security AdminAuth
@@ -54,7 +57,7 @@ func (h *Handler) uploadVideoHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := logging.ContextWithLogger(r.Context(), logger)
 
-	err = h.service.UploadFile(ctx, file, header)
+	saved, err := h.service.UploadFile(ctx, file, header)
 	if err != nil {
 		switch {
 		case errors.Is(err, admin.ErrFileTypeNotSupported):
@@ -69,18 +72,17 @@ func (h *Handler) uploadVideoHandler(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, admin.ErrFailedToSaveFile):
 			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save file")
 			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save file")
+			return
 		}
 	}
 
-	res := dto.SuccessResponse{
-		Message: fmt.Sprintf("Video %s uploaded successfully", header.Filename),
-	}
-
-	dto.RespondWithJSON(w, http.StatusOK, res)
+	dto.RespondWithJSON(w, http.StatusOK, uploadResponse("Video", saved))
 }
 
 // @Summary Получить список видеофайлов
-// @Description Возвращает список всех видеофайлов на сервере
+// @Description Возвращает список всех видеофайлов на сервере с видео, которые на них ссылаются
 // @Tags Admin Files
 // @Produce json
 // @Success 200 {array} dto.FileInfoResponse
@@ -111,9 +113,53 @@ func (h *Handler) listVideoFilesHandler(w http.ResponseWriter, r *http.Request)
 	res := make([]dto.FileInfoResponse, len(files))
 	for i, file := range files {
 		res[i] = dto.FileInfoResponse{
-			Name:    file.Name,
-			Size:    file.Size,
-			ModTime: file.ModTime,
+			Name:       file.Name,
+			Size:       file.Size,
+			ModTime:    file.ModTime,
+			Checksum:   file.Checksum,
+			UsageCount: len(file.UsedBy),
+			UsedBy:     fileUsageToDTO(file.UsedBy),
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Оптимизировать видеофайлы для быстрого старта
+// @Description Переносит moov атом в начало всех MP4/MOV файлов библиотеки без перекодирования
+// @Tags Admin Files
+// @Produce json
+// @Success 200 {array} dto.FaststartResultResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/video/faststart [post]
+func (h *Handler) optimizeVideoFilesHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.optimizeVideoFilesHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	results, err := h.service.OptimizeVideoFiles(ctx)
+	if err != nil {
+		if errors.Is(err, admin.ErrFileNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "No video files found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to optimize video files")
+		return
+	}
+
+	res := make([]dto.FaststartResultResponse, len(results))
+	for i, result := range results {
+		res[i] = dto.FaststartResultResponse{
+			Name:   result.Name,
+			Status: result.Status,
+			Error:  result.Error,
 		}
 	}
 
@@ -121,12 +167,12 @@ func (h *Handler) listVideoFilesHandler(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Загрузить изображение
-// @Description Загружает изображение на сервер (макс. 20MB)
+// @Description Загружает изображение на сервер (макс. 20MB). Из SVG удаляются скрипты и внешние ссылки, растровые изображения проверяются декодированием и ограничением размеров, из JPEG удаляются EXIF и другие метаданные. Возвращает SHA-256 и другие файлы с тем же содержимым.
 // @Tags Admin Files
 // @Accept multipart/form-data
 // @Produce json
 // @Param image formData file true "Изображение для загрузки"
-// @Success 200 {object} dto.SuccessResponse "Изображение успешно загружено"
+// @Success 200 {object} dto.UploadResponse "Изображение успешно загружено"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security AdminAuth
@@ -158,7 +204,7 @@ func (h *Handler) uploadImageHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := logging.ContextWithLogger(r.Context(), logger)
 
-	err = h.service.UploadImage(ctx, file, header)
+	saved, err := h.service.UploadImage(ctx, file, header)
 	if err != nil {
 		switch {
 		case errors.Is(err, admin.ErrInvalidFileName):
@@ -173,18 +219,26 @@ func (h *Handler) uploadImageHandler(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, admin.ErrFileTypeNotSupported):
 			dto.RespondWithError(w, http.StatusBadRequest, "Invalid image type. Only JPEG, PNG, GIF, SVG and WEBP are allowed")
 			return
+		case errors.Is(err, admin.ErrImageTooLarge):
+			dto.RespondWithError(w, http.StatusBadRequest, "Image dimensions exceed the allowed limits")
+			return
+		case errors.Is(err, admin.ErrImageInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Image is damaged or invalid")
+			return
+		case errors.Is(err, admin.ErrImageExtension):
+			dto.RespondWithError(w, http.StatusBadRequest, "Image extension does not match its content")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save image")
+			return
 		}
 	}
 
-	res := dto.SuccessResponse{
-		Message: fmt.Sprintf("Image %s uploaded successfully", header.Filename),
-	}
-
-	dto.RespondWithJSON(w, http.StatusOK, res)
+	dto.RespondWithJSON(w, http.StatusOK, uploadResponse("Image", saved))
 }
 
 // @Summary Получить список изображений
-// @Description Возвращает список всех изображений на сервере
+// @Description Возвращает список всех изображений на сервере с видео и категориями, которые на них ссылаются
 // @Tags Admin Files
 // @Produce json
 // @Success 200 {array} dto.FileInfoResponse
@@ -216,9 +270,246 @@ func (h *Handler) listImageFilesHandler(w http.ResponseWriter, r *http.Request)
 	res := make([]dto.FileInfoResponse, len(files))
 	for i, file := range files {
 		res[i] = dto.FileInfoResponse{
-			Name:    file.Name,
-			Size:    file.Size,
-			ModTime: file.ModTime,
+			Name:       file.Name,
+			Size:       file.Size,
+			ModTime:    file.ModTime,
+			Checksum:   file.Checksum,
+			UsageCount: len(file.UsedBy),
+			UsedBy:     fileUsageToDTO(file.UsedBy),
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Загрузить PDF-документ
+// @Description Загружает PDF-памятку на сервер (макс. 20MB). Возвращает SHA-256 и другие файлы с тем же содержимым.
+// @Tags Admin Files
+// @Accept multipart/form-data
+// @Produce json
+// @Param document formData file true "PDF-файл для загрузки"
+// @Success 200 {object} dto.UploadResponse "Документ успешно загружен"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/docs [post]
+func (h *Handler) uploadDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.uploadDocumentHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	// Ограничиваем размер файла
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocUploadSize)
+	if err := r.ParseMultipartForm(maxDocUploadSize); err != nil {
+		logger.Error("Document too large", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Document too large (max 20MB)")
+		return
+	}
+
+	// Получаем файл из запроса
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		logger.Error("Failed to get document from request", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid document upload")
+		return
+	}
+	defer file.Close()
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	saved, err := h.service.UploadDocument(ctx, file, header)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidFileName):
+			dto.RespondWithError(w, http.StatusBadRequest, "Имя файлов должны содержать только латинские буквы, цифры, дефисы и подчеркивания")
+			return
+		case errors.Is(err, admin.ErrFailedToReadFile):
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to read document")
+			return
+		case errors.Is(err, admin.ErrFailedToSaveFile):
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save document")
+			return
+		case errors.Is(err, admin.ErrFileTypeNotSupported):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid document type. Only PDF files with .pdf extension are allowed")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save document")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, uploadResponse("Document", saved))
+}
+
+// @Summary Получить список PDF-документов
+// @Description Возвращает список всех PDF-файлов на сервере с документами, которые на них ссылаются
+// @Tags Admin Files
+// @Produce json
+// @Success 200 {array} dto.FileInfoResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/docs [get]
+func (h *Handler) listDocumentFilesHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.listDocumentFilesHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	files, err := h.service.ListDocumentFiles(ctx)
+	if err != nil {
+		if errors.Is(err, admin.ErrFileNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "No document files found")
+			return
+		}
+		logger.Error("Failed to get documents list", sl.Err(err))
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get documents list")
+		return
+	}
+
+	res := make([]dto.FileInfoResponse, len(files))
+	for i, file := range files {
+		res[i] = dto.FileInfoResponse{
+			Name:       file.Name,
+			Size:       file.Size,
+			ModTime:    file.ModTime,
+			Checksum:   file.Checksum,
+			UsageCount: len(file.UsedBy),
+			UsedBy:     fileUsageToDTO(file.UsedBy),
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// uploadResponse описывает загруженный файл и другие файлы библиотеки с тем же содержимым
+func uploadResponse(kind string, saved *admin.SavedFile) dto.UploadResponse {
+	message := fmt.Sprintf("%s %s uploaded successfully", kind, saved.Name)
+	if len(saved.Duplicates) > 0 {
+		message += fmt.Sprintf(" (same content as %s)", strings.Join(saved.Duplicates, ", "))
+	}
+
+	return dto.UploadResponse{
+		Message:    message,
+		Checksum:   saved.Checksum,
+		Replaced:   saved.Replaced,
+		Duplicates: saved.Duplicates,
+	}
+}
+
+func fileUsageToDTO(usage []admin.FileUsage) []dto.FileUsageResponse {
+	res := make([]dto.FileUsageResponse, len(usage))
+	for i, u := range usage {
+		res[i] = dto.FileUsageResponse{
+			Entity: u.Entity,
+			ID:     u.ID,
+			Name:   u.Name,
+			Field:  u.Field,
+		}
+	}
+
+	return res
+}
+
+// @Summary Проверить медиабиблиотеку
+// @Description Сравнивает ссылки видео и категорий с файлами на диске: файлы без ссылок, ссылки на отсутствующие файлы и файлы в карантине
+// @Tags Admin Files
+// @Produce json
+// @Success 200 {object} dto.MediaAuditResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/audit [get]
+func (h *Handler) auditMediaHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.auditMediaHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	audit, err := h.service.AuditMedia(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to audit media library")
+		return
+	}
+
+	res := dto.MediaAuditResponse{
+		Orphans:     make([]dto.OrphanFileResponse, len(audit.Orphans)),
+		Missing:     make([]dto.MissingFileResponse, len(audit.Missing)),
+		Quarantined: make([]dto.QuarantinedFileResponse, len(audit.Quarantined)),
+	}
+
+	for i, file := range audit.Orphans {
+		res.Orphans[i] = dto.OrphanFileResponse{
+			Kind:          file.Kind,
+			Name:          file.Name,
+			Size:          file.Size,
+			ModTime:       file.ModTime,
+			InGracePeriod: file.InGracePeriod,
+		}
+	}
+
+	for i, file := range audit.Missing {
+		res.Missing[i] = dto.MissingFileResponse{
+			Kind:   file.Kind,
+			Name:   file.Name,
+			UsedBy: fileUsageToDTO(file.UsedBy),
+		}
+	}
+
+	for i, file := range audit.Quarantined {
+		res.Quarantined[i] = dto.QuarantinedFileResponse{
+			Kind:          file.Kind,
+			Name:          file.Name,
+			Size:          file.Size,
+			QuarantinedAt: file.QuarantinedAt,
+			DeleteAfter:   file.DeleteAfter,
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Очистить медиабиблиотеку
+// @Description Переносит файлы без ссылок в карантин, возвращает из карантина файлы, на которые снова ссылаются, и удаляет файлы с истекшим сроком хранения
+// @Tags Admin Files
+// @Produce json
+// @Success 200 {array} dto.CleanupResultResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/audit/cleanup [post]
+func (h *Handler) cleanupMediaHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.cleanupMediaHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	results, err := h.service.CleanupMedia(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to clean up media library")
+		return
+	}
+
+	res := make([]dto.CleanupResultResponse, len(results))
+	for i, result := range results {
+		res[i] = dto.CleanupResultResponse{
+			Kind:   result.Kind,
+			Name:   result.Name,
+			Action: result.Action,
+			Error:  result.Error,
 		}
 	}
 
@@ -0,0 +1,240 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Удалить видеофайл
+// @Description Удаляет видеофайл, если на него не ссылается ни одно видео. Иначе возвращает 409 со списком ссылок.
+// @Tags Admin Files
+// @Produce json
+// @Param filename path string true "Имя файла"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.FileInUseResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/video/{filename} [delete]
+func (h *Handler) deleteVideoFileHandler(w http.ResponseWriter, r *http.Request) {
+	h.deleteMediaFile(w, r, admin.MediaKindVideo, "admin.deleteVideoFileHandler")
+}
+
+// @Summary Удалить изображение
+// @Description Удаляет изображение, если на него не ссылаются видео, категории и объявления. Иначе возвращает 409 со списком ссылок.
+// @Tags Admin Files
+// @Produce json
+// @Param filename path string true "Имя файла"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.FileInUseResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/img/{filename} [delete]
+func (h *Handler) deleteImageFileHandler(w http.ResponseWriter, r *http.Request) {
+	h.deleteMediaFile(w, r, admin.MediaKindImage, "admin.deleteImageFileHandler")
+}
+
+func (h *Handler) deleteMediaFile(w http.ResponseWriter, r *http.Request, kind, op string) {
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+	filename := chi.URLParam(r, "filename")
+
+	usage, err := h.service.DeleteMediaFile(ctx, kind, filename)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidFileName):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid file name")
+		case errors.Is(err, admin.ErrFileNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "File not found")
+		case errors.Is(err, admin.ErrFileInUse):
+			dto.RespondWithJSON(w, http.StatusConflict, dto.FileInUseResponse{
+				Error:  "File is in use",
+				UsedBy: fileUsageToDTO(usage),
+			})
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to delete file")
+		}
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		Message: fmt.Sprintf("File %s deleted successfully", filename),
+	})
+}
+
+// @Summary Переименовать видеофайл
+// @Description Переименовывает видеофайл и в одной транзакции заменяет ссылки на него в видео
+// @Tags Admin Files
+// @Accept json
+// @Produce json
+// @Param filename path string true "Текущее имя файла"
+// @Param input body dto.RenameFileRequest true "Новое имя файла"
+// @Success 200 {object} dto.RenameFileResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/video/{filename}/rename [post]
+func (h *Handler) renameVideoFileHandler(w http.ResponseWriter, r *http.Request) {
+	h.renameMediaFile(w, r, admin.MediaKindVideo, "admin.renameVideoFileHandler")
+}
+
+// @Summary Переименовать изображение
+// @Description Переименовывает изображение и в одной транзакции заменяет ссылки на него в видео, категориях и объявлениях
+// @Tags Admin Files
+// @Accept json
+// @Produce json
+// @Param filename path string true "Текущее имя файла"
+// @Param input body dto.RenameFileRequest true "Новое имя файла"
+// @Success 200 {object} dto.RenameFileResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/img/{filename}/rename [post]
+func (h *Handler) renameImageFileHandler(w http.ResponseWriter, r *http.Request) {
+	h.renameMediaFile(w, r, admin.MediaKindImage, "admin.renameImageFileHandler")
+}
+
+func (h *Handler) renameMediaFile(w http.ResponseWriter, r *http.Request, kind, op string) {
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+	filename := chi.URLParam(r, "filename")
+
+	var req dto.RenameFileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	usage, err := h.service.RenameMediaFile(ctx, kind, filename, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidFileName):
+			dto.RespondWithError(w, http.StatusBadRequest, "Имя файлов должны содержать только латинские буквы, цифры, дефисы и подчеркивания")
+		case errors.Is(err, admin.ErrFileNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "File not found")
+		case errors.Is(err, admin.ErrFileAlreadyExists):
+			dto.RespondWithError(w, http.StatusConflict, "File with this name already exists")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to rename file")
+		}
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.RenameFileResponse{
+		Message: fmt.Sprintf("File %s renamed to %s", filename, req.Name),
+		Updated: fileUsageToDTO(usage),
+	})
+}
+
+// @Summary Заменить видеофайл
+// @Description Заменяет содержимое существующего видеофайла (макс. 500MB). Имя файла и ссылки на него не меняются.
+// @Tags Admin Files
+// @Accept multipart/form-data
+// @Produce json
+// @Param filename path string true "Имя заменяемого файла"
+// @Param video formData file true "Новый видеофайл"
+// @Success 200 {object} dto.UploadResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/video/{filename} [put]
+func (h *Handler) replaceVideoFileHandler(w http.ResponseWriter, r *http.Request) {
+	h.replaceMediaFile(w, r, "video", "Video", maxUploadSize, h.service.ReplaceVideoFile, "admin.replaceVideoFileHandler")
+}
+
+// @Summary Заменить изображение
+// @Description Заменяет содержимое существующего изображения (макс. 20MB). Имя файла и ссылки на него не меняются.
+// @Tags Admin Files
+// @Accept multipart/form-data
+// @Produce json
+// @Param filename path string true "Имя заменяемого файла"
+// @Param image formData file true "Новое изображение"
+// @Success 200 {object} dto.UploadResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/img/{filename} [put]
+func (h *Handler) replaceImageFileHandler(w http.ResponseWriter, r *http.Request) {
+	h.replaceMediaFile(w, r, "image", "Image", maxImageUploadSize, h.service.ReplaceImageFile, "admin.replaceImageFileHandler")
+}
+
+// replaceFunc заменяет содержимое файла библиотеки
+type replaceFunc func(ctx context.Context, filename string, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error)
+
+func (h *Handler) replaceMediaFile(w http.ResponseWriter, r *http.Request, field, kind string, maxSize int64, replace replaceFunc, op string) {
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		logger.Error("File too large", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", maxSize>>20))
+		return
+	}
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		logger.Error("Failed to get file from request", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid file upload")
+		return
+	}
+	defer file.Close()
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	saved, err := replace(ctx, chi.URLParam(r, "filename"), file, header)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidFileName):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid file name")
+		case errors.Is(err, admin.ErrFileNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "File not found")
+		case errors.Is(err, admin.ErrFileTypeNotSupported):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid file type")
+		case errors.Is(err, admin.ErrImageTooLarge):
+			dto.RespondWithError(w, http.StatusBadRequest, "Image dimensions exceed the allowed limits")
+		case errors.Is(err, admin.ErrImageInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Image is damaged or invalid")
+		case errors.Is(err, admin.ErrImageExtension):
+			dto.RespondWithError(w, http.StatusBadRequest, "Image extension does not match its content")
+		case errors.Is(err, admin.ErrFailedToReadFile):
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to read file")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save file")
+		}
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, uploadResponse(kind, saved))
+}
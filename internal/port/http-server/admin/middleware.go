@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
@@ -15,6 +17,15 @@ type Claims struct {
 	IsAdmin  bool   `json:"is_admin"`
 }
 
+// adminContextKey ключ контекста запроса с именем администратора из токена
+type adminContextKey struct{}
+
+// adminUsername возвращает имя администратора, запрос которого прошел AuthMiddleware
+func adminUsername(ctx context.Context) string {
+	username, _ := ctx.Value(adminContextKey{}).(string)
+	return username
+}
+
 // AuthMiddleware проверяет аутентификацию и права администратора
 // @security AdminAuth
 // @description Требуется JWT токен администратора в cookie с именем "token"
@@ -38,15 +49,8 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		// 3. Валидация токена
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(cookie.Value, claims, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(h.cfg.HTTPServer.SigningKey), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := parseAdminToken(cookie.Value, h.cfg.HTTPServer.SigningKey)
+		if err != nil {
 			h.logger.Warn("Invalid token", sl.Err(err))
 			dto.RespondWithError(w, http.StatusUnauthorized, "Invalid token")
 			return
@@ -99,10 +103,43 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
 			}
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminContextKey{}, claims.Username)))
 	})
 }
 
+// IsAdminRequest проверяет, что запрос несет cookie token с действительным токеном администратора.
+// Нужен для отдачи медиафайлов админке без подписанных ссылок.
+func IsAdminRequest(r *http.Request, signingKey string) bool {
+	cookie, err := r.Cookie("token")
+	if err != nil {
+		return false
+	}
+
+	claims, err := parseAdminToken(cookie.Value, signingKey)
+
+	return err == nil && claims.IsAdmin
+}
+
+// parseAdminToken проверяет подпись и срок токена из cookie и возвращает его данные
+func parseAdminToken(tokenString, signingKey string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(signingKey), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
 // SecurityHeadersMiddleware добавляет стандартные security headers ко всем ответам
 func (h *Handler) SecurityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -97,3 +97,39 @@ func TestAuthMiddleware_AdminOK(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.True(t, called)
 }
+
+func TestAuthMiddleware_AdminUsernameInContext(t *testing.T) {
+	h := &Handler{
+		logger: logdiscart.NewDiscardLogger(),
+		cfg:    &config.Config{HTTPServer: config.HTTPServer{SigningKey: "testkey"}},
+	}
+	token := makeJWTToken("testkey", true, "adminuser")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "token", Value: token})
+	w := httptest.NewRecorder()
+
+	var username string
+	h.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username = adminUsername(r.Context())
+	})).ServeHTTP(w, req)
+
+	// Обработчики получают имя администратора из токена
+	assert.Equal(t, "adminuser", username)
+}
+
+func TestIsAdminRequest(t *testing.T) {
+	request := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/video/a.mp4", nil)
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: "token", Value: token})
+		}
+		return req
+	}
+
+	assert.True(t, IsAdminRequest(request(makeJWTToken("testkey", true, "admin")), "testkey"))
+
+	// Без cookie, без прав администратора и с чужим ключом запрос не считается админским
+	assert.False(t, IsAdminRequest(request(""), "testkey"))
+	assert.False(t, IsAdminRequest(request(makeJWTToken("testkey", false, "user")), "testkey"))
+	assert.False(t, IsAdminRequest(request(makeJWTToken("otherkey", true, "admin")), "testkey"))
+}
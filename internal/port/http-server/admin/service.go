@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"io"
 	"mime/multipart"
 )
 
@@ -13,6 +14,10 @@ type Service interface {
 	GetVideos(ctx context.Context) ([]admin.Video, error)
 	UpdateVideo(ctx context.Context, req *admin.Video) error
 	DeleteVideo(ctx context.Context, id int64) error
+	// Subtitle methods
+	GetVideoSubtitles(ctx context.Context, videoID int64) ([]admin.Subtitle, error)
+	SaveVideoSubtitle(ctx context.Context, videoID int64, language, label string, file multipart.File) (*admin.Subtitle, error)
+	DeleteVideoSubtitle(ctx context.Context, videoID int64, language string) error
 	// User methods
 	AddUser(ctx context.Context, req *admin.Users) (*admin.Users, error)
 	GetUser(ctx context.Context, id int64) (*admin.Users, error)
@@ -34,8 +39,59 @@ type Service interface {
 	// Auth methods
 	Signing(ctx context.Context, login, password string) (*admin.Users, error)
 	// File methods
-	UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader) error
+	UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error)
 	ListVideoFiles(ctx context.Context) ([]admin.File, error)
-	UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader) error
+	OptimizeVideoFiles(ctx context.Context) ([]admin.FaststartResult, error)
+	UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error)
 	ListImageFiles(ctx context.Context) ([]admin.File, error)
+	UploadDocument(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error)
+	DeleteMediaFile(ctx context.Context, kind, filename string) ([]admin.FileUsage, error)
+	RenameMediaFile(ctx context.Context, kind, oldName, newName string) ([]admin.FileUsage, error)
+	ReplaceVideoFile(ctx context.Context, filename string, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error)
+	ReplaceImageFile(ctx context.Context, filename string, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error)
+	ListDocumentFiles(ctx context.Context) ([]admin.File, error)
+	CreateUpload(ctx context.Context, filename string, length int64) (*admin.Upload, error)
+	GetUpload(ctx context.Context, id string) (*admin.Upload, error)
+	WriteUpload(ctx context.Context, id string, offset int64, data io.Reader) (*admin.Upload, error)
+	DeleteUpload(ctx context.Context, id string) error
+	AuditMedia(ctx context.Context) (*admin.MediaAudit, error)
+	CleanupMedia(ctx context.Context) ([]admin.CleanupResult, error)
+	// Catalog methods
+	ExportCatalog(ctx context.Context, includeMedia bool) (*admin.CatalogBundle, error)
+	ImportCatalog(ctx context.Context, bundle *admin.CatalogBundle, dryRun bool) (*admin.ImportDiff, error)
+	// Feedback methods
+	ListFeedback(ctx context.Context, username string, filter *admin.FeedbackFilter) (*admin.FeedbackList, error)
+	GetFeedback(ctx context.Context, id int64, username string) (*admin.Feedback, error)
+	UpdateFeedbackStatus(ctx context.Context, id int64, status string) error
+	AssignFeedback(ctx context.Context, id, assigneeID int64) error
+	AddFeedbackNote(ctx context.Context, id int64, username, text string) (*admin.FeedbackNote, error)
+	// Announcement methods
+	AddAnnouncement(ctx context.Context, req *admin.Announcement) (int64, error)
+	GetAnnouncement(ctx context.Context, id int64) (*admin.Announcement, error)
+	GetAnnouncements(ctx context.Context) ([]admin.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, req *admin.Announcement) error
+	DeleteAnnouncement(ctx context.Context, id int64) error
+	// Document methods
+	AddDocument(ctx context.Context, req *admin.Document) (int64, error)
+	GetDocument(ctx context.Context, id int64) (*admin.Document, error)
+	GetDocuments(ctx context.Context) ([]admin.Document, error)
+	UpdateDocument(ctx context.Context, req *admin.Document) error
+	DeleteDocument(ctx context.Context, id int64) error
+	// Pain diary methods
+	GetUserDiary(ctx context.Context, userID int64, filter *admin.DiaryFilter) ([]admin.DiaryEntry, error)
+	GetDiaryTrend(ctx context.Context, filter *admin.DiaryTrendFilter) ([]admin.DiaryTrendPoint, error)
+	// Video comment methods
+	GetVideoComments(ctx context.Context, status string) ([]admin.VideoComment, error)
+	ModerateVideoComment(ctx context.Context, id int64, status, moderator string) error
+	// App config methods
+	GetAppConfig(ctx context.Context) (*admin.AppConfig, error)
+	UpdateAppConfig(ctx context.Context, cfg *admin.AppConfig) error
+	// Webhook methods
+	AddWebhook(ctx context.Context, req *admin.Webhook) (*admin.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*admin.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]admin.Webhook, error)
+	UpdateWebhook(ctx context.Context, req *admin.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	GetWebhookDeliveries(ctx context.Context, webhookID int64) ([]admin.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, deliveryID int64) (int64, error)
 }
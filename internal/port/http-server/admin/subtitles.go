@@ -0,0 +1,187 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Получить субтитры видео
+// @Description Возвращает дорожки субтитров WebVTT видео, по одной на язык
+// @Tags Admin Videos
+// @Produce json
+// @Param id path int true "ID видео"
+// @Success 200 {array} dto.SubtitleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/video/{id}/subtitles [get]
+func (h *Handler) getVideoSubtitles(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getVideoSubtitles"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid video ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid video ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	subtitles, err := h.service.GetVideoSubtitles(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrVideoNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Video not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get subtitles")
+		return
+	}
+
+	res := make([]dto.SubtitleResponse, len(subtitles))
+	for i := range subtitles {
+		res[i] = subtitleToDTO(&subtitles[i])
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Загрузить субтитры видео
+// @Description Загружает файл WebVTT (макс. 2MB) как дорожку субтитров видео на указанном языке. Дорожка на том же языке заменяется. Файл проверяется: заголовок WEBVTT, время и порядок реплик.
+// @Tags Admin Videos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID видео"
+// @Param subtitles formData file true "Файл WebVTT"
+// @Param language formData string true "Код языка BCP 47, например ru или en-US"
+// @Param label formData string false "Название дорожки в плеере, по умолчанию код языка"
+// @Success 200 {object} dto.SubtitleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/video/{id}/subtitles [post]
+func (h *Handler) uploadVideoSubtitle(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.uploadVideoSubtitle"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid video ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid video ID")
+		return
+	}
+
+	// Ограничиваем размер файла
+	r.Body = http.MaxBytesReader(w, r.Body, maxSubtitlesSize)
+	if err = r.ParseMultipartForm(maxSubtitlesSize); err != nil {
+		logger.Error("Subtitles too large", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Subtitles too large (max 2MB)")
+		return
+	}
+
+	file, _, err := r.FormFile("subtitles")
+	if err != nil {
+		logger.Error("Failed to get subtitles from request", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid subtitles upload")
+		return
+	}
+	defer file.Close()
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	sub, err := h.service.SaveVideoSubtitle(ctx, id, r.FormValue("language"), r.FormValue("label"), file)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrSubtitleInvalidLanguage):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid language code, expected BCP 47 tag like ru or en-US")
+		case errors.Is(err, admin.ErrSubtitleInvalidLabel):
+			dto.RespondWithError(w, http.StatusBadRequest, "Subtitle label is too long (max 64 characters)")
+		case errors.Is(err, admin.ErrSubtitleInvalidFile):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid WebVTT file", err.Error())
+		case errors.Is(err, admin.ErrVideoNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Video not found")
+		case errors.Is(err, admin.ErrFailedToReadFile):
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to read subtitles")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save subtitles")
+		}
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, subtitleToDTO(sub))
+}
+
+// @Summary Удалить субтитры видео
+// @Description Удаляет дорожку субтитров видео на указанном языке вместе с файлом
+// @Tags Admin Videos
+// @Produce json
+// @Param id path int true "ID видео"
+// @Param language path string true "Код языка"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/video/{id}/subtitles/{language} [delete]
+func (h *Handler) deleteVideoSubtitle(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.deleteVideoSubtitle"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid video ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid video ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.DeleteVideoSubtitle(ctx, id, chi.URLParam(r, "language"))
+	if err != nil {
+		if errors.Is(err, admin.ErrSubtitleNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Subtitle track not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to delete subtitles")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Subtitles deleted successfully",
+	})
+}
+
+func subtitleToDTO(sub *admin.Subtitle) dto.SubtitleResponse {
+	return dto.SubtitleResponse{
+		ID:        sub.ID,
+		VideoID:   sub.VideoID,
+		Language:  sub.Language,
+		Label:     sub.Label,
+		URL:       sub.URL,
+		CreatedAt: sub.CreatedAt,
+		UpdatedAt: sub.UpdatedAt,
+	}
+}
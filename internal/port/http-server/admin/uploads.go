@@ -0,0 +1,307 @@
+package admin
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/theartofdevel/logging"
+)
+
+// Протокол tus 1.0: https://tus.io/protocols/resumable-upload
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,expiration,termination"
+	tusContentType = "application/offset+octet-stream"
+)
+
+// @Summary Возможности загрузки по частям
+// @Description Возвращает версию протокола tus, поддерживаемые расширения и максимальный размер файла
+// @Tags Admin Files
+// @Success 204
+// @Security AdminAuth
+// @Router /admin/files/uploads [options]
+func (h *Handler) tusOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.cfg.Media.UploadMaxSize, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Начать загрузку видео по частям
+// @Description Создает загрузку по протоколу tus 1.0. Имя файла передается в Upload-Metadata (ключ filename или name, значение в base64), размер — в Upload-Length. Адрес загрузки возвращается в заголовке Location.
+// @Tags Admin Files
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Param Upload-Length header int true "Размер файла в байтах"
+// @Param Upload-Metadata header string true "Метаданные, например: filename bmVjay5tcDQ="
+// @Success 201
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/uploads [post]
+func (h *Handler) createUploadHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.createUploadHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	if !checkTusResumable(w, r) {
+		return
+	}
+
+	if r.Header.Get("Upload-Defer-Length") != "" {
+		dto.RespondWithError(w, http.StatusBadRequest, "Размер файла должен быть известен при создании загрузки")
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		dto.RespondWithError(w, http.StatusBadRequest, "Некорректный заголовок Upload-Length")
+		return
+	}
+
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		dto.RespondWithError(w, http.StatusBadRequest, "Некорректный заголовок Upload-Metadata")
+		return
+	}
+
+	filename := metadata["filename"]
+	if filename == "" {
+		filename = metadata["name"]
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	upload, err := h.service.CreateUpload(ctx, filename, length)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidFileName):
+			dto.RespondWithError(w, http.StatusBadRequest, "Имя файлов должны содержать только латинские буквы, цифры, дефисы и подчеркивания")
+		case errors.Is(err, admin.ErrUploadInvalidLength):
+			dto.RespondWithError(w, http.StatusBadRequest, "Размер файла должен быть больше нуля")
+		case errors.Is(err, admin.ErrUploadTooLarge):
+			dto.RespondWithError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("File too large (max %d bytes)", h.cfg.Media.UploadMaxSize))
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to create upload")
+		}
+		return
+	}
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+upload.ID)
+	w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Состояние загрузки
+// @Description Возвращает в заголовке Upload-Offset, сколько байт уже принято, чтобы клиент продолжил загрузку с этого места
+// @Tags Admin Files
+// @Param id path string true "ID загрузки"
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Success 200
+// @Failure 404
+// @Failure 421
+// @Security AdminAuth
+// @Router /admin/files/uploads/{id} [head]
+func (h *Handler) headUploadHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.headUploadHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Cache-Control", "no-store")
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	upload, err := h.service.GetUpload(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		// Ответ на HEAD не содержит тела
+		if errors.Is(err, admin.ErrUploadNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, admin.ErrUploadWrongNode) {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Передать часть файла
+// @Description Дописывает тело запроса в загрузку с позиции Upload-Offset. После последнего байта файл проверяется как при обычной загрузке видео и появляется в библиотеке.
+// @Tags Admin Files
+// @Accept application/offset+octet-stream
+// @Param id path string true "ID загрузки"
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Param Upload-Offset header int true "Смещение, с которого передаются данные"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 421 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 415 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/uploads/{id} [patch]
+func (h *Handler) patchUploadHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.patchUploadHandler"
+
+	id := chi.URLParam(r, "id")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"id", id,
+	)
+
+	if !checkTusResumable(w, r) {
+		return
+	}
+
+	if r.Header.Get("Content-Type") != tusContentType {
+		dto.RespondWithError(w, http.StatusUnsupportedMediaType, "Content-Type должен быть "+tusContentType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		dto.RespondWithError(w, http.StatusBadRequest, "Некорректный заголовок Upload-Offset")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	upload, err := h.service.WriteUpload(ctx, id, offset, r.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrUploadNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Upload not found")
+		case errors.Is(err, admin.ErrUploadWrongNode):
+			dto.RespondWithError(w, http.StatusMisdirectedRequest, "Upload belongs to another instance")
+		case errors.Is(err, admin.ErrUploadOffsetMismatch):
+			w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+			dto.RespondWithError(w, http.StatusConflict, "Upload-Offset не совпадает с принятым размером файла")
+		case errors.Is(err, admin.ErrUploadLocked):
+			dto.RespondWithError(w, http.StatusLocked, "Upload is locked by another request")
+		case errors.Is(err, admin.ErrFileTypeNotSupported):
+			dto.RespondWithError(w, http.StatusBadRequest, "Invalid file type. Only video files are allowed")
+		case errors.Is(err, admin.ErrFailedToReadFile):
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to read file")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save file")
+		}
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	if !upload.Completed {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Отменить загрузку
+// @Description Удаляет незавершенную загрузку и принятые данные
+// @Tags Admin Files
+// @Param id path string true "ID загрузки"
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 421 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security AdminAuth
+// @Router /admin/files/uploads/{id} [delete]
+func (h *Handler) deleteUploadHandler(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.deleteUploadHandler"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	if !checkTusResumable(w, r) {
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err := h.service.DeleteUpload(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrUploadNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Upload not found")
+		case errors.Is(err, admin.ErrUploadWrongNode):
+			dto.RespondWithError(w, http.StatusMisdirectedRequest, "Upload belongs to another instance")
+		case errors.Is(err, admin.ErrUploadLocked):
+			dto.RespondWithError(w, http.StatusLocked, "Upload is locked by another request")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to delete upload")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkTusResumable проставляет Tus-Resumable в ответ и проверяет версию протокола клиента
+func checkTusResumable(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Tus-Resumable", tusVersion)
+
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		dto.RespondWithError(w, http.StatusPreconditionFailed, "Unsupported tus version")
+		return false
+	}
+
+	return true
+}
+
+// parseUploadMetadata разбирает Upload-Metadata: пары "ключ значение_в_base64" через запятую, значение может отсутствовать
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, fmt.Errorf("empty metadata key")
+		}
+
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata value for %q: %w", key, err)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	// Так метаданные передает tus-js-client: значения в base64, ключ без значения допустим
+	metadata, err := parseUploadMetadata("filename bmVjay5tcDQ=,filetype dmlkZW8vbXA0, is_confidential")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"filename":        "neck.mp4",
+		"filetype":        "video/mp4",
+		"is_confidential": "",
+	}, metadata)
+
+	metadata, err = parseUploadMetadata("")
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+}
+
+func TestParseUploadMetadata_Invalid(t *testing.T) {
+	for _, header := range []string{
+		"filename neck.mp4",
+		",filename bmVjay5tcDQ=",
+	} {
+		_, err := parseUploadMetadata(header)
+		assert.Error(t, err, header)
+	}
+}
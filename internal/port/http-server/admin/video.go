@@ -11,6 +11,7 @@ import (
 	"github.com/langowen/bodybalance-backend/internal/entities/admin"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/langowen/bodybalance-backend/pkg/lib/slicesx"
 	"github.com/theartofdevel/logging"
 )
 
@@ -41,11 +42,21 @@ func (h *Handler) addVideo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	video := &admin.Video{
-		Name:        req.Name,
-		URL:         req.URL,
-		ImgURL:      req.ImgURL,
-		Description: req.Description,
-		Categories:  make([]admin.Category, len(req.CategoryIDs)),
+		Name:         req.Name,
+		URL:          req.URL,
+		ImgURL:       req.ImgURL,
+		Description:  req.Description,
+		Categories:   make([]admin.Category, len(req.CategoryIDs)),
+		Tags:         req.Tags,
+		NextVideoIDs: req.NextVideoIDs,
+		Details: admin.ExerciseDetails{
+			Steps:             req.Steps,
+			Contraindications: req.Contraindications,
+			Equipment:         req.Equipment,
+			Sets:              req.Sets,
+			Reps:              req.Reps,
+			Difficulty:        req.Difficulty,
+		},
 	}
 	for i, catID := range req.CategoryIDs {
 		video.Categories[i] = admin.Category{
@@ -82,6 +93,36 @@ func (h *Handler) addVideo(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, admin.ErrVideoImgSuspiciousPattern):
 			dto.RespondWithError(w, http.StatusBadRequest, "Подозрительный URL изображения видео")
 			return
+		case errors.Is(err, admin.ErrVideoInvalidTag):
+			dto.RespondWithError(w, http.StatusBadRequest, "Теги могут содержать только буквы, цифры, пробелы, дефисы и подчеркивания (до 50 символов)")
+			return
+		case errors.Is(err, admin.ErrVideoNextUpInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный список видео \"смотреть далее\"")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidSteps):
+			dto.RespondWithError(w, http.StatusBadRequest, "Не более 30 шагов, каждый до 500 символов")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidContraindications):
+			dto.RespondWithError(w, http.StatusBadRequest, "Не более 20 противопоказаний, каждое до 200 символов")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidEquipment):
+			dto.RespondWithError(w, http.StatusBadRequest, "Не более 20 позиций инвентаря, каждая до 200 символов")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidDosage):
+			dto.RespondWithError(w, http.StatusBadRequest, "Число подходов должно быть от 0 до 20, повторений от 0 до 100")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidDifficulty):
+			dto.RespondWithError(w, http.StatusBadRequest, "Уровень сложности: beginner, intermediate или advanced")
+			return
+		case errors.Is(err, admin.ErrVideoFileNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Видеофайл не найден на сервере, сначала загрузите его")
+			return
+		case errors.Is(err, admin.ErrVideoImgNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Изображение превью не найдено на сервере, сначала загрузите его")
+			return
+		case errors.Is(err, admin.ErrVideoNextUpNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "One or more next up videos not found")
+			return
 		case errors.Is(err, admin.ErrVideoSaveFailed):
 			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to save video")
 			return
@@ -100,7 +141,7 @@ func (h *Handler) addVideo(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Получить видео по ID
-// @Description Возвращает информацию о конкретном видео
+// @Description Возвращает информацию о конкретном видео вместе с итогом оценок пациентов
 // @Tags Admin Videos
 // @Produce json
 // @Param id path int true "ID видео"
@@ -144,12 +185,25 @@ func (h *Handler) getVideo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	res := dto.VideoResponse{
-		ID:          video.ID,
-		Name:        video.Name,
-		URL:         video.URL,
-		ImgURL:      video.ImgURL,
-		Description: video.Description,
-		Categories:  make([]dto.CategoryResponse, len(video.Categories)),
+		ID:                video.ID,
+		Name:              video.Name,
+		URL:               video.URL,
+		ImgURL:            video.ImgURL,
+		Description:       video.Description,
+		Categories:        make([]dto.CategoryResponse, len(video.Categories)),
+		Tags:              video.Tags,
+		NextVideoIDs:      video.NextVideoIDs,
+		Steps:             slicesx.NonNil(video.Details.Steps),
+		Contraindications: slicesx.NonNil(video.Details.Contraindications),
+		Equipment:         slicesx.NonNil(video.Details.Equipment),
+		Sets:              video.Details.Sets,
+		Reps:              video.Details.Reps,
+		Difficulty:        video.Details.Difficulty,
+		Rating: &dto.VideoRatingResponse{
+			Average:      video.Rating.Average,
+			Count:        video.Rating.Count,
+			Distribution: video.Rating.Distribution[:],
+		},
 	}
 	for i, cat := range video.Categories {
 		res.Categories[i] = dto.CategoryResponse{
@@ -194,13 +248,21 @@ func (h *Handler) getVideos(w http.ResponseWriter, r *http.Request) {
 	res := make([]dto.VideoResponse, len(videos))
 	for i, video := range videos {
 		res[i] = dto.VideoResponse{
-			ID:          video.ID,
-			Name:        video.Name,
-			URL:         video.URL,
-			ImgURL:      video.ImgURL,
-			Description: video.Description,
-			Categories:  make([]dto.CategoryResponse, len(video.Categories)),
-			DateCreated: video.DateCreated,
+			ID:                video.ID,
+			Name:              video.Name,
+			URL:               video.URL,
+			ImgURL:            video.ImgURL,
+			Description:       video.Description,
+			Categories:        make([]dto.CategoryResponse, len(video.Categories)),
+			Tags:              video.Tags,
+			NextVideoIDs:      video.NextVideoIDs,
+			Steps:             slicesx.NonNil(video.Details.Steps),
+			Contraindications: slicesx.NonNil(video.Details.Contraindications),
+			Equipment:         slicesx.NonNil(video.Details.Equipment),
+			Sets:              video.Details.Sets,
+			Reps:              video.Details.Reps,
+			Difficulty:        video.Details.Difficulty,
+			DateCreated:       video.DateCreated,
 		}
 		for j, cat := range video.Categories {
 			res[i].Categories[j] = dto.CategoryResponse{
@@ -255,12 +317,22 @@ func (h *Handler) updateVideo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	video := &admin.Video{
-		ID:          id,
-		Name:        req.Name,
-		URL:         req.URL,
-		ImgURL:      req.ImgURL,
-		Description: req.Description,
-		Categories:  make([]admin.Category, len(req.CategoryIDs)),
+		ID:           id,
+		Name:         req.Name,
+		URL:          req.URL,
+		ImgURL:       req.ImgURL,
+		Description:  req.Description,
+		Categories:   make([]admin.Category, len(req.CategoryIDs)),
+		Tags:         req.Tags,
+		NextVideoIDs: req.NextVideoIDs,
+		Details: admin.ExerciseDetails{
+			Steps:             req.Steps,
+			Contraindications: req.Contraindications,
+			Equipment:         req.Equipment,
+			Sets:              req.Sets,
+			Reps:              req.Reps,
+			Difficulty:        req.Difficulty,
+		},
 	}
 	for i, catID := range req.CategoryIDs {
 		video.Categories[i] = admin.Category{
@@ -298,6 +370,36 @@ func (h *Handler) updateVideo(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, admin.ErrVideoImgSuspiciousPattern):
 			dto.RespondWithError(w, http.StatusBadRequest, "Подозрительный URL изображения видео")
 			return
+		case errors.Is(err, admin.ErrVideoInvalidTag):
+			dto.RespondWithError(w, http.StatusBadRequest, "Теги могут содержать только буквы, цифры, пробелы, дефисы и подчеркивания (до 50 символов)")
+			return
+		case errors.Is(err, admin.ErrVideoNextUpInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный список видео \"смотреть далее\"")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidSteps):
+			dto.RespondWithError(w, http.StatusBadRequest, "Не более 30 шагов, каждый до 500 символов")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidContraindications):
+			dto.RespondWithError(w, http.StatusBadRequest, "Не более 20 противопоказаний, каждое до 200 символов")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidEquipment):
+			dto.RespondWithError(w, http.StatusBadRequest, "Не более 20 позиций инвентаря, каждая до 200 символов")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidDosage):
+			dto.RespondWithError(w, http.StatusBadRequest, "Число подходов должно быть от 0 до 20, повторений от 0 до 100")
+			return
+		case errors.Is(err, admin.ErrVideoInvalidDifficulty):
+			dto.RespondWithError(w, http.StatusBadRequest, "Уровень сложности: beginner, intermediate или advanced")
+			return
+		case errors.Is(err, admin.ErrVideoFileNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Видеофайл не найден на сервере, сначала загрузите его")
+			return
+		case errors.Is(err, admin.ErrVideoImgNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Изображение превью не найдено на сервере, сначала загрузите его")
+			return
+		case errors.Is(err, admin.ErrVideoNextUpNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "One or more next up videos not found")
+			return
 		case errors.Is(err, admin.ErrVideoUpdateFailed):
 			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to update video")
 			return
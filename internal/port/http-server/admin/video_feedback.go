@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Получить комментарии к видео
+// @Description Возвращает комментарии пациентов с указанным статусом. Без статуса возвращает очередь на модерации от старых к новым.
+// @Tags Admin Comments
+// @Produce json
+// @Param status query string false "Статус: pending, approved или rejected"
+// @Success 200 {array} dto.VideoCommentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/comments [get]
+func (h *Handler) getVideoComments(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getVideoComments"
+
+	status := r.URL.Query().Get("status")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"status", status,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	comments, err := h.service.GetVideoComments(ctx, status)
+	if err != nil {
+		if errors.Is(err, admin.ErrCommentInvalidStatus) {
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный статус", "status must be pending, approved or rejected")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get comments")
+		return
+	}
+
+	res := make([]dto.VideoCommentResponse, len(comments))
+	for i, c := range comments {
+		res[i] = dto.VideoCommentResponse{
+			ID:              c.ID,
+			VideoID:         c.VideoID,
+			VideoName:       c.VideoName,
+			Username:        c.Username,
+			ContentTypeID:   c.ContentTypeID,
+			ContentTypeName: c.ContentTypeName,
+			Text:            c.Text,
+			Status:          c.Status,
+			ModeratedBy:     c.ModeratedBy,
+			ModeratedAt:     c.ModeratedAt,
+			CreatedAt:       c.CreatedAt,
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Модерировать комментарий
+// @Description Одобряет или отклоняет комментарий пациента. Одобренный комментарий видят пациенты того же типа контента.
+// @Tags Admin Comments
+// @Accept json
+// @Produce json
+// @Param id path int true "ID комментария"
+// @Param input body dto.VideoCommentStatusRequest true "Новый статус"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/comments/{id}/status [put]
+func (h *Handler) moderateVideoComment(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.moderateVideoComment"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid comment ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	var req dto.VideoCommentStatusRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.ModerateVideoComment(ctx, id, req.Status, adminUsername(r.Context()))
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrCommentInvalidStatus):
+			dto.RespondWithError(w, http.StatusBadRequest, "Неверный статус", "status must be pending, approved or rejected")
+			return
+		case errors.Is(err, admin.ErrCommentNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Comment not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Failed to moderate comment")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Comment status updated successfully",
+	})
+}
@@ -264,6 +264,18 @@ function sortCategories(categories) {
     });
 }
 
+// Функция для заполнения списка родительских категорий, текущая категория в списке не показывается
+function fillCategoryParentSelect(categoryId, parentId) {
+    const $select = $('#category-parent').empty();
+    $select.append('<option value="0">Без родителя</option>');
+
+    categoriesList
+        .filter(c => c.id !== categoryId)
+        .forEach(c => $select.append($('<option>').val(c.id).text(c.name)));
+
+    $select.val(String(parentId || 0));
+}
+
 // Функция для открытия модального окна категории
 function openCategoryModal(categoryId = null) {
     // Удаляем предыдущие сообщения об ошибках
@@ -288,6 +300,7 @@ function openCategoryModal(categoryId = null) {
                 $('#category-id').val(category.id);
                 $('#category-name').val(category.name);
                 $('#category-img').val(category.img_url || '');
+                fillCategoryParentSelect(category.id, category.parent_id);
 
                 // Заполняем выбранные типы контента
                 selectedTypes = category.types.map(t => ({ id: t.id, name: t.name }));
@@ -302,6 +315,7 @@ function openCategoryModal(categoryId = null) {
         $('#delete-category-btn').addClass('d-none');
         $('#category-form')[0].reset();
         $('#category-id').val('');
+        fillCategoryParentSelect(null, 0);
         selectedTypes = [];
         updateSelectedTypesDisplay();
         categoryModal.show();
@@ -677,6 +691,15 @@ function playVideo(filename) {
 }
 
 // Открытие модального окна редактирования
+// Разбивает строку со значениями через запятую на массив
+function splitList(value) {
+    return (value || '').split(',').map(v => v.trim()).filter(v => v !== '');
+}
+
+function splitLines(value) {
+    return (value || '').split('\n').map(v => v.trim()).filter(v => v !== '');
+}
+
 function openVideoModal(videoId = null) {
     $('#video-error-message').remove();
 
@@ -693,6 +716,14 @@ function openVideoModal(videoId = null) {
                 $('#video-url').val(video.url);
                 $('#video-img').val(video.img_url || '');
                 $('#video-desc').val(video.description || '');
+                $('#video-tags').val((video.tags || []).join(', '));
+                $('#video-next').val((video.next_video_ids || []).join(', '));
+                $('#video-steps').val((video.steps || []).join('\n'));
+                $('#video-contraindications').val((video.contraindications || []).join('\n'));
+                $('#video-equipment').val((video.equipment || []).join(', '));
+                $('#video-sets').val(video.sets || '');
+                $('#video-reps').val(video.reps || '');
+                $('#video-difficulty').val(video.difficulty || '');
 
                 // Заполняем выбранные категории
                 selectedVideoCategories = video.categories || [];
@@ -1346,12 +1377,14 @@ function renderFilesList(fileType = 'video') {
         const isSelected = selectedFile && selectedFile.name === file.name;
         const sizeMB = (file.size / (1024 * 1024)).toFixed(2);
         const modDate = new Date(file.mod_time).toLocaleString();
+        const usedBy = (file.used_by || []).map(u => u.name).join(', ').replace(/"/g, '&quot;');
 
         $fileList.append(`
             <tr class="${isSelected ? 'selected' : ''}" data-name="${file.name}">
                 <td class="file-name-cell">${file.name}</td>
                 <td class="file-size">${sizeMB} MB</td>
                 <td>${modDate}</td>
+                <td title="${usedBy}">${file.usage_count || 0}</td>
             </tr>
         `);
     });
@@ -1502,7 +1535,15 @@ $(document).ready(function() {
             url: $('#video-url').val(),
             img_url: $('#video-img').val(),
             description: $('#video-desc').val(),
-            category_ids: selectedVideoCategories.map(c => c.id)
+            category_ids: selectedVideoCategories.map(c => c.id),
+            tags: splitList($('#video-tags').val()),
+            next_video_ids: splitList($('#video-next').val()).map(id => parseInt(id)),
+            steps: splitLines($('#video-steps').val()),
+            contraindications: splitLines($('#video-contraindications').val()),
+            equipment: splitList($('#video-equipment').val()),
+            sets: parseInt($('#video-sets').val()) || 0,
+            reps: parseInt($('#video-reps').val()) || 0,
+            difficulty: $('#video-difficulty').val()
         };
 
         const videoId = $('#video-id').val();
@@ -1784,7 +1825,8 @@ $(document).ready(function() {
         const categoryData = {
             name: $('#category-name').val(),
             img_url: $('#category-img').val(),
-            type_ids: selectedTypes.map(t => t.id)
+            type_ids: selectedTypes.map(t => t.id),
+            parent_id: parseInt($('#category-parent').val()) || 0
         };
 
         const categoryId = $('#category-id').val();
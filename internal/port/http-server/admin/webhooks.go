@@ -0,0 +1,385 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Создать подписку на события
+// @Description Создает webhook подписку на изменения каталога. Если секрет не указан, он генерируется и возвращается в ответе.
+// @Tags Admin Webhooks
+// @Accept json
+// @Produce json
+// @Param input body dto.WebhookRequest true "Данные подписки"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks [post]
+func (h *Handler) addWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.addWebhook"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	var req dto.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	webhook, err := h.service.AddWebhook(ctx, webhookFromDTO(0, &req))
+	if err != nil {
+		if respondWebhookValidationError(w, err) {
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, webhookToDTO(webhook, true))
+}
+
+// @Summary Получить подписку на события
+// @Description Возвращает webhook подписку вместе с секретом подписи
+// @Tags Admin Webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks/{id} [get]
+func (h *Handler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getWebhook"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid webhook ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	webhook, err := h.service.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get webhook")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, webhookToDTO(webhook, true))
+}
+
+// @Summary Получить подписки на события
+// @Description Возвращает все webhook подписки без секретов
+// @Tags Admin Webhooks
+// @Produce json
+// @Success 200 {array} dto.WebhookResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks [get]
+func (h *Handler) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getWebhooks"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	webhooks, err := h.service.GetWebhooks(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get webhooks")
+		return
+	}
+
+	res := make([]dto.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		res[i] = webhookToDTO(&webhooks[i], false)
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Изменить подписку на события
+// @Description Обновляет webhook подписку. Пустой секрет оставляет прежний.
+// @Tags Admin Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param input body dto.WebhookRequest true "Данные подписки"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks/{id} [put]
+func (h *Handler) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.updateWebhook"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid webhook ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	var req dto.WebhookRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.UpdateWebhook(ctx, webhookFromDTO(id, &req))
+	if err != nil {
+		if respondWebhookValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to update webhook")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Webhook updated successfully",
+	})
+}
+
+// @Summary Удалить подписку на события
+// @Description Удаляет webhook подписку вместе с журналом доставки
+// @Tags Admin Webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks/{id} [delete]
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.deleteWebhook"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid webhook ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err = h.service.DeleteWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to delete webhook")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.SuccessResponse{
+		ID:      id,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// @Summary Журнал доставки событий
+// @Description Возвращает последние 100 доставок подписки, новые первыми
+// @Tags Admin Webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {array} dto.WebhookDeliveryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *Handler) getWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.getWebhookDeliveries"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid webhook ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	deliveries, err := h.service.GetWebhookDeliveries(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to get webhook deliveries")
+		return
+	}
+
+	res := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		res[i] = webhookDeliveryToDTO(&deliveries[i])
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Повторить доставку события
+// @Description Ставит событие доставки в очередь повторно. Создается новая запись журнала, исходная не меняется.
+// @Tags Admin Webhooks
+// @Produce json
+// @Param id path int true "ID доставки"
+// @Success 202 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @security AdminAuth
+// @Router /admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *Handler) redeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.redeliverWebhook"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Error("invalid delivery ID", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	deliveryID, err := h.service.RedeliverWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookDeliveryNotFound) {
+			dto.RespondWithError(w, http.StatusNotFound, "Delivery not found")
+			return
+		}
+		dto.RespondWithError(w, http.StatusInternalServerError, "Failed to redeliver webhook")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusAccepted, dto.SuccessResponse{
+		ID:      deliveryID,
+		Message: "Delivery queued",
+	})
+}
+
+// respondWebhookValidationError отвечает 400 на ошибки проверки подписки и возвращает true, если ответ отправлен
+func respondWebhookValidationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, admin.ErrWebhookInvalidURL):
+		dto.RespondWithError(w, http.StatusBadRequest, "Неверный URL", "url must be an absolute http or https URL")
+		return true
+	case errors.Is(err, admin.ErrWebhookInvalidEvent):
+		dto.RespondWithError(w, http.StatusBadRequest, "Неизвестное событие", "events must be any of: "+strings.Join(admin.WebhookEvents, ", "))
+		return true
+	}
+	return false
+}
+
+func webhookFromDTO(id int64, req *dto.WebhookRequest) *admin.Webhook {
+	return &admin.Webhook{
+		ID:          id,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      req.Active,
+	}
+}
+
+func webhookToDTO(webhook *admin.Webhook, withSecret bool) dto.WebhookResponse {
+	res := dto.WebhookResponse{
+		ID:          webhook.ID,
+		URL:         webhook.URL,
+		Events:      webhook.Events,
+		Description: webhook.Description,
+		Active:      webhook.Active,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+
+	if withSecret {
+		res.Secret = webhook.Secret
+	}
+	if res.Events == nil {
+		res.Events = []string{}
+	}
+
+	return res
+}
+
+func webhookDeliveryToDTO(delivery *admin.WebhookDelivery) dto.WebhookDeliveryResponse {
+	res := dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+
+	if delivery.Status == admin.DeliveryPending {
+		res.NextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	return res
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
 	mwMetrics "github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/metrics"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/langowen/bodybalance-backend/pkg/lib/mediasign"
+	"github.com/langowen/bodybalance-backend/pkg/lib/slicesx"
 	"github.com/theartofdevel/logging"
 )
 
@@ -33,6 +35,7 @@ type Handler struct {
 	logger  *logging.Logger
 	cfg     *config.Config
 	service Service
+	signer  *mediasign.Signer
 }
 
 func New(app *app.App) *Handler {
@@ -40,22 +43,61 @@ func New(app *app.App) *Handler {
 		logger:  app.Logger,
 		cfg:     app.Cfg,
 		service: app.ServiceApi,
+		signer:  app.MediaSigner,
 	}
 }
 
 func (h *Handler) Router(r chi.Router) chi.Router {
-	r.Get("/video_categories", h.getVideosByCategoryAndType)
-	r.Get("/video", h.getVideo)
-	r.Get("/category", h.getCategoriesByType)
-	r.Get("/login", h.checkAccount)
-	r.Post("/feedback", h.feedback)
+	// Настройки и проверка здоровья доступны любой версии приложения
+	r.Get("/config", h.getConfig)
 	r.Get("/health", h.Health)
 
+	r.Group(func(r chi.Router) {
+		if h.cfg.HTTPServer.AppVersionCheck {
+			r.Use(h.AppVersionMiddleware)
+		}
+
+		r.Get("/video_categories", h.getVideosByCategoryAndType)
+		r.Get("/video", h.getVideo)
+		r.Get("/video/related", h.getRelatedVideos)
+		r.Get("/category", h.getCategoriesByType)
+		r.Get("/login", h.checkAccount)
+		r.Post("/login", h.login)
+		r.Get("/sync", h.sync)
+		r.Post("/feedback", h.feedback)
+		r.Get("/announcements", h.getAnnouncements)
+
+		// Оценки, комментарии, дневник и напоминания доступны только с токеном из /login
+		r.Group(func(r chi.Router) {
+			r.Use(h.AccountMiddleware)
+
+			r.Post("/video/rating", h.rateVideo)
+			r.Get("/video/comments", h.getVideoComments)
+			r.Post("/video/comments", h.addVideoComment)
+
+			// Дневник боли — данные о здоровье, поэтому нужен вход по паролю
+			r.Group(func(r chi.Router) {
+				r.Use(h.PasswordAuthMiddleware)
+
+				r.Post("/diary", h.addDiaryEntry)
+				r.Get("/diary", h.getDiaryEntries)
+				r.Delete("/diary/{id}", h.deleteDiaryEntry)
+			})
+
+			r.Get("/reminders", h.getReminders)
+			r.Post("/reminders", h.addReminder)
+			r.Put("/reminders/{id}", h.updateReminder)
+			r.Delete("/reminders/{id}", h.deleteReminder)
+			r.Post("/devices", h.registerDevice)
+			r.Delete("/devices/{token}", h.unregisterDevice)
+		})
+	})
+
 	return r
 }
 
 // @Summary Check account existence
-// @Description Checks if account with specified username exists and returns type info
+// @Description Checks if account with specified username exists and returns type info and a token for endpoints that require Authorization: Bearer. The token does not give access to the pain diary, use POST /login for it.
 // @Tags API v1
 // @Produce json
 // @Param username query string true "Username to check"
@@ -94,19 +136,86 @@ func (h *Handler) checkAccount(w http.ResponseWriter, r *http.Request) {
 
 	mwMetrics.RecordDataSource(r, account.DataSource)
 
+	token, err := h.issueAccountToken(account.Username, false)
+	if err != nil {
+		logger.Error("failed to sign account token", sl.Err(err))
+		dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to check account")
+		return
+	}
+
 	res := dto.AccountResponse{
 		TypeID:   account.ContentType.ID,
 		TypeName: account.ContentType.Name,
+		Token:    token,
 	}
 
 	dto.RespondWithJSON(w, http.StatusOK, res)
 }
 
+// @Summary Login with password
+// @Description Checks the password set by the administrator and returns type info and a token that also gives access to the pain diary
+// @Tags API v1
+// @Accept json
+// @Produce json
+// @Param credentials body dto.LoginRequest true "Username and password"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 500 {object} string
+// @Router /login [post]
+func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.login"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	logger = logger.With("username", req.Username)
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	account, err := h.service.LoginAccount(ctx, req.Username, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyUsername):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Username is empty")
+		case errors.Is(err, api.ErrEmptyPassword):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Password is empty")
+		case errors.Is(err, storage.ErrAccountNotFound):
+			dto.RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "Invalid username or password")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to check account")
+		}
+		return
+	}
+
+	token, err := h.issueAccountToken(account.Username, true)
+	if err != nil {
+		logger.Error("failed to sign account token", sl.Err(err))
+		dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to check account")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.AccountResponse{
+		TypeID:   account.ContentType.ID,
+		TypeName: account.ContentType.Name,
+		Token:    token,
+	})
+}
+
 // @Summary Get categories by type
-// @Description Returns all categories for specified type, ordered by name
+// @Description Returns all categories for specified type, ordered by name. With parent returns only direct subcategories, parent=0 returns root categories.
 // @Tags API v1
 // @Produce json
 // @Param type query int true "Type ID"
+// @Param parent query int false "Parent category ID, 0 for root categories"
 // @Success 200 {array} dto.CategoryResponse
 // @Failure 400 {object} string
 // @Failure 404 {object} string
@@ -116,16 +225,18 @@ func (h *Handler) getCategoriesByType(w http.ResponseWriter, r *http.Request) {
 	const op = "handlers.api.getCategoriesByType"
 
 	contentType := r.URL.Query().Get("type")
+	parent := r.URL.Query().Get("parent")
 
 	logger := h.logger.With(
 		"handler", op,
 		"request_id", middleware.GetReqID(r.Context()),
 		"type", contentType,
+		"parent", parent,
 	)
 
 	ctx := logging.ContextWithLogger(r.Context(), logger)
 
-	categories, err := h.service.GetCategoriesByType(ctx, contentType)
+	categories, err := h.service.GetCategoriesByType(ctx, contentType, parent)
 	if err != nil {
 		switch {
 		case errors.Is(err, api.ErrEmptyTypeID):
@@ -134,6 +245,9 @@ func (h *Handler) getCategoriesByType(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, api.ErrTypeInvalid):
 			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid type ID")
 			return
+		case errors.Is(err, api.ErrParentInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid parent category ID")
+			return
 		case errors.Is(err, storage.ErrContentTypeNotFound):
 			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Content type %s not found", contentType))
 			return
@@ -143,14 +257,20 @@ func (h *Handler) getCategoriesByType(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mwMetrics.RecordDataSource(r, categories[0].DataSource)
+	// После фильтрации по родителю список может быть пустым
+	if len(categories) > 0 {
+		mwMetrics.RecordDataSource(r, categories[0].DataSource)
+	}
 
+	sign := h.mediaSigner(r)
 	categoriesResponse := make([]dto.CategoryResponse, 0, len(categories))
 	for _, category := range categories {
 		categoriesResponse = append(categoriesResponse, dto.CategoryResponse{
-			ID:     category.ID,
-			Name:   category.Name,
-			ImgURL: category.ImgURL,
+			ID:        category.ID,
+			Name:      category.Name,
+			ImgURL:    sign(category.ImgURL),
+			ParentID:  category.ParentID,
+			Documents: documentsToDTO(category.Documents),
 		})
 	}
 
@@ -158,11 +278,11 @@ func (h *Handler) getCategoriesByType(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Get video by ID
-// @Description Returns video details by its ID
+// @Description Returns video details by its ID with PDF documents and WebVTT subtitle tracks
 // @Tags API v1
 // @Produce json
 // @Param video_id query int true "Video ID"
-// @Success 200 {object} dto.VideoResponse
+// @Success 200 {object} dto.VideoDetailsResponse
 // @Failure 400 {object} string
 // @Failure 404 {object} string
 // @Failure 500 {object} string
@@ -200,20 +320,101 @@ func (h *Handler) getVideo(w http.ResponseWriter, r *http.Request) {
 
 	mwMetrics.RecordDataSource(r, video.DataSource)
 
-	res := dto.VideoResponse{
-		ID:          video.ID,
-		Name:        video.Name,
-		Description: video.Description,
-		URL:         video.URL,
-		Category:    video.Category.Name,
-		ImgURL:      video.ImgURL,
+	sign := h.mediaSigner(r)
+	res := dto.VideoDetailsResponse{
+		VideoResponse: dto.VideoResponse{
+			ID:          video.ID,
+			Name:        video.Name,
+			Description: video.Description,
+			URL:         sign(video.URL),
+			Category:    video.Category.Name,
+			ImgURL:      sign(video.ImgURL),
+			Subtitles:   subtitlesToDTO(video.Subtitles),
+		},
+		Steps:             slicesx.NonNil(video.Details.Steps),
+		Contraindications: slicesx.NonNil(video.Details.Contraindications),
+		Equipment:         slicesx.NonNil(video.Details.Equipment),
+		Sets:              video.Details.Sets,
+		Reps:              video.Details.Reps,
+		Difficulty:        video.Details.Difficulty,
+		Documents:         documentsToDTO(video.Documents),
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Get related videos
+// @Description Returns videos to suggest after the specified one: admin-curated next up videos first, then videos of the same content type ranked by shared categories and tags
+// @Tags API v1
+// @Produce json
+// @Param video_id query int true "Video ID"
+// @Param type query int false "Type ID, limits suggestions to this content type"
+// @Success 200 {array} dto.VideoResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /video/related [get]
+func (h *Handler) getRelatedVideos(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.getRelatedVideos"
+
+	videoID := r.URL.Query().Get("video_id")
+	contentType := r.URL.Query().Get("type")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"video_id", videoID,
+		"type", contentType,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	videos, err := h.service.GetRelatedVideos(ctx, videoID, contentType)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyVideoID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Video id is empty")
+			return
+		case errors.Is(err, api.ErrInvalidVideoID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", fmt.Sprintf("Video ID '%s' is not a valid number", videoID))
+			return
+		case errors.Is(err, api.ErrTypeInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid type ID")
+			return
+		case errors.Is(err, storage.ErrVideoNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Video with id %s not found", videoID))
+			return
+		case errors.Is(err, storage.ErrContentTypeNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Content type %s not found", contentType))
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to get related videos")
+			return
+		}
+	}
+
+	if len(videos) > 0 {
+		mwMetrics.RecordDataSource(r, videos[0].DataSource)
+	}
+
+	sign := h.mediaSigner(r)
+	res := make([]dto.VideoResponse, 0, len(videos))
+	for _, video := range videos {
+		res = append(res, dto.VideoResponse{
+			ID:          video.ID,
+			URL:         sign(video.URL),
+			Name:        video.Name,
+			Description: video.Description,
+			Category:    video.Category.Name,
+			ImgURL:      sign(video.ImgURL),
+		})
 	}
 
 	dto.RespondWithJSON(w, http.StatusOK, res)
 }
 
 // @Summary Get videos by category and type
-// @Description Returns videos filtered by type and category, ordered by name
+// @Description Returns videos filtered by type and category, ordered by name. Videos with subtitles list their WebVTT tracks in the subtitles field
 // @Tags API v1
 // @Produce json
 // @Param type query int true "Type ID"
@@ -269,15 +470,162 @@ func (h *Handler) getVideosByCategoryAndType(w http.ResponseWriter, r *http.Requ
 
 	mwMetrics.RecordDataSource(r, videos[0].DataSource)
 
+	sign := h.mediaSigner(r)
 	res := make([]dto.VideoResponse, 0, len(videos))
 	for _, video := range videos {
 		res = append(res, dto.VideoResponse{
 			ID:          video.ID,
-			URL:         video.URL,
+			URL:         sign(video.URL),
 			Name:        video.Name,
 			Description: video.Description,
 			Category:    video.Category.Name,
-			ImgURL:      video.ImgURL,
+			ImgURL:      sign(video.ImgURL),
+			Subtitles:   subtitlesToDTO(video.Subtitles),
+		})
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Incremental catalog sync
+// @Description Returns categories and videos of the content type changed since the cursor, ids to delete locally and a new cursor. Without a cursor or with a cursor of another type returns the full catalog with full=true
+// @Tags API v1
+// @Produce json
+// @Param type query int true "Type ID"
+// @Param since query string false "Cursor from the previous response"
+// @Success 200 {object} dto.SyncResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /sync [get]
+func (h *Handler) sync(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.sync"
+
+	contentType := r.URL.Query().Get("type")
+	cursor := r.URL.Query().Get("since")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"type", contentType,
+		"since", cursor,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	changes, err := h.service.Sync(ctx, contentType, cursor)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyTypeID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Content type is empty")
+			return
+		case errors.Is(err, api.ErrTypeInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid type ID")
+			return
+		case errors.Is(err, api.ErrSyncCursorInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid sync cursor")
+			return
+		case errors.Is(err, storage.ErrContentTypeNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Content type %s not found", contentType))
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to sync catalog")
+			return
+		}
+	}
+
+	mwMetrics.RecordDataSource(r, mwMetrics.SourceSQL)
+
+	res := dto.SyncResponse{
+		Cursor:     changes.Cursor,
+		Full:       changes.Full,
+		Categories: make([]dto.CategoryResponse, 0, len(changes.Categories)),
+		Videos:     make([]dto.SyncVideoResponse, 0, len(changes.Videos)),
+		Deleted: dto.SyncDeletedResponse{
+			Categories: changes.DeletedCategories,
+			Videos:     changes.DeletedVideos,
+		},
+	}
+
+	sign := h.mediaSigner(r)
+	for _, category := range changes.Categories {
+		res.Categories = append(res.Categories, dto.CategoryResponse{
+			ID:       category.ID,
+			Name:     category.Name,
+			ImgURL:   sign(category.ImgURL),
+			ParentID: category.ParentID,
+		})
+	}
+
+	for _, video := range changes.Videos {
+		res.Videos = append(res.Videos, dto.SyncVideoResponse{
+			ID:          video.ID,
+			URL:         sign(video.URL),
+			Name:        video.Name,
+			Description: video.Description,
+			ImgURL:      sign(video.ImgURL),
+			CategoryIDs: video.CategoryIDs,
+		})
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Get announcements
+// @Description Returns announcements active now for specified type, newest first. Announcements without audience are returned for every type.
+// @Tags API v1
+// @Produce json
+// @Param type query int true "Type ID"
+// @Success 200 {array} dto.AnnouncementResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /announcements [get]
+func (h *Handler) getAnnouncements(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.getAnnouncements"
+
+	contentType := r.URL.Query().Get("type")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"type", contentType,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	announcements, err := h.service.GetAnnouncements(ctx, contentType)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyTypeID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Content type is empty")
+			return
+		case errors.Is(err, api.ErrTypeInvalid):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid type ID")
+			return
+		case errors.Is(err, storage.ErrContentTypeNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Content type %s not found", contentType))
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to get announcements")
+			return
+		}
+	}
+
+	if len(announcements) > 0 {
+		mwMetrics.RecordDataSource(r, announcements[0].DataSource)
+	}
+
+	sign := h.mediaSigner(r)
+	res := make([]dto.AnnouncementResponse, 0, len(announcements))
+	for _, announcement := range announcements {
+		res = append(res, dto.AnnouncementResponse{
+			ID:       announcement.ID,
+			Title:    announcement.Title,
+			Body:     announcement.Body,
+			ImgURL:   sign(announcement.ImgURL),
+			StartsAt: announcement.StartsAt,
+			EndsAt:   announcement.EndsAt,
 		})
 	}
 
@@ -390,3 +738,35 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 
 	dto.RespondWithJSON(w, http.StatusOK, res)
 }
+
+// documentsToDTO преобразует документы в ответ, nil превращается в пустой список
+func documentsToDTO(documents []api.Document) []dto.DocumentResponse {
+	res := make([]dto.DocumentResponse, len(documents))
+	for i, document := range documents {
+		res[i] = dto.DocumentResponse{
+			ID:    document.ID,
+			Title: document.Title,
+			URL:   document.URL,
+		}
+	}
+
+	return res
+}
+
+// subtitlesToDTO преобразует дорожки субтитров в ответ, без дорожек поле не выводится
+func subtitlesToDTO(subtitles []api.Subtitle) []dto.SubtitleResponse {
+	if len(subtitles) == 0 {
+		return nil
+	}
+
+	res := make([]dto.SubtitleResponse, len(subtitles))
+	for i, sub := range subtitles {
+		res[i] = dto.SubtitleResponse{
+			Language: sub.Language,
+			Label:    sub.Label,
+			URL:      sub.URL,
+		}
+	}
+
+	return res
+}
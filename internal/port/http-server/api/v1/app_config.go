@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	mwMetrics "github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/metrics"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// HeaderAppVersion заголовок с версией мобильного приложения
+const HeaderAppVersion = "X-App-Version"
+
+// @Summary Get app configuration
+// @Description Returns remote app configuration: supported versions, force update flag, support contacts and feature toggles. Available to outdated app versions too.
+// @Tags API v1
+// @Produce json
+// @Success 200 {object} dto.AppConfigResponse
+// @Failure 500 {object} string
+// @Router /config [get]
+func (h *Handler) getConfig(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.getConfig"
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	cfg, err := h.service.GetAppConfig(ctx)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to get app config")
+		return
+	}
+
+	mwMetrics.RecordDataSource(r, cfg.DataSource)
+
+	features := cfg.Features
+	if features == nil {
+		features = map[string]bool{}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.AppConfigResponse{
+		MinVersion:    cfg.MinVersion,
+		LatestVersion: cfg.LatestVersion,
+		ForceUpdate:   cfg.ForceUpdate,
+		Support: dto.SupportResponse{
+			Email:    cfg.SupportEmail,
+			Telegram: cfg.SupportTelegram,
+			Phone:    cfg.SupportPhone,
+		},
+		Features: features,
+	})
+}
+
+// AppVersionMiddleware отвечает 426 Upgrade Required клиентам, версия которых из X-App-Version больше не поддерживается.
+// Запросы без заголовка пропускаются, как и все запросы, если настройки не удалось получить.
+func (h *Handler) AppVersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.api.AppVersionMiddleware"
+
+		version := r.Header.Get(HeaderAppVersion)
+		if version == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := h.logger.With(
+			"handler", op,
+			"request_id", middleware.GetReqID(r.Context()),
+			"app_version", version,
+		)
+
+		ctx := logging.ContextWithLogger(r.Context(), logger)
+
+		cfg, err := h.service.CheckAppVersion(ctx, version)
+		if err != nil {
+			if errors.Is(err, api.ErrInvalidAppVersion) {
+				dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid "+HeaderAppVersion+" header")
+				return
+			}
+			// Сбой хранилища не должен блокировать приложение
+			logger.Warn("failed to check app version, request passed", sl.Err(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if cfg != nil {
+			dto.RespondWithJSON(w, http.StatusUpgradeRequired, dto.UpgradeRequiredResponse{
+				Error:         "upgrade_required",
+				Message:       "Эта версия приложения больше не поддерживается, обновите приложение",
+				AppVersion:    version,
+				MinVersion:    cfg.MinVersion,
+				LatestVersion: cfg.LatestVersion,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/appversion"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appConfigService реализует только методы Service, нужные для проверки версии
+type appConfigService struct {
+	Service
+	cfg *api.AppConfig
+	err error
+}
+
+func (s *appConfigService) CheckAppVersion(_ context.Context, version string) (*api.AppConfig, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	v, err := appversion.Parse(version)
+	if err != nil {
+		return nil, api.ErrInvalidAppVersion
+	}
+
+	if s.cfg.UpgradeRequired(v) {
+		return s.cfg, nil
+	}
+	return nil, nil
+}
+
+func serveWithVersion(t *testing.T, service Service, version string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	h := &Handler{
+		logger:  logdiscart.NewDiscardLogger(),
+		service: service,
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/category?type=1", nil)
+	if version != "" {
+		req.Header.Set(HeaderAppVersion, version)
+	}
+
+	rec := httptest.NewRecorder()
+	h.AppVersionMiddleware(next).ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAppVersionMiddleware(t *testing.T) {
+	service := &appConfigService{cfg: &api.AppConfig{MinVersion: "1.4.0", LatestVersion: "1.6.0"}}
+
+	tests := []struct {
+		name    string
+		version string
+		code    int
+	}{
+		{name: "без заголовка", version: "", code: http.StatusOK},
+		{name: "поддерживаемая версия", version: "1.4.0", code: http.StatusOK},
+		{name: "устаревшая версия", version: "1.3.9", code: http.StatusUpgradeRequired},
+		{name: "неверный заголовок", version: "latest", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveWithVersion(t, service, tt.version)
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestAppVersionMiddleware_UpgradeRequiredBody(t *testing.T) {
+	// С force_update устаревшей считается любая версия ниже последней
+	service := &appConfigService{cfg: &api.AppConfig{MinVersion: "1.4.0", LatestVersion: "1.6.0", ForceUpdate: true}}
+
+	rec := serveWithVersion(t, service, "1.5.2")
+	require.Equal(t, http.StatusUpgradeRequired, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var res dto.UpgradeRequiredResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, "upgrade_required", res.Error)
+	assert.Equal(t, "1.5.2", res.AppVersion)
+	assert.Equal(t, "1.4.0", res.MinVersion)
+	assert.Equal(t, "1.6.0", res.LatestVersion)
+}
+
+func TestAppVersionMiddleware_StorageErrorPassesRequest(t *testing.T) {
+	service := &appConfigService{err: errors.New("db is down")}
+
+	rec := serveWithVersion(t, service, "1.0.0")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// accountTokenAudience отличает токен пациента от токена администратора, подписанного тем же ключом
+const accountTokenAudience = "app"
+
+// AccountClaims данные токена пациента, выдаваемого в /v1/login
+type AccountClaims struct {
+	jwt.RegisteredClaims
+	Username string `json:"username"`
+	// PasswordAuth отмечает токен, выданный после проверки пароля в POST /v1/login.
+	// GET /v1/login выдает токен по одному имени пользователя.
+	PasswordAuth bool `json:"pwd,omitempty"`
+}
+
+// accountContextKey ключ контекста запроса с аккаунтом пациента из токена
+type accountContextKey struct{}
+
+// passwordAuthContextKey ключ контекста запроса с признаком входа по паролю
+type passwordAuthContextKey struct{}
+
+// accountFromContext возвращает аккаунт пациента, запрос которого прошел AccountMiddleware
+func accountFromContext(ctx context.Context) *api.Account {
+	account, _ := ctx.Value(accountContextKey{}).(*api.Account)
+	return account
+}
+
+// passwordAuthFromContext сообщает, выдан ли токен запроса после проверки пароля
+func passwordAuthFromContext(ctx context.Context) bool {
+	passwordAuth, _ := ctx.Value(passwordAuthContextKey{}).(bool)
+	return passwordAuth
+}
+
+// issueAccountToken подписывает токен пациента на срок HTTP_TOKEN_TTL
+func (h *Handler) issueAccountToken(username string, passwordAuth bool) (string, error) {
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &AccountClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   username,
+			Audience:  jwt.ClaimStrings{accountTokenAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(h.cfg.HTTPServer.TokenTTL)),
+		},
+		Username:     username,
+		PasswordAuth: passwordAuth,
+	})
+
+	return token.SignedString([]byte(h.cfg.HTTPServer.SigningKey))
+}
+
+// AccountMiddleware пропускает только запросы с токеном пациента в заголовке Authorization: Bearer.
+// Аккаунт перечитывается при каждом запросе, поэтому токен удаленного аккаунта перестает работать,
+// а смена типа контента учитывается сразу.
+func (h *Handler) AccountMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.api.AccountMiddleware"
+
+		logger := h.logger.With(
+			"handler", op,
+			"request_id", middleware.GetReqID(r.Context()),
+		)
+
+		claims, err := accountClaimsFromRequest(r, h.cfg.HTTPServer.SigningKey)
+		if err != nil {
+			if errors.Is(err, errNoAccountToken) {
+				dto.RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "Authorization: Bearer token is required")
+				return
+			}
+			logger.Warn("invalid account token", sl.Err(err))
+			dto.RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "Invalid token")
+			return
+		}
+
+		ctx := logging.ContextWithLogger(r.Context(), logger.With("username", claims.Username))
+
+		account, err := h.service.GetTypeByAccount(ctx, claims.Username)
+		if err != nil {
+			if errors.Is(err, storage.ErrAccountNotFound) {
+				dto.RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "Account not found")
+				return
+			}
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to check account")
+			return
+		}
+
+		ctx = context.WithValue(r.Context(), accountContextKey{}, account)
+		ctx = context.WithValue(ctx, passwordAuthContextKey{}, claims.PasswordAuth)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// PasswordAuthMiddleware пропускает только запросы с токеном, выданным после проверки пароля.
+// Ставится после AccountMiddleware на эндпоинты с данными о здоровье пациента: токен по одному
+// имени пользователя может получить любой, кто знает это имя.
+func (h *Handler) PasswordAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !passwordAuthFromContext(r.Context()) {
+			dto.RespondWithError(w, http.StatusForbidden, "Forbidden", "Login with password is required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+var errNoAccountToken = errors.New("account token is missing")
+
+// UsernameFromRequest возвращает имя пациента из токена в заголовке Authorization: Bearer.
+// Проверяются только подпись и срок токена, аккаунт в БД не перечитывается.
+func UsernameFromRequest(r *http.Request, signingKey string) (string, error) {
+	claims, err := accountClaimsFromRequest(r, signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Username, nil
+}
+
+// accountClaimsFromRequest проверяет подпись и срок токена пациента и возвращает его данные
+func accountClaimsFromRequest(r *http.Request, signingKey string) (*AccountClaims, error) {
+	tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || tokenString == "" {
+		return nil, errNoAccountToken
+	}
+
+	claims := &AccountClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(signingKey), nil
+	}, jwt.WithAudience(accountTokenAudience))
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid || claims.Username == "" {
+		return nil, errors.New("invalid account token")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accountService реализует только проверку аккаунта
+type accountService struct {
+	Service
+	accounts  map[string]int64
+	passwords map[string]string
+}
+
+func (s *accountService) LoginAccount(ctx context.Context, username, password string) (*api.Account, error) {
+	if s.passwords[username] == "" || s.passwords[username] != password {
+		return nil, storage.ErrAccountNotFound
+	}
+	return s.GetTypeByAccount(ctx, username)
+}
+
+func (s *accountService) GetTypeByAccount(_ context.Context, username string) (*api.Account, error) {
+	typeID, ok := s.accounts[username]
+	if !ok {
+		return nil, storage.ErrAccountNotFound
+	}
+	return &api.Account{Username: username, ContentType: api.ContentType{ID: typeID}}, nil
+}
+
+func (s *accountService) GetDiaryEntries(context.Context, string, string, string) ([]api.DiaryEntry, error) {
+	return nil, nil
+}
+
+func newAuthHandler() *Handler {
+	return &Handler{
+		logger: logdiscart.NewDiscardLogger(),
+		cfg:    &config.Config{HTTPServer: config.HTTPServer{SigningKey: "testkey", TokenTTL: time.Hour}},
+		service: &accountService{
+			accounts:  map[string]int64{"patient": 2, "nopassword": 2},
+			passwords: map[string]string{"patient": "hash(secret)"},
+		},
+	}
+}
+
+func serveWithToken(h *Handler, token string) (*httptest.ResponseRecorder, *api.Account) {
+	var account *api.Account
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account = accountFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/video/comments?video_id=1", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.AccountMiddleware(next).ServeHTTP(rec, req)
+
+	return rec, account
+}
+
+func TestAccountMiddleware_ValidToken(t *testing.T) {
+	h := newAuthHandler()
+
+	token, err := h.issueAccountToken("patient", false)
+	require.NoError(t, err)
+
+	rec, account := serveWithToken(h, token)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, account)
+	assert.Equal(t, "patient", account.Username)
+	assert.Equal(t, int64(2), account.ContentType.ID)
+}
+
+func TestAccountMiddleware_Rejected(t *testing.T) {
+	h := newAuthHandler()
+
+	deleted, err := h.issueAccountToken("deleted", false)
+	require.NoError(t, err)
+
+	// Токен администратора подписан тем же ключом, но без аудитории приложения
+	admin := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"username": "patient",
+		"is_admin": true,
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	})
+	adminToken, err := admin.SignedString([]byte("testkey"))
+	require.NoError(t, err)
+
+	other := &Handler{cfg: &config.Config{HTTPServer: config.HTTPServer{SigningKey: "otherkey", TokenTTL: time.Hour}}}
+	foreign, err := other.issueAccountToken("patient", false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "без токена", token: ""},
+		{name: "токен администратора", token: adminToken},
+		{name: "чужой ключ", token: foreign},
+		{name: "удаленный аккаунт", token: deleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, account := serveWithToken(h, tt.token)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Nil(t, account)
+		})
+	}
+}
+
+// serveDiary выполняет запрос к дневнику через роутер API
+func serveDiary(h *Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/diary", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.Router(chi.NewRouter()).ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestLogin(t *testing.T) {
+	h := newAuthHandler()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "верный пароль", body: `{"username":"patient","password":"hash(secret)"}`, wantCode: http.StatusOK},
+		{name: "неверный пароль", body: `{"username":"patient","password":"hash(wrong)"}`, wantCode: http.StatusUnauthorized},
+		{name: "аккаунт без пароля", body: `{"username":"nopassword","password":""}`, wantCode: http.StatusUnauthorized},
+		{name: "нет аккаунта", body: `{"username":"ghost","password":"hash(secret)"}`, wantCode: http.StatusUnauthorized},
+		{name: "неверный JSON", body: `{`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			h.Router(chi.NewRouter()).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			// Токен из входа по паролю открывает дневник
+			var res dto.AccountResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			assert.Equal(t, int64(2), res.TypeID)
+			assert.Equal(t, http.StatusOK, serveDiary(h, res.Token).Code)
+		})
+	}
+}
+
+func TestDiary_RequiresPasswordLogin(t *testing.T) {
+	h := newAuthHandler()
+
+	// Токен из GET /login выдается по одному имени пользователя и не открывает дневник
+	req := httptest.NewRequest(http.MethodGet, "/login?username=patient", nil)
+	rec := httptest.NewRecorder()
+	h.Router(chi.NewRouter()).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res dto.AccountResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+
+	assert.Equal(t, http.StatusForbidden, serveDiary(h, res.Token).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveDiary(h, "").Code)
+
+	// Остальные эндпоинты с токеном по имени пользователя работают как прежде
+	rec, account := serveWithToken(h, res.Token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotNil(t, account)
+}
@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Add pain diary entry
+// @Description Saves a dated pain diary entry: pain score 0-10, body area, notes and videos done that day. Several entries per day are allowed, for example for different body areas.
+// @Tags API v1
+// @Accept json
+// @Produce json
+// @Security AccountAuth
+// @Param entry body dto.DiaryEntryRequest true "Diary entry"
+// @Success 201 {object} dto.DiaryEntryResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 500 {object} string
+// @Router /diary [post]
+func (h *Handler) addDiaryEntry(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.addDiaryEntry"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	var req dto.DiaryEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	entry := api.DiaryEntry{
+		Username:  account.Username,
+		PainScore: req.PainScore,
+		BodyArea:  req.BodyArea,
+		Notes:     req.Notes,
+		VideoIDs:  req.VideoIDs,
+	}
+
+	err := h.service.AddDiaryEntry(ctx, &entry, req.Date)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidDiaryDate):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Date must be YYYY-MM-DD and not in the future")
+			return
+		case errors.Is(err, api.ErrInvalidPainScore):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Pain score must be between 0 and 10")
+			return
+		case errors.Is(err, api.ErrDiaryBodyAreaTooLong):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Body area is too long")
+			return
+		case errors.Is(err, api.ErrDiaryNotesTooLong):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Notes are too long")
+			return
+		case errors.Is(err, api.ErrDiaryInvalidVideo), errors.Is(err, storage.ErrVideoNotFound):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid video list")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to save diary entry")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, diaryEntryToDTO(&entry))
+}
+
+// @Summary Get pain diary
+// @Description Returns the patient's diary entries, newest first
+// @Tags API v1
+// @Produce json
+// @Security AccountAuth
+// @Param from query string false "Start date, YYYY-MM-DD"
+// @Param to query string false "End date inclusive, YYYY-MM-DD"
+// @Success 200 {array} dto.DiaryEntryResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 500 {object} string
+// @Router /diary [get]
+func (h *Handler) getDiaryEntries(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.getDiaryEntries"
+
+	account := accountFromContext(r.Context())
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+		"from", from,
+		"to", to,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	entries, err := h.service.GetDiaryEntries(ctx, account.Username, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidDiaryDate):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Dates must be YYYY-MM-DD")
+			return
+		case errors.Is(err, api.ErrInvalidDiaryRange):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "from must not be after to")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to get diary")
+			return
+		}
+	}
+
+	res := make([]dto.DiaryEntryResponse, 0, len(entries))
+	for i := range entries {
+		res = append(res, diaryEntryToDTO(&entries[i]))
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Delete pain diary entry
+// @Description Deletes the patient's own diary entry
+// @Tags API v1
+// @Security AccountAuth
+// @Param id path int true "Entry ID"
+// @Success 204
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /diary/{id} [delete]
+func (h *Handler) deleteDiaryEntry(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.deleteDiaryEntry"
+
+	account := accountFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+		"id", id,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err := h.service.DeleteDiaryEntry(ctx, account.Username, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidDiaryEntryID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid entry ID")
+			return
+		case errors.Is(err, api.ErrDiaryEntryNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", "Diary entry not found")
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to delete diary entry")
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func diaryEntryToDTO(entry *api.DiaryEntry) dto.DiaryEntryResponse {
+	videoIDs := entry.VideoIDs
+	if videoIDs == nil {
+		videoIDs = []int64{}
+	}
+
+	return dto.DiaryEntryResponse{
+		ID:        entry.ID,
+		Date:      entry.Date.Format(api.DiaryDateLayout),
+		PainScore: entry.PainScore,
+		BodyArea:  entry.BodyArea,
+		Notes:     entry.Notes,
+		VideoIDs:  videoIDs,
+		CreatedAt: entry.CreatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
 )
@@ -11,20 +12,125 @@ import (
 // VideoResponse представляет информацию о видео
 // @description Информация о видео, включая URL, описание и категорию
 type VideoResponse struct {
-	ID          int64  `json:"id"`          // ID из БД
-	URL         string `json:"url"`         // URL адрес до файла
-	Name        string `json:"name"`        // Название видео
-	Description string `json:"description"` // Описание видео
-	Category    string `json:"category"`    // Название категории
-	ImgURL      string `json:"img_url"`     // Превью картинка для видео
+	ID          int64              `json:"id"`                  // ID из БД
+	URL         string             `json:"url"`                 // URL адрес до файла
+	Name        string             `json:"name"`                // Название видео
+	Description string             `json:"description"`         // Описание видео
+	Category    string             `json:"category"`            // Название категории
+	ImgURL      string             `json:"img_url"`             // Превью картинка для видео
+	Subtitles   []SubtitleResponse `json:"subtitles,omitempty"` // Дорожки субтитров WebVTT, нет поля — субтитров нет
+}
+
+// SubtitleResponse представляет дорожку субтитров
+// @description Дорожка субтитров WebVTT к видео на одном языке
+type SubtitleResponse struct {
+	Language string `json:"language"` // Код языка BCP 47
+	Label    string `json:"label"`    // Название дорожки для меню плеера
+	URL      string `json:"url"`      // URL адрес до файла WebVTT
+}
+
+// VideoDetailsResponse представляет видео вместе со структурированным описанием упражнения
+// @description Информация о видео с шагами выполнения, противопоказаниями, инвентарем, рекомендациями и PDF-памятками
+type VideoDetailsResponse struct {
+	VideoResponse
+	Steps             []string           `json:"steps"`             // Шаги выполнения по порядку
+	Contraindications []string           `json:"contraindications"` // Противопоказания
+	Equipment         []string           `json:"equipment"`         // Необходимый инвентарь
+	Sets              int                `json:"sets"`              // Рекомендуемое число подходов, 0 если не задано
+	Reps              int                `json:"reps"`              // Рекомендуемое число повторений, 0 если не задано
+	Difficulty        string             `json:"difficulty"`        // Уровень сложности: beginner, intermediate, advanced или пусто
+	Documents         []DocumentResponse `json:"documents"`         // PDF-памятки к видео
+}
+
+// DocumentResponse представляет PDF-памятку
+// @description PDF-памятка, прикрепленная к видео или категории
+type DocumentResponse struct {
+	ID    int64  `json:"id"`    // ID из БД
+	Title string `json:"title"` // Название документа
+	URL   string `json:"url"`   // URL адрес до PDF-файла
 }
 
 // CategoryResponse представляет информацию о категории
 // @description Информация о категории контента
 type CategoryResponse struct {
-	ID     int64  `json:"id"`      // ID из БД
-	Name   string `json:"name"`    // Название категории
-	ImgURL string `json:"img_url"` // Превью картинка для категории
+	ID        int64              `json:"id"`                  // ID из БД
+	Name      string             `json:"name"`                // Название категории
+	ImgURL    string             `json:"img_url"`             // Превью картинка для категории
+	ParentID  int64              `json:"parent_id"`           // ID родительской категории, 0 для корневой
+	Documents []DocumentResponse `json:"documents,omitempty"` // PDF-памятки к категории, нет поля — документов нет
+}
+
+// AnnouncementResponse представляет объявление для пользователей приложения
+// @description Активное объявление для типа контента
+type AnnouncementResponse struct {
+	ID       int64      `json:"id"`                // ID из БД
+	Title    string     `json:"title"`             // Заголовок
+	Body     string     `json:"body"`              // Текст объявления
+	ImgURL   string     `json:"img_url,omitempty"` // Изображение, если задано
+	StartsAt time.Time  `json:"starts_at"`         // Начало показа
+	EndsAt   *time.Time `json:"ends_at,omitempty"` // Окончание показа, если задано
+}
+
+// AppConfigResponse представляет настройки мобильного приложения
+// @description Настройки, которые администратор меняет без выпуска новой сборки
+type AppConfigResponse struct {
+	MinVersion    string          `json:"min_version"`    // Минимальная поддерживаемая версия, пусто — без ограничения
+	LatestVersion string          `json:"latest_version"` // Последняя выпущенная версия
+	ForceUpdate   bool            `json:"force_update"`   // Обновление до latest_version обязательно
+	Support       SupportResponse `json:"support"`        // Контакты поддержки
+	Features      map[string]bool `json:"features"`       // Флаги функций приложения
+}
+
+// SupportResponse представляет контакты поддержки
+type SupportResponse struct {
+	Email    string `json:"email,omitempty"`    // Email поддержки
+	Telegram string `json:"telegram,omitempty"` // Telegram поддержки
+	Phone    string `json:"phone,omitempty"`    // Телефон поддержки
+}
+
+// UpgradeRequiredResponse представляет ошибку для устаревшей версии приложения
+// @description Версия приложения из X-App-Version больше не поддерживается, клиент должен предложить обновление
+type UpgradeRequiredResponse struct {
+	Error         string `json:"error"`          // Код ошибки, всегда upgrade_required
+	Message       string `json:"message"`        // Текст для пользователя
+	AppVersion    string `json:"app_version"`    // Версия клиента из запроса
+	MinVersion    string `json:"min_version"`    // Минимальная поддерживаемая версия
+	LatestVersion string `json:"latest_version"` // Последняя выпущенная версия
+}
+
+// SyncResponse представляет изменения каталога с момента курсора
+// @description Изменения категорий и видео для типа контента. Курсор нужно передать в следующем запросе.
+type SyncResponse struct {
+	Cursor     string              `json:"cursor"`     // Курсор для следующего запроса
+	Full       bool                `json:"full"`       // Полная выгрузка, локальные данные нужно заменить
+	Categories []CategoryResponse  `json:"categories"` // Новые и измененные категории
+	Videos     []SyncVideoResponse `json:"videos"`     // Новые и измененные видео
+	Deleted    SyncDeletedResponse `json:"deleted"`    // Удаленные записи
+}
+
+// SyncVideoResponse представляет видео в ответе синхронизации
+// @description Информация о видео со списком его категорий
+type SyncVideoResponse struct {
+	ID          int64   `json:"id"`           // ID из БД
+	URL         string  `json:"url"`          // URL адрес до файла
+	Name        string  `json:"name"`         // Название видео
+	Description string  `json:"description"`  // Описание видео
+	ImgURL      string  `json:"img_url"`      // Превью картинка для видео
+	CategoryIDs []int64 `json:"category_ids"` // ID категорий видео для типа контента
+}
+
+// SyncDeletedResponse представляет записи, которые клиент должен удалить
+// @description ID удаленных или недоступных для типа контента записей
+type SyncDeletedResponse struct {
+	Categories []int64 `json:"categories"` // ID категорий
+	Videos     []int64 `json:"videos"`     // ID видео
+}
+
+// LoginRequest представляет вход пациента по паролю
+// @description Имя пользователя и пароль, заданный администратором
+type LoginRequest struct {
+	Username string `json:"username"` // Имя пользователя
+	Password string `json:"password"` // Пароль; example: hash(password123)
 }
 
 // AccountResponse представляет информацию об аккаунте
@@ -32,6 +138,92 @@ type CategoryResponse struct {
 type AccountResponse struct {
 	TypeID   int64  `json:"type_id"`   // ID type из БД
 	TypeName string `json:"type_name"` // Название type
+	Token    string `json:"token"`     // Токен для заголовка Authorization: Bearer
+}
+
+// VideoRatingRequest представляет оценку видео
+// @description Оценка видео от 1 до 5, повторная оценка заменяет предыдущую
+type VideoRatingRequest struct {
+	VideoID int64 `json:"video_id"` // ID видео
+	Score   int   `json:"score"`    // Оценка от 1 до 5
+}
+
+// VideoRatingResponse представляет итог оценок видео
+// @description Оценка пациента и средняя оценка видео
+type VideoRatingResponse struct {
+	VideoID int64   `json:"video_id"` // ID видео
+	Score   int     `json:"score"`    // Оценка пациента
+	Average float64 `json:"average"`  // Средняя оценка видео
+	Count   int     `json:"count"`    // Количество оценок
+}
+
+// VideoCommentRequest представляет комментарий к видео
+// @description Комментарий к видео, виден другим пациентам после одобрения администратором
+type VideoCommentRequest struct {
+	VideoID int64  `json:"video_id"` // ID видео
+	Text    string `json:"text"`     // Текст комментария, до 1000 символов
+}
+
+// DiaryEntryRequest представляет запись дневника боли
+// @description Запись дневника боли за день
+type DiaryEntryRequest struct {
+	Date      string  `json:"date"`       // Дата записи, YYYY-MM-DD
+	PainScore int     `json:"pain_score"` // Оценка боли от 0 до 10
+	BodyArea  string  `json:"body_area"`  // Область тела, до 100 символов
+	Notes     string  `json:"notes"`      // Заметки, до 2000 символов
+	VideoIDs  []int64 `json:"video_ids"`  // Видео, выполненные в этот день, до 20
+}
+
+// DiaryEntryResponse представляет запись дневника боли
+// @description Сохраненная запись дневника боли
+type DiaryEntryResponse struct {
+	ID        int64     `json:"id"`         // ID записи
+	Date      string    `json:"date"`       // Дата записи, YYYY-MM-DD
+	PainScore int       `json:"pain_score"` // Оценка боли от 0 до 10
+	BodyArea  string    `json:"body_area"`  // Область тела
+	Notes     string    `json:"notes"`      // Заметки
+	VideoIDs  []int64   `json:"video_ids"`  // Видео, выполненные в этот день
+	CreatedAt time.Time `json:"created_at"` // Время отправки записи
+}
+
+// ReminderRequest представляет расписание напоминания
+// @description Напоминание о занятиях по дням недели в местном времени пациента
+type ReminderRequest struct {
+	Days     []int  `json:"days"`              // ISO дни недели: 1 — понедельник, 7 — воскресенье
+	Time     string `json:"time"`              // Местное время "HH:MM"
+	Timezone string `json:"timezone"`          // Часовой пояс IANA, например Europe/Moscow
+	Message  string `json:"message,omitempty"` // Текст уведомления, до 200 символов
+	Enabled  *bool  `json:"enabled,omitempty"` // Включено ли напоминание, по умолчанию true
+}
+
+// ReminderResponse представляет напоминание пациента
+// @description Сохраненное напоминание со временем ближайшего срабатывания
+type ReminderResponse struct {
+	ID        int64      `json:"id"`                    // ID напоминания
+	Days      []int      `json:"days"`                  // ISO дни недели по возрастанию
+	Time      string     `json:"time"`                  // Местное время "HH:MM"
+	Timezone  string     `json:"timezone"`              // Часовой пояс IANA
+	Message   string     `json:"message"`               // Текст уведомления
+	Enabled   bool       `json:"enabled"`               // Включено ли напоминание
+	NextRunAt *time.Time `json:"next_run_at,omitempty"` // Ближайшее срабатывание, нет у выключенного напоминания
+}
+
+// DeviceRequest представляет устройство для push-уведомлений
+// @description Токен устройства, выданный Firebase Cloud Messaging
+type DeviceRequest struct {
+	Token    string `json:"token"`              // Токен устройства FCM
+	Platform string `json:"platform,omitempty"` // Платформа: android или ios
+}
+
+// VideoCommentResponse представляет комментарий к видео
+// @description Одобренный комментарий или комментарий самого пациента с его статусом
+type VideoCommentResponse struct {
+	ID        int64     `json:"id"`         // ID комментария
+	VideoID   int64     `json:"video_id"`   // ID видео
+	Text      string    `json:"text"`       // Текст комментария
+	Status    string    `json:"status"`     // Статус: pending, approved или rejected
+	Own       bool      `json:"own"`        // Комментарий текущего пациента
+	CreatedAt time.Time `json:"created_at"` // Время отправки
 }
 
 // FeedbackResponse представляет информацию о фидбэке от пользователя
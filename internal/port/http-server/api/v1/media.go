@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"net/http"
+)
+
+// mediaSigner возвращает функцию подписи ссылок на видео и изображения для ответа на запрос r.
+// Подпись выполняется при отдаче ответа, а не в сервисе: списки видео кэшируются в Redis
+// на все аккаунты сразу, и подписанные ссылки в кэше быстро бы протухали.
+// Если в запросе есть действительный токен пациента, ссылки привязываются к его аккаунту.
+func (h *Handler) mediaSigner(r *http.Request) func(string) string {
+	if h.signer == nil {
+		return func(rawURL string) string { return rawURL }
+	}
+
+	username, _ := UsernameFromRequest(r, h.cfg.HTTPServer.SigningKey)
+
+	return func(rawURL string) string {
+		return h.signer.SignURL(rawURL, username)
+	}
+}
@@ -0,0 +1,334 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Get reminders
+// @Description Returns the patient's exercise reminder schedules
+// @Tags API v1
+// @Produce json
+// @Security AccountAuth
+// @Success 200 {array} dto.ReminderResponse
+// @Failure 401 {object} string
+// @Failure 500 {object} string
+// @Router /reminders [get]
+func (h *Handler) getReminders(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.getReminders"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	reminders, err := h.service.GetReminders(ctx, account.Username)
+	if err != nil {
+		dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to get reminders")
+		return
+	}
+
+	res := make([]dto.ReminderResponse, 0, len(reminders))
+	for i := range reminders {
+		res = append(res, reminderToDTO(&reminders[i]))
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// @Summary Add reminder
+// @Description Creates an exercise reminder: ISO weekdays (1 is Monday), local time HH:MM and IANA timezone. Push notifications are sent to every device registered via /devices.
+// @Tags API v1
+// @Accept json
+// @Produce json
+// @Security AccountAuth
+// @Param reminder body dto.ReminderRequest true "Reminder schedule"
+// @Success 201 {object} dto.ReminderResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /reminders [post]
+func (h *Handler) addReminder(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.addReminder"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	var req dto.ReminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	reminder := reminderFromDTO(&req, account.Username)
+
+	err := h.service.AddReminder(ctx, &reminder)
+	if err != nil {
+		if errors.Is(err, api.ErrTooManyReminders) {
+			dto.RespondWithError(w, http.StatusConflict, "Conflict", "Reminder limit reached")
+			return
+		}
+		respondReminderError(w, err, "Failed to save reminder")
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, reminderToDTO(&reminder))
+}
+
+// @Summary Update reminder
+// @Description Replaces the schedule of the patient's own reminder
+// @Tags API v1
+// @Accept json
+// @Produce json
+// @Security AccountAuth
+// @Param id path int true "Reminder ID"
+// @Param reminder body dto.ReminderRequest true "Reminder schedule"
+// @Success 200 {object} dto.ReminderResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /reminders/{id} [put]
+func (h *Handler) updateReminder(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.updateReminder"
+
+	account := accountFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+		"id", id,
+	)
+
+	var req dto.ReminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	reminder := reminderFromDTO(&req, account.Username)
+
+	err := h.service.UpdateReminder(ctx, id, &reminder)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidReminderID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid reminder ID")
+		case errors.Is(err, api.ErrReminderNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", "Reminder not found")
+		default:
+			respondReminderError(w, err, "Failed to update reminder")
+		}
+		return
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, reminderToDTO(&reminder))
+}
+
+// @Summary Delete reminder
+// @Description Deletes the patient's own reminder
+// @Tags API v1
+// @Security AccountAuth
+// @Param id path int true "Reminder ID"
+// @Success 204
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /reminders/{id} [delete]
+func (h *Handler) deleteReminder(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.deleteReminder"
+
+	account := accountFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+		"id", id,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err := h.service.DeleteReminder(ctx, account.Username, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidReminderID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid reminder ID")
+		case errors.Is(err, api.ErrReminderNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", "Reminder not found")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to delete reminder")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Register device
+// @Description Registers the device's FCM token for push notifications. A token registered by another account moves to the current one.
+// @Tags API v1
+// @Accept json
+// @Security AccountAuth
+// @Param device body dto.DeviceRequest true "Device"
+// @Success 204
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 500 {object} string
+// @Router /devices [post]
+func (h *Handler) registerDevice(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.registerDevice"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	var req dto.DeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err := h.service.RegisterDevice(ctx, &api.Device{
+		Token:    req.Token,
+		Username: account.Username,
+		Platform: req.Platform,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyDeviceToken):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid device token")
+		case errors.Is(err, api.ErrInvalidDevicePlatform):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Platform must be android or ios")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to register device")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Unregister device
+// @Description Stops push notifications to the device, for example on logout
+// @Tags API v1
+// @Security AccountAuth
+// @Param token path string true "FCM device token"
+// @Success 204
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /devices/{token} [delete]
+func (h *Handler) unregisterDevice(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.unregisterDevice"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	err := h.service.UnregisterDevice(ctx, account.Username, chi.URLParam(r, "token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyDeviceToken):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid device token")
+		case errors.Is(err, api.ErrDeviceNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", "Device not found")
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to unregister device")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondReminderError отвечает на ошибки проверки расписания, общие для создания и изменения напоминания
+func respondReminderError(w http.ResponseWriter, err error, serverMsg string) {
+	switch {
+	case errors.Is(err, api.ErrInvalidReminderDays):
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Days must contain weekdays from 1 to 7")
+	case errors.Is(err, api.ErrInvalidReminderTime):
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Time must be HH:MM")
+	case errors.Is(err, api.ErrInvalidReminderTimezone):
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Unknown timezone")
+	case errors.Is(err, api.ErrReminderMessageTooLong):
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Message is too long")
+	default:
+		dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", serverMsg)
+	}
+}
+
+func reminderFromDTO(req *dto.ReminderRequest, username string) api.Reminder {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return api.Reminder{
+		Username: username,
+		Days:     req.Days,
+		Time:     req.Time,
+		Timezone: req.Timezone,
+		Message:  req.Message,
+		Enabled:  enabled,
+	}
+}
+
+func reminderToDTO(reminder *api.Reminder) dto.ReminderResponse {
+	days := reminder.Days
+	if days == nil {
+		days = []int{}
+	}
+
+	return dto.ReminderResponse{
+		ID:        reminder.ID,
+		Days:      days,
+		Time:      reminder.Time,
+		Timezone:  reminder.Timezone,
+		Message:   reminder.Message,
+		Enabled:   reminder.Enabled,
+		NextRunAt: reminder.NextRunAt,
+	}
+}
@@ -8,9 +8,27 @@ import (
 
 type Service interface {
 	GetTypeByAccount(ctx context.Context, username string) (*api.Account, error)
-	GetCategoriesByType(ctx context.Context, contentType string) ([]api.Category, error)
+	LoginAccount(ctx context.Context, username, password string) (*api.Account, error)
+	GetCategoriesByType(ctx context.Context, contentType, parent string) ([]api.Category, error)
 	GetVideo(ctx context.Context, videoStr string) (*api.Video, error)
+	GetRelatedVideos(ctx context.Context, videoStr, contentType string) ([]api.Video, error)
 	GetVideosByCategoryAndType(ctx context.Context, contentType, category string) ([]api.Video, error)
+	Sync(ctx context.Context, contentType, cursor string) (*api.SyncChanges, error)
 	Feedback(ctx context.Context, feedback *api.Feedback) error
+	RateVideo(ctx context.Context, rating *api.VideoRating) error
+	AddVideoComment(ctx context.Context, comment *api.VideoComment) error
+	GetVideoComments(ctx context.Context, username, videoStr string) ([]api.VideoComment, error)
+	AddDiaryEntry(ctx context.Context, entry *api.DiaryEntry, date string) error
+	GetDiaryEntries(ctx context.Context, username, from, to string) ([]api.DiaryEntry, error)
+	DeleteDiaryEntry(ctx context.Context, username, idStr string) error
+	GetReminders(ctx context.Context, username string) ([]api.Reminder, error)
+	AddReminder(ctx context.Context, reminder *api.Reminder) error
+	UpdateReminder(ctx context.Context, idStr string, reminder *api.Reminder) error
+	DeleteReminder(ctx context.Context, username, idStr string) error
+	RegisterDevice(ctx context.Context, device *api.Device) error
+	UnregisterDevice(ctx context.Context, username, token string) error
+	GetAnnouncements(ctx context.Context, contentType string) ([]api.Announcement, error)
+	GetAppConfig(ctx context.Context) (*api.AppConfig, error)
+	CheckAppVersion(ctx context.Context, version string) (*api.AppConfig, error)
 	HealthCheck(ctx context.Context) (*api.HealthCheck, error)
 }
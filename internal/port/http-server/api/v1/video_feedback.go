@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// @Summary Rate video
+// @Description Saves the patient's 1-5 rating of a video available to their content type. A repeated rating replaces the previous one.
+// @Tags API v1
+// @Accept json
+// @Produce json
+// @Security AccountAuth
+// @Param rating body dto.VideoRatingRequest true "Rating"
+// @Success 200 {object} dto.VideoRatingResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /video/rating [post]
+func (h *Handler) rateVideo(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.rateVideo"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	var req dto.VideoRatingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	rating := api.VideoRating{
+		VideoID:  req.VideoID,
+		Username: account.Username,
+		Score:    req.Score,
+	}
+
+	err := h.service.RateVideo(ctx, &rating)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidVideoID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid video ID")
+			return
+		case errors.Is(err, api.ErrInvalidScore):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Score must be between 1 and 5")
+			return
+		case errors.Is(err, storage.ErrVideoNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Video with id %d not found", req.VideoID))
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to save rating")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, dto.VideoRatingResponse{
+		VideoID: rating.VideoID,
+		Score:   rating.Score,
+		Average: rating.Average,
+		Count:   rating.Count,
+	})
+}
+
+// @Summary Comment on video
+// @Description Saves the patient's comment on a video available to their content type. Other patients see the comment after an administrator approves it.
+// @Tags API v1
+// @Accept json
+// @Produce json
+// @Security AccountAuth
+// @Param comment body dto.VideoCommentRequest true "Comment"
+// @Success 201 {object} dto.VideoCommentResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /video/comments [post]
+func (h *Handler) addVideoComment(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.addVideoComment"
+
+	account := accountFromContext(r.Context())
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+	)
+
+	var req dto.VideoCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", sl.Err(err))
+		dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid request format")
+		return
+	}
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	comment := api.VideoComment{
+		VideoID:  req.VideoID,
+		Username: account.Username,
+		Text:     req.Text,
+	}
+
+	err := h.service.AddVideoComment(ctx, &comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrInvalidVideoID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Invalid video ID")
+			return
+		case errors.Is(err, api.ErrEmptyComment):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Comment is empty")
+			return
+		case errors.Is(err, api.ErrCommentTooLong):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Comment is too long")
+			return
+		case errors.Is(err, storage.ErrVideoNotFound):
+			dto.RespondWithError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("Video with id %d not found", req.VideoID))
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to save comment")
+			return
+		}
+	}
+
+	dto.RespondWithJSON(w, http.StatusCreated, videoCommentToDTO(&comment))
+}
+
+// @Summary Get video comments
+// @Description Returns approved comments of patients with the same content type and the patient's own comments with their moderation status, oldest first. Authors are not disclosed.
+// @Tags API v1
+// @Produce json
+// @Security AccountAuth
+// @Param video_id query int true "Video ID"
+// @Success 200 {array} dto.VideoCommentResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 500 {object} string
+// @Router /video/comments [get]
+func (h *Handler) getVideoComments(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.api.getVideoComments"
+
+	account := accountFromContext(r.Context())
+	videoID := r.URL.Query().Get("video_id")
+
+	logger := h.logger.With(
+		"handler", op,
+		"request_id", middleware.GetReqID(r.Context()),
+		"username", account.Username,
+		"video_id", videoID,
+	)
+
+	ctx := logging.ContextWithLogger(r.Context(), logger)
+
+	comments, err := h.service.GetVideoComments(ctx, account.Username, videoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, api.ErrEmptyVideoID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "Video id is empty")
+			return
+		case errors.Is(err, api.ErrInvalidVideoID):
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", fmt.Sprintf("Video ID '%s' is not a valid number", videoID))
+			return
+		default:
+			dto.RespondWithError(w, http.StatusInternalServerError, "Server Error", "Failed to get comments")
+			return
+		}
+	}
+
+	res := make([]dto.VideoCommentResponse, 0, len(comments))
+	for i := range comments {
+		res = append(res, videoCommentToDTO(&comments[i]))
+	}
+
+	dto.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func videoCommentToDTO(comment *api.VideoComment) dto.VideoCommentResponse {
+	return dto.VideoCommentResponse{
+		ID:        comment.ID,
+		VideoID:   comment.VideoID,
+		Text:      comment.Text,
+		Status:    comment.Status,
+		Own:       comment.Own,
+		CreatedAt: comment.CreatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package document
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(docsPatch string) http.Handler {
+	cfg := &config.Config{
+		Media: config.Media{
+			DocsPatch: docsPatch,
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/docs/{filename}", ServeDocFile(cfg, mediastore.NewLocal(cfg.Media.DocsPatch), logdiscart.NewDiscardLogger()))
+	return r
+}
+
+func TestServeDocFile_Success(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := []byte("%PDF-1.4 fake document")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "knee.pdf"), content, 0644))
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/knee.pdf", nil)
+	rec := httptest.NewRecorder()
+	newRouter(tmpDir).ServeHTTP(rec, req)
+
+	// Документ отдается для просмотра в браузере, а не для скачивания
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, content, rec.Body.Bytes())
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="knee.pdf"`, rec.Header().Get("Content-Disposition"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+}
+
+func TestServeDocFile_NotFound(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/docs/missing.pdf", nil)
+	rec := httptest.NewRecorder()
+	newRouter(t.TempDir()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServeDocFile_PathTraversal(t *testing.T) {
+	tmpDir := t.TempDir()
+	docsDir := filepath.Join(tmpDir, "docs")
+	require.NoError(t, os.MkdirAll(docsDir, 0755))
+
+	// Соседняя директория с тем же префиксом не должна быть доступна
+	require.NoError(t, os.MkdirAll(docsDir+"-private", 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(docsDir+"-private", "secret.pdf"), []byte("secret"), 0644))
+
+	cfg := &config.Config{
+		Media: config.Media{
+			DocsPatch: docsDir,
+		},
+	}
+
+	// Роутер не декодирует слеши в параметре, поэтому подставляем имя файла напрямую
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("filename", "../docs-private/secret.pdf")
+	req := httptest.NewRequest(http.MethodGet, "/docs/secret.pdf", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rec := httptest.NewRecorder()
+	ServeDocFile(cfg, mediastore.NewLocal(cfg.Media.DocsPatch), logdiscart.NewDiscardLogger()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
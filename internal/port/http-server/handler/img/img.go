@@ -1,34 +1,42 @@
 package img
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/metrics"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
 	"github.com/theartofdevel/logging"
+	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
 )
 
 // ServeImgFile
 // @Summary Serve img file
-// @Description Send img file by filename
+// @Description Send img file by filename. JPEG, PNG and static GIF images can be downscaled with w and h from the allowed sizes list; SVG and other formats are always served unchanged.
 // @Tags Files
 // @Accept  json
 // @Produce  json
 // @Param filename path string true "Video filename (e.g. 'shee_video.jpg')"
+// @Param w query int false "Max width, one of IMAGE_SIZES"
+// @Param h query int false "Max height, one of IMAGE_SIZES"
+// @Param fit query string false "contain (default) or cover"
 // @Success 200 {file} file
+// @Success 302 {string} string "Redirect to a temporary storage URL when MEDIA_STORE_REDIRECT is on and no resize is requested"
+// @Failure 400 {string} string "Bad Request"
 // @Failure 403 {string} string "Forbidden"
 // @Failure 404 {string} string "Not Found"
 // @Failure 500 {string} string "Internal Server Error"
 // @Router /img/{filename} [get]
 // GET /img/{filename}
-func ServeImgFile(cfg *config.Config, logger *logging.Logger) http.HandlerFunc {
+func ServeImgFile(cfg *config.Config, store mediastore.MediaStore, logger *logging.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.images.ServeImgFile"
 
@@ -39,37 +47,93 @@ func ServeImgFile(cfg *config.Config, logger *logging.Logger) http.HandlerFunc {
 
 		filename := chi.URLParam(r, "filename")
 
-		filePath := filepath.Join(cfg.Media.ImagesPatch, filename)
-
-		cleanPath := filepath.Clean(filePath)
-		if !strings.HasPrefix(cleanPath, cfg.Media.ImagesPatch) {
-			dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+		params, err := parseResizeParams(r.URL.Query(), cfg.Media.ImageSizes)
+		if err != nil {
+			dto.RespondWithError(w, http.StatusBadRequest, "Bad Request", "w and h must be one of the allowed sizes, fit must be contain or cover")
 			return
 		}
 
-		file, err := os.Open(cleanPath)
-		if err != nil {
-			logger.Error("File not found", "filename", filename, sl.Err(err))
-			dto.RespondWithError(w, http.StatusNotFound, "Not Found", err.Error())
-			return
+		// Уменьшенные копии готовит сервер, поэтому в хранилище перенаправляется только запрос исходника
+		if cfg.MediaStore.Redirect && !params.requested() {
+			if presigner, ok := store.(mediastore.Presigner); ok {
+				link, err := presigner.PresignGet(r.Context(), filename, cfg.MediaStore.PresignTTL)
+				if err != nil {
+					logger.Error("Failed to presign file url", "filename", filename, sl.Err(err))
+					dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+					return
+				}
+
+				// Ссылка временная, поэтому сам редирект не кэшируется
+				w.Header().Set("Cache-Control", "no-store")
+				http.Redirect(w, r, link, http.StatusFound)
+				return
+			}
 		}
-		defer file.Close()
 
-		fileInfo, err := file.Stat()
+		source, fileInfo, err := store.Open(r.Context(), filename)
 		if err != nil {
-			logger.Error("Failed to get file info", "filename", filename, sl.Err(err))
-			dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+			switch {
+			case errors.Is(err, mediastore.ErrInvalidName):
+				dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+			case errors.Is(err, mediastore.ErrNotFound):
+				logger.Error("File not found", "filename", filename, sl.Err(err))
+				dto.RespondWithError(w, http.StatusNotFound, "Not Found", err.Error())
+			default:
+				logger.Error("Failed to open file", "filename", filename, sl.Err(err))
+				dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+			}
 			return
 		}
+		defer source.Close()
+
+		var file io.ReadSeeker = source
+
+		etag, digest := fileInfo.ETag(), fileInfo.DigestHeader()
+		size, modTime := fileInfo.Size, fileInfo.ModTime
+
+		if params.requested() {
+			variantPath, err := resizedVariant(cfg.Media.ImageCachePatch, filename, fileInfo.ModTime, source, params)
+			if err != nil {
+				// Без уменьшенной копии клиент получит исходник
+				logger.Error("Failed to resize image", "filename", filename, sl.Err(err))
+			} else if variantPath != "" {
+				variant, err := os.Open(variantPath)
+				if err != nil {
+					logger.Error("Failed to open resized image", "filename", filename, sl.Err(err))
+					dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error")
+					return
+				}
+				defer variant.Close()
+
+				variantInfo, err := variant.Stat()
+				if err != nil {
+					logger.Error("Failed to get resized image info", "filename", filename, sl.Err(err))
+					dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error")
+					return
+				}
+
+				// ETag и время изменения берутся от исходника: копия создается из него и меняется вместе с ним
+				etag = fmt.Sprintf(`"%x-%x-%dx%d-%s"`, fileInfo.Size, fileInfo.ModTime.UnixNano(), params.Width, params.Height, params.Fit)
+				file, size, digest = variant, variantInfo.Size(), ""
+			}
+		}
 
 		if tempRecorder, ok := w.(*metrics.TempResponseRecorder); ok {
-			tempRecorder.SetFileSize(fileInfo.Size())
+			tempRecorder.SetFileSize(size)
 			return
 		}
 
 		w.Header().Set("Cache-Control", "public, max-age=86400")
-		w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, fileInfo.Size(), fileInfo.ModTime().UnixNano()))
+		w.Header().Set("ETag", etag)
+		if digest != "" {
+			w.Header().Set("Digest", digest)
+		}
+
+		// SVG, загруженные до очистки при загрузке, могут содержать скрипты: запрещаем их исполнение
+		if strings.EqualFold(path.Ext(filename), ".svg") {
+			w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src data:; sandbox")
+		}
 
-		http.ServeContent(w, r, filename, fileInfo.ModTime(), file)
+		http.ServeContent(w, r, filename, modTime, file)
 	}
 }
@@ -1,10 +1,14 @@
 package img
 
 import (
+	"bytes"
 	"github.com/go-chi/chi/v5"
 	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
 	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -33,7 +37,7 @@ func TestServeImgFile_Success(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/img/{filename}", ServeImgFile(cfg, logger))
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logger))
 
 	// Создаем запрос
 	req := httptest.NewRequest(http.MethodGet, "/img/test.jpg", nil)
@@ -62,7 +66,7 @@ func TestServeImgFile_NotFound(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/img/{filename}", ServeImgFile(cfg, logger))
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logger))
 
 	// Создаем запрос к несуществующему файлу
 	req := httptest.NewRequest(http.MethodGet, "/img/nonexistent.jpg", nil)
@@ -105,7 +109,7 @@ func TestServeImgFile_PathTraversal(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/img/{filename}", ServeImgFile(cfg, logger))
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logger))
 
 	// Проверяем корректный доступ к файлу
 	req := httptest.NewRequest(http.MethodGet, "/img/valid.jpg", nil)
@@ -145,7 +149,7 @@ func TestServeImgFile_EmptyFilename(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/img/{filename}", ServeImgFile(cfg, logger))
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logger))
 
 	// Создаем запрос с пустым именем файла
 	req := httptest.NewRequest(http.MethodGet, "/img/", nil)
@@ -184,7 +188,7 @@ func TestServeImgFile_ContentTypeHandling(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/img/{filename}", ServeImgFile(cfg, logger))
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logger))
 
 	// Проверяем правильный Content-Type для разных форматов
 	testCases := []struct {
@@ -210,3 +214,79 @@ func TestServeImgFile_ContentTypeHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestServeImgFile_Resize(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Создаем PNG 400×200
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200)))
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, "test.png"), buf.Bytes(), 0644)
+	assert.NoError(t, err)
+
+	cfg := &config.Config{
+		Media: config.Media{
+			ImagesPatch:     tmpDir,
+			ImageCachePatch: filepath.Join(tmpDir, "cache"),
+			ImageSizes:      []int{100, 200},
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logdiscart.NewDiscardLogger()))
+
+	req := httptest.NewRequest(http.MethodGet, "/img/test.png?w=100", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=86400", rec.Header().Get("Cache-Control"))
+
+	resized, err := png.DecodeConfig(bytes.NewReader(rec.Body.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, resized.Width)
+	assert.Equal(t, 50, resized.Height)
+
+	// Повторный запрос с тем же ETag отдается из кэша браузера
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	req = httptest.NewRequest(http.MethodGet, "/img/test.png?w=100", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	// Размер не из списка отклоняется
+	req = httptest.NewRequest(http.MethodGet, "/img/test.png?w=101", nil)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServeImgFile_ResizeSVGPassThrough(t *testing.T) {
+	tmpDir := t.TempDir()
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="200"></svg>`)
+	err := os.WriteFile(filepath.Join(tmpDir, "icon.svg"), svg, 0644)
+	assert.NoError(t, err)
+
+	cfg := &config.Config{
+		Media: config.Media{
+			ImagesPatch:     tmpDir,
+			ImageCachePatch: filepath.Join(tmpDir, "cache"),
+			ImageSizes:      []int{100},
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/img/{filename}", ServeImgFile(cfg, mediastore.NewLocal(cfg.Media.ImagesPatch), logdiscart.NewDiscardLogger()))
+
+	req := httptest.NewRequest(http.MethodGet, "/img/icon.svg?w=100", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, svg, rec.Body.Bytes())
+}
@@ -0,0 +1,185 @@
+package img
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/pkg/lib/imgresize"
+)
+
+const (
+	// maxResizePixels изображения больше не уменьшаются и отдаются как есть, чтобы не держать в памяти гигантский растр
+	maxResizePixels = 50_000_000
+	jpegQuality     = 85
+)
+
+var errInvalidSize = errors.New("size is not allowed")
+
+// resizeParams параметры уменьшения из запроса /img/{filename}?w=&h=&fit=
+type resizeParams struct {
+	Width  int
+	Height int
+	Fit    string
+}
+
+func (p resizeParams) requested() bool {
+	return p.Width > 0 || p.Height > 0
+}
+
+// parseResizeParams разбирает w, h и fit. Размеры ограничены списком sizes,
+// чтобы произвольные значения не забивали кэш на диске.
+func parseResizeParams(query url.Values, sizes []int) (resizeParams, error) {
+	var p resizeParams
+	var err error
+
+	if p.Width, err = parseSize(query.Get("w"), sizes); err != nil {
+		return p, err
+	}
+	if p.Height, err = parseSize(query.Get("h"), sizes); err != nil {
+		return p, err
+	}
+	if p.Fit, err = imgresize.ParseFit(query.Get("fit")); err != nil {
+		return p, err
+	}
+
+	// Без одной из сторон рамки cover не отличается от contain, один вариант в кэше вместо двух
+	if p.Width == 0 || p.Height == 0 {
+		p.Fit = imgresize.FitContain
+	}
+
+	return p, nil
+}
+
+func parseSize(value string, sizes []int) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || !slices.Contains(sizes, size) {
+		return 0, errInvalidSize
+	}
+
+	return size, nil
+}
+
+// resizedVariant возвращает путь к уменьшенной копии изображения, создавая ее при первом запросе.
+// Копия хранится в cacheDir и привязана ко времени изменения исходника: после замены файла
+// создается новая копия, а устаревшие удаляются. Пустой путь означает, что нужно отдать
+// исходник: SVG и другие форматы без уменьшения, анимированный GIF или изображение уже не больше рамки.
+func resizedVariant(cacheDir, name string, modTime time.Time, src io.ReadSeeker, p resizeParams) (string, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".svg" {
+		return "", nil
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	dir := filepath.Join(cacheDir, hex.EncodeToString(sum[:8]))
+	version := fmt.Sprintf("-%x%s", modTime.UnixNano(), ext)
+	variantPath := filepath.Join(dir, fmt.Sprintf("%dx%d-%s%s", p.Width, p.Height, p.Fit, version))
+
+	if info, err := os.Stat(variantPath); err == nil && info.Mode().IsRegular() {
+		return variantPath, nil
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind image: %w", err)
+	}
+
+	cfg, format, err := image.DecodeConfig(src)
+	if err != nil || cfg.Width*cfg.Height > maxResizePixels {
+		return "", nil
+	}
+
+	crop, dstW, dstH := imgresize.Plan(cfg.Width, cfg.Height, p.Width, p.Height, p.Fit)
+	if crop == image.Rect(0, 0, cfg.Width, cfg.Height) && dstW == cfg.Width && dstH == cfg.Height {
+		return "", nil
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind image: %w", err)
+	}
+
+	var img image.Image
+	if format == "gif" {
+		anim, err := gif.DecodeAll(src)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode gif: %w", err)
+		}
+		if len(anim.Image) > 1 {
+			return "", nil
+		}
+		img = anim.Image[0]
+	} else {
+		if img, _, err = image.Decode(src); err != nil {
+			return "", fmt.Errorf("failed to decode image: %w", err)
+		}
+	}
+
+	resized := imgresize.Resize(img, p.Width, p.Height, p.Fit)
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Параллельные запросы пишут каждый в свой временный файл, последний rename побеждает
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache file: %w", err)
+	}
+
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(tmp, resized, &jpeg.Options{Quality: jpegQuality})
+	case "gif":
+		err = gif.Encode(tmp, resized, nil)
+	default:
+		err = png.Encode(tmp, resized)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), variantPath); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to save cache file: %w", err)
+	}
+
+	removeStaleVariants(dir, version)
+
+	return variantPath, nil
+}
+
+// removeStaleVariants удаляет копии, созданные из прежней версии исходника
+func removeStaleVariants(dir, version string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, version) || strings.HasSuffix(name, ".tmp") {
+			continue
+		}
+		os.Remove(filepath.Join(dir, name))
+	}
+}
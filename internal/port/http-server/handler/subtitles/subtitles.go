@@ -0,0 +1,90 @@
+package subtitles
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/metrics"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// ServeSubtitlesFile
+// @Summary Serve subtitles file
+// @Description Send WebVTT subtitles by filename. Links to the files are listed in the subtitles field of /v1/video and /v1/video_categories
+// @Tags Files
+// @Produce text/vtt
+// @Param filename path string true "Subtitles filename (e.g. 'video4_ru.vtt')"
+// @Success 200 {file} file
+// @Success 302 {string} string "Redirect to a temporary storage URL when MEDIA_STORE_REDIRECT is on"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not Found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /subtitles/{filename} [get]
+// GET /subtitles/{filename}
+func ServeSubtitlesFile(cfg *config.Config, store mediastore.MediaStore, logger *logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.subtitles.ServeSubtitlesFile"
+
+		logger := logger.With(
+			"op", op,
+			"request_id", middleware.GetReqID(r.Context()),
+		)
+
+		filename := chi.URLParam(r, "filename")
+
+		if cfg.MediaStore.Redirect {
+			if presigner, ok := store.(mediastore.Presigner); ok {
+				link, err := presigner.PresignGet(r.Context(), filename, cfg.MediaStore.PresignTTL)
+				if err != nil {
+					logger.Error("Failed to presign file url", "filename", filename, sl.Err(err))
+					dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+					return
+				}
+
+				// Ссылка временная, поэтому сам редирект не кэшируется
+				w.Header().Set("Cache-Control", "no-store")
+				http.Redirect(w, r, link, http.StatusFound)
+				return
+			}
+		}
+
+		file, fileInfo, err := store.Open(r.Context(), filename)
+		if err != nil {
+			switch {
+			case errors.Is(err, mediastore.ErrInvalidName):
+				dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+			case errors.Is(err, mediastore.ErrNotFound):
+				logger.Error("File not found", "filename", filename, sl.Err(err))
+				dto.RespondWithError(w, http.StatusNotFound, "Not Found")
+			default:
+				logger.Error("Failed to open file", "filename", filename, sl.Err(err))
+				dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error")
+			}
+			return
+		}
+		defer file.Close()
+
+		if tempRecorder, ok := w.(*metrics.TempResponseRecorder); ok {
+			tempRecorder.SetFileSize(fileInfo.Size)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		// Плеер может загружать субтитры с другого домена, чем страница
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		w.Header().Set("ETag", fileInfo.ETag())
+		if digest := fileInfo.DigestHeader(); digest != "" {
+			w.Header().Set("Digest", digest)
+		}
+
+		http.ServeContent(w, r, filename, fileInfo.ModTime, file)
+	}
+}
@@ -0,0 +1,53 @@
+package subtitles
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(subtitlesPatch string) http.Handler {
+	cfg := &config.Config{
+		Media: config.Media{
+			SubtitlesPatch: subtitlesPatch,
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/subtitles/{filename}", ServeSubtitlesFile(cfg, mediastore.NewLocal(cfg.Media.SubtitlesPatch), logdiscart.NewDiscardLogger()))
+	return r
+}
+
+func TestServeSubtitlesFile_Success(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := []byte("WEBVTT\n\n00:00.000 --> 00:02.000\nСядьте на стул\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "video4_ru.vtt"), content, 0644))
+
+	req := httptest.NewRequest(http.MethodGet, "/subtitles/video4_ru.vtt", nil)
+	rec := httptest.NewRecorder()
+	newRouter(tmpDir).ServeHTTP(rec, req)
+
+	// Субтитры отдаются как WebVTT в UTF-8 независимо от настроек MIME-типов на сервере
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, content, rec.Body.Bytes())
+	assert.Equal(t, "text/vtt; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+}
+
+func TestServeSubtitlesFile_NotFound(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/subtitles/missing.vtt", nil)
+	rec := httptest.NewRecorder()
+	newRouter(t.TempDir()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
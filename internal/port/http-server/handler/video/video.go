@@ -1,18 +1,16 @@
 package video
 
 import (
-	"fmt"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1/dto"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/metrics"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
 	"github.com/theartofdevel/logging"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 )
 
 // ServeVideoFile
@@ -23,13 +21,14 @@ import (
 // @Produce  json
 // @Param filename path string true "Video filename (e.g. 'Sheya_baza.mp4')"
 // @Success 200 {file} file
+// @Success 302 {string} string "Redirect to a temporary storage URL when MEDIA_STORE_REDIRECT is on"
 // @Failure 304 {string} string "Not Modified"
 // @Failure 403 {string} string "Forbidden"
 // @Failure 404 {string} string "Not Found"
 // @Failure 500 {string} string "Internal Server Error"
 // @Router /video/{filename} [get]
 // GET /video/{filename}
-func ServeVideoFile(cfg *config.Config, logger *logging.Logger) http.HandlerFunc {
+func ServeVideoFile(cfg *config.Config, store mediastore.MediaStore, logger *logging.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.video.ServeVideoFile"
 
@@ -39,38 +38,51 @@ func ServeVideoFile(cfg *config.Config, logger *logging.Logger) http.HandlerFunc
 		)
 
 		filename := chi.URLParam(r, "filename")
-		filePath := filepath.Join(cfg.Media.VideoPatch, filename)
 
-		cleanPath := filepath.Clean(filePath)
-		if !strings.HasPrefix(cleanPath, cfg.Media.VideoPatch) {
-			dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
-			return
-		}
+		if cfg.MediaStore.Redirect {
+			if presigner, ok := store.(mediastore.Presigner); ok {
+				link, err := presigner.PresignGet(r.Context(), filename, cfg.MediaStore.PresignTTL)
+				if err != nil {
+					logger.Error("Failed to presign file url", "filename", filename, sl.Err(err))
+					dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+					return
+				}
 
-		file, err := os.Open(cleanPath)
-		if err != nil {
-			logger.Error("File not found", "filename", filename, sl.Err(err))
-			dto.RespondWithError(w, http.StatusNotFound, "Not Found", err.Error())
-			return
+				// Ссылка временная, поэтому сам редирект не кэшируется
+				w.Header().Set("Cache-Control", "no-store")
+				http.Redirect(w, r, link, http.StatusFound)
+				return
+			}
 		}
-		defer file.Close()
 
-		fileInfo, err := file.Stat()
+		file, fileInfo, err := store.Open(r.Context(), filename)
 		if err != nil {
-			logger.Error("Failed to get file info", "filename", filename, sl.Err(err))
-			dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+			switch {
+			case errors.Is(err, mediastore.ErrInvalidName):
+				dto.RespondWithError(w, http.StatusForbidden, "Forbidden")
+			case errors.Is(err, mediastore.ErrNotFound):
+				logger.Error("File not found", "filename", filename, sl.Err(err))
+				dto.RespondWithError(w, http.StatusNotFound, "Not Found", err.Error())
+			default:
+				logger.Error("Failed to open file", "filename", filename, sl.Err(err))
+				dto.RespondWithError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+			}
 			return
 		}
+		defer file.Close()
 
 		if tempRecorder, ok := w.(*metrics.TempResponseRecorder); ok {
-			tempRecorder.SetFileSize(fileInfo.Size())
+			tempRecorder.SetFileSize(fileInfo.Size)
 			return
 		}
 
 		w.Header().Set("Cache-Control", "public, max-age=86400")
-		w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, fileInfo.Size(), fileInfo.ModTime().UnixNano()))
+		w.Header().Set("ETag", fileInfo.ETag())
+		if digest := fileInfo.DigestHeader(); digest != "" {
+			w.Header().Set("Digest", digest)
+		}
 
-		http.ServeContent(w, r, filename, fileInfo.ModTime(), file)
+		http.ServeContent(w, r, filename, fileInfo.ModTime, file)
 	}
 }
 
@@ -1,15 +1,19 @@
 package video
 
 import (
+	"context"
 	"github.com/go-chi/chi/v5"
 	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestServeVideoFile_Success(t *testing.T) {
@@ -33,7 +37,7 @@ func TestServeVideoFile_Success(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/video/{filename}", ServeVideoFile(cfg, logger))
+	r.Get("/video/{filename}", ServeVideoFile(cfg, mediastore.NewLocal(cfg.Media.VideoPatch), logger))
 
 	// Создаем запрос
 	req := httptest.NewRequest(http.MethodGet, "/video/test.mp4", nil)
@@ -62,7 +66,7 @@ func TestServeVideoFile_NotFound(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/video/{filename}", ServeVideoFile(cfg, logger))
+	r.Get("/video/{filename}", ServeVideoFile(cfg, mediastore.NewLocal(cfg.Media.VideoPatch), logger))
 
 	// Создаем запрос к несуществующему файлу
 	req := httptest.NewRequest(http.MethodGet, "/video/nonexistent.mp4", nil)
@@ -105,7 +109,7 @@ func TestServeVideoFile_PathTraversal(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/video/{filename}", ServeVideoFile(cfg, logger))
+	r.Get("/video/{filename}", ServeVideoFile(cfg, mediastore.NewLocal(cfg.Media.VideoPatch), logger))
 
 	// Проверяем корректный доступ к файлу
 	req := httptest.NewRequest(http.MethodGet, "/video/valid.mp4", nil)
@@ -143,7 +147,7 @@ func TestServeVideoFile_EmptyFilename(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/video/{filename}", ServeVideoFile(cfg, logger))
+	r.Get("/video/{filename}", ServeVideoFile(cfg, mediastore.NewLocal(cfg.Media.VideoPatch), logger))
 
 	// Создаем запрос с пустым именем файла
 	req := httptest.NewRequest(http.MethodGet, "/video/", nil)
@@ -181,7 +185,7 @@ func TestServeVideoFile_RangeRequest(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/video/{filename}", ServeVideoFile(cfg, logger))
+	r.Get("/video/{filename}", ServeVideoFile(cfg, mediastore.NewLocal(cfg.Media.VideoPatch), logger))
 
 	// Создаем запрос с заголовком Range
 	req := httptest.NewRequest(http.MethodGet, "/video/test.mp4", nil)
@@ -219,7 +223,7 @@ func TestServeVideoFile_IfNoneMatchHeader(t *testing.T) {
 
 	// Создаем роутер с нужным параметром
 	r := chi.NewRouter()
-	r.Get("/video/{filename}", ServeVideoFile(cfg, logger))
+	r.Get("/video/{filename}", ServeVideoFile(cfg, mediastore.NewLocal(cfg.Media.VideoPatch), logger))
 
 	// Сначала отправляем обычный запрос, чтобы получить ETag
 	req := httptest.NewRequest(http.MethodGet, "/video/test.mp4", nil)
@@ -288,3 +292,69 @@ func (m *mockStatusResponseWriter) WriteHeader(statusCode int) {
 func (m *mockStatusResponseWriter) Status() int {
 	return m.status
 }
+
+// presignStore локальное хранилище, которое выдает временные ссылки как S3
+type presignStore struct {
+	*mediastore.Local
+}
+
+func (s presignStore) PresignGet(_ context.Context, name string, _ time.Duration) (string, error) {
+	return "https://storage.example.com/video/" + name + "?X-Amz-Signature=test", nil
+}
+
+func TestServeVideoFile_Redirect(t *testing.T) {
+	cfg := &config.Config{
+		MediaStore: config.MediaStore{Redirect: true, PresignTTL: time.Minute},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/video/{filename}", ServeVideoFile(cfg, presignStore{mediastore.NewLocal(t.TempDir())}, logdiscart.NewDiscardLogger()))
+
+	req := httptest.NewRequest(http.MethodGet, "/video/test.mp4", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	// Файл отдает хранилище, сервер только перенаправляет на временную ссылку
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://storage.example.com/video/test.mp4?X-Amz-Signature=test", rec.Header().Get("Location"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+}
+
+// digestStore локальное хранилище, которое знает SHA-256 содержимого, как хранилище по содержимому
+type digestStore struct {
+	*mediastore.Local
+}
+
+func (s digestStore) Open(ctx context.Context, name string) (io.ReadSeekCloser, *mediastore.Object, error) {
+	file, obj, err := s.Local.Open(ctx, name)
+	if err == nil {
+		// SHA-256 строки "fake video content"
+		obj.Digest = "28b5f9183aff4ee56160bb14fe47f340a603010929655d1af184607a44d10283"
+	}
+	return file, obj, err
+}
+
+func TestServeVideoFile_Digest(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.mp4"), []byte("fake video content"), 0644))
+
+	r := chi.NewRouter()
+	r.Get("/video/{filename}", ServeVideoFile(&config.Config{}, digestStore{mediastore.NewLocal(tmpDir)}, logdiscart.NewDiscardLogger()))
+
+	req := httptest.NewRequest(http.MethodGet, "/video/test.mp4", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	// ETag и Digest считаются от содержимого, а не от времени изменения
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"28b5f9183aff4ee56160bb14fe47f340a603010929655d1af184607a44d10283"`, rec.Header().Get("ETag"))
+	assert.Equal(t, "sha-256=KLX5GDr/TuVhYLsU/kfzQKYDAQkpZV0a8YRgekTRAoM=", rec.Header().Get("Digest"))
+
+	// Клиент с тем же содержимым получает 304
+	req = httptest.NewRequest(http.MethodGet, "/video/test.mp4", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
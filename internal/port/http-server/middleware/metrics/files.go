@@ -177,18 +177,33 @@ func WrapVideoHandler(next http.HandlerFunc) http.HandlerFunc {
 
 // WrapImgHandler оборачивает обработчик изображений для сбора метрик
 func WrapImgHandler(next http.HandlerFunc) http.HandlerFunc {
+	return wrapFileHandler("image", next)
+}
+
+// WrapDocHandler оборачивает обработчик PDF-документов для сбора метрик
+func WrapDocHandler(next http.HandlerFunc) http.HandlerFunc {
+	return wrapFileHandler("document", next)
+}
+
+// WrapSubtitlesHandler оборачивает обработчик субтитров WebVTT для сбора метрик
+func WrapSubtitlesHandler(next http.HandlerFunc) http.HandlerFunc {
+	return wrapFileHandler("subtitles", next)
+}
+
+// wrapFileHandler собирает метрики отдачи статических файлов вида fileType
+func wrapFileHandler(fileType string, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		filename := chi.URLParam(r, "filename")
 
 		// Для запросов с If-None-Match или If-Modified-Since
 		// передаем управление напрямую основному обработчику, но регистрируем метрики
 		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
-			fileResponseWriter := NewFileResponseWriter(w, r, "image", filename, 0)
+			fileResponseWriter := NewFileResponseWriter(w, r, fileType, filename, 0)
 			next.ServeHTTP(fileResponseWriter, r)
 
 			// Если статус был 304 Not Modified, увеличиваем счетчик кэш-хитов
 			if fileResponseWriter.status == http.StatusNotModified {
-				metrics.StaticFileCacheHit.WithLabelValues("image", filename).Inc()
+				metrics.StaticFileCacheHit.WithLabelValues(fileType, filename).Inc()
 			}
 			return
 		}
@@ -207,7 +222,7 @@ func WrapImgHandler(next http.HandlerFunc) http.HandlerFunc {
 
 			// Записываем метрику запроса с ошибкой
 			metrics.StaticFileRequests.WithLabelValues(
-				"image",
+				fileType,
 				filename,
 				http.StatusText(tempResponseRecorder.Status),
 			).Inc()
@@ -216,7 +231,7 @@ func WrapImgHandler(next http.HandlerFunc) http.HandlerFunc {
 
 		// Файл найден, отправляем с метриками
 		fileSize := tempResponseRecorder.fileSize
-		fileResponseWriter := NewFileResponseWriter(w, r, "image", filename, fileSize)
+		fileResponseWriter := NewFileResponseWriter(w, r, fileType, filename, fileSize)
 
 		// Копируем заголовки из предварительной проверки
 		for k, v := range tempResponseRecorder.Headers {
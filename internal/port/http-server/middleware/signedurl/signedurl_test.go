@@ -0,0 +1,96 @@
+package signedurl
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/logdiscart"
+	"github.com/langowen/bodybalance-backend/pkg/lib/mediasign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Wrap(t *testing.T) {
+	signer, err := mediasign.New([]mediasign.Key{{ID: "k1", Secret: []byte("secret")}}, time.Hour)
+	require.NoError(t, err)
+
+	// Имя пациента передается в заголовке вместо токена
+	username := func(r *http.Request) (string, error) {
+		if name := r.Header.Get("X-Username"); name != "" {
+			return name, nil
+		}
+		return "", errors.New("no token")
+	}
+
+	signedPath := func(rawURL, username string) string {
+		u, err := url.Parse(signer.SignURL(rawURL, username))
+		require.NoError(t, err)
+		return u.RequestURI()
+	}
+
+	// Администратор определяется по заголовку вместо cookie админки
+	isAdmin := func(r *http.Request) bool {
+		return r.Header.Get("X-Admin") == "true"
+	}
+
+	tests := []struct {
+		name     string
+		mode     string
+		path     string
+		username string
+		admin    bool
+		want     int
+	}{
+		{"sign: без подписи", mediasign.ModeSign, "/video/a.mp4", "", false, http.StatusOK},
+		{"sign: подпись от другого файла", mediasign.ModeSign, "/video/a.mp4?" + signer.SignURL("https://x/video/b.mp4", "")[len("https://x/video/b.mp4?"):], "", false, http.StatusForbidden},
+		{"sign: чужой аккаунт только логируется", mediasign.ModeSign, signedPath("https://x/video/a.mp4", "anna"), "ivan", false, http.StatusOK},
+		{"enforce: без подписи", mediasign.ModeEnforce, "/video/a.mp4", "", false, http.StatusForbidden},
+		{"enforce: без привязки", mediasign.ModeEnforce, signedPath("https://x/video/a.mp4", ""), "", false, http.StatusOK},
+		{"enforce: свой аккаунт", mediasign.ModeEnforce, signedPath("https://x/video/a.mp4", "anna"), "anna", false, http.StatusOK},
+		{"enforce: чужой аккаунт", mediasign.ModeEnforce, signedPath("https://x/video/a.mp4", "anna"), "ivan", false, http.StatusForbidden},
+		{"enforce: привязка без токена", mediasign.ModeEnforce, signedPath("https://x/video/a.mp4", "anna"), "", false, http.StatusForbidden},
+		{"enforce: админка без подписи", mediasign.ModeEnforce, "/video/a.mp4", "", true, http.StatusOK},
+		{"enforce: админка со ссылкой пациента", mediasign.ModeEnforce, signedPath("https://x/video/a.mp4", "anna"), "", true, http.StatusOK},
+		{"sign: админка с поддельной подписью", mediasign.ModeSign, "/video/a.mp4?exp=1&sig=x", "", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(signer, tt.mode, logdiscart.NewDiscardLogger(), username, isAdmin)
+
+			r := chi.NewRouter()
+			r.Get("/video/{filename}", v.Wrap("video", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.username != "" {
+				req.Header.Set("X-Username", tt.username)
+			}
+			if tt.admin {
+				req.Header.Set("X-Admin", "true")
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestVerifier_WrapDisabled(t *testing.T) {
+	// Без signer обработчик вызывается напрямую
+	v := New(nil, mediasign.ModeOff, logdiscart.NewDiscardLogger(), nil, nil)
+
+	called := false
+	handler := v.Wrap("img", func(w http.ResponseWriter, r *http.Request) { called = true })
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/img/a.png", nil))
+
+	assert.True(t, called)
+}
@@ -15,11 +15,14 @@ import (
 	"github.com/langowen/bodybalance-backend/internal/app"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/admin"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/api/v1"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/handler/document"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/handler/img"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/handler/subtitles"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/handler/video"
 	mwLogger "github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/logger"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/metrics"
 	"github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/ratelimit"
+	"github.com/langowen/bodybalance-backend/internal/port/http-server/middleware/signedurl"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/theartofdevel/logging"
 )
@@ -108,9 +111,18 @@ func (s *Server) setupRouter() *chi.Mux {
 	adminRouter.Use(metrics.Middleware("admin"))
 	r.Mount("/admin", admin.New(s.app).Router(adminRouter))
 
+	// Подписанные ссылки на видео и изображения проверяются до отдачи файла, админка с cookie проходит без подписи
+	signed := signedurl.New(s.app.MediaSigner, s.cfg.MediaSigning.Mode, s.logger, func(r *http.Request) (string, error) {
+		return v1.UsernameFromRequest(r, s.cfg.HTTPServer.SigningKey)
+	}, func(r *http.Request) bool {
+		return admin.IsAdminRequest(r, s.cfg.HTTPServer.SigningKey)
+	})
+
 	// Статические файлы с метриками
-	r.Get("/video/{filename}", metrics.WrapVideoHandler(video.ServeVideoFile(s.cfg, s.logger)))
-	r.Get("/img/{filename}", metrics.WrapImgHandler(img.ServeImgFile(s.cfg, s.logger)))
+	r.Get("/video/{filename}", signed.Wrap("video", metrics.WrapVideoHandler(video.ServeVideoFile(s.cfg, s.app.Media.Video, s.logger))))
+	r.Get("/img/{filename}", signed.Wrap("img", metrics.WrapImgHandler(img.ServeImgFile(s.cfg, s.app.Media.Images, s.logger))))
+	r.Get("/docs/{filename}", metrics.WrapDocHandler(document.ServeDocFile(s.cfg, s.app.Media.Docs, s.logger)))
+	r.Get("/subtitles/{filename}", metrics.WrapSubtitlesHandler(subtitles.ServeSubtitlesFile(s.cfg, s.app.Media.Subtitles, s.logger)))
 
 	// Prometheus metrics endpoint
 	r.Handle("/metrics", promhttp.Handler())
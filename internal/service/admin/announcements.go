@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+func (s *ServiceAdmin) AddAnnouncement(ctx context.Context, req *admin.Announcement) (int64, error) {
+	const op = "service.AddAnnouncement"
+
+	err := s.validAnnouncement(ctx, req)
+	if err != nil {
+		logging.L(ctx).Warn("invalid announcement data", "op", op, "title", req.Title, "img_url", req.ImgURL, sl.Err(err))
+		return 0, err
+	}
+
+	id, err := s.db.AddAnnouncement(ctx, req)
+	if err != nil {
+		if errors.Is(err, admin.ErrTypeNotFound) {
+			logging.L(ctx).Warn("content type not found", "op", op, "content_types", req.ContentType)
+			return 0, err
+		}
+		logging.L(ctx).Error("failed to add announcement", "op", op, sl.Err(err))
+		return 0, err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeAnnouncementsCache(ctx, op)
+	}
+
+	return id, nil
+}
+
+func (s *ServiceAdmin) GetAnnouncement(ctx context.Context, id int64) (*admin.Announcement, error) {
+	const op = "service.GetAnnouncement"
+
+	announcement, err := s.db.GetAnnouncement(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrAnnouncementNotFound) {
+			logging.L(ctx).Warn("announcement not found", "op", op, "announcement_id", id)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get announcement", "op", op, "announcement_id", id, sl.Err(err))
+		return nil, err
+	}
+
+	return announcement, nil
+}
+
+func (s *ServiceAdmin) GetAnnouncements(ctx context.Context) ([]admin.Announcement, error) {
+	const op = "service.GetAnnouncements"
+
+	announcements, err := s.db.GetAnnouncements(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get announcements", "op", op, sl.Err(err))
+		return nil, err
+	}
+
+	return announcements, nil
+}
+
+func (s *ServiceAdmin) UpdateAnnouncement(ctx context.Context, req *admin.Announcement) error {
+	const op = "service.UpdateAnnouncement"
+
+	err := s.validAnnouncement(ctx, req)
+	if err != nil {
+		logging.L(ctx).Warn("invalid announcement data", "op", op, "announcement_id", req.ID, "img_url", req.ImgURL, sl.Err(err))
+		return err
+	}
+
+	err = s.db.UpdateAnnouncement(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAnnouncementNotFound):
+			logging.L(ctx).Warn("announcement not found", "op", op, "announcement_id", req.ID)
+		case errors.Is(err, admin.ErrTypeNotFound):
+			logging.L(ctx).Warn("content type not found", "op", op, "content_types", req.ContentType)
+		default:
+			logging.L(ctx).Error("failed to update announcement", "op", op, "announcement_id", req.ID, sl.Err(err))
+		}
+		return err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeAnnouncementsCache(ctx, op)
+	}
+
+	return nil
+}
+
+func (s *ServiceAdmin) DeleteAnnouncement(ctx context.Context, id int64) error {
+	const op = "service.DeleteAnnouncement"
+
+	err := s.db.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrAnnouncementNotFound) {
+			logging.L(ctx).Warn("announcement not found", "op", op, "announcement_id", id)
+			return err
+		}
+		logging.L(ctx).Error("failed to delete announcement", "op", op, "announcement_id", id, sl.Err(err))
+		return err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeAnnouncementsCache(ctx, op)
+	}
+
+	return nil
+}
+
+// removeAnnouncementsCache удаляет кэш объявлений, остальной кэш каталога не затрагивается
+func (s *ServiceAdmin) removeAnnouncementsCache(ctx context.Context, op string) {
+	ctxRedis, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.redis.InvalidateAnnouncementsCache(ctxRedis); err != nil {
+		logging.L(ctx).Warn("failed to invalidate announcements cache", "service", op, sl.Err(err))
+	}
+}
+
+// validAnnouncement проверяет заголовок, период показа и изображение объявления
+func (s *ServiceAdmin) validAnnouncement(ctx context.Context, req *admin.Announcement) error {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+	req.ImgURL = strings.TrimSpace(req.ImgURL)
+
+	if req.Title == "" {
+		return admin.ErrAnnouncementEmptyTitle
+	}
+
+	if req.StartsAt.IsZero() {
+		req.StartsAt = time.Now()
+	}
+
+	if req.EndsAt != nil && !req.EndsAt.After(req.StartsAt) {
+		return admin.ErrAnnouncementInvalidPeriod
+	}
+
+	if req.ImgURL == "" {
+		return nil
+	}
+
+	if !validFilePattern.MatchString(req.ImgURL) {
+		return admin.ErrInvalidImgFormat
+	}
+
+	for _, pattern := range suspiciousPatterns {
+		if strings.Contains(req.ImgURL, pattern) {
+			return admin.ErrSuspiciousContent
+		}
+	}
+
+	if s.cfg.Media.CheckFiles {
+		exists, err := fileExists(ctx, s.media.Images, req.ImgURL)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return admin.ErrImgNotFound
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/appversion"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// validFeaturePattern паттерн имени флага функции
+var validFeaturePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+func (s *ServiceAdmin) GetAppConfig(ctx context.Context) (*admin.AppConfig, error) {
+	const op = "service.GetAppConfig"
+
+	cfg, err := s.db.GetAppConfig(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get app config", "op", op, sl.Err(err))
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (s *ServiceAdmin) UpdateAppConfig(ctx context.Context, cfg *admin.AppConfig) error {
+	const op = "service.UpdateAppConfig"
+
+	err := validAppConfig(cfg)
+	if err != nil {
+		logging.L(ctx).Warn("invalid app config", "op", op, "min_version", cfg.MinVersion,
+			"latest_version", cfg.LatestVersion, sl.Err(err))
+		return err
+	}
+
+	err = s.db.UpdateAppConfig(ctx, cfg)
+	if err != nil {
+		logging.L(ctx).Error("failed to update app config", "op", op, sl.Err(err))
+		return err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeAppConfigCache(ctx, op)
+	}
+
+	return nil
+}
+
+// removeAppConfigCache удаляет настройки приложения из кэша
+func (s *ServiceAdmin) removeAppConfigCache(ctx context.Context, op string) {
+	ctxRedis, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.redis.InvalidateAppConfigCache(ctxRedis); err != nil {
+		logging.L(ctx).Warn("failed to invalidate app config cache", "service", op, sl.Err(err))
+	}
+}
+
+// validAppConfig проверяет версии и имена флагов функций
+func validAppConfig(cfg *admin.AppConfig) error {
+	cfg.MinVersion = strings.TrimSpace(cfg.MinVersion)
+	cfg.LatestVersion = strings.TrimSpace(cfg.LatestVersion)
+	cfg.SupportEmail = strings.TrimSpace(cfg.SupportEmail)
+	cfg.SupportTelegram = strings.TrimSpace(cfg.SupportTelegram)
+	cfg.SupportPhone = strings.TrimSpace(cfg.SupportPhone)
+
+	var minVersion, latestVersion appversion.Version
+	var err error
+
+	if cfg.MinVersion != "" {
+		if minVersion, err = appversion.Parse(cfg.MinVersion); err != nil {
+			return admin.ErrAppConfigInvalidVersion
+		}
+	}
+
+	if cfg.LatestVersion != "" {
+		if latestVersion, err = appversion.Parse(cfg.LatestVersion); err != nil {
+			return admin.ErrAppConfigInvalidVersion
+		}
+	}
+
+	if minVersion != nil && latestVersion != nil && latestVersion.Less(minVersion) {
+		return admin.ErrAppConfigVersionOrder
+	}
+
+	if cfg.Features == nil {
+		cfg.Features = map[string]bool{}
+	}
+
+	for name := range cfg.Features {
+		if !validFeaturePattern.MatchString(name) {
+			return admin.ErrAppConfigInvalidFeature
+		}
+	}
+
+	return nil
+}
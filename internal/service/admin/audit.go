@@ -0,0 +1,318 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// mediaDir библиотека и ссылки на ее файлы из БД
+type mediaDir struct {
+	kind  string
+	store mediastore.MediaStore
+	usage map[string][]admin.FileUsage
+}
+
+// AuditMedia сравнивает ссылки на медиафайлы в БД с файлами в директориях видео и изображений
+func (s *ServiceAdmin) AuditMedia(ctx context.Context) (*admin.MediaAudit, error) {
+	const op = "service.AuditMedia"
+
+	dirs, err := s.mediaDirs(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get media usage", "op", op, sl.Err(err))
+		return nil, admin.ErrMediaAuditFailed
+	}
+
+	audit, err := s.auditMedia(ctx, dirs)
+	if err != nil {
+		logging.L(ctx).Error("failed to audit media library", "op", op, sl.Err(err))
+		return nil, admin.ErrMediaAuditFailed
+	}
+
+	return audit, nil
+}
+
+// CleanupMedia переносит файлы без ссылок в карантин, возвращает в библиотеку файлы из карантина,
+// на которые снова появились ссылки, и удаляет файлы, срок хранения которых в карантине истек
+func (s *ServiceAdmin) CleanupMedia(ctx context.Context) ([]admin.CleanupResult, error) {
+	const op = "service.CleanupMedia"
+
+	dirs, err := s.mediaDirs(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get media usage", "op", op, sl.Err(err))
+		return nil, admin.ErrMediaCleanupFailed
+	}
+
+	audit, err := s.auditMedia(ctx, dirs)
+	if err != nil {
+		logging.L(ctx).Error("failed to audit media library", "op", op, sl.Err(err))
+		return nil, admin.ErrMediaCleanupFailed
+	}
+
+	libraries := make(map[string]mediaDir, len(dirs))
+	for _, dir := range dirs {
+		libraries[dir.kind] = dir
+	}
+
+	results := make([]admin.CleanupResult, 0)
+	now := time.Now()
+
+	for _, orphan := range audit.Orphans {
+		if orphan.InGracePeriod {
+			continue
+		}
+
+		res := admin.CleanupResult{Kind: orphan.Kind, Name: orphan.Name, Action: admin.CleanupQuarantined}
+
+		if err = s.quarantineFile(ctx, libraries[orphan.Kind], orphan.Name, now); err != nil {
+			logging.L(ctx).Warn("failed to quarantine file", "op", op, "kind", orphan.Kind, "file", orphan.Name, sl.Err(err))
+			res.Action = admin.CleanupFailed
+			res.Error = err.Error()
+		} else {
+			logging.L(ctx).Info("file moved to quarantine", "op", op, "kind", orphan.Kind, "file", orphan.Name)
+		}
+
+		results = append(results, res)
+	}
+
+	for _, file := range audit.Quarantined {
+		library := libraries[file.Kind]
+		path := filepath.Join(s.cfg.Media.QuarantinePatch, file.Kind, file.Name)
+		res := admin.CleanupResult{Kind: file.Kind, Name: file.Name}
+
+		switch {
+		case len(library.usage[file.Name]) > 0:
+			res.Action = admin.CleanupRestored
+			err = restoreFile(ctx, library.store, path, file.Name)
+		case now.After(file.DeleteAfter):
+			res.Action = admin.CleanupDeleted
+			err = os.Remove(path)
+		default:
+			continue
+		}
+
+		if err != nil {
+			logging.L(ctx).Warn("failed to process quarantined file", "op", op, "kind", file.Kind, "file", file.Name, "action", res.Action, sl.Err(err))
+			res.Action = admin.CleanupFailed
+			res.Error = err.Error()
+		} else {
+			logging.L(ctx).Info("quarantined file processed", "op", op, "kind", file.Kind, "file", file.Name, "action", res.Action)
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// adopter хранилище по содержимому, которое переносит файлы, загруженные до него
+type adopter interface {
+	Adopt(ctx context.Context) ([]string, error)
+}
+
+// IndexMedia считает SHA-256 файлов, загруженных до хранения по содержимому, и переносит их под имена из SHA-256.
+// После этого у файлов появляются контрольные суммы, а одинаковые файлы хранятся один раз.
+func (s *ServiceAdmin) IndexMedia(ctx context.Context) ([]admin.IndexedFile, error) {
+	const op = "service.IndexMedia"
+
+	libraries := []struct {
+		kind  string
+		store mediastore.MediaStore
+	}{
+		{kind: admin.MediaKindVideo, store: s.media.Video},
+		{kind: admin.MediaKindImage, store: s.media.Images},
+		{kind: admin.MediaKindDocument, store: s.media.Docs},
+		{kind: admin.MediaKindSubtitle, store: s.media.Subtitles},
+	}
+
+	result := make([]admin.IndexedFile, 0)
+	for _, library := range libraries {
+		store, ok := library.store.(adopter)
+		if !ok {
+			continue
+		}
+
+		adopted, err := store.Adopt(ctx)
+		for _, name := range adopted {
+			result = append(result, admin.IndexedFile{Kind: library.kind, Name: name})
+		}
+
+		if err != nil {
+			logging.L(ctx).Error("failed to index media library", "op", op, "kind", library.kind, sl.Err(err))
+			return result, admin.ErrMediaIndexFailed
+		}
+
+		logging.L(ctx).Info("media library indexed", "op", op, "kind", library.kind, "files", len(adopted))
+	}
+
+	return result, nil
+}
+
+func (s *ServiceAdmin) mediaDirs(ctx context.Context) ([]mediaDir, error) {
+	videoUsage, err := s.db.GetVideoFileUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imageUsage, err := s.db.GetImageFileUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	documentUsage, err := s.db.GetDocumentFileUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subtitleUsage, err := s.db.GetSubtitleFileUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mediaDir{
+		{kind: admin.MediaKindVideo, store: s.media.Video, usage: videoUsage},
+		{kind: admin.MediaKindImage, store: s.media.Images, usage: imageUsage},
+		{kind: admin.MediaKindDocument, store: s.media.Docs, usage: documentUsage},
+		{kind: admin.MediaKindSubtitle, store: s.media.Subtitles, usage: subtitleUsage},
+	}, nil
+}
+
+func (s *ServiceAdmin) auditMedia(ctx context.Context, dirs []mediaDir) (*admin.MediaAudit, error) {
+	audit := &admin.MediaAudit{
+		Orphans:     make([]admin.OrphanFile, 0),
+		Missing:     make([]admin.MissingFile, 0),
+		Quarantined: make([]admin.QuarantinedFile, 0),
+	}
+
+	now := time.Now()
+
+	for _, dir := range dirs {
+		files, err := listMediaFiles(ctx, dir.store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s directory: %w", dir.kind, err)
+		}
+
+		present := make(map[string]bool, len(files))
+		for _, file := range files {
+			present[file.Name] = true
+
+			if len(dir.usage[file.Name]) > 0 {
+				continue
+			}
+
+			audit.Orphans = append(audit.Orphans, admin.OrphanFile{
+				Kind:          dir.kind,
+				Name:          file.Name,
+				Size:          file.Size,
+				ModTime:       file.ModTime,
+				InGracePeriod: now.Sub(file.ModTime) < s.cfg.Media.OrphanGrace,
+			})
+		}
+
+		missing := make([]string, 0)
+		for name := range dir.usage {
+			if !present[name] {
+				missing = append(missing, name)
+			}
+		}
+		sort.Strings(missing)
+
+		for _, name := range missing {
+			audit.Missing = append(audit.Missing, admin.MissingFile{
+				Kind:   dir.kind,
+				Name:   name,
+				UsedBy: dir.usage[name],
+			})
+		}
+
+		quarantined, err := listMediaFiles(ctx, mediastore.NewLocal(filepath.Join(s.cfg.Media.QuarantinePatch, dir.kind)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s quarantine directory: %w", dir.kind, err)
+		}
+
+		for _, file := range quarantined {
+			audit.Quarantined = append(audit.Quarantined, admin.QuarantinedFile{
+				Kind:          dir.kind,
+				Name:          file.Name,
+				Size:          file.Size,
+				QuarantinedAt: file.ModTime,
+				DeleteAfter:   file.ModTime.Add(s.cfg.Media.QuarantineTTL),
+			})
+		}
+	}
+
+	return audit, nil
+}
+
+// listMediaFiles возвращает файлы библиотеки без временных файлов faststart
+func listMediaFiles(ctx context.Context, store mediastore.MediaStore) ([]admin.File, error) {
+	files, err := getFilesList(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]admin.File, 0, len(files))
+	for _, file := range files {
+		if strings.HasSuffix(file.Name, faststartTmpSuffix) {
+			continue
+		}
+		result = append(result, file)
+	}
+
+	return result, nil
+}
+
+// quarantineFile копирует файл библиотеки в карантин на диске и удаляет его из библиотеки
+func (s *ServiceAdmin) quarantineFile(ctx context.Context, library mediaDir, name string, now time.Time) error {
+	src, obj, err := library.store.Open(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	quarantine := mediastore.NewLocal(filepath.Join(s.cfg.Media.QuarantinePatch, library.kind))
+	if err = quarantine.Save(ctx, name, src, obj.Size); err != nil {
+		return err
+	}
+
+	// Время изменения отсчитывает срок хранения в карантине
+	if err = os.Chtimes(filepath.Join(quarantine.Dir(), name), now, now); err != nil {
+		return fmt.Errorf("failed to set quarantine time: %w", err)
+	}
+
+	return library.store.Delete(ctx, name)
+}
+
+// restoreFile возвращает файл из карантина в библиотеку. Если файл с тем же именем уже загружен заново,
+// копия из карантина удаляется, чтобы не перезаписать новый файл.
+func restoreFile(ctx context.Context, store mediastore.MediaStore, path, name string) error {
+	if _, err := store.Stat(ctx, name); err == nil {
+		return os.Remove(path)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if err = store.Save(ctx, name, src, info.Size()); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usageStorage ссылки на файлы библиотек из видео и категорий
+type usageStorage struct {
+	AdmStorage
+	video map[string][]admin.FileUsage
+	image map[string][]admin.FileUsage
+}
+
+func (s *usageStorage) GetVideoFileUsage(context.Context) (map[string][]admin.FileUsage, error) {
+	return s.video, nil
+}
+
+func (s *usageStorage) GetImageFileUsage(context.Context) (map[string][]admin.FileUsage, error) {
+	return s.image, nil
+}
+
+func (s *usageStorage) GetDocumentFileUsage(context.Context) (map[string][]admin.FileUsage, error) {
+	return map[string][]admin.FileUsage{}, nil
+}
+
+func (s *usageStorage) GetSubtitleFileUsage(context.Context) (map[string][]admin.FileUsage, error) {
+	return map[string][]admin.FileUsage{}, nil
+}
+
+// newAuditService создает сервис с локальными библиотеками и карантином во временных директориях
+func newAuditService(t *testing.T, db *usageStorage) (*ServiceAdmin, *mediastore.Local) {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Media.QuarantinePatch = t.TempDir()
+	cfg.Media.QuarantineTTL = 24 * time.Hour
+	cfg.Media.OrphanGrace = time.Hour
+
+	videos := mediastore.NewLocal(t.TempDir())
+
+	return &ServiceAdmin{cfg: cfg, db: db, media: mediastore.Stores{
+		Video:     videos,
+		Images:    mediastore.NewLocal(t.TempDir()),
+		Docs:      mediastore.NewLocal(t.TempDir()),
+		Subtitles: mediastore.NewLocal(t.TempDir()),
+	}}, videos
+}
+
+// saveAged сохраняет файл в библиотеку и сдвигает время его изменения на age назад
+func saveAged(t *testing.T, store *mediastore.Local, name string, age time.Duration) {
+	t.Helper()
+
+	require.NoError(t, store.Save(context.Background(), name, bytes.NewReader([]byte(name)), int64(len(name))))
+	old := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(filepath.Join(store.Dir(), name), old, old))
+}
+
+func TestAuditMedia(t *testing.T) {
+	db := &usageStorage{
+		video: map[string][]admin.FileUsage{"neck.mp4": {{Entity: "video", ID: 1, Field: "url"}}},
+		image: map[string][]admin.FileUsage{"neck.jpg": {{Entity: "video", ID: 1, Field: "img_url"}}},
+	}
+	s, videos := newAuditService(t, db)
+
+	saveAged(t, videos, "neck.mp4", 48*time.Hour)
+	saveAged(t, videos, "old.mp4", 48*time.Hour)
+	saveAged(t, videos, "fresh.mp4", time.Minute)
+
+	audit, err := s.AuditMedia(context.Background())
+	require.NoError(t, err)
+
+	// Недавно загруженный файл без ссылок еще не считается брошенным
+	require.Len(t, audit.Orphans, 2)
+	orphans := map[string]bool{}
+	for _, orphan := range audit.Orphans {
+		assert.Equal(t, admin.MediaKindVideo, orphan.Kind)
+		orphans[orphan.Name] = orphan.InGracePeriod
+	}
+	assert.Equal(t, map[string]bool{"old.mp4": false, "fresh.mp4": true}, orphans)
+
+	// Превью, на которое ссылается видео, не загружено
+	require.Len(t, audit.Missing, 1)
+	assert.Equal(t, admin.MediaKindImage, audit.Missing[0].Kind)
+	assert.Equal(t, "neck.jpg", audit.Missing[0].Name)
+	assert.Equal(t, db.image["neck.jpg"], audit.Missing[0].UsedBy)
+}
+
+func TestCleanupMedia(t *testing.T) {
+	db := &usageStorage{video: map[string][]admin.FileUsage{}, image: map[string][]admin.FileUsage{}}
+	s, videos := newAuditService(t, db)
+	ctx := context.Background()
+
+	saveAged(t, videos, "old.mp4", 48*time.Hour)
+	saveAged(t, videos, "fresh.mp4", time.Minute)
+
+	// Брошенный файл переносится в карантин, а не удаляется сразу
+	results, err := s.CleanupMedia(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []admin.CleanupResult{{Kind: admin.MediaKindVideo, Name: "old.mp4", Action: admin.CleanupQuarantined}}, results)
+
+	quarantined := filepath.Join(s.cfg.Media.QuarantinePatch, admin.MediaKindVideo, "old.mp4")
+	assert.FileExists(t, quarantined)
+	_, err = videos.Stat(ctx, "old.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+	_, err = videos.Stat(ctx, "fresh.mp4")
+	assert.NoError(t, err)
+
+	// На файл снова сослались — он возвращается в библиотеку
+	db.video["old.mp4"] = []admin.FileUsage{{Entity: "video", ID: 2, Field: "url"}}
+	results, err = s.CleanupMedia(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []admin.CleanupResult{{Kind: admin.MediaKindVideo, Name: "old.mp4", Action: admin.CleanupRestored}}, results)
+	assert.NoFileExists(t, quarantined)
+	_, err = videos.Stat(ctx, "old.mp4")
+	assert.NoError(t, err)
+
+	// Ссылку убрали снова: после QuarantineTTL файл удаляется из карантина
+	delete(db.video, "old.mp4")
+	saveAged(t, videos, "old.mp4", 48*time.Hour)
+	_, err = s.CleanupMedia(ctx)
+	require.NoError(t, err)
+
+	expired := time.Now().Add(-2 * s.cfg.Media.QuarantineTTL)
+	require.NoError(t, os.Chtimes(quarantined, expired, expired))
+
+	results, err = s.CleanupMedia(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []admin.CleanupResult{{Kind: admin.MediaKindVideo, Name: "old.mp4", Action: admin.CleanupDeleted}}, results)
+	assert.NoFileExists(t, quarantined)
+}
@@ -6,7 +6,10 @@ import (
 
 type CashStorage interface {
 	InvalidateVideosCache(ctx context.Context) error
+	InvalidateVideoCache(ctx context.Context) error
 	InvalidateCategoriesCache(ctx context.Context) error
 	InvalidateAccountsCache(ctx context.Context) error
+	InvalidateAnnouncementsCache(ctx context.Context) error
+	InvalidateAppConfigCache(ctx context.Context) error
 	InvalidateAllCache(ctx context.Context) error
 }
@@ -0,0 +1,245 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// ExportCatalog возвращает выгрузку каталога, при includeMedia добавляет список используемых медиафайлов
+func (s *ServiceAdmin) ExportCatalog(ctx context.Context, includeMedia bool) (*admin.CatalogBundle, error) {
+	const op = "service.ExportCatalog"
+
+	bundle, err := s.db.ExportCatalog(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to export catalog", "op", op, sl.Err(err))
+		return nil, admin.ErrCatalogExportFailed
+	}
+
+	if includeMedia {
+		bundle.Media = catalogMedia(bundle)
+	}
+
+	return bundle, nil
+}
+
+// ImportCatalog проверяет выгрузку и применяет ее. При dryRun изменения не сохраняются.
+func (s *ServiceAdmin) ImportCatalog(ctx context.Context, bundle *admin.CatalogBundle, dryRun bool) (*admin.ImportDiff, error) {
+	const op = "service.ImportCatalog"
+
+	if err := validCatalog(bundle); err != nil {
+		logging.L(ctx).Warn("invalid catalog bundle", "op", op, sl.Err(err))
+		return nil, err
+	}
+
+	diff, err := s.db.ImportCatalog(ctx, bundle, dryRun)
+	if err != nil {
+		if errors.Is(err, admin.ErrCatalogUnknownReference) {
+			logging.L(ctx).Warn("catalog bundle references unknown entity", "op", op, sl.Err(err))
+			return nil, err
+		}
+		if errors.Is(err, admin.ErrCatalogInvalid) {
+			logging.L(ctx).Warn("invalid catalog bundle", "op", op, sl.Err(err))
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to import catalog", "op", op, sl.Err(err))
+		return nil, admin.ErrCatalogImportFailed
+	}
+
+	diff.MissingMedia = s.missingMedia(ctx, bundle.Media)
+
+	if !dryRun {
+		if s.cfg.Redis.Enable == true {
+			go s.removeCache(ctx, op)
+		}
+
+		s.emitImportEvents(ctx, bundle, diff)
+	}
+
+	return diff, nil
+}
+
+// emitImportEvents отправляет подписчикам webhook те же события, что и изменения категорий и видео по одному
+func (s *ServiceAdmin) emitImportEvents(ctx context.Context, bundle *admin.CatalogBundle, diff *admin.ImportDiff) {
+	for _, name := range diff.Categories.Created {
+		s.emitWebhookEvent(ctx, admin.EventCategoryCreated, map[string]any{"id": diff.Categories.IDs[name], "name": name})
+	}
+	for _, name := range diff.Categories.Updated {
+		s.emitWebhookEvent(ctx, admin.EventCategoryUpdated, map[string]any{"id": diff.Categories.IDs[name], "name": name})
+	}
+
+	// Видео в выгрузке определяются по имени файла, а в событии передается название
+	names := make(map[string]string, len(bundle.Videos))
+	for _, video := range bundle.Videos {
+		names[video.URL] = video.Name
+	}
+
+	for _, url := range diff.Videos.Created {
+		s.emitWebhookEvent(ctx, admin.EventVideoCreated, map[string]any{"id": diff.Videos.IDs[url], "name": names[url]})
+	}
+	for _, url := range diff.Videos.Updated {
+		s.emitWebhookEvent(ctx, admin.EventVideoUpdated, map[string]any{"id": diff.Videos.IDs[url], "name": names[url]})
+	}
+}
+
+// missingMedia возвращает файлы из списка выгрузки, которых нет на этом сервере
+func (s *ServiceAdmin) missingMedia(ctx context.Context, media *admin.CatalogMedia) []string {
+	missing := make([]string, 0)
+	if media == nil {
+		return missing
+	}
+
+	check := func(store mediastore.MediaStore, names []string) {
+		for _, name := range names {
+			if _, err := store.Stat(ctx, name); err != nil {
+				missing = append(missing, name)
+			}
+		}
+	}
+
+	check(s.media.Video, media.Videos)
+	check(s.media.Images, media.Images)
+
+	return missing
+}
+
+// catalogMedia собирает уникальные имена видео и изображений, на которые ссылается выгрузка
+func catalogMedia(bundle *admin.CatalogBundle) *admin.CatalogMedia {
+	media := &admin.CatalogMedia{
+		Videos: make([]string, 0, len(bundle.Videos)),
+		Images: make([]string, 0, len(bundle.Videos)+len(bundle.Categories)),
+	}
+
+	for _, video := range bundle.Videos {
+		media.Videos = append(media.Videos, video.URL)
+		if video.ImgURL != "" {
+			media.Images = append(media.Images, video.ImgURL)
+		}
+	}
+
+	for _, category := range bundle.Categories {
+		if category.ImgURL != "" {
+			media.Images = append(media.Images, category.ImgURL)
+		}
+	}
+
+	slices.Sort(media.Videos)
+	slices.Sort(media.Images)
+	media.Videos = slices.Compact(media.Videos)
+	media.Images = slices.Compact(media.Images)
+
+	return media
+}
+
+// validCatalog проверяет выгрузку целиком до начала импорта и нормализует теги видео
+func validCatalog(bundle *admin.CatalogBundle) error {
+	if bundle.Version != admin.CatalogBundleVersion {
+		return fmt.Errorf("%w: %d", admin.ErrCatalogVersion, bundle.Version)
+	}
+
+	typeNames := make(map[string]bool, len(bundle.ContentTypes))
+	for _, contentType := range bundle.ContentTypes {
+		switch {
+		case strings.TrimSpace(contentType.Name) == "":
+			return fmt.Errorf("%w: empty content type name", admin.ErrCatalogInvalid)
+		case typeNames[contentType.Name]:
+			return fmt.Errorf("%w: duplicate content type %q", admin.ErrCatalogInvalid, contentType.Name)
+		}
+		typeNames[contentType.Name] = true
+	}
+
+	categoryNames := make(map[string]bool, len(bundle.Categories))
+	for _, category := range bundle.Categories {
+		switch {
+		case strings.TrimSpace(category.Name) == "":
+			return fmt.Errorf("%w: empty category name", admin.ErrCatalogInvalid)
+		case categoryNames[category.Name]:
+			return fmt.Errorf("%w: duplicate category %q", admin.ErrCatalogInvalid, category.Name)
+		case len(category.ContentTypes) == 0:
+			return fmt.Errorf("%w: category %q has no content types", admin.ErrCatalogInvalid, category.Name)
+		case !validMediaName(category.ImgURL):
+			return fmt.Errorf("%w: invalid image %q of category %q", admin.ErrCatalogInvalid, category.ImgURL, category.Name)
+		}
+		categoryNames[category.Name] = true
+	}
+
+	for _, category := range bundle.Categories {
+		if category.Parent == category.Name {
+			return fmt.Errorf("%w: category %q is its own parent", admin.ErrCatalogInvalid, category.Name)
+		}
+	}
+
+	videoURLs := make(map[string]bool, len(bundle.Videos))
+	for i := range bundle.Videos {
+		video := &bundle.Videos[i]
+
+		switch {
+		case !validMediaName(video.URL):
+			return fmt.Errorf("%w: invalid video file %q", admin.ErrCatalogInvalid, video.URL)
+		case videoURLs[video.URL]:
+			return fmt.Errorf("%w: duplicate video %q", admin.ErrCatalogInvalid, video.URL)
+		case strings.TrimSpace(video.Name) == "":
+			return fmt.Errorf("%w: empty name of video %q", admin.ErrCatalogInvalid, video.URL)
+		case len(video.Categories) == 0:
+			return fmt.Errorf("%w: video %q has no categories", admin.ErrCatalogInvalid, video.URL)
+		case !validMediaName(video.ImgURL):
+			return fmt.Errorf("%w: invalid image %q of video %q", admin.ErrCatalogInvalid, video.ImgURL, video.URL)
+		}
+		videoURLs[video.URL] = true
+
+		tags, err := normalizeTags(video.Tags)
+		if err != nil {
+			return fmt.Errorf("%w: invalid tags of video %q", admin.ErrCatalogInvalid, video.URL)
+		}
+		video.Tags = tags
+
+		nextUp := make([]string, 0, len(video.NextUp))
+		for _, url := range video.NextUp {
+			if url == video.URL || !validMediaName(url) {
+				return fmt.Errorf("%w: invalid next up video %q of video %q", admin.ErrCatalogInvalid, url, video.URL)
+			}
+			if !slices.Contains(nextUp, url) {
+				nextUp = append(nextUp, url)
+			}
+		}
+		video.NextUp = nextUp
+
+		details, err := normalizeExerciseDetails(video.Details)
+		if err != nil {
+			return fmt.Errorf("%w: video %q: %v", admin.ErrCatalogInvalid, video.URL, err)
+		}
+		video.Details = details
+	}
+
+	if bundle.Media != nil {
+		for _, name := range slices.Concat(bundle.Media.Videos, bundle.Media.Images) {
+			if !validMediaName(name) {
+				return fmt.Errorf("%w: invalid media file %q", admin.ErrCatalogInvalid, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validMediaName проверяет имя медиафайла так же, как при создании видео и категорий
+func validMediaName(name string) bool {
+	if !validFilePattern.MatchString(name) {
+		return false
+	}
+
+	for _, pattern := range suspiciousPatterns {
+		if strings.Contains(name, pattern) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalogStorage возвращает заданный результат импорта и запоминает события webhook
+type catalogStorage struct {
+	AdmStorage
+	diff   admin.ImportDiff
+	events []string
+}
+
+func (s *catalogStorage) ImportCatalog(_ context.Context, _ *admin.CatalogBundle, dryRun bool) (*admin.ImportDiff, error) {
+	diff := s.diff
+	diff.DryRun = dryRun
+	return &diff, nil
+}
+
+func (s *catalogStorage) EnqueueWebhookEvent(_ context.Context, event string, payload []byte) (int64, error) {
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return 0, err
+	}
+	s.events = append(s.events, fmt.Sprint(event, " ", body.Data["id"], " ", body.Data["name"]))
+	return 1, nil
+}
+
+func TestImportCatalog_WebhookEvents(t *testing.T) {
+	db := &catalogStorage{}
+	db.diff.Categories.AddCreated("Шея", 1)
+	db.diff.Categories.AddUpdated("Спина", 2)
+	db.diff.Categories.Unchanged = []string{"Колени"}
+	db.diff.Videos.AddCreated("neck.mp4", 10)
+	db.diff.Videos.AddUpdated("back.mp4", 11)
+
+	s := &ServiceAdmin{cfg: &config.Config{}, db: db}
+	bundle := &admin.CatalogBundle{
+		Version:      admin.CatalogBundleVersion,
+		ContentTypes: []admin.CatalogContentType{{Name: "Пациент"}},
+		Categories: []admin.CatalogCategory{
+			{Name: "Шея", ImgURL: "neck.jpg", ContentTypes: []string{"Пациент"}},
+			{Name: "Спина", ImgURL: "back.jpg", ContentTypes: []string{"Пациент"}},
+			{Name: "Колени", ImgURL: "knee.jpg", ContentTypes: []string{"Пациент"}},
+		},
+		Videos: []admin.CatalogVideo{
+			{URL: "neck.mp4", Name: "Разминка шеи", ImgURL: "neck.jpg", Categories: []string{"Шея"}},
+			{URL: "back.mp4", Name: "Растяжка спины", ImgURL: "back.jpg", Categories: []string{"Спина"}},
+		},
+	}
+
+	// Пробный запуск ничего не отправляет
+	_, err := s.ImportCatalog(context.Background(), bundle, true)
+	require.NoError(t, err)
+	assert.Empty(t, db.events)
+
+	_, err = s.ImportCatalog(context.Background(), bundle, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		admin.EventCategoryCreated + " 1 Шея",
+		admin.EventCategoryUpdated + " 2 Спина",
+		admin.EventVideoCreated + " 10 Разминка шеи",
+		admin.EventVideoUpdated + " 11 Растяжка спины",
+	}, db.events)
+}
@@ -12,14 +12,28 @@ import (
 func (s *ServiceAdmin) AddCategory(ctx context.Context, req *admin.Category) (*admin.Category, error) {
 	const op = "service.AddCategory"
 
-	err := s.validCat(req)
+	err := s.validCat(ctx, 0, req)
 	if err != nil {
 		logging.L(ctx).Error("invalid category data", "op", op, "category", req, sl.Err(err))
 		return nil, err
 	}
 
+	err = s.checkCategoryFiles(ctx, req)
+	if err != nil {
+		if !errors.Is(err, admin.ErrImgNotFound) {
+			logging.L(ctx).Error("failed to check category image", "op", op, "img_url", req.ImgURL, sl.Err(err))
+			return nil, err
+		}
+		logging.L(ctx).Warn("category image file not found", "op", op, "img_url", req.ImgURL, sl.Err(err))
+		return nil, err
+	}
+
 	category, err := s.db.AddCategory(ctx, req)
 	if err != nil {
+		if errors.Is(err, admin.ErrParentNotFound) || errors.Is(err, admin.ErrCategoryCycle) {
+			logging.L(ctx).Warn("invalid parent category", "op", op, "parent_id", req.ParentID, sl.Err(err))
+			return nil, err
+		}
 		logging.L(ctx).Error("failed to add category", "op", op, "category", category, sl.Err(err))
 		return nil, err
 	}
@@ -28,6 +42,8 @@ func (s *ServiceAdmin) AddCategory(ctx context.Context, req *admin.Category) (*a
 		go s.removeCache(ctx, op)
 	}
 
+	s.emitWebhookEvent(ctx, admin.EventCategoryCreated, map[string]any{"id": category.ID, "name": category.Name})
+
 	return category, nil
 }
 
@@ -62,18 +78,32 @@ func (s *ServiceAdmin) GetCategories(ctx context.Context) ([]admin.Category, err
 func (s *ServiceAdmin) UpdateCategory(ctx context.Context, id int64, req *admin.Category) error {
 	const op = "service.UpdateCategory"
 
-	err := s.validCat(req)
+	err := s.validCat(ctx, id, req)
 	if err != nil {
 		logging.L(ctx).Error("invalid category data", "op", op, "category_id", id, sl.Err(err))
 		return err
 	}
 
+	err = s.checkCategoryFiles(ctx, req)
+	if err != nil {
+		if !errors.Is(err, admin.ErrImgNotFound) {
+			logging.L(ctx).Error("failed to check category image", "op", op, "category_id", id, "img_url", req.ImgURL, sl.Err(err))
+			return err
+		}
+		logging.L(ctx).Warn("category image file not found", "op", op, "category_id", id, "img_url", req.ImgURL, sl.Err(err))
+		return err
+	}
+
 	err = s.db.UpdateCategory(ctx, id, req)
 	if err != nil {
 		if errors.Is(err, admin.ErrCategoryNotFound) {
 			logging.L(ctx).Warn("category not found", "op", op, "category_id", id, sl.Err(err))
 			return err
 		}
+		if errors.Is(err, admin.ErrParentNotFound) || errors.Is(err, admin.ErrCategoryCycle) {
+			logging.L(ctx).Warn("invalid parent category", "op", op, "category_id", id, "parent_id", req.ParentID, sl.Err(err))
+			return err
+		}
 		logging.L(ctx).Error("failed to update category", "op", op, "category_id", id, sl.Err(err))
 		return err
 	}
@@ -82,6 +112,8 @@ func (s *ServiceAdmin) UpdateCategory(ctx context.Context, id int64, req *admin.
 		go s.removeCache(ctx, op)
 	}
 
+	s.emitWebhookEvent(ctx, admin.EventCategoryUpdated, map[string]any{"id": id, "name": req.Name})
+
 	return nil
 }
 
@@ -102,11 +134,14 @@ func (s *ServiceAdmin) DeleteCategory(ctx context.Context, id int64) error {
 		go s.removeCache(ctx, op)
 	}
 
+	s.emitWebhookEvent(ctx, admin.EventCategoryDeleted, map[string]any{"id": id})
+
 	return nil
 }
 
-// validCat проверят данные входящего запроса на их валидность
-func (s *ServiceAdmin) validCat(category *admin.Category) error {
+// validCat проверят данные входящего запроса на их валидность.
+// id — ID изменяемой категории, для новой категории 0.
+func (s *ServiceAdmin) validCat(ctx context.Context, id int64, category *admin.Category) error {
 	switch {
 	case category.Name == "":
 		return admin.ErrEmptyName
@@ -124,5 +159,18 @@ func (s *ServiceAdmin) validCat(category *admin.Category) error {
 		}
 	}
 
+	return validParent(id, category.ParentID)
+}
+
+// validParent отклоняет заведомо неверного родителя. Существование родителя и отсутствие цикла проверяет
+// хранилище в транзакции изменения: проверка здесь не защитила бы от встречных переносов категорий.
+func validParent(id, parentID int64) error {
+	switch {
+	case parentID < 0:
+		return admin.ErrParentNotFound
+	case parentID != 0 && parentID == id:
+		return admin.ErrCategoryCycle
+	}
+
 	return nil
 }
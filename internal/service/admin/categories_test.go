@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// categoryStorage дерево категорий, которое, как Postgres, проверяет родителя под блокировкой дерева
+type categoryStorage struct {
+	AdmStorage
+	mu      sync.Mutex
+	parents map[int64]int64 // 0 — корневая категория
+	ready   *sync.WaitGroup // Если задан, изменение ждет, пока до хранилища дойдут все запросы
+}
+
+func (s *categoryStorage) UpdateCategory(_ context.Context, id int64, req *admin.Category) error {
+	if s.ready != nil {
+		s.ready.Done()
+		s.ready.Wait()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.parents[id]; !ok {
+		return admin.ErrCategoryNotFound
+	}
+
+	if req.ParentID != 0 {
+		if _, ok := s.parents[req.ParentID]; !ok {
+			return admin.ErrParentNotFound
+		}
+		for p := req.ParentID; p != 0; p = s.parents[p] {
+			if p == id {
+				return admin.ErrCategoryCycle
+			}
+		}
+	}
+
+	s.parents[id] = req.ParentID
+	return nil
+}
+
+func (s *categoryStorage) EnqueueWebhookEvent(context.Context, string, []byte) (int64, error) {
+	return 0, nil
+}
+
+func newCategory(parentID int64) *admin.Category {
+	return &admin.Category{
+		Name:        "Шея",
+		ImgURL:      "neck.jpg",
+		ParentID:    parentID,
+		ContentType: []admin.ContentType{{ID: 1}},
+	}
+}
+
+func TestUpdateCategory_ConcurrentCycle(t *testing.T) {
+	ready := &sync.WaitGroup{}
+	ready.Add(2)
+	db := &categoryStorage{parents: map[int64]int64{1: 0, 2: 0}, ready: ready}
+	s := &ServiceAdmin{cfg: &config.Config{}, db: db}
+	ctx := context.Background()
+
+	// Встречные переносы A в B и B в A: по отдельности каждый допустим, вместе они дали бы цикл
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i, move := range [][2]int64{{1, 2}, {2, 1}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.UpdateCategory(ctx, move[0], newCategory(move[1]))
+		}()
+	}
+	wg.Wait()
+
+	// Проходит только один перенос, второй видит его результат
+	if errs[0] == nil {
+		assert.ErrorIs(t, errs[1], admin.ErrCategoryCycle)
+	} else {
+		assert.ErrorIs(t, errs[0], admin.ErrCategoryCycle)
+		assert.NoError(t, errs[1])
+	}
+	assert.True(t, db.parents[1] == 0 || db.parents[2] == 0)
+}
+
+func TestUpdateCategory_InvalidParent(t *testing.T) {
+	db := &categoryStorage{parents: map[int64]int64{1: 0, 2: 1, 3: 2}}
+	s := &ServiceAdmin{cfg: &config.Config{}, db: db}
+	ctx := context.Background()
+
+	// Родитель не существует
+	err := s.UpdateCategory(ctx, 1, newCategory(99))
+	require.ErrorIs(t, err, admin.ErrParentNotFound)
+
+	err = s.UpdateCategory(ctx, 1, newCategory(-1))
+	require.ErrorIs(t, err, admin.ErrParentNotFound)
+
+	// Категория в самой себе и в собственной подкатегории
+	err = s.UpdateCategory(ctx, 1, newCategory(1))
+	require.ErrorIs(t, err, admin.ErrCategoryCycle)
+
+	err = s.UpdateCategory(ctx, 1, newCategory(3))
+	require.ErrorIs(t, err, admin.ErrCategoryCycle)
+
+	assert.Equal(t, map[int64]int64{1: 0, 2: 1, 3: 2}, db.parents)
+
+	// Перенос в соседнюю ветку допустим
+	require.NoError(t, s.UpdateCategory(ctx, 3, newCategory(1)))
+	assert.Equal(t, int64(1), db.parents[3])
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+const (
+	diaryTrendDefaultRange = 90 * 24 * time.Hour  // Период динамики по умолчанию
+	diaryTrendMaxRange     = 731 * 24 * time.Hour // Не больше двух лет за один запрос
+)
+
+// GetUserDiary возвращает записи дневника боли пациента за период
+func (s *ServiceAdmin) GetUserDiary(ctx context.Context, userID int64, filter *admin.DiaryFilter) ([]admin.DiaryEntry, error) {
+	const op = "service.GetUserDiary"
+
+	if userID <= 0 {
+		logging.L(ctx).Warn("invalid user ID", "op", op, "user_id", userID)
+		return nil, admin.ErrUserInvalidID
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		logging.L(ctx).Warn("invalid diary range", "op", op, "from", filter.From, "to", filter.To)
+		return nil, admin.ErrDiaryInvalidRange
+	}
+
+	entries, err := s.db.GetUserDiary(ctx, userID, filter)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			logging.L(ctx).Warn("user not found", "op", op, "user_id", userID)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get user diary", "op", op, "user_id", userID, sl.Err(err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetDiaryTrend возвращает динамику боли по типу контента. По умолчанию — по неделям за последние 90 дней.
+func (s *ServiceAdmin) GetDiaryTrend(ctx context.Context, filter *admin.DiaryTrendFilter) ([]admin.DiaryTrendPoint, error) {
+	const op = "service.GetDiaryTrend"
+
+	if filter.ContentTypeID <= 0 {
+		logging.L(ctx).Warn("invalid content type ID", "op", op, "type_id", filter.ContentTypeID)
+		return nil, admin.ErrTypeInvalid
+	}
+
+	switch filter.Period {
+	case "":
+		filter.Period = admin.DiaryPeriodWeek
+	case admin.DiaryPeriodDay, admin.DiaryPeriodWeek, admin.DiaryPeriodMonth:
+	default:
+		logging.L(ctx).Warn("invalid diary period", "op", op, "period", filter.Period)
+		return nil, admin.ErrDiaryInvalidPeriod
+	}
+
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-diaryTrendDefaultRange)
+	}
+
+	if filter.From.After(filter.To) || filter.To.Sub(filter.From) > diaryTrendMaxRange {
+		logging.L(ctx).Warn("invalid diary range", "op", op, "from", filter.From, "to", filter.To)
+		return nil, admin.ErrDiaryInvalidRange
+	}
+
+	points, err := s.db.GetDiaryTrend(ctx, filter)
+	if err != nil {
+		if errors.Is(err, admin.ErrTypeNotFound) {
+			logging.L(ctx).Warn("content type not found", "op", op, "type_id", filter.ContentTypeID)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get diary trend", "op", op, "type_id", filter.ContentTypeID, sl.Err(err))
+		return nil, err
+	}
+
+	return points, nil
+}
@@ -0,0 +1,174 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+func (s *ServiceAdmin) AddDocument(ctx context.Context, req *admin.Document) (int64, error) {
+	const op = "service.AddDocument"
+
+	err := s.validDocument(ctx, req)
+	if err != nil {
+		logging.L(ctx).Warn("invalid document data", "op", op, "title", req.Title, "url", req.URL, sl.Err(err))
+		return 0, err
+	}
+
+	id, err := s.db.AddDocument(ctx, req)
+	if err != nil {
+		if errors.Is(err, admin.ErrVideoNotFound) || errors.Is(err, admin.ErrCategoryNotFound) {
+			logging.L(ctx).Warn("document link target not found", "op", op, "video_ids", req.VideoIDs, "category_ids", req.CategoryIDs, sl.Err(err))
+			return 0, err
+		}
+		logging.L(ctx).Error("failed to add document", "op", op, sl.Err(err))
+		return 0, err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+	}
+
+	return id, nil
+}
+
+func (s *ServiceAdmin) GetDocument(ctx context.Context, id int64) (*admin.Document, error) {
+	const op = "service.GetDocument"
+
+	document, err := s.db.GetDocument(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrDocumentNotFound) {
+			logging.L(ctx).Warn("document not found", "op", op, "document_id", id)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get document", "op", op, "document_id", id, sl.Err(err))
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (s *ServiceAdmin) GetDocuments(ctx context.Context) ([]admin.Document, error) {
+	const op = "service.GetDocuments"
+
+	documents, err := s.db.GetDocuments(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get documents", "op", op, sl.Err(err))
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (s *ServiceAdmin) UpdateDocument(ctx context.Context, req *admin.Document) error {
+	const op = "service.UpdateDocument"
+
+	err := s.validDocument(ctx, req)
+	if err != nil {
+		logging.L(ctx).Warn("invalid document data", "op", op, "document_id", req.ID, "url", req.URL, sl.Err(err))
+		return err
+	}
+
+	err = s.db.UpdateDocument(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrDocumentNotFound):
+			logging.L(ctx).Warn("document not found", "op", op, "document_id", req.ID)
+		case errors.Is(err, admin.ErrVideoNotFound), errors.Is(err, admin.ErrCategoryNotFound):
+			logging.L(ctx).Warn("document link target not found", "op", op, "video_ids", req.VideoIDs, "category_ids", req.CategoryIDs, sl.Err(err))
+		default:
+			logging.L(ctx).Error("failed to update document", "op", op, "document_id", req.ID, sl.Err(err))
+		}
+		return err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+	}
+
+	return nil
+}
+
+func (s *ServiceAdmin) DeleteDocument(ctx context.Context, id int64) error {
+	const op = "service.DeleteDocument"
+
+	err := s.db.DeleteDocument(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrDocumentNotFound) {
+			logging.L(ctx).Warn("document not found", "op", op, "document_id", id)
+			return err
+		}
+		logging.L(ctx).Error("failed to delete document", "op", op, "document_id", id, sl.Err(err))
+		return err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+	}
+
+	return nil
+}
+
+// validDocument проверяет заголовок, имя файла и список видео и категорий документа
+func (s *ServiceAdmin) validDocument(ctx context.Context, req *admin.Document) error {
+	req.Title = strings.TrimSpace(req.Title)
+	req.URL = strings.TrimSpace(req.URL)
+
+	switch {
+	case req.Title == "":
+		return admin.ErrDocumentEmptyTitle
+	case req.URL == "":
+		return admin.ErrDocumentEmptyURL
+	case !validFilePattern.MatchString(req.URL) || !strings.EqualFold(filepath.Ext(req.URL), ".pdf"):
+		return admin.ErrDocumentURLPattern
+	}
+
+	for _, pattern := range suspiciousPatterns {
+		if strings.Contains(req.URL, pattern) {
+			return admin.ErrDocumentSuspiciousPattern
+		}
+	}
+
+	var ok bool
+	if req.VideoIDs, ok = uniqueIDs(req.VideoIDs); !ok {
+		return admin.ErrDocumentInvalidLink
+	}
+	if req.CategoryIDs, ok = uniqueIDs(req.CategoryIDs); !ok {
+		return admin.ErrDocumentInvalidLink
+	}
+
+	if s.cfg.Media.CheckFiles {
+		exists, err := fileExists(ctx, s.media.Docs, req.URL)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return admin.ErrDocumentFileNotFound
+		}
+	}
+
+	return nil
+}
+
+// uniqueIDs убирает повторы с сохранением порядка, false — если встретился ID меньше 1
+func uniqueIDs(ids []int64) ([]int64, bool) {
+	result := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, false
+		}
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result, true
+}
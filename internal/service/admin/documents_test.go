@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// documentStorage сохраняет и удаляет документы; остальные методы AdmStorage не нужны
+type documentStorage struct {
+	AdmStorage
+}
+
+func (documentStorage) UpdateDocument(context.Context, *admin.Document) error { return nil }
+func (documentStorage) DeleteDocument(context.Context, int64) error           { return nil }
+
+func TestDocuments_InvalidateVideoCache(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Redis.Enable = true
+	cache := newMemCache()
+	s := &ServiceAdmin{cfg: cfg, db: documentStorage{}, redis: cache}
+	ctx := context.Background()
+
+	// Документы видео отдаются в /v1/video из кэша video:<id>
+	cache.set("video:7")
+	doc := &admin.Document{ID: 1, Title: "Памятка", URL: "memo.pdf", VideoIDs: []int64{7}}
+	require.NoError(t, s.UpdateDocument(ctx, doc))
+	assert.Eventually(t, func() bool { return !cache.has("video:7") }, time.Second, 10*time.Millisecond)
+
+	cache.set("video:7")
+	require.NoError(t, s.DeleteDocument(ctx, 1))
+	assert.Eventually(t, func() bool { return !cache.has("video:7") }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// maxFeedbackNoteLength ограничение длины внутренней заметки в символах
+const maxFeedbackNoteLength = 4000
+
+// ListFeedback возвращает страницу обратной связи и счетчики непрочитанного для администратора username
+func (s *ServiceAdmin) ListFeedback(ctx context.Context, username string, filter *admin.FeedbackFilter) (*admin.FeedbackList, error) {
+	const op = "service.ListFeedback"
+
+	if filter.Status != "" && !validFeedbackStatus(filter.Status) {
+		logging.L(ctx).Warn("invalid feedback status", "op", op, "status", filter.Status)
+		return nil, admin.ErrFeedbackInvalidStatus
+	}
+
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = admin.FeedbackDefaultLimit
+	}
+	if filter.Limit > admin.FeedbackMaxLimit {
+		filter.Limit = admin.FeedbackMaxLimit
+	}
+
+	list, err := s.db.ListFeedback(ctx, username, filter)
+	if err != nil {
+		logging.L(ctx).Error("failed to list feedback", "op", op, sl.Err(err))
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetFeedback возвращает сообщение с заметками и отмечает его прочитанным администратором username
+func (s *ServiceAdmin) GetFeedback(ctx context.Context, id int64, username string) (*admin.Feedback, error) {
+	const op = "service.GetFeedback"
+
+	feedback, err := s.db.GetFeedback(ctx, id, username)
+	if err != nil {
+		if errors.Is(err, admin.ErrFeedbackNotFound) {
+			logging.L(ctx).Warn("feedback not found", "op", op, "feedback_id", id)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get feedback", "op", op, "feedback_id", id, sl.Err(err))
+		return nil, err
+	}
+
+	if !feedback.Read {
+		// Ошибка отметки не мешает показать сообщение
+		if err = s.db.MarkFeedbackRead(ctx, id, username); err != nil {
+			logging.L(ctx).Warn("failed to mark feedback as read", "op", op, "feedback_id", id, sl.Err(err))
+		}
+	}
+
+	return feedback, nil
+}
+
+func (s *ServiceAdmin) UpdateFeedbackStatus(ctx context.Context, id int64, status string) error {
+	const op = "service.UpdateFeedbackStatus"
+
+	if !validFeedbackStatus(status) {
+		logging.L(ctx).Warn("invalid feedback status", "op", op, "feedback_id", id, "status", status)
+		return admin.ErrFeedbackInvalidStatus
+	}
+
+	err := s.db.UpdateFeedbackStatus(ctx, id, status)
+	if err != nil {
+		if errors.Is(err, admin.ErrFeedbackNotFound) {
+			logging.L(ctx).Warn("feedback not found", "op", op, "feedback_id", id)
+			return err
+		}
+		logging.L(ctx).Error("failed to update feedback status", "op", op, "feedback_id", id, sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *ServiceAdmin) AssignFeedback(ctx context.Context, id, assigneeID int64) error {
+	const op = "service.AssignFeedback"
+
+	if assigneeID < 0 {
+		return admin.ErrFeedbackAssigneeNotAdmin
+	}
+
+	err := s.db.AssignFeedback(ctx, id, assigneeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrFeedbackNotFound):
+			logging.L(ctx).Warn("feedback not found", "op", op, "feedback_id", id)
+		case errors.Is(err, admin.ErrFeedbackAssigneeNotAdmin):
+			logging.L(ctx).Warn("assignee is not an admin", "op", op, "feedback_id", id, "assignee_id", assigneeID)
+		default:
+			logging.L(ctx).Error("failed to assign feedback", "op", op, "feedback_id", id, sl.Err(err))
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *ServiceAdmin) AddFeedbackNote(ctx context.Context, id int64, username, text string) (*admin.FeedbackNote, error) {
+	const op = "service.AddFeedbackNote"
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, admin.ErrFeedbackEmptyNote
+	}
+
+	if utf8.RuneCountInString(text) > maxFeedbackNoteLength {
+		return nil, admin.ErrFeedbackNoteTooLong
+	}
+
+	note, err := s.db.AddFeedbackNote(ctx, id, username, text)
+	if err != nil {
+		if errors.Is(err, admin.ErrFeedbackNotFound) {
+			logging.L(ctx).Warn("feedback not found", "op", op, "feedback_id", id)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to add feedback note", "op", op, "feedback_id", id, sl.Err(err))
+		return nil, err
+	}
+
+	return note, nil
+}
+
+func validFeedbackStatus(status string) bool {
+	switch status {
+	case admin.FeedbackStatusNew, admin.FeedbackStatusInProgress, admin.FeedbackStatusResolved:
+		return true
+	}
+	return false
+}
@@ -1,62 +1,202 @@
 package admin
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gabriel-vasile/mimetype"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
 	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/faststart"
+	"github.com/langowen/bodybalance-backend/pkg/lib/imgsafe"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
 	"github.com/theartofdevel/logging"
 	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
 const (
 	videoMIMETypes = "video/mp4,video/quicktime,video/webm,video/ogg"
 	imageMIMETypes = "image/jpeg,image/png,image/gif,image/webp,image/svg+xml"
+	docMIMEType    = "application/pdf"
+
+	// faststartMIMETypes форматы на базе ISO BMFF, в которых можно перенести moov
+	faststartMIMETypes = "video/mp4,video/quicktime"
+	// faststartTmpSuffix суффикс временной копии файла на время перезаписи
+	faststartTmpSuffix = ".faststart.tmp"
 )
 
-func (s *ServiceAdmin) UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader) error {
+// imageExtensions допустимые расширения для каждого типа изображения. Файл отдается с Content-Type
+// по расширению, поэтому GIF с именем x.html ушел бы браузеру как text/html.
+var imageExtensions = map[string][]string{
+	"image/jpeg":    {".jpg", ".jpeg"},
+	"image/png":     {".png"},
+	"image/gif":     {".gif"},
+	"image/webp":    {".webp"},
+	"image/svg+xml": {".svg"},
+}
+
+func (s *ServiceAdmin) UploadFile(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error) {
 	const op = "service.UploadFile"
 
 	if !validFilePattern.MatchString(header.Filename) {
 		logging.L(ctx).Warn("invalid file format in URL", "url", header.Filename, "op", op)
-		return admin.ErrInvalidFileName
+		return nil, admin.ErrInvalidFileName
 	}
 
 	buff := make([]byte, 512)
 	if _, err := file.Read(buff); err != nil {
 		logging.L(ctx).Error("Failed to read file header", sl.Err(err), "op", op)
-		return admin.ErrFailedToReadFile
+		return nil, admin.ErrFailedToReadFile
 	}
 
-	mimeType := mimetype.Detect(buff)
-
-	if !strings.Contains(videoMIMETypes, mimeType.String()) {
-		logging.L(ctx).Error("Invalid image type", "content_type", mimeType.String(), "op", op)
-		return admin.ErrFileTypeNotSupported
+	mimeType, ok := detectVideoMIME(buff)
+	if !ok {
+		logging.L(ctx).Error("Invalid image type", "content_type", mimeType, "op", op)
+		return nil, admin.ErrFileTypeNotSupported
 	}
 
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		logging.L(ctx).Error("Failed to reset file position", sl.Err(err), "op", op)
-		return admin.ErrFailedToReadFile
+		return nil, admin.ErrFailedToReadFile
 	}
 
-	if err := saveFile(header.Filename, file, s.cfg.Media.VideoPatch); err != nil {
-		logging.L(ctx).Error("Failed to save image", sl.Err(err), "op", op)
-		return admin.ErrFailedToSaveFile
+	saved, err := s.saveVideo(ctx, header.Filename, file, header.Size, mimeType, op)
+	if err != nil {
+		logging.L(ctx).Error("Failed to save video", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
 	}
 
-	return nil
+	return saved, nil
+}
+
+// detectVideoMIME определяет MIME тип по первым байтам файла и проверяет, что это поддерживаемое видео
+func detectVideoMIME(header []byte) (string, bool) {
+	mimeType := mimetype.Detect(header).String()
+
+	return mimeType, strings.Contains(videoMIMETypes, mimeType)
+}
+
+// saveVideo сохраняет видео в библиотеку. Если включен faststart, сохраняется оптимизированная
+// временная копия из UPLOADS_PATCH, ошибка оптимизации не прерывает загрузку: сохраняется исходный файл.
+func (s *ServiceAdmin) saveVideo(ctx context.Context, filename string, src io.ReadSeeker, size int64, mimeType, op string) (*admin.SavedFile, error) {
+	if s.needsFaststart(mimeType) {
+		tmp, err := faststartCopy(src, size, s.cfg.Media.UploadsPatch)
+		switch {
+		case err != nil:
+			logging.L(ctx).Warn("Failed to apply faststart, original file kept", "file", filename, sl.Err(err), "op", op)
+		case tmp != nil:
+			defer func() {
+				tmp.Close()
+				os.Remove(tmp.Name())
+			}()
+
+			logging.L(ctx).Info("moov atom moved to the beginning of file", "file", filename, "op", op)
+			src = tmp
+		}
+
+		if _, err = src.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind video: %w", err)
+		}
+	}
+
+	return saveFile(ctx, s.media.Video, filename, src, size)
+}
+
+func (s *ServiceAdmin) needsFaststart(mimeType string) bool {
+	return s.cfg.Media.Faststart && strings.Contains(faststartMIMETypes, mimeType)
+}
+
+// OptimizeVideoFiles применяет faststart ко всем видеофайлам в библиотеке.
+// Оптимизированная копия сохраняется под тем же именем как новое содержимое.
+func (s *ServiceAdmin) OptimizeVideoFiles(ctx context.Context) ([]admin.FaststartResult, error) {
+	const op = "service.OptimizeVideoFiles"
+
+	files, err := listMediaFiles(ctx, s.media.Video)
+	if err != nil {
+		logging.L(ctx).Error("Failed to read video directory", sl.Err(err), "op", op)
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		logging.L(ctx).Warn("No video files found", "op", op)
+		return nil, admin.ErrFileNotFound
+	}
+
+	result := make([]admin.FaststartResult, 0, len(files))
+	for _, file := range files {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		res := admin.FaststartResult{Name: file.Name}
+
+		res.Status, err = s.faststartStored(ctx, file.Name)
+		if err != nil {
+			res.Error = err.Error()
+			logging.L(ctx).Warn("Failed to apply faststart", "file", file.Name, sl.Err(err), "op", op)
+		}
+
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+// faststartStored применяет faststart к видеофайлу из библиотеки и возвращает статус оптимизации
+func (s *ServiceAdmin) faststartStored(ctx context.Context, filename string) (string, error) {
+	src, obj, err := s.media.Video.Open(ctx, filename)
+	if err != nil {
+		return admin.FaststartFailed, err
+	}
+	defer src.Close()
+
+	mimeType, err := mimetype.DetectReader(src)
+	if err != nil {
+		return admin.FaststartFailed, err
+	}
+
+	if !strings.Contains(faststartMIMETypes, mimeType.String()) {
+		return admin.FaststartSkipped, nil
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return admin.FaststartFailed, fmt.Errorf("failed to rewind video: %w", err)
+	}
+
+	tmp, err := faststartCopy(src, obj.Size, s.cfg.Media.UploadsPatch)
+	if err != nil {
+		return admin.FaststartFailed, err
+	}
+
+	if tmp == nil {
+		return admin.FaststartAlreadyDone, nil
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return admin.FaststartFailed, fmt.Errorf("failed to rewind video: %w", err)
+	}
+
+	if err = s.media.Video.Save(ctx, filename, tmp, obj.Size); err != nil {
+		return admin.FaststartFailed, err
+	}
+
+	return admin.FaststartOptimized, nil
 }
 
 func (s *ServiceAdmin) ListVideoFiles(ctx context.Context) ([]admin.File, error) {
 	const op = "service.ListVideoFiles"
 
-	files, err := getFilesList(ctx, s.cfg.Media.VideoPatch)
+	files, err := getFilesList(ctx, s.media.Video)
 	if err != nil {
 		logging.L(ctx).Error("Failed to read video directory", sl.Err(err), "op", op)
 		return nil, err
@@ -67,47 +207,93 @@ func (s *ServiceAdmin) ListVideoFiles(ctx context.Context) ([]admin.File, error)
 		return nil, admin.ErrFileNotFound
 	}
 
+	usage, err := s.db.GetVideoFileUsage(ctx)
+	if err != nil {
+		logging.L(ctx).Error("Failed to get video files usage", sl.Err(err), "op", op)
+		return nil, err
+	}
+
+	for i := range files {
+		files[i].UsedBy = usage[files[i].Name]
+	}
+
 	return files, nil
 }
 
-func (s *ServiceAdmin) UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader) error {
+func (s *ServiceAdmin) UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error) {
 	const op = "service.UploadImage"
 
 	if !validFilePattern.MatchString(header.Filename) {
 		logging.L(ctx).Warn("invalid file format in URL", "url", header.Filename, "op", op)
-		return admin.ErrInvalidFileName
+		return nil, admin.ErrInvalidFileName
 	}
 
-	buff := make([]byte, 512)
-	if _, err := file.Read(buff); err != nil {
-		logging.L(ctx).Error("Failed to read image header", sl.Err(err), "op", op)
-		return admin.ErrFailedToReadFile
+	// Изображение целиком проверяется в памяти, размер ограничен лимитом загрузки
+	data, err := io.ReadAll(file)
+	if err != nil {
+		logging.L(ctx).Error("Failed to read image", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToReadFile
 	}
 
-	mimeType := mimetype.Detect(buff)
+	mimeType := mimetype.Detect(data)
 
 	if !strings.Contains(imageMIMETypes, mimeType.String()) {
 		logging.L(ctx).Error("Invalid image type", "content_type", mimeType.String(), "op", op)
-		return admin.ErrFileTypeNotSupported
+		return nil, admin.ErrFileTypeNotSupported
 	}
 
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		logging.L(ctx).Error("Failed to reset file position", sl.Err(err), "op", op)
-		return admin.ErrFailedToReadFile
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !slices.Contains(imageExtensions[mimeType.String()], ext) {
+		logging.L(ctx).Warn("Image extension does not match content", "file", header.Filename, "content_type", mimeType.String(), "op", op)
+		return nil, admin.ErrImageExtension
 	}
 
-	if err := saveFile(header.Filename, file, s.cfg.Media.ImagesPatch); err != nil {
+	data, err = s.cleanImage(data, mimeType.String())
+	if err != nil {
+		logging.L(ctx).Warn("Image rejected", "file", header.Filename, "content_type", mimeType.String(), sl.Err(err), "op", op)
+		if errors.Is(err, imgsafe.ErrTooLarge) {
+			return nil, admin.ErrImageTooLarge
+		}
+		return nil, admin.ErrImageInvalid
+	}
+
+	saved, err := saveFile(ctx, s.media.Images, header.Filename, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
 		logging.L(ctx).Error("Failed to save image", sl.Err(err), "op", op)
-		return admin.ErrFailedToSaveFile
+		return nil, admin.ErrFailedToSaveFile
 	}
 
-	return nil
+	return saved, nil
+}
+
+// cleanImage удаляет из SVG скрипты и внешние ссылки, а растровое изображение полностью декодирует
+// с проверкой размеров. Из JPEG убираются EXIF с координатами съемки и другие метаданные.
+func (s *ServiceAdmin) cleanImage(data []byte, mimeType string) ([]byte, error) {
+	if mimeType == "image/svg+xml" {
+		return imgsafe.SanitizeSVG(data)
+	}
+
+	limits := imgsafe.Limits{
+		MaxWidth:  s.cfg.Media.ImageMaxWidth,
+		MaxHeight: s.cfg.Media.ImageMaxHeight,
+		MaxPixels: s.cfg.Media.ImageMaxPixels,
+	}
+
+	if err := imgsafe.CheckRaster(data, limits); err != nil {
+		return nil, err
+	}
+
+	if mimeType == "image/jpeg" {
+		return imgsafe.StripJPEGMetadata(data)
+	}
+
+	return data, nil
 }
 
 func (s *ServiceAdmin) ListImageFiles(ctx context.Context) ([]admin.File, error) {
 	const op = "service.ListImageFiles"
 
-	files, err := getFilesList(ctx, s.cfg.Media.ImagesPatch)
+	files, err := getFilesList(ctx, s.media.Images)
 	if err != nil {
 		logging.L(ctx).Error("Failed to read image directory", sl.Err(err), "op", op)
 		return nil, err
@@ -118,57 +304,254 @@ func (s *ServiceAdmin) ListImageFiles(ctx context.Context) ([]admin.File, error)
 		return nil, admin.ErrFileNotFound
 	}
 
+	usage, err := s.db.GetImageFileUsage(ctx)
+	if err != nil {
+		logging.L(ctx).Error("Failed to get image files usage", sl.Err(err), "op", op)
+		return nil, err
+	}
+
+	for i := range files {
+		files[i].UsedBy = usage[files[i].Name]
+	}
+
+	return files, nil
+}
+
+// UploadDocument сохраняет PDF-памятку в DOCS_PATCH
+func (s *ServiceAdmin) UploadDocument(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error) {
+	const op = "service.UploadDocument"
+
+	if !validFilePattern.MatchString(header.Filename) {
+		logging.L(ctx).Warn("invalid file format in URL", "url", header.Filename, "op", op)
+		return nil, admin.ErrInvalidFileName
+	}
+
+	buff := make([]byte, 512)
+	if _, err := file.Read(buff); err != nil {
+		logging.L(ctx).Error("Failed to read document header", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToReadFile
+	}
+
+	mimeType := mimetype.Detect(buff)
+
+	if !mimeType.Is(docMIMEType) || !strings.EqualFold(filepath.Ext(header.Filename), ".pdf") {
+		logging.L(ctx).Error("Invalid document type", "content_type", mimeType.String(), "file", header.Filename, "op", op)
+		return nil, admin.ErrFileTypeNotSupported
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		logging.L(ctx).Error("Failed to reset file position", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToReadFile
+	}
+
+	saved, err := saveFile(ctx, s.media.Docs, header.Filename, file, header.Size)
+	if err != nil {
+		logging.L(ctx).Error("Failed to save document", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	return saved, nil
+}
+
+func (s *ServiceAdmin) ListDocumentFiles(ctx context.Context) ([]admin.File, error) {
+	const op = "service.ListDocumentFiles"
+
+	files, err := getFilesList(ctx, s.media.Docs)
+	if err != nil {
+		logging.L(ctx).Error("Failed to read documents directory", sl.Err(err), "op", op)
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		logging.L(ctx).Warn("No document files found", "op", op)
+		return nil, admin.ErrFileNotFound
+	}
+
+	usage, err := s.db.GetDocumentFileUsage(ctx)
+	if err != nil {
+		logging.L(ctx).Error("Failed to get document files usage", sl.Err(err), "op", op)
+		return nil, err
+	}
+
+	for i := range files {
+		files[i].UsedBy = usage[files[i].Name]
+	}
+
 	return files, nil
 }
 
-// saveFile сохраняет файлы
-func saveFile(filename string, file multipart.File, patch string) error {
-	if err := os.MkdirAll(patch, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+// checkVideoFiles проверяет, что видеофайл и превью видео загружены на сервер
+func (s *ServiceAdmin) checkVideoFiles(ctx context.Context, video *admin.Video) error {
+	if !s.cfg.Media.CheckFiles {
+		return nil
 	}
 
-	dst, err := os.Create(filepath.Join(patch, filename))
+	exists, err := fileExists(ctx, s.media.Video, video.URL)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return err
+	}
+	if !exists {
+		return admin.ErrVideoFileNotFound
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		return fmt.Errorf("failed to save file: %w", err)
+	exists, err = fileExists(ctx, s.media.Images, video.ImgURL)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return admin.ErrVideoImgNotFound
 	}
 
 	return nil
 }
 
-// getFilesList возвращает список файлов в указанной директории
-func getFilesList(ctx context.Context, patch string) ([]admin.File, error) {
-	files, err := os.ReadDir(patch)
+// checkCategoryFiles проверяет, что изображение категории загружено на сервер
+func (s *ServiceAdmin) checkCategoryFiles(ctx context.Context, category *admin.Category) error {
+	if !s.cfg.Media.CheckFiles {
+		return nil
+	}
+
+	exists, err := fileExists(ctx, s.media.Images, category.ImgURL)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !exists {
+		return admin.ErrImgNotFound
 	}
 
-	var result []admin.File
-	for _, file := range files {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-			if file.IsDir() {
-				continue
-			}
-
-			info, err := file.Info()
-			if err != nil {
-				continue
-			}
-
-			result = append(result, admin.File{
-				Name:    file.Name(),
-				Size:    info.Size(),
-				ModTime: info.ModTime(),
-			})
+	return nil
+}
+
+// fileExists проверяет наличие файла в библиотеке. Сбой хранилища возвращается как ошибка,
+// чтобы недоступное хранилище не выглядело как отсутствующий файл.
+func fileExists(ctx context.Context, store mediastore.MediaStore, filename string) (bool, error) {
+	_, err := store.Stat(ctx, filename)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, mediastore.ErrNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check file %q: %w", filename, err)
+	}
+}
+
+// saveFile сохраняет файл в библиотеку и находит в ней другие файлы с тем же содержимым
+func saveFile(ctx context.Context, store mediastore.MediaStore, filename string, file io.Reader, size int64) (*admin.SavedFile, error) {
+	prev, err := store.Stat(ctx, filename)
+	if err != nil && !errors.Is(err, mediastore.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if err = store.Save(ctx, filename, file, size); err != nil {
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	obj, err := store.Stat(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved file info: %w", err)
+	}
+
+	saved := &admin.SavedFile{
+		Name:       filename,
+		Checksum:   obj.Digest,
+		Replaced:   prev != nil && prev.Digest != obj.Digest,
+		Duplicates: make([]string, 0),
+	}
+
+	// Файл уже сохранен, поэтому ошибка поиска дубликатов не прерывает загрузку
+	objects, err := findByDigest(ctx, store, obj.Digest)
+	if err != nil {
+		logging.L(ctx).Warn("Failed to find duplicate files", "file", filename, sl.Err(err))
+		return saved, nil
+	}
+
+	for _, other := range objects {
+		if other.Digest == obj.Digest && other.Name != filename {
+			saved.Duplicates = append(saved.Duplicates, other.Name)
 		}
 	}
 
+	if len(saved.Duplicates) > 0 {
+		logging.L(ctx).Info("uploaded file duplicates existing files", "file", filename, "duplicates", strings.Join(saved.Duplicates, ","))
+	}
+
+	return saved, nil
+}
+
+// findByDigest возвращает файлы библиотеки с тем же содержимым. Хранилище по содержимому ищет их
+// по индексу в БД, остальные хранилища перебираются целиком.
+func findByDigest(ctx context.Context, store mediastore.MediaStore, digest string) ([]mediastore.Object, error) {
+	if digest == "" {
+		return nil, nil
+	}
+
+	if finder, ok := store.(mediastore.DigestFinder); ok {
+		return finder.FindByDigest(ctx, digest)
+	}
+
+	return store.List(ctx)
+}
+
+// faststartCopy записывает во временный файл в dir копию видео с moov в начале.
+// Возвращает nil без ошибки, если видео уже оптимизировано.
+func faststartCopy(src io.ReadSeeker, size int64, dir string) (*os.File, error) {
+	need, err := faststart.NeedsFaststart(src)
+	if err != nil || !need {
+		return nil, err
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind file: %w", err)
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	dst, err := os.CreateTemp(dir, "*"+faststartTmpSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	if err = rewriteAndVerify(src, dst, size); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// rewriteAndVerify записывает оптимизированную копию в dst и проверяет ее
+func rewriteAndVerify(src io.ReadSeeker, dst *os.File, size int64) error {
+	if err := faststart.Rewrite(src, dst); err != nil {
+		return err
+	}
+
+	if err := dst.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+
+	return faststart.Verify(dst, size)
+}
+
+// getFilesList возвращает список файлов библиотеки
+func getFilesList(ctx context.Context, store mediastore.MediaStore) ([]admin.File, error) {
+	objects, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]admin.File, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, admin.File{
+			Name:     obj.Name,
+			Size:     obj.Size,
+			ModTime:  obj.ModTime,
+			Checksum: obj.Digest,
+		})
+	}
+
 	return result, nil
 }
@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"testing"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileExists(t *testing.T) {
+	ctx := context.Background()
+	local := mediastore.NewLocal(t.TempDir())
+	require.NoError(t, local.Save(ctx, "neck.mp4", bytes.NewReader([]byte("video")), 5))
+
+	store := &brokenStatStore{Local: local, broken: "back.mp4"}
+
+	exists, err := fileExists(ctx, store, "neck.mp4")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = fileExists(ctx, store, "missing.mp4")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Сбой хранилища — ошибка, а не отсутствующий файл
+	_, err = fileExists(ctx, store, "back.mp4")
+	assert.Error(t, err)
+}
+
+func TestCheckVideoFiles_StoreError(t *testing.T) {
+	ctx := context.Background()
+	local := mediastore.NewLocal(t.TempDir())
+	require.NoError(t, local.Save(ctx, "neck.mp4", bytes.NewReader([]byte("video")), 5))
+
+	cfg := &config.Config{}
+	cfg.Media.CheckFiles = true
+
+	s := &ServiceAdmin{cfg: cfg, media: mediastore.Stores{
+		Video:  local,
+		Images: &brokenStatStore{Local: mediastore.NewLocal(t.TempDir()), broken: "neck.jpg"},
+	}}
+
+	// Недоступное хранилище не превращается в ответ 400 о ненайденном файле
+	err := s.checkVideoFiles(ctx, &admin.Video{URL: "neck.mp4", ImgURL: "neck.jpg"})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, admin.ErrVideoImgNotFound)
+
+	err = s.checkVideoFiles(ctx, &admin.Video{URL: "neck.mp4", ImgURL: "other.jpg"})
+	assert.ErrorIs(t, err, admin.ErrVideoImgNotFound)
+}
+
+// memFile файл загрузки из памяти
+type memFile struct {
+	*bytes.Reader
+}
+
+func (memFile) Close() error { return nil }
+
+func TestUploadImage_ExtensionMismatch(t *testing.T) {
+	// Заголовок PNG: mimetype определяет тип по сигнатуре
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+
+	s := &ServiceAdmin{cfg: &config.Config{}, media: mediastore.Stores{Images: mediastore.NewLocal(t.TempDir())}}
+
+	for _, name := range []string{"x.html", "x.jpg", "x.svg", "x.PNG.gif"} {
+		_, err := s.UploadImage(context.Background(), memFile{bytes.NewReader(png)}, &multipart.FileHeader{Filename: name})
+		assert.ErrorIs(t, err, admin.ErrImageExtension, name)
+	}
+}
+
+func TestListVideoFiles_Usage(t *testing.T) {
+	ctx := context.Background()
+	videos := mediastore.NewLocal(t.TempDir())
+	for _, name := range []string{"neck.mp4", "back.mp4"} {
+		require.NoError(t, videos.Save(ctx, name, bytes.NewReader([]byte(name)), int64(len(name))))
+	}
+
+	usage := []admin.FileUsage{
+		{Entity: "video", ID: 1, Name: "Шея", Field: "url"},
+		{Entity: "video", ID: 2, Name: "Шея, вариант", Field: "url"},
+	}
+	db := &usageStorage{video: map[string][]admin.FileUsage{"neck.mp4": usage}}
+	s := &ServiceAdmin{cfg: &config.Config{}, db: db, media: mediastore.Stores{Video: videos}}
+
+	// Каждый файл списка содержит ссылки на него, файл без ссылок — пустой список
+	files, err := s.ListVideoFiles(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	used := map[string][]admin.FileUsage{}
+	for _, file := range files {
+		used[file.Name] = file.UsedBy
+	}
+	assert.Equal(t, usage, used["neck.mp4"])
+	assert.Empty(t, used["back.mp4"])
+}
+
+func TestCheckCategoryFiles(t *testing.T) {
+	ctx := context.Background()
+	images := mediastore.NewLocal(t.TempDir())
+	require.NoError(t, images.Save(ctx, "neck.jpg", bytes.NewReader([]byte("jpg")), 3))
+
+	cfg := &config.Config{}
+	cfg.Media.CheckFiles = true
+	db := &categoryStorage{parents: map[int64]int64{1: 0}}
+	s := &ServiceAdmin{cfg: cfg, db: db, media: mediastore.Stores{Images: images}}
+
+	require.NoError(t, s.checkCategoryFiles(ctx, &admin.Category{ImgURL: "neck.jpg"}))
+
+	// Категория с незагруженным превью не сохраняется
+	category := newCategory(0)
+	category.ImgURL = "back.jpg"
+	err := s.UpdateCategory(ctx, 1, category)
+	require.ErrorIs(t, err, admin.ErrImgNotFound)
+
+	// MEDIA_CHECK_FILES=false отключает проверку
+	cfg.Media.CheckFiles = false
+	require.NoError(t, s.UpdateCategory(ctx, 1, category))
+}
@@ -0,0 +1,228 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// fileLibrary хранилище библиотеки и ссылки на ее файлы из БД
+type fileLibrary struct {
+	store  mediastore.MediaStore
+	usage  func(ctx context.Context) (map[string][]admin.FileUsage, error)
+	rename func(ctx context.Context, oldName, newName string) ([]admin.FileUsage, error)
+}
+
+// fileLibrary возвращает библиотеку видео или изображений
+func (s *ServiceAdmin) fileLibrary(kind string) (*fileLibrary, bool) {
+	switch kind {
+	case admin.MediaKindVideo:
+		return &fileLibrary{store: s.media.Video, usage: s.db.GetVideoFileUsage, rename: s.db.RenameVideoFile}, true
+	case admin.MediaKindImage:
+		return &fileLibrary{store: s.media.Images, usage: s.db.GetImageFileUsage, rename: s.db.RenameImageFile}, true
+	default:
+		return nil, false
+	}
+}
+
+// DeleteMediaFile удаляет видеофайл или изображение. Файл, на который ссылаются видео, категории
+// или объявления, не удаляется: возвращаются ссылки на него и ErrFileInUse.
+func (s *ServiceAdmin) DeleteMediaFile(ctx context.Context, kind, filename string) ([]admin.FileUsage, error) {
+	const op = "service.DeleteMediaFile"
+
+	library, ok := s.fileLibrary(kind)
+	if !ok {
+		return nil, admin.ErrFileTypeNotSupported
+	}
+
+	if !validFilePattern.MatchString(filename) {
+		logging.L(ctx).Warn("invalid file name", "file", filename, "op", op)
+		return nil, admin.ErrInvalidFileName
+	}
+
+	usage, err := library.usage(ctx)
+	if err != nil {
+		logging.L(ctx).Error("Failed to get files usage", "kind", kind, sl.Err(err), "op", op)
+		return nil, admin.ErrFileDeleteFailed
+	}
+
+	if used := usage[filename]; len(used) > 0 {
+		logging.L(ctx).Warn("file is in use", "kind", kind, "file", filename, "references", len(used), "op", op)
+		return used, admin.ErrFileInUse
+	}
+
+	if err = library.store.Delete(ctx, filename); err != nil {
+		if errors.Is(err, mediastore.ErrNotFound) {
+			return nil, admin.ErrFileNotFound
+		}
+		logging.L(ctx).Error("Failed to delete file", "kind", kind, "file", filename, sl.Err(err), "op", op)
+		return nil, admin.ErrFileDeleteFailed
+	}
+
+	logging.L(ctx).Info("file deleted", "kind", kind, "file", filename, "op", op)
+
+	return nil, nil
+}
+
+// RenameMediaFile переименовывает видеофайл или изображение и в одной транзакции меняет ссылки на него
+// в видео, категориях и объявлениях. Возвращает обновленные записи.
+func (s *ServiceAdmin) RenameMediaFile(ctx context.Context, kind, oldName, newName string) ([]admin.FileUsage, error) {
+	const op = "service.RenameMediaFile"
+
+	library, ok := s.fileLibrary(kind)
+	if !ok {
+		return nil, admin.ErrFileTypeNotSupported
+	}
+
+	if !validFilePattern.MatchString(oldName) || !validFilePattern.MatchString(newName) {
+		logging.L(ctx).Warn("invalid file name", "file", oldName, "new_name", newName, "op", op)
+		return nil, admin.ErrInvalidFileName
+	}
+
+	if _, err := library.store.Stat(ctx, oldName); err != nil {
+		if errors.Is(err, mediastore.ErrNotFound) {
+			return nil, admin.ErrFileNotFound
+		}
+		logging.L(ctx).Error("Failed to get file info", "kind", kind, "file", oldName, sl.Err(err), "op", op)
+		return nil, admin.ErrFileRenameFailed
+	}
+
+	if oldName == newName {
+		logging.L(ctx).Warn("file already exists", "kind", kind, "file", newName, "op", op)
+		return nil, admin.ErrFileAlreadyExists
+	}
+
+	linker, ok := library.store.(mediastore.Linker)
+	if !ok {
+		logging.L(ctx).Error("Media store can not link files", "kind", kind, "op", op)
+		return nil, admin.ErrFileRenameFailed
+	}
+
+	// Сначала появляется файл с новым именем, поэтому ссылки ни в какой момент не указывают на отсутствующий файл.
+	// Link не перезаписывает занятое имя, поэтому параллельная загрузка или переименование в newName не теряется.
+	err := linker.Link(ctx, oldName, newName)
+	switch {
+	case errors.Is(err, mediastore.ErrExists):
+		logging.L(ctx).Warn("file already exists", "kind", kind, "file", newName, "op", op)
+		return nil, admin.ErrFileAlreadyExists
+	case errors.Is(err, mediastore.ErrNotFound):
+		return nil, admin.ErrFileNotFound
+	case err != nil:
+		logging.L(ctx).Error("Failed to link file", "kind", kind, "file", oldName, "new_name", newName, sl.Err(err), "op", op)
+		return nil, admin.ErrFileRenameFailed
+	}
+
+	usage, err := library.rename(ctx, oldName, newName)
+	if err != nil {
+		logging.L(ctx).Error("Failed to rename file references", "kind", kind, "file", oldName, sl.Err(err), "op", op)
+
+		// Ссылки не изменились, копия под новым именем не нужна
+		if err = library.store.Delete(ctx, newName); err != nil {
+			logging.L(ctx).Warn("Failed to delete file copy", "kind", kind, "file", newName, sl.Err(err), "op", op)
+		}
+
+		return nil, admin.ErrFileRenameFailed
+	}
+
+	if err = library.store.Delete(ctx, oldName); err != nil {
+		logging.L(ctx).Warn("Failed to delete file with old name", "kind", kind, "file", oldName, sl.Err(err), "op", op)
+	}
+
+	logging.L(ctx).Info("file renamed", "kind", kind, "file", oldName, "new_name", newName, "references", len(usage), "op", op)
+
+	s.fileReferencesChanged(ctx, usage, op)
+
+	return usage, nil
+}
+
+// ReplaceVideoFile заменяет содержимое существующего видеофайла. Имя файла и ссылки на него не меняются.
+func (s *ServiceAdmin) ReplaceVideoFile(ctx context.Context, filename string, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error) {
+	const op = "service.ReplaceVideoFile"
+
+	if err := s.checkReplacedFile(ctx, s.media.Video, filename, op); err != nil {
+		return nil, err
+	}
+
+	replacement := *header
+	replacement.Filename = filename
+
+	saved, err := s.UploadFile(ctx, file, &replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+	}
+
+	return saved, nil
+}
+
+// ReplaceImageFile заменяет содержимое существующего изображения. Имя файла и ссылки на него не меняются.
+func (s *ServiceAdmin) ReplaceImageFile(ctx context.Context, filename string, file multipart.File, header *multipart.FileHeader) (*admin.SavedFile, error) {
+	const op = "service.ReplaceImageFile"
+
+	if err := s.checkReplacedFile(ctx, s.media.Images, filename, op); err != nil {
+		return nil, err
+	}
+
+	replacement := *header
+	replacement.Filename = filename
+
+	saved, err := s.UploadImage(ctx, file, &replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+		go s.removeAnnouncementsCache(ctx, op)
+	}
+
+	return saved, nil
+}
+
+// checkReplacedFile проверяет, что заменяемый файл существует
+func (s *ServiceAdmin) checkReplacedFile(ctx context.Context, store mediastore.MediaStore, filename, op string) error {
+	if !validFilePattern.MatchString(filename) {
+		logging.L(ctx).Warn("invalid file name", "file", filename, "op", op)
+		return admin.ErrInvalidFileName
+	}
+
+	if _, err := store.Stat(ctx, filename); err != nil {
+		if errors.Is(err, mediastore.ErrNotFound) {
+			logging.L(ctx).Warn("replaced file not found", "file", filename, "op", op)
+			return admin.ErrFileNotFound
+		}
+		logging.L(ctx).Error("Failed to get file info", "file", filename, sl.Err(err), "op", op)
+		return admin.ErrFailedToSaveFile
+	}
+
+	return nil
+}
+
+// fileReferencesChanged сбрасывает кэш и уведомляет подписчиков webhook об изменении ссылок на файл
+func (s *ServiceAdmin) fileReferencesChanged(ctx context.Context, usage []admin.FileUsage, op string) {
+	if len(usage) == 0 {
+		return
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+		go s.removeAnnouncementsCache(ctx, op)
+	}
+
+	for _, u := range usage {
+		switch u.Entity {
+		case admin.UsageVideo:
+			s.emitWebhookEvent(ctx, admin.EventVideoUpdated, map[string]any{"id": u.ID, "name": u.Name})
+		case admin.UsageCategory:
+			s.emitWebhookEvent(ctx, admin.EventCategoryUpdated, map[string]any{"id": u.ID, "name": u.Name})
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fileUsageStorage отдает ссылки на видеофайлы и переименовывает их; остальные методы AdmStorage не нужны
+type fileUsageStorage struct {
+	AdmStorage
+	usage     map[string][]admin.FileUsage
+	renameErr error
+	renamed   []string
+	// references ссылки, которые возвращает переименование
+	references []admin.FileUsage
+}
+
+func (s *fileUsageStorage) GetVideoFileUsage(context.Context) (map[string][]admin.FileUsage, error) {
+	return s.usage, nil
+}
+
+func (s *fileUsageStorage) RenameVideoFile(_ context.Context, oldName, newName string) ([]admin.FileUsage, error) {
+	if s.renameErr != nil {
+		return nil, s.renameErr
+	}
+	s.renamed = append(s.renamed, oldName+"->"+newName)
+	return s.references, nil
+}
+
+func (s *fileUsageStorage) EnqueueWebhookEvent(context.Context, string, []byte) (int64, error) {
+	return 0, nil
+}
+
+// brokenStatStore хранилище, которое не может проверить файл с именем broken.
+// Если задан onStat, он вызывается перед проверкой, как если бы в это время работал другой запрос.
+type brokenStatStore struct {
+	*mediastore.Local
+	broken string
+	onStat func(name string)
+}
+
+func (s *brokenStatStore) Stat(ctx context.Context, name string) (*mediastore.Object, error) {
+	if s.onStat != nil {
+		s.onStat(name)
+	}
+	if name == s.broken {
+		return nil, errors.New("storage unavailable")
+	}
+	return s.Local.Stat(ctx, name)
+}
+
+func newMediaFilesService(t *testing.T, db *fileUsageStorage, store mediastore.MediaStore) *ServiceAdmin {
+	t.Helper()
+
+	ctx := context.Background()
+	for _, name := range []string{"neck.mp4", "back.mp4"} {
+		require.NoError(t, store.Save(ctx, name, bytes.NewReader([]byte(name)), int64(len(name))))
+	}
+
+	return &ServiceAdmin{cfg: &config.Config{}, db: db, media: mediastore.Stores{Video: store}}
+}
+
+func TestDeleteMediaFile_InUse(t *testing.T) {
+	used := []admin.FileUsage{{Entity: admin.UsageVideo, ID: 7, Name: "Шея", Field: "url"}}
+	db := &fileUsageStorage{usage: map[string][]admin.FileUsage{"neck.mp4": used}}
+	store := mediastore.NewLocal(t.TempDir())
+	s := newMediaFilesService(t, db, store)
+	ctx := context.Background()
+
+	// Файл, на который ссылается видео, не удаляется, а ссылки возвращаются
+	got, err := s.DeleteMediaFile(ctx, admin.MediaKindVideo, "neck.mp4")
+	require.ErrorIs(t, err, admin.ErrFileInUse)
+	assert.Equal(t, used, got)
+
+	_, err = store.Stat(ctx, "neck.mp4")
+	assert.NoError(t, err)
+
+	// Файл без ссылок удаляется
+	_, err = s.DeleteMediaFile(ctx, admin.MediaKindVideo, "back.mp4")
+	require.NoError(t, err)
+
+	_, err = store.Stat(ctx, "back.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+}
+
+func TestRenameMediaFile(t *testing.T) {
+	db := &fileUsageStorage{}
+	store := mediastore.NewLocal(t.TempDir())
+	s := newMediaFilesService(t, db, store)
+	ctx := context.Background()
+
+	_, err := s.RenameMediaFile(ctx, admin.MediaKindVideo, "neck.mp4", "neck-1.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"neck.mp4->neck-1.mp4"}, db.renamed)
+
+	_, err = store.Stat(ctx, "neck.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+	_, err = store.Stat(ctx, "neck-1.mp4")
+	assert.NoError(t, err)
+
+	// Занятое и отсутствующее имя
+	_, err = s.RenameMediaFile(ctx, admin.MediaKindVideo, "neck-1.mp4", "back.mp4")
+	assert.ErrorIs(t, err, admin.ErrFileAlreadyExists)
+
+	_, err = s.RenameMediaFile(ctx, admin.MediaKindVideo, "missing.mp4", "other.mp4")
+	assert.ErrorIs(t, err, admin.ErrFileNotFound)
+}
+
+func TestRenameMediaFile_InvalidatesVideoCache(t *testing.T) {
+	db := &fileUsageStorage{references: []admin.FileUsage{{Entity: admin.UsageVideo, ID: 7, Name: "Шея", Field: "url"}}}
+	s := newMediaFilesService(t, db, mediastore.NewLocal(t.TempDir()))
+	s.cfg.Redis.Enable = true
+	cache := newMemCache("video:7", "video:7:related:1", "videos:1:2")
+	s.redis = cache
+
+	_, err := s.RenameMediaFile(context.Background(), admin.MediaKindVideo, "neck.mp4", "neck-1.mp4")
+	require.NoError(t, err)
+
+	// Старый файл удален, поэтому видео со старой ссылкой не должно остаться в кэше
+	assert.Eventually(t, func() bool {
+		return !cache.has("video:7") && !cache.has("video:7:related:1") && !cache.has("videos:1:2")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRenameMediaFile_Rollback(t *testing.T) {
+	db := &fileUsageStorage{renameErr: errors.New("deadlock detected")}
+	store := mediastore.NewLocal(t.TempDir())
+	s := newMediaFilesService(t, db, store)
+	ctx := context.Background()
+
+	// Ссылки в БД не изменились: копия под новым именем удаляется, исходный файл остается
+	_, err := s.RenameMediaFile(ctx, admin.MediaKindVideo, "neck.mp4", "neck-1.mp4")
+	require.ErrorIs(t, err, admin.ErrFileRenameFailed)
+
+	_, err = store.Stat(ctx, "neck.mp4")
+	assert.NoError(t, err)
+	_, err = store.Stat(ctx, "neck-1.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+}
+
+func TestRenameMediaFile_StoreError(t *testing.T) {
+	ctx := context.Background()
+
+	// Сбой хранилища при проверке исходного файла не считается его отсутствием
+	store := &brokenStatStore{Local: mediastore.NewLocal(t.TempDir()), broken: "neck.mp4"}
+	db := &fileUsageStorage{}
+	s := newMediaFilesService(t, db, store)
+
+	_, err := s.RenameMediaFile(ctx, admin.MediaKindVideo, "neck.mp4", "neck-1.mp4")
+	require.ErrorIs(t, err, admin.ErrFileRenameFailed)
+	assert.Empty(t, db.renamed)
+	_, err = store.Local.Stat(ctx, "neck-1.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+
+	// Занятое имя не перезаписывается, даже если хранилище не может его проверить
+	store = &brokenStatStore{Local: mediastore.NewLocal(t.TempDir()), broken: "back.mp4"}
+	db = &fileUsageStorage{}
+	s = newMediaFilesService(t, db, store)
+
+	_, err = s.RenameMediaFile(ctx, admin.MediaKindVideo, "neck.mp4", "back.mp4")
+	require.ErrorIs(t, err, admin.ErrFileAlreadyExists)
+	assert.Empty(t, db.renamed)
+	assertFileContent(t, store.Local, "back.mp4", "back.mp4")
+	assertFileContent(t, store.Local, "neck.mp4", "neck.mp4")
+}
+
+func TestRenameMediaFile_ConcurrentUpload(t *testing.T) {
+	ctx := context.Background()
+	store := &brokenStatStore{Local: mediastore.NewLocal(t.TempDir())}
+	db := &fileUsageStorage{}
+	s := newMediaFilesService(t, db, store)
+
+	// Файл с новым именем загружают, пока проверяется исходный файл
+	store.onStat = func(name string) {
+		if name == "neck.mp4" {
+			require.NoError(t, store.Local.Save(ctx, "neck-1.mp4", bytes.NewReader([]byte("upload")), 6))
+		}
+	}
+
+	_, err := s.RenameMediaFile(ctx, admin.MediaKindVideo, "neck.mp4", "neck-1.mp4")
+	require.ErrorIs(t, err, admin.ErrFileAlreadyExists)
+	assert.Empty(t, db.renamed)
+	assertFileContent(t, store.Local, "neck-1.mp4", "upload")
+	assertFileContent(t, store.Local, "neck.mp4", "neck.mp4")
+}
+
+func TestReplaceFile_StoreError(t *testing.T) {
+	ctx := context.Background()
+	store := &brokenStatStore{Local: mediastore.NewLocal(t.TempDir()), broken: "back.mp4"}
+	s := newMediaFilesService(t, &fileUsageStorage{}, store)
+
+	// Сбой хранилища не считается отсутствием заменяемого файла
+	err := s.checkReplacedFile(ctx, store, "back.mp4", "test")
+	assert.ErrorIs(t, err, admin.ErrFailedToSaveFile)
+	assert.NotErrorIs(t, err, admin.ErrFileNotFound)
+
+	// Отсутствующий файл не создается заменой
+	_, err = s.ReplaceVideoFile(ctx, "missing.mp4", memFile{bytes.NewReader([]byte("video"))}, &multipart.FileHeader{Filename: "x.mp4"})
+	assert.ErrorIs(t, err, admin.ErrFileNotFound)
+	_, err = store.Local.Stat(ctx, "missing.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+
+	_, err = s.ReplaceVideoFile(ctx, "back.mp4", memFile{bytes.NewReader([]byte("video"))}, &multipart.FileHeader{Filename: "x.mp4"})
+	assert.ErrorIs(t, err, admin.ErrFailedToSaveFile)
+	assertFileContent(t, store.Local, "back.mp4", "back.mp4")
+}
+
+// assertFileContent проверяет содержимое файла хранилища
+func assertFileContent(t *testing.T, store mediastore.MediaStore, name, expected string) {
+	t.Helper()
+
+	r, _, err := store.Open(context.Background(), name)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var content bytes.Buffer
+	_, err = content.ReadFrom(r)
+	require.NoError(t, err)
+	assert.Equal(t, expected, content.String())
+}
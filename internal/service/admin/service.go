@@ -3,9 +3,11 @@ package admin
 import (
 	"context"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
 	"github.com/theartofdevel/logging"
 )
@@ -13,20 +15,40 @@ import (
 // validFilePattern паттерны для проверки правильности названия файлов
 var validFilePattern = regexp.MustCompile(`^[a-zA-Z0-9_\-.]+\.[a-zA-Z0-9]+$`)
 
+// validTagPattern паттерн для проверки тегов видео
+var validTagPattern = regexp.MustCompile(`^[\p{L}\p{N}_\- ]{1,50}$`)
+
+// Ограничения структурированного описания упражнения
+const (
+	maxExerciseSteps   = 30
+	maxExerciseStepLen = 500
+	maxExerciseItems   = 20
+	maxExerciseItemLen = 200
+	maxExerciseSets    = 20
+	maxExerciseReps    = 100
+)
+
 // suspiciousPatterns паттерны для проверки нет ли лишних символов и ссылок в данных
 var suspiciousPatterns = []string{"://", "//", "../", "./", "\\", "?", "&", "=", "%"}
 
 type ServiceAdmin struct {
-	cfg   *config.Config
-	db    AdmStorage
-	redis CashStorage
+	cfg      *config.Config
+	db       AdmStorage
+	redis    CashStorage
+	webhooks WebhookSender
+	media    mediastore.Stores
+
+	// uploadLocks не дает двум запросам одновременно дописывать одну загрузку
+	uploadLocks sync.Map
 }
 
-func NewServiceAdmin(cfg *config.Config, storage AdmStorage, redis CashStorage) *ServiceAdmin {
+func NewServiceAdmin(cfg *config.Config, storage AdmStorage, redis CashStorage, webhooks WebhookSender, media mediastore.Stores) *ServiceAdmin {
 	return &ServiceAdmin{
-		cfg:   cfg,
-		db:    storage,
-		redis: redis,
+		cfg:      cfg,
+		db:       storage,
+		redis:    redis,
+		webhooks: webhooks,
+		media:    media,
 	}
 }
 
@@ -40,6 +62,11 @@ func (s *ServiceAdmin) removeCache(ctx context.Context, op string) {
 		logging.L(ctx).Warn("failed to invalidate videos cache", "service", op, sl.Err(err))
 	}
 
+	err = s.redis.InvalidateVideoCache(ctxRedis)
+	if err != nil {
+		logging.L(ctx).Warn("failed to invalidate video cache", "service", op, sl.Err(err))
+	}
+
 	err = s.redis.InvalidateCategoriesCache(ctxRedis)
 	if err != nil {
 		logging.L(ctx).Warn("failed to invalidate category cache", "service", op, sl.Err(err))
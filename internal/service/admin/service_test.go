@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"context"
+	"path"
+	"sync"
+)
+
+// memCache кэш в памяти: ключи удаляются по тем же шаблонам, что и в redis
+type memCache struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func newMemCache(keys ...string) *memCache {
+	c := &memCache{keys: make(map[string]bool)}
+	for _, key := range keys {
+		c.keys[key] = true
+	}
+	return c
+}
+
+func (c *memCache) set(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys[key] = true
+}
+
+func (c *memCache) has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.keys[key]
+}
+
+func (c *memCache) invalidate(pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.keys {
+		if ok, _ := path.Match(pattern, key); ok {
+			delete(c.keys, key)
+		}
+	}
+	return nil
+}
+
+func (c *memCache) InvalidateVideosCache(context.Context) error { return c.invalidate("videos:*") }
+func (c *memCache) InvalidateVideoCache(context.Context) error  { return c.invalidate("video:*") }
+func (c *memCache) InvalidateCategoriesCache(context.Context) error {
+	return c.invalidate("categories:*")
+}
+func (c *memCache) InvalidateAccountsCache(context.Context) error { return c.invalidate("account:*") }
+func (c *memCache) InvalidateAnnouncementsCache(context.Context) error {
+	return c.invalidate("announcements:*")
+}
+func (c *memCache) InvalidateAppConfigCache(context.Context) error { return c.invalidate("app_config") }
+func (c *memCache) InvalidateAllCache(context.Context) error       { return c.invalidate("*") }
@@ -2,6 +2,8 @@ package admin
 
 import (
 	"context"
+	"time"
+
 	"github.com/langowen/bodybalance-backend/internal/entities/admin"
 )
 
@@ -32,4 +34,57 @@ type AdmStorage interface {
 	GetCategories(ctx context.Context) ([]admin.Category, error)
 	UpdateCategory(ctx context.Context, id int64, req *admin.Category) error
 	DeleteCategory(ctx context.Context, id int64) error
+
+	GetVideoFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error)
+	GetImageFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error)
+	GetDocumentFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error)
+	GetSubtitleFileUsage(ctx context.Context) (map[string][]admin.FileUsage, error)
+	RenameVideoFile(ctx context.Context, oldName, newName string) ([]admin.FileUsage, error)
+	RenameImageFile(ctx context.Context, oldName, newName string) ([]admin.FileUsage, error)
+
+	ExportCatalog(ctx context.Context) (*admin.CatalogBundle, error)
+	ImportCatalog(ctx context.Context, bundle *admin.CatalogBundle, dryRun bool) (*admin.ImportDiff, error)
+
+	ListFeedback(ctx context.Context, username string, filter *admin.FeedbackFilter) (*admin.FeedbackList, error)
+	GetFeedback(ctx context.Context, id int64, username string) (*admin.Feedback, error)
+	MarkFeedbackRead(ctx context.Context, id int64, username string) error
+	UpdateFeedbackStatus(ctx context.Context, id int64, status string) error
+	AssignFeedback(ctx context.Context, id, assigneeID int64) error
+	AddFeedbackNote(ctx context.Context, id int64, username, text string) (*admin.FeedbackNote, error)
+
+	AddAnnouncement(ctx context.Context, req *admin.Announcement) (int64, error)
+	GetAnnouncement(ctx context.Context, id int64) (*admin.Announcement, error)
+	GetAnnouncements(ctx context.Context) ([]admin.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, req *admin.Announcement) error
+	DeleteAnnouncement(ctx context.Context, id int64) error
+
+	AddDocument(ctx context.Context, req *admin.Document) (int64, error)
+	GetDocument(ctx context.Context, id int64) (*admin.Document, error)
+	GetDocuments(ctx context.Context) ([]admin.Document, error)
+	UpdateDocument(ctx context.Context, req *admin.Document) error
+	DeleteDocument(ctx context.Context, id int64) error
+
+	GetVideoSubtitles(ctx context.Context, videoID int64) ([]admin.Subtitle, error)
+	SaveVideoSubtitle(ctx context.Context, sub *admin.Subtitle) error
+	DeleteVideoSubtitle(ctx context.Context, videoID int64, language string) (string, error)
+
+	GetUserDiary(ctx context.Context, userID int64, filter *admin.DiaryFilter) ([]admin.DiaryEntry, error)
+	GetDiaryTrend(ctx context.Context, filter *admin.DiaryTrendFilter) ([]admin.DiaryTrendPoint, error)
+
+	GetVideoComments(ctx context.Context, status string) ([]admin.VideoComment, error)
+	UpdateVideoCommentStatus(ctx context.Context, id int64, status, moderator string) error
+
+	GetAppConfig(ctx context.Context) (*admin.AppConfig, error)
+	UpdateAppConfig(ctx context.Context, cfg *admin.AppConfig) error
+
+	AddWebhook(ctx context.Context, req *admin.Webhook) (*admin.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (*admin.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]admin.Webhook, error)
+	UpdateWebhook(ctx context.Context, req *admin.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnqueueWebhookEvent(ctx context.Context, event string, payload []byte) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]admin.WebhookDelivery, error)
+	SaveWebhookAttempt(ctx context.Context, id int64, attempt *admin.WebhookAttempt) error
+	GetWebhookDeliveries(ctx context.Context, webhookID int64, limit int) ([]admin.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, deliveryID int64) (int64, error)
 }
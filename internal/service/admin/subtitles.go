@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/langowen/bodybalance-backend/pkg/lib/webvtt"
+	"github.com/theartofdevel/logging"
+)
+
+// validLanguagePattern код языка BCP 47: основной подтег и необязательные регион или письменность
+var validLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// maxSubtitleLabelLength максимальная длина названия дорожки в символах
+const maxSubtitleLabelLength = 64
+
+func (s *ServiceAdmin) GetVideoSubtitles(ctx context.Context, videoID int64) ([]admin.Subtitle, error) {
+	const op = "service.GetVideoSubtitles"
+
+	subtitles, err := s.db.GetVideoSubtitles(ctx, videoID)
+	if err != nil {
+		if errors.Is(err, admin.ErrVideoNotFound) {
+			logging.L(ctx).Warn("video not found", "op", op, "video_id", videoID)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get subtitles", "op", op, "video_id", videoID, sl.Err(err))
+		return nil, err
+	}
+
+	return subtitles, nil
+}
+
+// SaveVideoSubtitle проверяет файл WebVTT и сохраняет его как дорожку субтитров видео на языке language.
+// Дорожка на том же языке заменяется. Файл получает имя из ID видео и языка.
+func (s *ServiceAdmin) SaveVideoSubtitle(ctx context.Context, videoID int64, language, label string, file multipart.File) (*admin.Subtitle, error) {
+	const op = "service.SaveVideoSubtitle"
+
+	language = strings.TrimSpace(language)
+	label = strings.TrimSpace(label)
+
+	if !validLanguagePattern.MatchString(language) {
+		logging.L(ctx).Warn("invalid subtitle language", "op", op, "video_id", videoID, "language", language)
+		return nil, admin.ErrSubtitleInvalidLanguage
+	}
+
+	if utf8.RuneCountInString(label) > maxSubtitleLabelLength {
+		return nil, admin.ErrSubtitleInvalidLabel
+	}
+
+	if label == "" {
+		label = language
+	}
+
+	// Размер файла ограничен лимитом загрузки
+	data, err := io.ReadAll(file)
+	if err != nil {
+		logging.L(ctx).Error("Failed to read subtitles", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToReadFile
+	}
+
+	cues, err := webvtt.Validate(data)
+	if err != nil {
+		logging.L(ctx).Warn("invalid webvtt file", "op", op, "video_id", videoID, "language", language, sl.Err(err))
+		return nil, fmt.Errorf("%w: %v", admin.ErrSubtitleInvalidFile, err)
+	}
+
+	sub := &admin.Subtitle{
+		VideoID:  videoID,
+		Language: language,
+		Label:    label,
+		URL:      fmt.Sprintf("video%d_%s.vtt", videoID, language),
+	}
+
+	// Сначала проверяем видео, чтобы не сохранять файл, на который не будет ссылки
+	if _, err = s.db.GetVideoSubtitles(ctx, videoID); err != nil {
+		if errors.Is(err, admin.ErrVideoNotFound) {
+			logging.L(ctx).Warn("video not found", "op", op, "video_id", videoID)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get subtitles", "op", op, "video_id", videoID, sl.Err(err))
+		return nil, err
+	}
+
+	if _, err = saveFile(ctx, s.media.Subtitles, sub.URL, bytes.NewReader(data), int64(len(data))); err != nil {
+		logging.L(ctx).Error("Failed to save subtitles", "file", sub.URL, sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	if err = s.db.SaveVideoSubtitle(ctx, sub); err != nil {
+		if errors.Is(err, admin.ErrVideoNotFound) {
+			logging.L(ctx).Warn("video not found", "op", op, "video_id", videoID)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to save subtitle track", "op", op, "video_id", videoID, "language", language, sl.Err(err))
+		return nil, err
+	}
+
+	logging.L(ctx).Info("subtitles saved", "op", op, "video_id", videoID, "language", language, "cues", cues)
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+	}
+
+	return sub, nil
+}
+
+// DeleteVideoSubtitle удаляет дорожку субтитров видео и ее файл
+func (s *ServiceAdmin) DeleteVideoSubtitle(ctx context.Context, videoID int64, language string) error {
+	const op = "service.DeleteVideoSubtitle"
+
+	filename, err := s.db.DeleteVideoSubtitle(ctx, videoID, language)
+	if err != nil {
+		if errors.Is(err, admin.ErrSubtitleNotFound) {
+			logging.L(ctx).Warn("subtitle track not found", "op", op, "video_id", videoID, "language", language)
+			return err
+		}
+		logging.L(ctx).Error("failed to delete subtitle track", "op", op, "video_id", videoID, "language", language, sl.Err(err))
+		return err
+	}
+
+	if err = s.media.Subtitles.Delete(ctx, filename); err != nil && !errors.Is(err, mediastore.ErrNotFound) {
+		logging.L(ctx).Warn("Failed to delete subtitles file", "file", filename, sl.Err(err), "op", op)
+	}
+
+	if s.cfg.Redis.Enable == true {
+		go s.removeCache(ctx, op)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subtitleStorage хранит дорожки субтитров одного видео; остальные методы AdmStorage не нужны
+type subtitleStorage struct {
+	AdmStorage
+}
+
+func (subtitleStorage) GetVideoSubtitles(context.Context, int64) ([]admin.Subtitle, error) {
+	return nil, nil
+}
+
+func (subtitleStorage) SaveVideoSubtitle(context.Context, *admin.Subtitle) error { return nil }
+
+func (subtitleStorage) DeleteVideoSubtitle(context.Context, int64, string) (string, error) {
+	return "video7_ru.vtt", nil
+}
+
+func TestSubtitles_InvalidateVideoCache(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Redis.Enable = true
+	cache := newMemCache()
+	s := &ServiceAdmin{
+		cfg:   cfg,
+		db:    subtitleStorage{},
+		redis: cache,
+		media: mediastore.Stores{Subtitles: mediastore.NewLocal(t.TempDir())},
+	}
+	ctx := context.Background()
+
+	// Дорожки субтитров отдаются в /v1/video из кэша video:<id>
+	cache.set("video:7")
+	vtt := []byte("WEBVTT\n\n00:00.000 --> 00:04.500\nСядьте на край стула\n")
+	_, err := s.SaveVideoSubtitle(ctx, 7, "ru", "Русский", memFile{bytes.NewReader(vtt)})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return !cache.has("video:7") }, time.Second, 10*time.Millisecond)
+
+	cache.set("video:7")
+	require.NoError(t, s.DeleteVideoSubtitle(ctx, 7, "ru"))
+	assert.Eventually(t, func() bool { return !cache.has("video:7") }, time.Second, 10*time.Millisecond)
+}
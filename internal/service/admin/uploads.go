@@ -0,0 +1,422 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// Файлы незавершенной загрузки в UploadsPatch: описание и принятые байты.
+// Смещение загрузки — размер файла с данными, поэтому оно переживает перезапуск сервиса.
+const (
+	uploadInfoSuffix = ".info"
+	uploadDataSuffix = ".bin"
+)
+
+// validUploadID ID загрузки: имя экземпляра из UPLOADS_NODE с точкой и 16 случайных байт в hex.
+// Без UPLOADS_NODE имя экземпляра в ID не пишется.
+var validUploadID = regexp.MustCompile(`^(?:([a-z0-9-]{1,63})\.)?[0-9a-f]{32}$`)
+
+// uploadInfo описание загрузки, которое хранится рядом с данными
+type uploadInfo struct {
+	Filename  string    `json:"filename"`
+	Length    int64     `json:"length"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateUpload создает загрузку видео по частям. Имя файла проверяется сразу, чтобы не принимать гигабайты впустую.
+func (s *ServiceAdmin) CreateUpload(ctx context.Context, filename string, length int64) (*admin.Upload, error) {
+	const op = "service.CreateUpload"
+
+	if !validFilePattern.MatchString(filename) {
+		logging.L(ctx).Warn("invalid upload file name", "file", filename, "op", op)
+		return nil, admin.ErrInvalidFileName
+	}
+
+	if length <= 0 {
+		logging.L(ctx).Warn("invalid upload length", "length", length, "op", op)
+		return nil, admin.ErrUploadInvalidLength
+	}
+
+	if length > s.cfg.Media.UploadMaxSize {
+		logging.L(ctx).Warn("upload is too large", "length", length, "op", op)
+		return nil, admin.ErrUploadTooLarge
+	}
+
+	// Брошенные загрузки удаляются при создании новых
+	s.removeExpiredUploads(ctx)
+
+	id, err := newUploadID(s.cfg.Media.UploadsNode)
+	if err != nil {
+		logging.L(ctx).Error("Failed to generate upload ID", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	if err = os.MkdirAll(s.cfg.Media.UploadsPatch, 0755); err != nil {
+		logging.L(ctx).Error("Failed to create uploads directory", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	info, err := json.Marshal(uploadInfo{Filename: filename, Length: length, CreatedAt: time.Now()})
+	if err != nil {
+		logging.L(ctx).Error("Failed to encode upload info", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	dataPath := s.uploadPath(id, uploadDataSuffix)
+	if err = os.WriteFile(dataPath, nil, 0644); err != nil {
+		logging.L(ctx).Error("Failed to create upload data file", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	// Описание пишется последним: загрузка без описания считается несуществующей
+	if err = os.WriteFile(s.uploadPath(id, uploadInfoSuffix), info, 0644); err != nil {
+		os.Remove(dataPath)
+		logging.L(ctx).Error("Failed to save upload info", sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	logging.L(ctx).Info("upload created", "id", id, "file", filename, "length", length, "op", op)
+
+	return &admin.Upload{
+		ID:        id,
+		Filename:  filename,
+		Length:    length,
+		ExpiresAt: time.Now().Add(s.cfg.Media.UploadTTL),
+	}, nil
+}
+
+// GetUpload возвращает состояние загрузки
+func (s *ServiceAdmin) GetUpload(ctx context.Context, id string) (*admin.Upload, error) {
+	const op = "service.GetUpload"
+
+	if err := s.checkUploadNode(ctx, id, op); err != nil {
+		return nil, err
+	}
+
+	upload, err := s.loadUpload(id)
+	if err != nil {
+		if !errors.Is(err, admin.ErrUploadNotFound) {
+			logging.L(ctx).Error("Failed to load upload", "id", id, sl.Err(err), "op", op)
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// WriteUpload дописывает данные в загрузку с указанного смещения. Байты, принятые до обрыва соединения,
+// сохраняются, и клиент продолжает с нового смещения. После последнего байта файл проверяется
+// так же, как в UploadFile, и переносится в библиотеку видео.
+func (s *ServiceAdmin) WriteUpload(ctx context.Context, id string, offset int64, data io.Reader) (*admin.Upload, error) {
+	const op = "service.WriteUpload"
+
+	if err := s.checkUploadNode(ctx, id, op); err != nil {
+		return nil, err
+	}
+
+	unlock, ok := s.lockUpload(id)
+	if !ok {
+		logging.L(ctx).Warn("upload is locked", "id", id, "op", op)
+		return nil, admin.ErrUploadLocked
+	}
+	defer unlock()
+
+	upload, err := s.loadUpload(id)
+	if err != nil {
+		if !errors.Is(err, admin.ErrUploadNotFound) {
+			logging.L(ctx).Error("Failed to load upload", "id", id, sl.Err(err), "op", op)
+		}
+		return nil, err
+	}
+
+	if offset != upload.Offset {
+		logging.L(ctx).Warn("upload offset mismatch", "id", id, "offset", offset, "expected", upload.Offset, "op", op)
+		return upload, admin.ErrUploadOffsetMismatch
+	}
+
+	dst, err := os.OpenFile(s.uploadPath(id, uploadDataSuffix), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logging.L(ctx).Error("Failed to open upload data file", "id", id, sl.Err(err), "op", op)
+		return nil, admin.ErrFailedToSaveFile
+	}
+
+	written, copyErr := io.Copy(dst, io.LimitReader(data, upload.Length-upload.Offset))
+	if err = dst.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+
+	upload.Offset += written
+	upload.ExpiresAt = time.Now().Add(s.cfg.Media.UploadTTL)
+
+	if copyErr != nil {
+		// Обрыв соединения — обычная ситуация для загрузки по частям
+		logging.L(ctx).Warn("upload interrupted", "id", id, "offset", upload.Offset, sl.Err(copyErr), "op", op)
+		return upload, admin.ErrFailedToReadFile
+	}
+
+	if upload.Offset < upload.Length {
+		return upload, nil
+	}
+
+	if err = s.completeUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	upload.Completed = true
+
+	return upload, nil
+}
+
+// DeleteUpload отменяет загрузку и удаляет принятые данные
+func (s *ServiceAdmin) DeleteUpload(ctx context.Context, id string) error {
+	const op = "service.DeleteUpload"
+
+	if err := s.checkUploadNode(ctx, id, op); err != nil {
+		return err
+	}
+
+	unlock, ok := s.lockUpload(id)
+	if !ok {
+		logging.L(ctx).Warn("upload is locked", "id", id, "op", op)
+		return admin.ErrUploadLocked
+	}
+	defer unlock()
+
+	if _, err := s.loadUpload(id); err != nil {
+		if !errors.Is(err, admin.ErrUploadNotFound) {
+			logging.L(ctx).Error("Failed to load upload", "id", id, sl.Err(err), "op", op)
+		}
+		return err
+	}
+
+	if err := s.removeUpload(id); err != nil {
+		logging.L(ctx).Error("Failed to delete upload", "id", id, sl.Err(err), "op", op)
+		return admin.ErrFailedToSaveFile
+	}
+
+	return nil
+}
+
+// completeUpload проверяет тип принятого файла и сохраняет его в библиотеку видео.
+// Файл неподдерживаемого типа удаляется вместе с загрузкой.
+func (s *ServiceAdmin) completeUpload(ctx context.Context, upload *admin.Upload) error {
+	const op = "service.completeUpload"
+
+	dataPath := s.uploadPath(upload.ID, uploadDataSuffix)
+
+	buff, err := readFileHeader(dataPath)
+	if err != nil {
+		logging.L(ctx).Error("Failed to read file header", "id", upload.ID, sl.Err(err), "op", op)
+		return admin.ErrFailedToReadFile
+	}
+
+	mimeType, ok := detectVideoMIME(buff)
+	if !ok {
+		logging.L(ctx).Error("Invalid video type", "content_type", mimeType, "id", upload.ID, "op", op)
+		if err = s.removeUpload(upload.ID); err != nil {
+			logging.L(ctx).Error("Failed to delete upload", "id", upload.ID, sl.Err(err), "op", op)
+		}
+		return admin.ErrFileTypeNotSupported
+	}
+
+	saved, err := s.storeUploadedVideo(ctx, dataPath, upload, mimeType, op)
+	if err != nil {
+		logging.L(ctx).Error("Failed to store uploaded video", "id", upload.ID, sl.Err(err), "op", op)
+		return admin.ErrFailedToSaveFile
+	}
+
+	if err = s.removeUpload(upload.ID); err != nil {
+		logging.L(ctx).Warn("Failed to delete upload files", "id", upload.ID, sl.Err(err), "op", op)
+	}
+
+	logging.L(ctx).Info("upload completed", "id", upload.ID, "file", upload.Filename, "sha256", saved.Checksum, "op", op)
+
+	return nil
+}
+
+// storeUploadedVideo сохраняет принятый файл в библиотеку видео
+func (s *ServiceAdmin) storeUploadedVideo(ctx context.Context, dataPath string, upload *admin.Upload, mimeType, op string) (*admin.SavedFile, error) {
+	file, err := os.Open(dataPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	return s.saveVideo(ctx, upload.Filename, file, upload.Length, mimeType, op)
+}
+
+// loadUpload читает описание загрузки и текущее смещение. Загрузка с истекшим сроком считается несуществующей.
+func (s *ServiceAdmin) loadUpload(id string) (*admin.Upload, error) {
+	if !validUploadID.MatchString(id) {
+		return nil, admin.ErrUploadNotFound
+	}
+
+	data, err := os.ReadFile(s.uploadPath(id, uploadInfoSuffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, admin.ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to read upload info: %w", err)
+	}
+
+	var info uploadInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode upload info: %w", err)
+	}
+
+	stat, err := os.Stat(s.uploadPath(id, uploadDataSuffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, admin.ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to stat upload data: %w", err)
+	}
+
+	expiresAt := stat.ModTime().Add(s.cfg.Media.UploadTTL)
+	if time.Now().After(expiresAt) {
+		return nil, admin.ErrUploadNotFound
+	}
+
+	return &admin.Upload{
+		ID:        id,
+		Filename:  info.Filename,
+		Length:    info.Length,
+		Offset:    stat.Size(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// removeExpiredUploads удаляет загрузки, в которые не писали дольше UploadTTL
+func (s *ServiceAdmin) removeExpiredUploads(ctx context.Context) {
+	const op = "service.removeExpiredUploads"
+
+	entries, err := os.ReadDir(s.cfg.Media.UploadsPatch)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logging.L(ctx).Warn("Failed to read uploads directory", sl.Err(err), "op", op)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), uploadInfoSuffix)
+		if !ok || !validUploadID.MatchString(id) {
+			continue
+		}
+
+		unlock, ok := s.lockUpload(id)
+		if !ok {
+			continue
+		}
+
+		if _, err = s.loadUpload(id); errors.Is(err, admin.ErrUploadNotFound) {
+			if err = s.removeUpload(id); err != nil {
+				logging.L(ctx).Warn("Failed to delete expired upload", "id", id, sl.Err(err), "op", op)
+			} else {
+				logging.L(ctx).Info("expired upload deleted", "id", id, "op", op)
+			}
+		}
+
+		unlock()
+	}
+}
+
+// checkUploadNode проверяет, что загрузка создана этим экземпляром. Данные и блокировка загрузки есть
+// только у него: другой экземпляр не нашел бы загрузку или начал бы писать ее параллельно.
+func (s *ServiceAdmin) checkUploadNode(ctx context.Context, id, op string) error {
+	m := validUploadID.FindStringSubmatch(id)
+	if m == nil || m[1] == s.cfg.Media.UploadsNode {
+		return nil
+	}
+
+	logging.L(ctx).Warn("upload belongs to another instance", "id", id, "node", m[1], "op", op)
+	return admin.ErrUploadWrongNode
+}
+
+// lockUpload захватывает загрузку без ожидания. Возвращает false, если в нее уже пишет другой запрос.
+// Если к освобождению загрузки больше нет — она завершена, отменена или удалена по сроку, — ее мьютекс
+// убирается из uploadLocks, иначе карта росла бы с каждой загрузкой.
+func (s *ServiceAdmin) lockUpload(id string) (func(), bool) {
+	v, _ := s.uploadLocks.LoadOrStore(id, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+
+	if !mu.TryLock() {
+		return nil, false
+	}
+
+	return func() {
+		if !s.uploadExists(id) {
+			s.uploadLocks.CompareAndDelete(id, mu)
+		}
+		mu.Unlock()
+	}, true
+}
+
+// uploadExists проверяет, что описание загрузки еще лежит в UploadsPatch
+func (s *ServiceAdmin) uploadExists(id string) bool {
+	if !validUploadID.MatchString(id) {
+		return false
+	}
+
+	_, err := os.Stat(s.uploadPath(id, uploadInfoSuffix))
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func (s *ServiceAdmin) removeUpload(id string) error {
+	for _, suffix := range []string{uploadInfoSuffix, uploadDataSuffix} {
+		if err := os.Remove(s.uploadPath(id, suffix)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ServiceAdmin) uploadPath(id, suffix string) string {
+	return filepath.Join(s.cfg.Media.UploadsPatch, id+suffix)
+}
+
+func newUploadID(node string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	if node == "" {
+		return hex.EncodeToString(b), nil
+	}
+
+	return node + "." + hex.EncodeToString(b), nil
+}
+
+// readFileHeader читает первые 512 байт файла для определения MIME типа
+func readFileHeader(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buff := make([]byte, 512)
+	n, err := io.ReadFull(f, buff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+
+	return buff[:n], nil
+}
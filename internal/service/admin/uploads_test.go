@@ -0,0 +1,226 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/mediastore"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader отдает данные, а затем ошибку, как оборванное соединение
+type failingReader struct {
+	data []byte
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func newUploadService(t *testing.T) (*ServiceAdmin, *mediastore.Local) {
+	t.Helper()
+
+	videos := mediastore.NewLocal(t.TempDir())
+
+	cfg := &config.Config{}
+	cfg.Media.UploadsPatch = t.TempDir()
+	cfg.Media.UploadTTL = time.Hour
+	cfg.Media.UploadMaxSize = 1 << 20
+
+	return &ServiceAdmin{cfg: cfg, media: mediastore.Stores{Video: videos}}, videos
+}
+
+// mp4Data возвращает начало MP4 с заголовком ftyp, дополненное до size байт
+func mp4Data(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"))
+	return data
+}
+
+// lockCount возвращает число записей в uploadLocks
+func lockCount(s *ServiceAdmin) int {
+	n := 0
+	s.uploadLocks.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestWriteUpload_OffsetMismatch(t *testing.T) {
+	s, _ := newUploadService(t)
+	ctx := context.Background()
+
+	upload, err := s.CreateUpload(ctx, "neck.mp4", 100)
+	require.NoError(t, err)
+
+	// Смещение не совпадает с числом принятых байт — данные не пишутся, клиент получает текущее смещение
+	got, err := s.WriteUpload(ctx, upload.ID, 10, bytes.NewReader(make([]byte, 10)))
+	require.ErrorIs(t, err, admin.ErrUploadOffsetMismatch)
+	assert.Equal(t, int64(0), got.Offset)
+
+	got, err = s.GetUpload(ctx, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), got.Offset)
+}
+
+func TestWriteUpload_Resume(t *testing.T) {
+	s, videos := newUploadService(t)
+	ctx := context.Background()
+
+	data := mp4Data(1000)
+
+	upload, err := s.CreateUpload(ctx, "neck.mp4", int64(len(data)))
+	require.NoError(t, err)
+
+	// Соединение оборвалось после 300 байт: принятые байты сохраняются
+	got, err := s.WriteUpload(ctx, upload.ID, 0, &failingReader{data: data[:300]})
+	require.ErrorIs(t, err, admin.ErrFailedToReadFile)
+	assert.Equal(t, int64(300), got.Offset)
+
+	got, err = s.GetUpload(ctx, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), got.Offset)
+
+	// Клиент продолжает с нового смещения и завершает загрузку
+	got, err = s.WriteUpload(ctx, upload.ID, 300, bytes.NewReader(data[300:]))
+	require.NoError(t, err)
+	assert.True(t, got.Completed)
+	assert.Equal(t, int64(len(data)), got.Offset)
+
+	r, _, err := videos.Open(ctx, "neck.mp4")
+	require.NoError(t, err)
+	defer r.Close()
+
+	var saved bytes.Buffer
+	_, err = saved.ReadFrom(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, saved.Bytes())
+
+	// Завершенная загрузка удаляется вместе с мьютексом
+	_, err = s.GetUpload(ctx, upload.ID)
+	assert.ErrorIs(t, err, admin.ErrUploadNotFound)
+	assert.Zero(t, lockCount(s))
+}
+
+func TestWriteUpload_NotVideo(t *testing.T) {
+	s, videos := newUploadService(t)
+	ctx := context.Background()
+
+	data := []byte("<html><script>alert(1)</script></html>")
+
+	upload, err := s.CreateUpload(ctx, "neck.mp4", int64(len(data)))
+	require.NoError(t, err)
+
+	// Тип проверяется по содержимому после последнего байта, загрузка удаляется
+	_, err = s.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(data))
+	require.ErrorIs(t, err, admin.ErrFileTypeNotSupported)
+
+	_, err = videos.Stat(ctx, "neck.mp4")
+	assert.ErrorIs(t, err, mediastore.ErrNotFound)
+
+	_, err = s.GetUpload(ctx, upload.ID)
+	assert.ErrorIs(t, err, admin.ErrUploadNotFound)
+	assert.Zero(t, lockCount(s))
+}
+
+func TestUpload_Expired(t *testing.T) {
+	s, _ := newUploadService(t)
+	ctx := context.Background()
+
+	upload, err := s.CreateUpload(ctx, "neck.mp4", 100)
+	require.NoError(t, err)
+
+	_, err = s.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(make([]byte, 10)))
+	require.NoError(t, err)
+
+	// В загрузку не писали дольше UploadTTL
+	old := time.Now().Add(-2 * s.cfg.Media.UploadTTL)
+	require.NoError(t, os.Chtimes(s.uploadPath(upload.ID, uploadDataSuffix), old, old))
+
+	_, err = s.GetUpload(ctx, upload.ID)
+	require.ErrorIs(t, err, admin.ErrUploadNotFound)
+
+	_, err = s.WriteUpload(ctx, upload.ID, 10, bytes.NewReader(make([]byte, 10)))
+	require.ErrorIs(t, err, admin.ErrUploadNotFound)
+
+	// Файлы истекшей загрузки удаляются при создании следующей
+	_, err = s.CreateUpload(ctx, "back.mp4", 100)
+	require.NoError(t, err)
+
+	_, err = os.Stat(s.uploadPath(upload.ID, uploadInfoSuffix))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(s.uploadPath(upload.ID, uploadDataSuffix))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, exists := s.uploadLocks.Load(upload.ID)
+	assert.False(t, exists)
+}
+
+func TestDeleteUpload(t *testing.T) {
+	s, _ := newUploadService(t)
+	ctx := context.Background()
+
+	upload, err := s.CreateUpload(ctx, "neck.mp4", 100)
+	require.NoError(t, err)
+
+	// Пока загрузка существует, мьютекс остается в карте
+	_, err = s.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(make([]byte, 10)))
+	require.NoError(t, err)
+	assert.Equal(t, 1, lockCount(s))
+
+	require.NoError(t, s.DeleteUpload(ctx, upload.ID))
+	assert.Zero(t, lockCount(s))
+
+	err = s.DeleteUpload(ctx, upload.ID)
+	assert.ErrorIs(t, err, admin.ErrUploadNotFound)
+	assert.Zero(t, lockCount(s))
+}
+
+func TestUpload_AnotherNode(t *testing.T) {
+	first, videos := newUploadService(t)
+	first.cfg.Media.UploadsNode = "api-1"
+	second, _ := newUploadService(t)
+	second.cfg.Media.UploadsNode = "api-2"
+	ctx := context.Background()
+
+	data := mp4Data(100)
+
+	upload, err := first.CreateUpload(ctx, "neck.mp4", int64(len(data)))
+	require.NoError(t, err)
+	assert.Regexp(t, `^api-1\.[0-9a-f]{32}$`, upload.ID)
+
+	_, err = first.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(data[:40]))
+	require.NoError(t, err)
+
+	// Запросы, попавшие на другой экземпляр, отклоняются, а не получают 404 или пишут в пустую загрузку
+	_, err = second.GetUpload(ctx, upload.ID)
+	require.ErrorIs(t, err, admin.ErrUploadWrongNode)
+
+	_, err = second.WriteUpload(ctx, upload.ID, 40, bytes.NewReader(data[40:]))
+	require.ErrorIs(t, err, admin.ErrUploadWrongNode)
+
+	err = second.DeleteUpload(ctx, upload.ID)
+	require.ErrorIs(t, err, admin.ErrUploadWrongNode)
+	assert.Zero(t, lockCount(second))
+
+	// Свой экземпляр продолжает загрузку с принятого смещения
+	got, err := first.WriteUpload(ctx, upload.ID, 40, bytes.NewReader(data[40:]))
+	require.NoError(t, err)
+	assert.True(t, got.Completed)
+
+	_, err = videos.Stat(ctx, "neck.mp4")
+	assert.NoError(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/langowen/bodybalance-backend/internal/entities/admin"
 	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
@@ -40,6 +41,34 @@ func (s *ServiceAdmin) AddVideo(ctx context.Context, req *admin.Video) (int64, e
 		case errors.Is(err, admin.ErrVideoImgSuspiciousPattern):
 			logging.L(ctx).Warn("suspicious pattern in video ImgURL", "imgurl", req.ImgURL, "op", op)
 			return 0, admin.ErrVideoImgSuspiciousPattern
+		case errors.Is(err, admin.ErrVideoInvalidTag):
+			logging.L(ctx).Warn("invalid video tag", "tags", req.Tags, "op", op)
+			return 0, admin.ErrVideoInvalidTag
+		case errors.Is(err, admin.ErrVideoNextUpInvalid):
+			logging.L(ctx).Warn("invalid next up videos", "next_video_ids", req.NextVideoIDs, "op", op)
+			return 0, admin.ErrVideoNextUpInvalid
+		case errors.Is(err, admin.ErrVideoInvalidSteps),
+			errors.Is(err, admin.ErrVideoInvalidContraindications),
+			errors.Is(err, admin.ErrVideoInvalidEquipment),
+			errors.Is(err, admin.ErrVideoInvalidDosage),
+			errors.Is(err, admin.ErrVideoInvalidDifficulty):
+			logging.L(ctx).Warn("invalid exercise details", "details", req.Details, "op", op, sl.Err(err))
+			return 0, err
+		}
+	}
+
+	err = s.checkVideoFiles(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrVideoFileNotFound):
+			logging.L(ctx).Warn("video file not found", "url", req.URL, "op", op)
+			return 0, admin.ErrVideoFileNotFound
+		case errors.Is(err, admin.ErrVideoImgNotFound):
+			logging.L(ctx).Warn("video image file not found", "imgurl", req.ImgURL, "op", op)
+			return 0, admin.ErrVideoImgNotFound
+		default:
+			logging.L(ctx).Error("failed to check video files", "url", req.URL, "imgurl", req.ImgURL, "op", op, sl.Err(err))
+			return 0, err
 		}
 	}
 
@@ -49,6 +78,10 @@ func (s *ServiceAdmin) AddVideo(ctx context.Context, req *admin.Video) (int64, e
 			logging.L(ctx).Warn("category not found", "op", op, "categories", req.Categories, sl.Err(err))
 			return 0, admin.ErrCategoryNotFound
 		}
+		if errors.Is(err, admin.ErrVideoNextUpNotFound) {
+			logging.L(ctx).Warn("next up video not found", "op", op, "next_video_ids", req.NextVideoIDs, sl.Err(err))
+			return 0, admin.ErrVideoNextUpNotFound
+		}
 		logging.L(ctx).Error("failed to add video", "op", op, "video", video, sl.Err(err))
 		return 0, admin.ErrVideoSaveFailed
 	}
@@ -57,6 +90,8 @@ func (s *ServiceAdmin) AddVideo(ctx context.Context, req *admin.Video) (int64, e
 		go s.removeCache(ctx, op)
 	}
 
+	s.emitWebhookEvent(ctx, admin.EventVideoCreated, map[string]any{"id": video, "name": req.Name})
+
 	return video, nil
 }
 
@@ -132,6 +167,34 @@ func (s *ServiceAdmin) UpdateVideo(ctx context.Context, req *admin.Video) error
 		case errors.Is(err, admin.ErrVideoImgSuspiciousPattern):
 			logging.L(ctx).Warn("suspicious pattern in video ImgURL", "imgurl", req.ImgURL, "op", op)
 			return admin.ErrVideoImgSuspiciousPattern
+		case errors.Is(err, admin.ErrVideoInvalidTag):
+			logging.L(ctx).Warn("invalid video tag", "tags", req.Tags, "op", op)
+			return admin.ErrVideoInvalidTag
+		case errors.Is(err, admin.ErrVideoNextUpInvalid):
+			logging.L(ctx).Warn("invalid next up videos", "next_video_ids", req.NextVideoIDs, "op", op)
+			return admin.ErrVideoNextUpInvalid
+		case errors.Is(err, admin.ErrVideoInvalidSteps),
+			errors.Is(err, admin.ErrVideoInvalidContraindications),
+			errors.Is(err, admin.ErrVideoInvalidEquipment),
+			errors.Is(err, admin.ErrVideoInvalidDosage),
+			errors.Is(err, admin.ErrVideoInvalidDifficulty):
+			logging.L(ctx).Warn("invalid exercise details", "details", req.Details, "op", op, sl.Err(err))
+			return err
+		}
+	}
+
+	err = s.checkVideoFiles(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrVideoFileNotFound):
+			logging.L(ctx).Warn("video file not found", "url", req.URL, "op", op)
+			return admin.ErrVideoFileNotFound
+		case errors.Is(err, admin.ErrVideoImgNotFound):
+			logging.L(ctx).Warn("video image file not found", "imgurl", req.ImgURL, "op", op)
+			return admin.ErrVideoImgNotFound
+		default:
+			logging.L(ctx).Error("failed to check video files", "url", req.URL, "imgurl", req.ImgURL, "op", op, sl.Err(err))
+			return err
 		}
 	}
 
@@ -141,6 +204,10 @@ func (s *ServiceAdmin) UpdateVideo(ctx context.Context, req *admin.Video) error
 			logging.L(ctx).Warn("video not found", "op", op, "video_id", req.ID, sl.Err(err))
 			return admin.ErrVideoNotFound
 		}
+		if errors.Is(err, admin.ErrVideoNextUpNotFound) {
+			logging.L(ctx).Warn("next up video not found", "op", op, "next_video_ids", req.NextVideoIDs, sl.Err(err))
+			return admin.ErrVideoNextUpNotFound
+		}
 		logging.L(ctx).Error("failed to update video", "op", op, "video_id", req.ID, sl.Err(err))
 		return admin.ErrVideoUpdateFailed
 	}
@@ -149,6 +216,8 @@ func (s *ServiceAdmin) UpdateVideo(ctx context.Context, req *admin.Video) error
 		go s.removeCache(ctx, op)
 	}
 
+	s.emitWebhookEvent(ctx, admin.EventVideoUpdated, map[string]any{"id": req.ID, "name": req.Name})
+
 	return nil
 }
 
@@ -174,6 +243,8 @@ func (s *ServiceAdmin) DeleteVideo(ctx context.Context, id int64) error {
 		go s.removeCache(ctx, op)
 	}
 
+	s.emitWebhookEvent(ctx, admin.EventVideoDeleted, map[string]any{"id": id})
+
 	return nil
 }
 
@@ -206,5 +277,104 @@ func validVideo(req *admin.Video) error {
 		}
 	}
 
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return err
+	}
+	req.Tags = tags
+
+	nextIDs := make([]int64, 0, len(req.NextVideoIDs))
+	seen := make(map[int64]bool, len(req.NextVideoIDs))
+	for _, id := range req.NextVideoIDs {
+		if id <= 0 || id == req.ID {
+			return admin.ErrVideoNextUpInvalid
+		}
+		if !seen[id] {
+			seen[id] = true
+			nextIDs = append(nextIDs, id)
+		}
+	}
+	req.NextVideoIDs = nextIDs
+
+	details, err := normalizeExerciseDetails(req.Details)
+	if err != nil {
+		return err
+	}
+	req.Details = details
+
 	return nil
 }
+
+// normalizeExerciseDetails убирает пробелы по краям и пустые строки в списках упражнения
+// и проверяет ограничения на их размер, подходы, повторения и уровень сложности
+func normalizeExerciseDetails(details admin.ExerciseDetails) (admin.ExerciseDetails, error) {
+	var ok bool
+
+	if details.Steps, ok = normalizeTextList(details.Steps, maxExerciseSteps, maxExerciseStepLen, false); !ok {
+		return details, admin.ErrVideoInvalidSteps
+	}
+	if details.Contraindications, ok = normalizeTextList(details.Contraindications, maxExerciseItems, maxExerciseItemLen, true); !ok {
+		return details, admin.ErrVideoInvalidContraindications
+	}
+	if details.Equipment, ok = normalizeTextList(details.Equipment, maxExerciseItems, maxExerciseItemLen, true); !ok {
+		return details, admin.ErrVideoInvalidEquipment
+	}
+
+	if details.Sets < 0 || details.Sets > maxExerciseSets || details.Reps < 0 || details.Reps > maxExerciseReps {
+		return details, admin.ErrVideoInvalidDosage
+	}
+
+	details.Difficulty = strings.ToLower(strings.TrimSpace(details.Difficulty))
+	switch details.Difficulty {
+	case "", admin.DifficultyBeginner, admin.DifficultyIntermediate, admin.DifficultyAdvanced:
+	default:
+		return details, admin.ErrVideoInvalidDifficulty
+	}
+
+	return details, nil
+}
+
+// normalizeTextList убирает пустые строки и, если dedup, повторы. Порядок элементов сохраняется.
+func normalizeTextList(items []string, maxItems, maxLen int, dedup bool) ([]string, bool) {
+	result := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if utf8.RuneCountInString(item) > maxLen {
+			return nil, false
+		}
+		if dedup {
+			key := strings.ToLower(item)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		result = append(result, item)
+	}
+
+	return result, len(result) <= maxItems
+}
+
+// normalizeTags приводит теги к нижнему регистру, убирает пробелы по краям и дубликаты
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !validTagPattern.MatchString(tag) {
+			return nil, admin.ErrVideoInvalidTag
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	return result, nil
+}
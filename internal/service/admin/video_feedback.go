@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// GetVideoComments возвращает комментарии пациентов с указанным статусом, по умолчанию — очередь на модерации
+func (s *ServiceAdmin) GetVideoComments(ctx context.Context, status string) ([]admin.VideoComment, error) {
+	const op = "service.GetVideoComments"
+
+	if status == "" {
+		status = admin.CommentStatusPending
+	}
+
+	if !validCommentStatus(status) {
+		logging.L(ctx).Warn("invalid comment status", "op", op, "status", status)
+		return nil, admin.ErrCommentInvalidStatus
+	}
+
+	comments, err := s.db.GetVideoComments(ctx, status)
+	if err != nil {
+		logging.L(ctx).Error("failed to get comments", "op", op, "status", status, sl.Err(err))
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// ModerateVideoComment одобряет или отклоняет комментарий. Комментарий можно вернуть на модерацию статусом pending.
+func (s *ServiceAdmin) ModerateVideoComment(ctx context.Context, id int64, status, moderator string) error {
+	const op = "service.ModerateVideoComment"
+
+	if !validCommentStatus(status) {
+		logging.L(ctx).Warn("invalid comment status", "op", op, "comment_id", id, "status", status)
+		return admin.ErrCommentInvalidStatus
+	}
+
+	err := s.db.UpdateVideoCommentStatus(ctx, id, status, moderator)
+	if err != nil {
+		if errors.Is(err, admin.ErrCommentNotFound) {
+			logging.L(ctx).Warn("comment not found", "op", op, "comment_id", id)
+			return err
+		}
+		logging.L(ctx).Error("failed to moderate comment", "op", op, "comment_id", id, sl.Err(err))
+		return err
+	}
+
+	logging.L(ctx).Info("comment moderated", "op", op, "comment_id", id, "status", status, "moderator", moderator)
+
+	return nil
+}
+
+func validCommentStatus(status string) bool {
+	switch status {
+	case admin.CommentStatusPending, admin.CommentStatusApproved, admin.CommentStatusRejected:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTextList(t *testing.T) {
+	// Пробелы по краям и пустые строки убираются, порядок сохраняется
+	got, ok := normalizeTextList([]string{" Лягте на спину ", "", "  ", "Согните колени"}, 5, 100, false)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Лягте на спину", "Согните колени"}, got)
+
+	// Без dedup повторы остаются
+	got, ok = normalizeTextList([]string{"Вдох", "Выдох", "Вдох"}, 5, 100, false)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Вдох", "Выдох", "Вдох"}, got)
+
+	// С dedup повторы без учета регистра убираются, остается первый
+	got, ok = normalizeTextList([]string{"Коврик", "коврик", "Мяч"}, 5, 100, true)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Коврик", "Мяч"}, got)
+
+	// nil дает пустой срез
+	got, ok = normalizeTextList(nil, 5, 100, true)
+	require.True(t, ok)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+}
+
+func TestNormalizeTextList_Limits(t *testing.T) {
+	// Длина считается в символах, а не в байтах
+	_, ok := normalizeTextList([]string{strings.Repeat("я", 10)}, 5, 10, false)
+	assert.True(t, ok)
+
+	_, ok = normalizeTextList([]string{strings.Repeat("я", 11)}, 5, 10, false)
+	assert.False(t, ok)
+
+	// Пустые строки и повторы не учитываются в лимите количества
+	_, ok = normalizeTextList([]string{"а", "", "А", "б"}, 2, 10, true)
+	assert.True(t, ok)
+
+	_, ok = normalizeTextList([]string{"а", "б", "в"}, 2, 10, true)
+	assert.False(t, ok)
+}
+
+func TestNormalizeExerciseDetails(t *testing.T) {
+	details, err := normalizeExerciseDetails(admin.ExerciseDetails{
+		Steps:             []string{" Встаньте прямо ", ""},
+		Contraindications: []string{"Грыжа", "грыжа"},
+		Equipment:         nil,
+		Sets:              3,
+		Reps:              12,
+		Difficulty:        " Beginner ",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Встаньте прямо"}, details.Steps)
+	assert.Equal(t, []string{"Грыжа"}, details.Contraindications)
+	assert.Empty(t, details.Equipment)
+	assert.Equal(t, 3, details.Sets)
+	assert.Equal(t, 12, details.Reps)
+	assert.Equal(t, admin.DifficultyBeginner, details.Difficulty)
+}
+
+func TestNormalizeExerciseDetails_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		details admin.ExerciseDetails
+		err     error
+	}{
+		{"слишком много шагов", admin.ExerciseDetails{Steps: tooManySteps()}, admin.ErrVideoInvalidSteps},
+		{"длинное противопоказание", admin.ExerciseDetails{Contraindications: []string{strings.Repeat("x", maxExerciseItemLen+1)}}, admin.ErrVideoInvalidContraindications},
+		{"длинный инвентарь", admin.ExerciseDetails{Equipment: []string{strings.Repeat("x", maxExerciseItemLen+1)}}, admin.ErrVideoInvalidEquipment},
+		{"отрицательные подходы", admin.ExerciseDetails{Sets: -1}, admin.ErrVideoInvalidDosage},
+		{"слишком много подходов", admin.ExerciseDetails{Sets: maxExerciseSets + 1}, admin.ErrVideoInvalidDosage},
+		{"слишком много повторений", admin.ExerciseDetails{Reps: maxExerciseReps + 1}, admin.ErrVideoInvalidDosage},
+		{"неизвестная сложность", admin.ExerciseDetails{Difficulty: "expert"}, admin.ErrVideoInvalidDifficulty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := normalizeExerciseDetails(tt.details)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+// tooManySteps возвращает на один шаг больше допустимого
+func tooManySteps() []string {
+	steps := make([]string, maxExerciseSteps+1)
+	for i := range steps {
+		steps[i] = "шаг"
+	}
+	return steps
+}
+
+// videoStorage сохраняет видео; остальные методы AdmStorage не нужны
+type videoStorage struct {
+	AdmStorage
+	updated *admin.Video
+}
+
+func (s *videoStorage) UpdateVideo(_ context.Context, video *admin.Video) error {
+	s.updated = video
+	return nil
+}
+
+func (s *videoStorage) EnqueueWebhookEvent(context.Context, string, []byte) (int64, error) {
+	return 0, nil
+}
+
+func TestUpdateVideo_InvalidatesVideoCache(t *testing.T) {
+	db := &videoStorage{}
+	cache := newMemCache("video:7", "video:8")
+	cfg := &config.Config{}
+	cfg.Redis.Enable = true
+	s := &ServiceAdmin{cfg: cfg, db: db, redis: cache}
+
+	video := &admin.Video{
+		ID:         7,
+		Name:       "Шея",
+		URL:        "neck.mp4",
+		ImgURL:     "neck.jpg",
+		Categories: []admin.Category{{ID: 1}},
+		Details:    admin.ExerciseDetails{Steps: []string{"Наклоните голову"}, Sets: 3, Reps: 10},
+	}
+	require.NoError(t, s.UpdateVideo(context.Background(), video))
+	assert.Equal(t, []string{"Наклоните голову"}, db.updated.Details.Steps)
+
+	// /v1/video отдает описание упражнения из кэша video:<id>, поэтому после изменения он сбрасывается
+	assert.Eventually(t, func() bool {
+		return !cache.has("video:7") && !cache.has("video:8")
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,340 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/admin"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+const (
+	// webhookMaxRetryDelay верхняя граница задержки между повторами
+	webhookMaxRetryDelay = 6 * time.Hour
+	// webhookDeliveriesLimit сколько последних доставок показывается в журнале
+	webhookDeliveriesLimit = 100
+	// maxWebhookErrorLength ограничение длины сохраняемой ошибки
+	maxWebhookErrorLength = 500
+)
+
+// WebhookSender отправляет доставку подписчику и возвращает HTTP статус ответа
+type WebhookSender interface {
+	Send(ctx context.Context, delivery *admin.WebhookDelivery) (int, error)
+}
+
+// webhookPayload тело запроса, которое получает подписчик
+type webhookPayload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+func (s *ServiceAdmin) AddWebhook(ctx context.Context, req *admin.Webhook) (*admin.Webhook, error) {
+	const op = "service.AddWebhook"
+
+	err := validWebhook(req)
+	if err != nil {
+		logging.L(ctx).Warn("invalid webhook data", "op", op, "url", req.URL, "events", req.Events, sl.Err(err))
+		return nil, err
+	}
+
+	if req.Secret == "" {
+		req.Secret, err = newWebhookSecret()
+		if err != nil {
+			logging.L(ctx).Error("failed to generate webhook secret", "op", op, sl.Err(err))
+			return nil, err
+		}
+	}
+
+	webhook, err := s.db.AddWebhook(ctx, req)
+	if err != nil {
+		logging.L(ctx).Error("failed to add webhook", "op", op, "url", req.URL, sl.Err(err))
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *ServiceAdmin) GetWebhook(ctx context.Context, id int64) (*admin.Webhook, error) {
+	const op = "service.GetWebhook"
+
+	webhook, err := s.db.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			logging.L(ctx).Warn("webhook not found", "op", op, "webhook_id", id)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get webhook", "op", op, "webhook_id", id, sl.Err(err))
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *ServiceAdmin) GetWebhooks(ctx context.Context) ([]admin.Webhook, error) {
+	const op = "service.GetWebhooks"
+
+	webhooks, err := s.db.GetWebhooks(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get webhooks", "op", op, sl.Err(err))
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook обновляет подписку. Пустой секрет оставляет прежний.
+func (s *ServiceAdmin) UpdateWebhook(ctx context.Context, req *admin.Webhook) error {
+	const op = "service.UpdateWebhook"
+
+	err := validWebhook(req)
+	if err != nil {
+		logging.L(ctx).Warn("invalid webhook data", "op", op, "webhook_id", req.ID, "url", req.URL, "events", req.Events, sl.Err(err))
+		return err
+	}
+
+	if req.Secret == "" {
+		current, err := s.GetWebhook(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		req.Secret = current.Secret
+	}
+
+	err = s.db.UpdateWebhook(ctx, req)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			logging.L(ctx).Warn("webhook not found", "op", op, "webhook_id", req.ID)
+			return err
+		}
+		logging.L(ctx).Error("failed to update webhook", "op", op, "webhook_id", req.ID, sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *ServiceAdmin) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "service.DeleteWebhook"
+
+	err := s.db.DeleteWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			logging.L(ctx).Warn("webhook not found", "op", op, "webhook_id", id)
+			return err
+		}
+		logging.L(ctx).Error("failed to delete webhook", "op", op, "webhook_id", id, sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries возвращает журнал последних доставок подписки
+func (s *ServiceAdmin) GetWebhookDeliveries(ctx context.Context, webhookID int64) ([]admin.WebhookDelivery, error) {
+	const op = "service.GetWebhookDeliveries"
+
+	deliveries, err := s.db.GetWebhookDeliveries(ctx, webhookID, webhookDeliveriesLimit)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookNotFound) {
+			logging.L(ctx).Warn("webhook not found", "op", op, "webhook_id", webhookID)
+			return nil, err
+		}
+		logging.L(ctx).Error("failed to get webhook deliveries", "op", op, "webhook_id", webhookID, sl.Err(err))
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhook ставит событие доставки deliveryID в очередь повторно и возвращает ID новой доставки
+func (s *ServiceAdmin) RedeliverWebhook(ctx context.Context, deliveryID int64) (int64, error) {
+	const op = "service.RedeliverWebhook"
+
+	id, err := s.db.RedeliverWebhook(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, admin.ErrWebhookDeliveryNotFound) {
+			logging.L(ctx).Warn("webhook delivery not found", "op", op, "delivery_id", deliveryID)
+			return 0, err
+		}
+		logging.L(ctx).Error("failed to redeliver webhook", "op", op, "delivery_id", deliveryID, sl.Err(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// emitWebhookEvent ставит событие каталога в очередь доставки подписчикам.
+// Ошибка только логируется: изменение каталога уже сохранено и не должно откатываться.
+func (s *ServiceAdmin) emitWebhookEvent(ctx context.Context, event string, data any) {
+	const op = "service.emitWebhookEvent"
+
+	payload, err := json.Marshal(webhookPayload{
+		Event:      event,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		logging.L(ctx).Warn("failed to encode webhook event", "op", op, "event", event, sl.Err(err))
+		return
+	}
+
+	count, err := s.db.EnqueueWebhookEvent(ctx, event, payload)
+	if err != nil {
+		logging.L(ctx).Warn("failed to enqueue webhook event", "op", op, "event", event, sl.Err(err))
+		return
+	}
+
+	if count > 0 {
+		logging.L(ctx).Debug("webhook event enqueued", "op", op, "event", event, "deliveries", count)
+	}
+}
+
+// RunWebhookDispatcher отправляет доставки из очереди до отмены ctx
+func (s *ServiceAdmin) RunWebhookDispatcher(ctx context.Context) {
+	if s.webhooks == nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Webhooks.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatchWebhooks(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchWebhooks отправляет доставки, время которых наступило, пока очередь не опустеет
+func (s *ServiceAdmin) dispatchWebhooks(ctx context.Context) {
+	const op = "service.dispatchWebhooks"
+
+	// Пока доставка отправляется, она скрыта от других экземпляров сервиса
+	lease := s.cfg.Webhooks.Timeout*time.Duration(s.cfg.Webhooks.BatchSize) + time.Minute
+
+	for ctx.Err() == nil {
+		deliveries, err := s.db.ClaimWebhookDeliveries(ctx, s.cfg.Webhooks.BatchSize, lease)
+		if err != nil {
+			logging.L(ctx).Error("failed to claim webhook deliveries", "op", op, sl.Err(err))
+			return
+		}
+
+		for i := range deliveries {
+			s.deliverWebhook(ctx, &deliveries[i])
+		}
+
+		if len(deliveries) < s.cfg.Webhooks.BatchSize {
+			return
+		}
+	}
+}
+
+func (s *ServiceAdmin) deliverWebhook(ctx context.Context, delivery *admin.WebhookDelivery) {
+	const op = "service.deliverWebhook"
+
+	statusCode, err := s.webhooks.Send(ctx, delivery)
+	if err != nil && ctx.Err() != nil {
+		// Доставка вернется в очередь после истечения lease
+		return
+	}
+
+	attempt := webhookAttempt(delivery.Attempts+1, s.cfg.Webhooks.MaxAttempts, s.cfg.Webhooks.RetryBase, statusCode, err)
+
+	switch attempt.Status {
+	case admin.DeliverySuccess:
+		logging.L(ctx).Debug("webhook delivered", "op", op, "delivery_id", delivery.ID, "event", delivery.Event)
+	case admin.DeliveryFailed:
+		logging.L(ctx).Error("webhook delivery failed, attempts exhausted", "op", op, "delivery_id", delivery.ID,
+			"webhook_id", delivery.WebhookID, "event", delivery.Event, "status_code", statusCode, sl.Err(err))
+	default:
+		logging.L(ctx).Warn("webhook delivery failed, retrying", "op", op, "delivery_id", delivery.ID,
+			"webhook_id", delivery.WebhookID, "event", delivery.Event, "status_code", statusCode,
+			"next_attempt_at", attempt.NextAttemptAt, sl.Err(err))
+	}
+
+	if err = s.db.SaveWebhookAttempt(ctx, delivery.ID, attempt); err != nil {
+		logging.L(ctx).Error("failed to save webhook attempt", "op", op, "delivery_id", delivery.ID, sl.Err(err))
+	}
+}
+
+// webhookAttempt определяет результат попытки номер attempts. Задержка повтора retryBase·2^(attempts-1),
+// но не больше webhookMaxRetryDelay.
+func webhookAttempt(attempts, maxAttempts int, retryBase time.Duration, statusCode int, err error) *admin.WebhookAttempt {
+	attempt := &admin.WebhookAttempt{
+		StatusCode:    statusCode,
+		Status:        admin.DeliverySuccess,
+		NextAttemptAt: time.Now(),
+	}
+
+	if err == nil {
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	if len([]rune(attempt.Error)) > maxWebhookErrorLength {
+		attempt.Error = string([]rune(attempt.Error)[:maxWebhookErrorLength])
+	}
+
+	if attempts >= maxAttempts {
+		attempt.Status = admin.DeliveryFailed
+		return attempt
+	}
+
+	delay := retryBase
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, webhookMaxRetryDelay)
+
+	attempt.Status = admin.DeliveryPending
+	attempt.NextAttemptAt = attempt.NextAttemptAt.Add(delay)
+
+	return attempt
+}
+
+// validWebhook проверяет URL и список событий подписки
+func validWebhook(req *admin.Webhook) error {
+	req.URL = strings.TrimSpace(req.URL)
+	req.Description = strings.TrimSpace(req.Description)
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return admin.ErrWebhookInvalidURL
+	}
+
+	if req.Events == nil {
+		req.Events = []string{}
+	}
+
+	for _, event := range req.Events {
+		if !slices.Contains(admin.WebhookEvents, event) {
+			return admin.ErrWebhookInvalidEvent
+		}
+	}
+
+	slices.Sort(req.Events)
+	req.Events = slices.Compact(req.Events)
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/redis/go-redis/v9"
+	"github.com/theartofdevel/logging"
+)
+
+// GetAnnouncements возвращает объявления, активные сейчас для типа контента
+func (s *ServiceApi) GetAnnouncements(ctx context.Context, contentType string) ([]api.Announcement, error) {
+	const op = "service.GetAnnouncements"
+
+	if contentType == "" {
+		logging.L(ctx).Error("Content type ID is empty", "op", op)
+		return nil, api.ErrEmptyTypeID
+	}
+
+	typeID, err := strconv.ParseInt(contentType, 10, 64)
+	if err != nil {
+		logging.L(ctx).Error("invalid type ID", "op", op, sl.Err(err))
+		return nil, api.ErrTypeInvalid
+	}
+
+	if s.cfg.Redis.Enable {
+		announcements, err := s.rdb.GetAnnouncements(ctx, typeID)
+		if err == nil && announcements != nil {
+			logging.L(ctx).Debug("announcements fetched from redis cache", "op", op)
+			return activeAnnouncements(announcements, time.Now()), nil
+		}
+
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				logging.L(ctx).Debug("announcements not found in redis cache", sl.Err(err), "op", op)
+			} else {
+				logging.L(ctx).Error("failed to get announcements from redis", sl.Err(err), "op", op)
+			}
+		}
+	}
+
+	announcements, err := s.db.GetAnnouncements(ctx, typeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrContentTypeNotFound) {
+			logging.L(ctx).Debug("content type not found", sl.Err(err), "op", op)
+			return nil, err
+		}
+
+		logging.L(ctx).Error("failed to get announcements from DB", sl.Err(err), "op", op)
+		return nil, api.ErrStorageServerError
+	}
+
+	if s.cfg.Redis.Enable {
+		go func() {
+			ctxRedis, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err = s.rdb.SetAnnouncements(ctxRedis, typeID, announcements); err != nil {
+				logging.L(ctx).Warn("failed to cache announcements in redis", sl.Err(err), "op", op)
+			}
+		}()
+	}
+
+	return activeAnnouncements(announcements, time.Now()), nil
+}
+
+// activeAnnouncements оставляет объявления, активные в момент now. Кэш хранит и запланированные объявления,
+// поэтому период проверяется после чтения, а источник данных переносится на первый элемент результата.
+func activeAnnouncements(announcements []api.Announcement, now time.Time) []api.Announcement {
+	res := make([]api.Announcement, 0, len(announcements))
+	for _, announcement := range announcements {
+		if announcement.Active(now) {
+			res = append(res, announcement)
+		}
+	}
+
+	if len(res) > 0 && len(announcements) > 0 {
+		res[0].DataSource = announcements[0].DataSource
+	}
+
+	return res
+}
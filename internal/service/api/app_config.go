@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/appversion"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/redis/go-redis/v9"
+	"github.com/theartofdevel/logging"
+)
+
+// GetAppConfig возвращает настройки мобильного приложения
+func (s *ServiceApi) GetAppConfig(ctx context.Context) (*api.AppConfig, error) {
+	const op = "service.GetAppConfig"
+
+	if s.cfg.Redis.Enable {
+		cfg, err := s.rdb.GetAppConfig(ctx)
+		if err == nil && cfg != nil {
+			logging.L(ctx).Debug("app config fetched from redis cache", "op", op)
+			return cfg, nil
+		}
+
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				logging.L(ctx).Debug("app config not found in redis cache", sl.Err(err), "op", op)
+			} else {
+				logging.L(ctx).Error("failed to get app config from redis", sl.Err(err), "op", op)
+			}
+		}
+	}
+
+	cfg, err := s.db.GetAppConfig(ctx)
+	if err != nil {
+		logging.L(ctx).Error("failed to get app config from DB", sl.Err(err), "op", op)
+		return nil, api.ErrStorageServerError
+	}
+
+	if s.cfg.Redis.Enable {
+		go func() {
+			ctxRedis, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err = s.rdb.SetAppConfig(ctxRedis, cfg); err != nil {
+				logging.L(ctx).Warn("failed to cache app config in redis", sl.Err(err), "op", op)
+			}
+		}()
+	}
+
+	return cfg, nil
+}
+
+// CheckAppVersion проверяет, поддерживается ли версия клиента. Возвращает настройки приложения,
+// если клиенту нужно обновиться, и nil, если версия поддерживается.
+func (s *ServiceApi) CheckAppVersion(ctx context.Context, version string) (*api.AppConfig, error) {
+	const op = "service.CheckAppVersion"
+
+	v, err := appversion.Parse(version)
+	if err != nil {
+		logging.L(ctx).Warn("invalid app version", "op", op, "app_version", version)
+		return nil, api.ErrInvalidAppVersion
+	}
+
+	cfg, err := s.GetAppConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.UpgradeRequired(v) {
+		return nil, nil
+	}
+
+	logging.L(ctx).Debug("app upgrade required", "op", op, "app_version", version, "min_version", cfg.MinVersion)
+
+	return cfg, nil
+}
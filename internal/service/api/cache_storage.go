@@ -13,7 +13,13 @@ type CacheStorageApi interface {
 	SetAccount(ctx context.Context, account *api.Account) error
 	GetVideo(ctx context.Context, videoID int64) (*api.Video, error)
 	SetVideo(ctx context.Context, videoID int64, video *api.Video) error
+	GetRelatedVideos(ctx context.Context, videoID, typeID int64) ([]api.Video, error)
+	SetRelatedVideos(ctx context.Context, videoID, typeID int64, videos []api.Video) error
 	GetVideosByCategoryAndType(ctx context.Context, typeID, catID int64) ([]api.Video, error)
 	SetVideosByCategoryAndType(ctx context.Context, typeID, catID int64, videos []api.Video) error
+	GetAnnouncements(ctx context.Context, typeID int64) ([]api.Announcement, error)
+	SetAnnouncements(ctx context.Context, typeID int64, announcements []api.Announcement) error
+	GetAppConfig(ctx context.Context) (*api.AppConfig, error)
+	SetAppConfig(ctx context.Context, cfg *api.AppConfig) error
 	HealthCheck(ctx context.Context) error
 }
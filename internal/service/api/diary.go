@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// Ограничения записи дневника боли
+const (
+	maxDiaryBodyAreaLen = 100
+	maxDiaryNotesLen    = 2000
+	maxDiaryVideos      = 20
+)
+
+// AddDiaryEntry сохраняет запись дневника боли пациента за дату YYYY-MM-DD.
+// Дата из будущего допускается только на сутки вперед из-за разницы часовых поясов.
+func (s *ServiceApi) AddDiaryEntry(ctx context.Context, entry *api.DiaryEntry, date string) error {
+	const op = "service.AddDiaryEntry"
+
+	entryDate, err := time.Parse(api.DiaryDateLayout, date)
+	if err != nil || entryDate.After(time.Now().Add(24*time.Hour)) {
+		logging.L(ctx).Warn("invalid diary date", "op", op, "date", date)
+		return api.ErrInvalidDiaryDate
+	}
+	entry.Date = entryDate
+
+	if entry.PainScore < 0 || entry.PainScore > 10 {
+		logging.L(ctx).Warn("invalid pain score", "op", op, "pain_score", entry.PainScore)
+		return api.ErrInvalidPainScore
+	}
+
+	entry.BodyArea = strings.TrimSpace(entry.BodyArea)
+	if utf8.RuneCountInString(entry.BodyArea) > maxDiaryBodyAreaLen {
+		logging.L(ctx).Warn("body area is too long", "op", op)
+		return api.ErrDiaryBodyAreaTooLong
+	}
+
+	entry.Notes = strings.TrimSpace(entry.Notes)
+	if utf8.RuneCountInString(entry.Notes) > maxDiaryNotesLen {
+		logging.L(ctx).Warn("diary notes are too long", "op", op)
+		return api.ErrDiaryNotesTooLong
+	}
+
+	videoIDs := make([]int64, 0, len(entry.VideoIDs))
+	seen := make(map[int64]bool, len(entry.VideoIDs))
+	for _, id := range entry.VideoIDs {
+		if id <= 0 {
+			logging.L(ctx).Warn("invalid diary video ID", "op", op, "video_id", id)
+			return api.ErrDiaryInvalidVideo
+		}
+		if !seen[id] {
+			seen[id] = true
+			videoIDs = append(videoIDs, id)
+		}
+	}
+	if len(videoIDs) > maxDiaryVideos {
+		logging.L(ctx).Warn("too many diary videos", "op", op, "count", len(videoIDs))
+		return api.ErrDiaryInvalidVideo
+	}
+	entry.VideoIDs = videoIDs
+
+	err = s.db.AddDiaryEntry(ctx, entry)
+	if err != nil {
+		if errors.Is(err, storage.ErrVideoNotFound) {
+			logging.L(ctx).Warn("diary video not found", "op", op, "video_ids", entry.VideoIDs)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to save diary entry", "op", op, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// GetDiaryEntries возвращает записи дневника пациента за период, новые сначала. Пустые границы периода не ограничивают выборку.
+func (s *ServiceApi) GetDiaryEntries(ctx context.Context, username, from, to string) ([]api.DiaryEntry, error) {
+	const op = "service.GetDiaryEntries"
+
+	var fromDate, toDate time.Time
+	var err error
+
+	if from != "" {
+		if fromDate, err = time.Parse(api.DiaryDateLayout, from); err != nil {
+			logging.L(ctx).Warn("invalid diary from date", "op", op, "from", from)
+			return nil, api.ErrInvalidDiaryDate
+		}
+	}
+
+	if to != "" {
+		if toDate, err = time.Parse(api.DiaryDateLayout, to); err != nil {
+			logging.L(ctx).Warn("invalid diary to date", "op", op, "to", to)
+			return nil, api.ErrInvalidDiaryDate
+		}
+	}
+
+	if !fromDate.IsZero() && !toDate.IsZero() && fromDate.After(toDate) {
+		logging.L(ctx).Warn("invalid diary range", "op", op, "from", from, "to", to)
+		return nil, api.ErrInvalidDiaryRange
+	}
+
+	entries, err := s.db.GetDiaryEntries(ctx, username, fromDate, toDate)
+	if err != nil {
+		logging.L(ctx).Error("failed to get diary entries", "op", op, sl.Err(err))
+		return nil, api.ErrStorageServerError
+	}
+
+	return entries, nil
+}
+
+// DeleteDiaryEntry удаляет запись дневника. Пациент может удалить только свою запись.
+func (s *ServiceApi) DeleteDiaryEntry(ctx context.Context, username, idStr string) error {
+	const op = "service.DeleteDiaryEntry"
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logging.L(ctx).Warn("invalid diary entry ID", "op", op, "id", idStr)
+		return api.ErrInvalidDiaryEntryID
+	}
+
+	err = s.db.DeleteDiaryEntry(ctx, username, id)
+	if err != nil {
+		if errors.Is(err, api.ErrDiaryEntryNotFound) {
+			logging.L(ctx).Warn("diary entry not found", "op", op, "id", id)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to delete diary entry", "op", op, "id", id, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"context"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+)
+
+// FeedbackNotifier уведомляет сотрудников о новой обратной связи.
+// Вызов только ставит уведомление в очередь и не ждет отправки.
+type FeedbackNotifier interface {
+	NotifyFeedback(ctx context.Context, feedback *api.Feedback)
+}
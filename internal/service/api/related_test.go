@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// relatedStorage отдает кандидатов в порядке ID, как GetRelatedCandidates
+type relatedStorage struct {
+	SqlStorageApi
+	candidates []api.RelatedCandidate
+	err        error
+}
+
+func (s *relatedStorage) GetRelatedCandidates(context.Context, int64, int64) ([]api.RelatedCandidate, error) {
+	return append([]api.RelatedCandidate(nil), s.candidates...), s.err
+}
+
+func relatedIDs(videos []api.Video) []int64 {
+	ids := make([]int64, 0, len(videos))
+	for _, v := range videos {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func TestGetRelatedVideos_Order(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	candidate := func(id int64, nextUp, categories, tags int, created time.Time) api.RelatedCandidate {
+		return api.RelatedCandidate{
+			Video:            api.Video{ID: id},
+			NextUpPosition:   nextUp,
+			SharedCategories: categories,
+			SharedTags:       tags,
+			CreatedAt:        created,
+		}
+	}
+
+	db := &relatedStorage{candidates: []api.RelatedCandidate{
+		candidate(1, -1, 1, 0, day),                  // Сходство 2
+		candidate(2, -1, 0, 3, day),                  // Сходство 3: три тега важнее одной категории
+		candidate(3, 1, 0, 0, day),                   // Вторая ссылка "смотреть далее"
+		candidate(4, -1, 0, 2, day.AddDate(0, 0, 1)), // Сходство 2, но новее видео 1
+		candidate(5, 0, 0, 0, day),                   // Первая ссылка "смотреть далее"
+		candidate(6, -1, 2, 1, day),                  // Сходство 5
+		candidate(7, -1, 1, 0, day),                  // Как видео 1, порядок по ID
+	}}
+	s := &ServiceApi{cfg: &config.Config{}, db: db}
+
+	videos, err := s.GetRelatedVideos(context.Background(), "10", "1")
+	require.NoError(t, err)
+
+	// Ссылки администратора идут первыми в его порядке, даже без общих категорий и тегов
+	assert.Equal(t, []int64{5, 3, 6, 2, 4, 1, 7}, relatedIDs(videos))
+}
+
+func TestGetRelatedVideos_Limit(t *testing.T) {
+	db := &relatedStorage{}
+	for id := int64(1); id <= relatedVideosLimit+5; id++ {
+		db.candidates = append(db.candidates, api.RelatedCandidate{
+			Video:            api.Video{ID: id},
+			NextUpPosition:   -1,
+			SharedCategories: int(id),
+		})
+	}
+	s := &ServiceApi{cfg: &config.Config{}, db: db}
+
+	// В ответ попадают самые похожие видео, а не первые по ID
+	videos, err := s.GetRelatedVideos(context.Background(), "100", "")
+	require.NoError(t, err)
+	require.Len(t, videos, relatedVideosLimit)
+	assert.Equal(t, int64(relatedVideosLimit+5), videos[0].ID)
+	assert.Equal(t, int64(6), videos[relatedVideosLimit-1].ID)
+}
+
+func TestGetRelatedVideos_Errors(t *testing.T) {
+	s := &ServiceApi{cfg: &config.Config{}, db: &relatedStorage{err: storage.ErrVideoNotFound}}
+	ctx := context.Background()
+
+	_, err := s.GetRelatedVideos(ctx, "10", "1")
+	assert.ErrorIs(t, err, storage.ErrVideoNotFound)
+
+	_, err = s.GetRelatedVideos(ctx, "", "1")
+	assert.ErrorIs(t, err, api.ErrEmptyVideoID)
+
+	_, err = s.GetRelatedVideos(ctx, "abc", "1")
+	assert.ErrorIs(t, err, api.ErrInvalidVideoID)
+
+	_, err = s.GetRelatedVideos(ctx, "10", "abc")
+	assert.ErrorIs(t, err, api.ErrTypeInvalid)
+}
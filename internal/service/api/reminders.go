@@ -0,0 +1,324 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/langowen/bodybalance-backend/pkg/lib/weekly"
+	"github.com/theartofdevel/logging"
+)
+
+// Ограничения напоминаний и устройств
+const (
+	maxRemindersPerAccount = 10
+	maxReminderMessageLen  = 200
+	maxDeviceTokenLen      = 4096
+
+	reminderTitle          = "BodyBalance"
+	defaultReminderMessage = "Пора выполнить упражнения"
+)
+
+// PushSender отправляет push-уведомление на устройство.
+// Если устройство больше не принимает уведомления, возвращает ошибку, оборачивающую api.ErrPushTokenInvalid.
+type PushSender interface {
+	Send(ctx context.Context, msg *api.PushMessage) error
+}
+
+// GetReminders возвращает напоминания пациента
+func (s *ServiceApi) GetReminders(ctx context.Context, username string) ([]api.Reminder, error) {
+	const op = "service.GetReminders"
+
+	reminders, err := s.db.GetReminders(ctx, username)
+	if err != nil {
+		logging.L(ctx).Error("failed to get reminders", "op", op, sl.Err(err))
+		return nil, api.ErrStorageServerError
+	}
+
+	return reminders, nil
+}
+
+// AddReminder проверяет расписание, рассчитывает ближайшее срабатывание и сохраняет напоминание
+func (s *ServiceApi) AddReminder(ctx context.Context, reminder *api.Reminder) error {
+	const op = "service.AddReminder"
+
+	if err := prepareReminder(reminder, time.Now()); err != nil {
+		logging.L(ctx).Warn("invalid reminder", "op", op, sl.Err(err))
+		return err
+	}
+
+	err := s.db.AddReminder(ctx, reminder, maxRemindersPerAccount)
+	if err != nil {
+		if errors.Is(err, api.ErrTooManyReminders) {
+			logging.L(ctx).Warn("reminder limit reached", "op", op, "username", reminder.Username)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to save reminder", "op", op, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// UpdateReminder заменяет расписание напоминания. Пациент может изменить только свое напоминание.
+func (s *ServiceApi) UpdateReminder(ctx context.Context, idStr string, reminder *api.Reminder) error {
+	const op = "service.UpdateReminder"
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logging.L(ctx).Warn("invalid reminder ID", "op", op, "id", idStr)
+		return api.ErrInvalidReminderID
+	}
+	reminder.ID = id
+
+	if err = prepareReminder(reminder, time.Now()); err != nil {
+		logging.L(ctx).Warn("invalid reminder", "op", op, sl.Err(err))
+		return err
+	}
+
+	err = s.db.UpdateReminder(ctx, reminder)
+	if err != nil {
+		if errors.Is(err, api.ErrReminderNotFound) {
+			logging.L(ctx).Warn("reminder not found", "op", op, "id", id)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to update reminder", "op", op, "id", id, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// DeleteReminder удаляет напоминание пациента
+func (s *ServiceApi) DeleteReminder(ctx context.Context, username, idStr string) error {
+	const op = "service.DeleteReminder"
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logging.L(ctx).Warn("invalid reminder ID", "op", op, "id", idStr)
+		return api.ErrInvalidReminderID
+	}
+
+	err = s.db.DeleteReminder(ctx, username, id)
+	if err != nil {
+		if errors.Is(err, api.ErrReminderNotFound) {
+			logging.L(ctx).Warn("reminder not found", "op", op, "id", id)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to delete reminder", "op", op, "id", id, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// RegisterDevice сохраняет токен устройства пациента для push-уведомлений
+func (s *ServiceApi) RegisterDevice(ctx context.Context, device *api.Device) error {
+	const op = "service.RegisterDevice"
+
+	device.Token = strings.TrimSpace(device.Token)
+	if device.Token == "" || len(device.Token) > maxDeviceTokenLen {
+		logging.L(ctx).Warn("invalid device token", "op", op)
+		return api.ErrEmptyDeviceToken
+	}
+
+	device.Platform = strings.ToLower(strings.TrimSpace(device.Platform))
+	switch device.Platform {
+	case "", api.PlatformAndroid, api.PlatformIOS:
+	default:
+		logging.L(ctx).Warn("invalid device platform", "op", op, "platform", device.Platform)
+		return api.ErrInvalidDevicePlatform
+	}
+
+	if err := s.db.SaveDevice(ctx, device); err != nil {
+		logging.L(ctx).Error("failed to save device", "op", op, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// UnregisterDevice удаляет токен устройства пациента, например при выходе из приложения
+func (s *ServiceApi) UnregisterDevice(ctx context.Context, username, token string) error {
+	const op = "service.UnregisterDevice"
+
+	if token == "" {
+		logging.L(ctx).Warn("empty device token", "op", op)
+		return api.ErrEmptyDeviceToken
+	}
+
+	err := s.db.DeleteDevice(ctx, username, token)
+	if err != nil {
+		if errors.Is(err, api.ErrDeviceNotFound) {
+			logging.L(ctx).Warn("device not found", "op", op)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to delete device", "op", op, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// RunReminderScheduler отправляет напоминания, время которых наступило, до отмены ctx
+func (s *ServiceApi) RunReminderScheduler(ctx context.Context) {
+	if s.push == nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.Push.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatchReminders(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchReminders отправляет напоминания, время которых наступило, пока очередь не опустеет
+func (s *ServiceApi) dispatchReminders(ctx context.Context) {
+	const op = "service.dispatchReminders"
+
+	// Напоминания пачки скрыты от других экземпляров сервиса, пока до них не дойдет очередь.
+	// Перед отправкой аренда продлевается с учетом числа устройств, см. sendReminder.
+	lease := s.cfg.Push.Timeout*time.Duration(s.cfg.Push.BatchSize) + time.Minute
+
+	for ctx.Err() == nil {
+		reminders, err := s.db.ClaimDueReminders(ctx, s.cfg.Push.BatchSize, lease)
+		if err != nil {
+			logging.L(ctx).Error("failed to claim due reminders", "op", op, sl.Err(err))
+			return
+		}
+
+		for i := range reminders {
+			s.sendReminder(ctx, &reminders[i])
+		}
+
+		if len(reminders) < s.cfg.Push.BatchSize {
+			return
+		}
+	}
+}
+
+// sendReminder отправляет напоминание на все устройства пациента и назначает следующее срабатывание.
+// Напоминание, опоздавшее больше чем на MaxDelay, например после простоя сервиса, не отправляется.
+func (s *ServiceApi) sendReminder(ctx context.Context, reminder *api.DueReminder) {
+	const op = "service.sendReminder"
+
+	// Устройства получают уведомление по очереди, поэтому аренда должна покрывать тайм-аут каждого из них.
+	// Если напоминание изменили или забрал другой экземпляр, оно не отправляется.
+	lease := s.cfg.Push.Timeout*time.Duration(len(reminder.Tokens)) + time.Minute
+	leasedUntil, err := s.db.ExtendReminderLease(ctx, reminder.ID, reminder.LeaseUntil, lease)
+	if err != nil {
+		if errors.Is(err, api.ErrReminderLeaseLost) {
+			logging.L(ctx).Info("reminder changed while waiting to be sent", "op", op, "reminder_id", reminder.ID)
+			return
+		}
+		logging.L(ctx).Error("failed to extend reminder lease", "op", op, "reminder_id", reminder.ID, sl.Err(err))
+		return
+	}
+	reminder.LeaseUntil = leasedUntil
+
+	now := time.Now()
+	var sentAt *time.Time
+
+	if now.Sub(reminder.DueAt) <= s.cfg.Push.MaxDelay {
+		body := reminder.Message
+		if body == "" {
+			body = defaultReminderMessage
+		}
+
+		for _, token := range reminder.Tokens {
+			err := s.push.Send(ctx, &api.PushMessage{
+				Token: token,
+				Title: reminderTitle,
+				Body:  body,
+				Data: map[string]string{
+					"type":        "reminder",
+					"reminder_id": strconv.FormatInt(reminder.ID, 10),
+				},
+			})
+			if err == nil {
+				continue
+			}
+
+			if errors.Is(err, api.ErrPushTokenInvalid) {
+				logging.L(ctx).Info("removing invalid device token", "op", op, "reminder_id", reminder.ID)
+				if err = s.db.DeleteDeviceToken(ctx, token); err != nil {
+					logging.L(ctx).Error("failed to delete device token", "op", op, sl.Err(err))
+				}
+				continue
+			}
+
+			logging.L(ctx).Error("failed to send reminder", "op", op, "reminder_id", reminder.ID, sl.Err(err))
+		}
+
+		sentAt = &now
+	} else {
+		logging.L(ctx).Warn("reminder skipped as overdue", "op", op, "reminder_id", reminder.ID, "due_at", reminder.DueAt)
+	}
+
+	var nextRunAt *time.Time
+	sched, err := weekly.New(reminder.Days, reminder.Time, reminder.Timezone)
+	if err != nil {
+		// Расписание стало недействительным, например часовой пояс пропал из базы tzdata
+		logging.L(ctx).Error("invalid stored reminder schedule", "op", op, "reminder_id", reminder.ID, sl.Err(err))
+	} else {
+		after := now
+		if reminder.DueAt.After(after) {
+			after = reminder.DueAt
+		}
+		next := sched.Next(after)
+		nextRunAt = &next
+	}
+
+	if err = s.db.RescheduleReminder(ctx, reminder.ID, reminder.LeaseUntil, nextRunAt, sentAt); err != nil {
+		logging.L(ctx).Error("failed to reschedule reminder", "op", op, "reminder_id", reminder.ID, sl.Err(err))
+	}
+}
+
+// prepareReminder проверяет напоминание, приводит расписание к каноничному виду и рассчитывает ближайшее срабатывание
+func prepareReminder(reminder *api.Reminder, now time.Time) error {
+	sched, err := weekly.New(reminder.Days, reminder.Time, reminder.Timezone)
+	switch {
+	case errors.Is(err, weekly.ErrInvalidDays):
+		return api.ErrInvalidReminderDays
+	case errors.Is(err, weekly.ErrInvalidClock):
+		return api.ErrInvalidReminderTime
+	case errors.Is(err, weekly.ErrInvalidTimezone):
+		return api.ErrInvalidReminderTimezone
+	case err != nil:
+		return err
+	}
+
+	reminder.Message = strings.TrimSpace(reminder.Message)
+	if utf8.RuneCountInString(reminder.Message) > maxReminderMessageLen {
+		return api.ErrReminderMessageTooLong
+	}
+
+	reminder.Days = sched.Days
+	reminder.Time = sched.Clock()
+	reminder.Timezone = sched.Location.String()
+
+	reminder.NextRunAt = nil
+	if reminder.Enabled {
+		next := sched.Next(now)
+		reminder.NextRunAt = &next
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reminderStorage одно напоминание в памяти: аренда и расписание меняются только при совпадении next_run_at,
+// как в UPDATE ... WHERE next_run_at = $2
+type reminderStorage struct {
+	SqlStorageApi
+	nextRunAt   *time.Time
+	lease       time.Duration
+	rescheduled bool
+}
+
+func (s *reminderStorage) ExtendReminderLease(_ context.Context, _ int64, leasedUntil time.Time, lease time.Duration) (time.Time, error) {
+	if s.nextRunAt == nil || !s.nextRunAt.Equal(leasedUntil) {
+		return time.Time{}, api.ErrReminderLeaseLost
+	}
+	until := time.Now().Add(lease)
+	s.nextRunAt = &until
+	s.lease = lease
+	return until, nil
+}
+
+func (s *reminderStorage) RescheduleReminder(_ context.Context, _ int64, leasedUntil time.Time, nextRunAt, _ *time.Time) error {
+	if s.nextRunAt != nil && s.nextRunAt.Equal(leasedUntil) {
+		s.nextRunAt = nextRunAt
+		s.rescheduled = true
+	}
+	return nil
+}
+
+// pushRecorder запоминает токены, на которые ушли уведомления
+type pushRecorder struct {
+	tokens []string
+	onSend func()
+}
+
+func (p *pushRecorder) Send(_ context.Context, msg *api.PushMessage) error {
+	p.tokens = append(p.tokens, msg.Token)
+	if p.onSend != nil {
+		p.onSend()
+	}
+	return nil
+}
+
+func newDueReminder(leaseUntil time.Time) *api.DueReminder {
+	return &api.DueReminder{
+		Reminder:   api.Reminder{ID: 1, Days: []int{1, 2, 3, 4, 5, 6, 7}, Time: "09:00", Timezone: "UTC", Enabled: true},
+		DueAt:      time.Now().Add(-time.Minute),
+		LeaseUntil: leaseUntil,
+		Tokens:     []string{"a", "b", "c"},
+	}
+}
+
+func TestSendReminder(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Push.Timeout = 10 * time.Second
+	cfg.Push.MaxDelay = time.Hour
+
+	claimed := time.Now().Add(time.Minute).Truncate(time.Microsecond)
+	db := &reminderStorage{nextRunAt: &claimed}
+	push := &pushRecorder{}
+	s := &ServiceApi{cfg: cfg, db: db, push: push}
+
+	// Аренда покрывает тайм-аут каждого устройства, после отправки назначается следующее срабатывание
+	s.sendReminder(context.Background(), newDueReminder(claimed))
+	assert.Equal(t, []string{"a", "b", "c"}, push.tokens)
+	assert.Equal(t, 3*cfg.Push.Timeout+time.Minute, db.lease)
+	require.True(t, db.rescheduled)
+	require.NotNil(t, db.nextRunAt)
+	assert.Equal(t, 9, db.nextRunAt.Hour())
+}
+
+func TestSendReminder_ChangedByPatient(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Push.Timeout = 10 * time.Second
+	cfg.Push.MaxDelay = time.Hour
+	claimed := time.Now().Add(time.Minute).Truncate(time.Microsecond)
+
+	// Напоминание выключили, пока оно ждало отправки: уведомление не уходит
+	db := &reminderStorage{}
+	push := &pushRecorder{}
+	s := &ServiceApi{cfg: cfg, db: db, push: push}
+
+	s.sendReminder(context.Background(), newDueReminder(claimed))
+	assert.Empty(t, push.tokens)
+	assert.False(t, db.rescheduled)
+	assert.Nil(t, db.nextRunAt)
+
+	// Расписание изменили во время отправки: новое расписание пациента не перезаписывается
+	edited := time.Now().Add(48 * time.Hour)
+	db = &reminderStorage{nextRunAt: &claimed}
+	push = &pushRecorder{onSend: func() { db.nextRunAt = &edited }}
+	s = &ServiceApi{cfg: cfg, db: db, push: push}
+
+	s.sendReminder(context.Background(), newDueReminder(claimed))
+	assert.False(t, db.rescheduled)
+	assert.Equal(t, &edited, db.nextRunAt)
+}
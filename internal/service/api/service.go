@@ -1,9 +1,11 @@
 package api
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,16 +19,20 @@ import (
 )
 
 type ServiceApi struct {
-	cfg *config.Config
-	db  SqlStorageApi
-	rdb CacheStorageApi
+	cfg      *config.Config
+	db       SqlStorageApi
+	rdb      CacheStorageApi
+	notifier FeedbackNotifier
+	push     PushSender
 }
 
-func NewServiceApi(cfg *config.Config, db SqlStorageApi, rdb CacheStorageApi) *ServiceApi {
+func NewServiceApi(cfg *config.Config, db SqlStorageApi, rdb CacheStorageApi, notifier FeedbackNotifier, push PushSender) *ServiceApi {
 	return &ServiceApi{
-		cfg: cfg,
-		db:  db,
-		rdb: rdb,
+		cfg:      cfg,
+		db:       db,
+		rdb:      rdb,
+		notifier: notifier,
+		push:     push,
 	}
 }
 
@@ -80,7 +86,38 @@ func (s *ServiceApi) GetTypeByAccount(ctx context.Context, username string) (*ap
 	return res, nil
 }
 
-func (s *ServiceApi) GetCategoriesByType(ctx context.Context, contentType string) ([]api.Category, error) {
+// LoginAccount проверяет пароль пациента. Аккаунт читается из БД в обход кэша,
+// чтобы смена пароля администратором действовала сразу.
+func (s *ServiceApi) LoginAccount(ctx context.Context, username, password string) (*api.Account, error) {
+	const op = "service.LoginAccount"
+
+	if username == "" {
+		logging.L(ctx).Error("Username is empty", "op", op)
+		return nil, api.ErrEmptyUsername
+	}
+
+	if password == "" {
+		logging.L(ctx).Error("Password is empty", "op", op)
+		return nil, api.ErrEmptyPassword
+	}
+
+	res, err := s.db.CheckAccountPassword(ctx, &api.Account{Username: username}, password)
+	if err != nil {
+		if errors.Is(err, storage.ErrAccountNotFound) {
+			logging.L(ctx).Warn("invalid username or password", "op", op)
+			return nil, err
+		}
+
+		logging.L(ctx).Error("storage get error", sl.Err(err), "op", op)
+		return nil, api.ErrStorageServerError
+	}
+
+	return res, nil
+}
+
+// GetCategoriesByType возвращает категории типа контента. Пустой parent возвращает все категории,
+// "0" — только корневые, ID категории — только ее прямые подкатегории.
+func (s *ServiceApi) GetCategoriesByType(ctx context.Context, contentType, parent string) ([]api.Category, error) {
 	const op = "service.getCategoriesByType"
 
 	if contentType == "" {
@@ -94,11 +131,20 @@ func (s *ServiceApi) GetCategoriesByType(ctx context.Context, contentType string
 		return nil, api.ErrTypeInvalid
 	}
 
+	parentID := allCategories
+	if parent != "" {
+		parentID, err = strconv.ParseInt(parent, 10, 64)
+		if err != nil || parentID < 0 {
+			logging.L(ctx).Warn("invalid parent category ID", "op", op, "parent", parent)
+			return nil, api.ErrParentInvalid
+		}
+	}
+
 	if s.cfg.Redis.Enable {
 		categories, err := s.rdb.GetCategories(ctx, typeID)
 		if err == nil && categories != nil {
 			logging.L(ctx).Debug("categories fetched from redis cache", "op", op)
-			return categories, nil
+			return filterCategories(categories, parentID), nil
 		}
 
 		if err != nil {
@@ -132,7 +178,31 @@ func (s *ServiceApi) GetCategoriesByType(ctx context.Context, contentType string
 		}()
 	}
 
-	return categories, nil
+	return filterCategories(categories, parentID), nil
+}
+
+// allCategories значение parentID, при котором категории не фильтруются по родителю
+const allCategories int64 = -1
+
+// filterCategories оставляет категории с указанным родителем. Кэш хранит полный список типа,
+// поэтому фильтрация выполняется после чтения, а источник данных переносится на первый элемент результата.
+func filterCategories(categories []api.Category, parentID int64) []api.Category {
+	if parentID == allCategories || len(categories) == 0 {
+		return categories
+	}
+
+	res := make([]api.Category, 0)
+	for _, category := range categories {
+		if category.ParentID == parentID {
+			res = append(res, category)
+		}
+	}
+
+	if len(res) > 0 {
+		res[0].DataSource = categories[0].DataSource
+	}
+
+	return res
 }
 
 func (s *ServiceApi) GetVideo(ctx context.Context, videoStr string) (*api.Video, error) {
@@ -190,6 +260,112 @@ func (s *ServiceApi) GetVideo(ctx context.Context, videoStr string) (*api.Video,
 	return video, nil
 }
 
+// relatedVideosLimit максимальное количество похожих видео в ответе
+const relatedVideosLimit = 10
+
+func (s *ServiceApi) GetRelatedVideos(ctx context.Context, videoStr, contentType string) ([]api.Video, error) {
+	const op = "service.GetRelatedVideos"
+
+	if videoStr == "" {
+		logging.L(ctx).Error("Video id is empty", "op", op)
+		return nil, api.ErrEmptyVideoID
+	}
+
+	videoID, err := strconv.ParseInt(videoStr, 10, 64)
+	if err != nil {
+		logging.L(ctx).Error("Invalid video ID", "op", op, sl.Err(err))
+		return nil, api.ErrInvalidVideoID
+	}
+
+	var typeID int64
+	if contentType != "" {
+		typeID, err = strconv.ParseInt(contentType, 10, 64)
+		if err != nil {
+			logging.L(ctx).Error("Invalid type ID", "op", op, sl.Err(err))
+			return nil, api.ErrTypeInvalid
+		}
+	}
+
+	if s.cfg.Redis.Enable {
+		videos, err := s.rdb.GetRelatedVideos(ctx, videoID, typeID)
+		if err == nil && videos != nil {
+			logging.L(ctx).Debug("related videos fetched from redis cache", "op", op)
+			return videos, nil
+		}
+
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				logging.L(ctx).Debug("related videos not found in redis cache", sl.Err(err), "op", op)
+			} else {
+				logging.L(ctx).Error("failed to get related videos from redis", sl.Err(err), "op", op)
+			}
+		}
+	}
+
+	candidates, err := s.db.GetRelatedCandidates(ctx, videoID, typeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrVideoNotFound):
+			logging.L(ctx).Debug("video not found in DB", sl.Err(err), "op", op)
+			return nil, storage.ErrVideoNotFound
+		case errors.Is(err, storage.ErrContentTypeNotFound):
+			logging.L(ctx).Warn("content type not found", sl.Err(err), "op", op)
+			return nil, err
+		default:
+			logging.L(ctx).Error("failed to get related videos", sl.Err(err), "op", op)
+			return nil, api.ErrStorageServerError
+		}
+	}
+
+	videos := rankRelatedVideos(candidates, relatedVideosLimit)
+
+	if s.cfg.Redis.Enable {
+		go func() {
+			ctxRedis, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err = s.rdb.SetRelatedVideos(ctxRedis, videoID, typeID, videos); err != nil {
+				logging.L(ctx).Warn("failed to cache related videos in redis", sl.Err(err), "op", op)
+			}
+		}()
+	}
+
+	return videos, nil
+}
+
+// rankRelatedVideos упорядочивает кандидатов и возвращает не больше limit видео. Сначала идут ссылки
+// "смотреть далее" в порядке, заданном администратором, затем видео по сходству: общая категория весит
+// вдвое больше общего тега. При равном сходстве новые видео идут раньше.
+func rankRelatedVideos(candidates []api.RelatedCandidate, limit int) []api.Video {
+	slices.SortStableFunc(candidates, func(a, b api.RelatedCandidate) int {
+		aNext, bNext := a.NextUpPosition >= 0, b.NextUpPosition >= 0
+		switch {
+		case aNext != bNext:
+			if aNext {
+				return -1
+			}
+			return 1
+		case aNext:
+			return cmp.Compare(a.NextUpPosition, b.NextUpPosition)
+		}
+
+		if c := cmp.Compare(b.SharedCategories*2+b.SharedTags, a.SharedCategories*2+a.SharedTags); c != 0 {
+			return c
+		}
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Video.ID, b.Video.ID)
+	})
+
+	videos := make([]api.Video, 0, min(len(candidates), limit))
+	for _, c := range candidates[:min(len(candidates), limit)] {
+		videos = append(videos, c.Video)
+	}
+
+	return videos
+}
+
 func (s *ServiceApi) GetVideosByCategoryAndType(ctx context.Context, contentType, category string) ([]api.Video, error) {
 	const op = "service.GetVideosByCategoryAndType"
 
@@ -296,6 +472,8 @@ func (s *ServiceApi) Feedback(ctx context.Context, feedback *api.Feedback) error
 		return api.ErrStorageServerError
 	}
 
+	s.notifier.NotifyFeedback(ctx, feedback)
+
 	return nil
 }
 
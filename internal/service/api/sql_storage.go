@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/langowen/bodybalance-backend/internal/entities/api"
 )
@@ -11,7 +12,28 @@ type SqlStorageApi interface {
 	GetVideosByCategoryAndType(ctx context.Context, TypeID, CatID int64) ([]api.Video, error)
 	GetCategories(ctx context.Context, TypeID int64) ([]api.Category, error)
 	CheckAccount(ctx context.Context, account *api.Account) (*api.Account, error)
+	CheckAccountPassword(ctx context.Context, account *api.Account, passwordHash string) (*api.Account, error)
 	GetVideo(ctx context.Context, videoID int64) (*api.Video, error)
+	GetRelatedCandidates(ctx context.Context, videoID, typeID int64) ([]api.RelatedCandidate, error)
+	GetSyncChanges(ctx context.Context, typeID int64, since time.Time) (*api.SyncChanges, error)
 	Feedback(ctx context.Context, feedback *api.Feedback) error
+	RateVideo(ctx context.Context, rating *api.VideoRating) error
+	AddVideoComment(ctx context.Context, comment *api.VideoComment) error
+	GetVideoComments(ctx context.Context, videoID int64, username string) ([]api.VideoComment, error)
+	AddDiaryEntry(ctx context.Context, entry *api.DiaryEntry) error
+	GetDiaryEntries(ctx context.Context, username string, from, to time.Time) ([]api.DiaryEntry, error)
+	DeleteDiaryEntry(ctx context.Context, username string, id int64) error
+	GetReminders(ctx context.Context, username string) ([]api.Reminder, error)
+	AddReminder(ctx context.Context, reminder *api.Reminder, maxPerAccount int) error
+	UpdateReminder(ctx context.Context, reminder *api.Reminder) error
+	DeleteReminder(ctx context.Context, username string, id int64) error
+	ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]api.DueReminder, error)
+	ExtendReminderLease(ctx context.Context, id int64, leasedUntil time.Time, lease time.Duration) (time.Time, error)
+	RescheduleReminder(ctx context.Context, id int64, leasedUntil time.Time, nextRunAt, sentAt *time.Time) error
+	SaveDevice(ctx context.Context, device *api.Device) error
+	DeleteDevice(ctx context.Context, username, token string) error
+	DeleteDeviceToken(ctx context.Context, token string) error
+	GetAnnouncements(ctx context.Context, typeID int64) ([]api.Announcement, error)
+	GetAppConfig(ctx context.Context) (*api.AppConfig, error)
 	HealthCheck(ctx context.Context) error
 }
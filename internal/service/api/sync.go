@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// syncCursorOverlap на сколько курсор отстает от момента выгрузки. Транзакции, начатые раньше
+// и зафиксированные позже выгрузки, попадут в следующий ответ, а повторные записи клиент просто перезапишет.
+const syncCursorOverlap = 30 * time.Second
+
+// Sync возвращает изменения каталога для типа контента с момента курсора и новый курсор.
+// Пустой курсор или курсор другого типа контента приводят к полной выгрузке.
+func (s *ServiceApi) Sync(ctx context.Context, contentType, cursor string) (*api.SyncChanges, error) {
+	const op = "service.Sync"
+
+	if contentType == "" {
+		logging.L(ctx).Error("Content type is empty", "op", op)
+		return nil, api.ErrEmptyTypeID
+	}
+
+	typeID, err := strconv.ParseInt(contentType, 10, 64)
+	if err != nil {
+		logging.L(ctx).Error("Invalid type ID", "op", op, sl.Err(err))
+		return nil, api.ErrTypeInvalid
+	}
+
+	var since time.Time
+	if cursor != "" {
+		cursorTypeID, cursorTime, err := decodeSyncCursor(cursor)
+		if err != nil {
+			logging.L(ctx).Warn("invalid sync cursor", "op", op, "cursor", cursor, sl.Err(err))
+			return nil, api.ErrSyncCursorInvalid
+		}
+
+		if cursorTypeID == typeID {
+			since = cursorTime
+		} else {
+			logging.L(ctx).Debug("sync cursor belongs to another content type", "op", op, "cursor_type", cursorTypeID)
+		}
+	}
+
+	changes, err := s.db.GetSyncChanges(ctx, typeID, since)
+	if err != nil {
+		if errors.Is(err, storage.ErrContentTypeNotFound) {
+			logging.L(ctx).Warn("content type not found", sl.Err(err), "op", op)
+			return nil, err
+		}
+
+		logging.L(ctx).Error("failed to get sync changes", sl.Err(err), "op", op)
+		return nil, api.ErrStorageServerError
+	}
+
+	changes.Cursor = encodeSyncCursor(typeID, changes.Until.Add(-syncCursorOverlap))
+
+	return changes, nil
+}
+
+// encodeSyncCursor кодирует тип контента и время в непрозрачную для клиента строку
+func encodeSyncCursor(typeID int64, t time.Time) string {
+	raw := fmt.Sprintf("%d:%d", typeID, t.UnixMicro())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncCursor(cursor string) (int64, time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	typePart, timePart, found := strings.Cut(string(raw), ":")
+	if !found {
+		return 0, time.Time{}, errors.New("missing separator")
+	}
+
+	typeID, err := strconv.ParseInt(typePart, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	micros, err := strconv.ParseInt(timePart, 10, 64)
+	if err != nil || micros <= 0 {
+		return 0, time.Time{}, errors.New("invalid timestamp")
+	}
+
+	return typeID, time.UnixMicro(micros), nil
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/langowen/bodybalance-backend/deploy/config"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncRow видео каталога с моментом последнего изменения
+type syncRow struct {
+	id        int64
+	updatedAt time.Time
+	deleted   bool
+}
+
+// syncStorage отбирает видео, как GetSyncChanges: измененные строго после since, удаленные — только в списке удаленных
+type syncStorage struct {
+	SqlStorageApi
+	now   time.Time
+	rows  []syncRow
+	since []time.Time
+}
+
+func (s *syncStorage) GetSyncChanges(_ context.Context, _ int64, since time.Time) (*api.SyncChanges, error) {
+	s.since = append(s.since, since)
+
+	full := since.IsZero()
+	changes := &api.SyncChanges{Full: full, Until: s.now}
+	for _, row := range s.rows {
+		if !row.updatedAt.After(since) {
+			continue
+		}
+		switch {
+		case !row.deleted:
+			changes.Videos = append(changes.Videos, api.SyncVideo{ID: row.id})
+		case !full:
+			changes.DeletedVideos = append(changes.DeletedVideos, row.id)
+		}
+	}
+
+	return changes, nil
+}
+
+func syncVideoIDs(changes *api.SyncChanges) []int64 {
+	ids := make([]int64, 0, len(changes.Videos))
+	for _, v := range changes.Videos {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func TestSync_Cursor(t *testing.T) {
+	now := time.Now().Truncate(time.Microsecond)
+	cursorTime := now.Add(-syncCursorOverlap)
+
+	db := &syncStorage{now: now, rows: []syncRow{
+		{id: 1, updatedAt: now.Add(-time.Hour)},
+		{id: 2, updatedAt: cursorTime}, // Изменено ровно в момент курсора
+		{id: 3, updatedAt: cursorTime.Add(time.Microsecond)},
+		{id: 4, updatedAt: now.Add(-time.Hour), deleted: true},
+	}}
+	s := &ServiceApi{cfg: &config.Config{}, db: db}
+	ctx := context.Background()
+
+	// Полная выгрузка без курсора не содержит удаленных
+	changes, err := s.Sync(ctx, "1", "")
+	require.NoError(t, err)
+	assert.True(t, changes.Full)
+	assert.Equal(t, []int64{1, 2, 3}, syncVideoIDs(changes))
+	assert.Empty(t, changes.DeletedVideos)
+
+	typeID, since, err := decodeSyncCursor(changes.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), typeID)
+	assert.True(t, since.Equal(cursorTime))
+
+	// Курсор передается в хранилище без потери точности: запись с updated_at, равным курсору,
+	// уже была в прошлом ответе, а запись на микросекунду позже приходит повторно из-за запаса
+	changes, err = s.Sync(ctx, "1", changes.Cursor)
+	require.NoError(t, err)
+	assert.False(t, changes.Full)
+	assert.True(t, db.since[1].Equal(cursorTime))
+	assert.Equal(t, []int64{3}, syncVideoIDs(changes))
+	assert.Empty(t, changes.DeletedVideos)
+}
+
+func TestSync_Tombstones(t *testing.T) {
+	now := time.Now().Truncate(time.Microsecond)
+	db := &syncStorage{now: now, rows: []syncRow{
+		{id: 1, updatedAt: now.Add(-time.Hour)},
+		{id: 2, updatedAt: now.Add(-time.Hour)},
+	}}
+	s := &ServiceApi{cfg: &config.Config{}, db: db}
+	ctx := context.Background()
+
+	first, err := s.Sync(ctx, "1", "")
+	require.NoError(t, err)
+
+	// Видео 1 удалено, видео 2 удалено и восстановлено, видео 3 добавлено и сразу удалено
+	db.now = now.Add(time.Minute)
+	db.rows = []syncRow{
+		{id: 1, updatedAt: now.Add(10 * time.Second), deleted: true},
+		{id: 2, updatedAt: now.Add(20 * time.Second)},
+		{id: 3, updatedAt: now.Add(30 * time.Second), deleted: true},
+	}
+
+	changes, err := s.Sync(ctx, "1", first.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, syncVideoIDs(changes))
+	assert.Equal(t, []int64{1, 3}, changes.DeletedVideos)
+
+	// Удаление, которое клиент уже получил, не повторяется после следующего курсора
+	db.now = now.Add(2 * time.Minute)
+	changes, err = s.Sync(ctx, "1", changes.Cursor)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Videos)
+	assert.Empty(t, changes.DeletedVideos)
+}
+
+func TestSync_CursorOfAnotherType(t *testing.T) {
+	now := time.Now().Truncate(time.Microsecond)
+	db := &syncStorage{now: now, rows: []syncRow{{id: 1, updatedAt: now.Add(-time.Hour)}}}
+	s := &ServiceApi{cfg: &config.Config{}, db: db}
+	ctx := context.Background()
+
+	first, err := s.Sync(ctx, "1", "")
+	require.NoError(t, err)
+
+	// После смены типа контента клиент получает полную выгрузку
+	changes, err := s.Sync(ctx, "2", first.Cursor)
+	require.NoError(t, err)
+	assert.True(t, changes.Full)
+	assert.True(t, db.since[1].IsZero())
+	assert.Equal(t, []int64{1}, syncVideoIDs(changes))
+}
+
+func TestSync_InvalidCursor(t *testing.T) {
+	s := &ServiceApi{cfg: &config.Config{}, db: &syncStorage{now: time.Now()}}
+	ctx := context.Background()
+
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	for _, cursor := range []string{"!!!", encode("1"), encode("1:0"), encode("1:-5"), encode("x:5"), encode("1:x")} {
+		_, err := s.Sync(ctx, "1", cursor)
+		assert.ErrorIs(t, err, api.ErrSyncCursorInvalid, cursor)
+	}
+
+	_, err := s.Sync(ctx, "", "")
+	assert.ErrorIs(t, err, api.ErrEmptyTypeID)
+}
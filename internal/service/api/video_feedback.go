@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/langowen/bodybalance-backend/internal/adapter/storage"
+	"github.com/langowen/bodybalance-backend/internal/entities/api"
+	"github.com/langowen/bodybalance-backend/pkg/lib/logger/sl"
+	"github.com/theartofdevel/logging"
+)
+
+// maxCommentLen максимальная длина комментария к видео в символах
+const maxCommentLen = 1000
+
+// RateVideo сохраняет оценку видео пациентом и заполняет среднюю оценку и количество оценок.
+// Оценки и комментарии не кэшируются: они у каждого пациента свои.
+func (s *ServiceApi) RateVideo(ctx context.Context, rating *api.VideoRating) error {
+	const op = "service.RateVideo"
+
+	if rating.VideoID <= 0 {
+		logging.L(ctx).Warn("invalid video ID", "op", op, "video_id", rating.VideoID)
+		return api.ErrInvalidVideoID
+	}
+
+	if rating.Score < 1 || rating.Score > 5 {
+		logging.L(ctx).Warn("invalid score", "op", op, "score", rating.Score)
+		return api.ErrInvalidScore
+	}
+
+	err := s.db.RateVideo(ctx, rating)
+	if err != nil {
+		if errors.Is(err, storage.ErrVideoNotFound) {
+			logging.L(ctx).Warn("video not available for rating", "op", op, "video_id", rating.VideoID)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to save rating", "op", op, "video_id", rating.VideoID, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// AddVideoComment сохраняет комментарий пациента на модерацию
+func (s *ServiceApi) AddVideoComment(ctx context.Context, comment *api.VideoComment) error {
+	const op = "service.AddVideoComment"
+
+	if comment.VideoID <= 0 {
+		logging.L(ctx).Warn("invalid video ID", "op", op, "video_id", comment.VideoID)
+		return api.ErrInvalidVideoID
+	}
+
+	comment.Text = strings.TrimSpace(comment.Text)
+	if comment.Text == "" {
+		logging.L(ctx).Warn("comment is empty", "op", op)
+		return api.ErrEmptyComment
+	}
+
+	if utf8.RuneCountInString(comment.Text) > maxCommentLen {
+		logging.L(ctx).Warn("comment is too long", "op", op)
+		return api.ErrCommentTooLong
+	}
+
+	err := s.db.AddVideoComment(ctx, comment)
+	if err != nil {
+		if errors.Is(err, storage.ErrVideoNotFound) {
+			logging.L(ctx).Warn("video not available for comment", "op", op, "video_id", comment.VideoID)
+			return err
+		}
+
+		logging.L(ctx).Error("failed to save comment", "op", op, "video_id", comment.VideoID, sl.Err(err))
+		return api.ErrStorageServerError
+	}
+
+	return nil
+}
+
+// GetVideoComments возвращает одобренные комментарии пациентов того же типа контента и собственные комментарии пациента
+func (s *ServiceApi) GetVideoComments(ctx context.Context, username, videoStr string) ([]api.VideoComment, error) {
+	const op = "service.GetVideoComments"
+
+	if videoStr == "" {
+		logging.L(ctx).Warn("Video id is empty", "op", op)
+		return nil, api.ErrEmptyVideoID
+	}
+
+	videoID, err := strconv.ParseInt(videoStr, 10, 64)
+	if err != nil {
+		logging.L(ctx).Warn("Invalid video ID", "op", op, sl.Err(err))
+		return nil, api.ErrInvalidVideoID
+	}
+
+	comments, err := s.db.GetVideoComments(ctx, videoID, username)
+	if err != nil {
+		logging.L(ctx).Error("failed to get comments", "op", op, "video_id", videoID, sl.Err(err))
+		return nil, api.ErrStorageServerError
+	}
+
+	return comments, nil
+}
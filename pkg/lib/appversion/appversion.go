@@ -0,0 +1,79 @@
+// Package appversion разбирает и сравнивает версии мобильного приложения вида "1.4.2".
+// Суффиксы сборки и предрелизные метки ("1.4.2-beta", "1.4.2+105", "1.4.2 (105)") при сравнении не учитываются.
+package appversion
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidVersion = errors.New("invalid app version")
+
+// maxParts ограничение количества числовых частей версии
+const maxParts = 4
+
+// Version числовые части версии, отсутствующие части считаются нулями
+type Version []int
+
+// Parse разбирает строку версии
+func Parse(s string) (Version, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "v")
+
+	if i := strings.IndexAny(s, "-+ "); i >= 0 {
+		s = s[:i]
+	}
+
+	if s == "" {
+		return nil, ErrInvalidVersion
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > maxParts {
+		return nil, ErrInvalidVersion
+	}
+
+	v := make(Version, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, ErrInvalidVersion
+		}
+		v[i] = n
+	}
+
+	return v, nil
+}
+
+// Valid проверяет, что строка является версией
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// Compare возвращает -1, если v меньше other, 0 при равенстве и 1, если v больше other
+func (v Version) Compare(other Version) int {
+	for i := 0; i < max(len(v), len(other)); i++ {
+		a, b := v.part(i), other.part(i)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Less проверяет, что v меньше other
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+func (v Version) part(i int) int {
+	if i < len(v) {
+		return v[i]
+	}
+	return 0
+}
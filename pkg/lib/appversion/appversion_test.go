@@ -0,0 +1,63 @@
+package appversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{in: "1.4.2", want: Version{1, 4, 2}},
+		{in: "v2.0", want: Version{2, 0}},
+		{in: " 3 ", want: Version{3}},
+		{in: "1.4.2-beta", want: Version{1, 4, 2}},
+		{in: "1.4.2+105", want: Version{1, 4, 2}},
+		{in: "1.4.2 (105)", want: Version{1, 4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "1..2", "1.x", "-1", "1.2.3.4.5", "1.-2"} {
+		_, err := Parse(in)
+		assert.ErrorIs(t, err, ErrInvalidVersion, in)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.4.2", b: "1.4.2", want: 0},
+		// Отсутствующие части считаются нулями
+		{a: "1.4", b: "1.4.0", want: 0},
+		{a: "1.4.2", b: "1.4.10", want: -1},
+		{a: "1.10", b: "1.9.9", want: 1},
+		{a: "2", b: "1.99", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, err := Parse(tt.a)
+			require.NoError(t, err)
+			b, err := Parse(tt.b)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, a.Compare(b))
+			assert.Equal(t, tt.want < 0, a.Less(b))
+		})
+	}
+}
@@ -0,0 +1,321 @@
+// Package faststart переносит атом moov в начало MP4/MOV файла без перекодирования,
+// чтобы плеер мог начать воспроизведение не загружая конец файла.
+package faststart
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrInvalidAtom        = errors.New("invalid mp4 atom")
+	ErrMoovNotFound       = errors.New("moov atom not found")
+	ErrMdatNotFound       = errors.New("mdat atom not found")
+	ErrCompressedMoov     = errors.New("compressed moov atom is not supported")
+	ErrMoovTooLarge       = errors.New("moov atom is too large")
+	ErrOffsetOverflow     = errors.New("chunk offset overflow")
+	ErrAlreadyOptimized   = errors.New("moov atom is already before mdat")
+	ErrVerificationFailed = errors.New("faststart verification failed")
+)
+
+// maxMoovSize ограничивает размер moov, который читается в память целиком
+const maxMoovSize = 256 << 20 // 256 MB
+
+// containerAtoms атомы внутри moov, которые содержат таблицы смещений чанков
+var containerAtoms = map[string]bool{
+	"moov": true,
+	"trak": true,
+	"mdia": true,
+	"minf": true,
+	"stbl": true,
+}
+
+type atom struct {
+	typ    string
+	offset int64 // Смещение начала атома в файле
+	size   int64 // Полный размер атома вместе с заголовком
+}
+
+// NeedsFaststart проверяет, расположен ли атом moov после mdat
+func NeedsFaststart(r io.ReadSeeker) (bool, error) {
+	atoms, _, err := readAtoms(r)
+	if err != nil {
+		return false, err
+	}
+
+	moovIdx, mdatIdx, err := findMoovMdat(atoms)
+	if err != nil {
+		return false, err
+	}
+
+	return moovIdx > mdatIdx, nil
+}
+
+// Rewrite записывает в w копию файла из r, в которой moov стоит перед первым mdat,
+// а смещения в таблицах stco/co64 скорректированы на размер перенесенного moov
+func Rewrite(r io.ReadSeeker, w io.Writer) error {
+	atoms, _, err := readAtoms(r)
+	if err != nil {
+		return err
+	}
+
+	moovIdx, mdatIdx, err := findMoovMdat(atoms)
+	if err != nil {
+		return err
+	}
+
+	if moovIdx < mdatIdx {
+		return ErrAlreadyOptimized
+	}
+
+	moov := atoms[moovIdx]
+	if moov.size > maxMoovSize {
+		return ErrMoovTooLarge
+	}
+
+	buf := make([]byte, moov.size)
+	if _, err = r.Seek(moov.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek moov: %w", err)
+	}
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return fmt.Errorf("failed to read moov: %w", err)
+	}
+
+	// Данные между первым mdat и старым положением moov сдвигаются на размер moov,
+	// все что лежит после moov остается на своих местах
+	insertPos := atoms[mdatIdx].offset
+	shift := func(offset uint64) (uint64, error) {
+		if offset >= uint64(insertPos) && offset < uint64(moov.offset) {
+			return offset + uint64(moov.size), nil
+		}
+		return offset, nil
+	}
+
+	if err = walkChunkOffsets(buf, shift); err != nil {
+		return err
+	}
+
+	for i, a := range atoms {
+		if i == mdatIdx {
+			if _, err = w.Write(buf); err != nil {
+				return fmt.Errorf("failed to write moov: %w", err)
+			}
+		}
+
+		if i == moovIdx {
+			continue
+		}
+
+		if _, err = r.Seek(a.offset, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek %s atom: %w", a.typ, err)
+		}
+		if _, err = io.CopyN(w, r, a.size); err != nil {
+			return fmt.Errorf("failed to copy %s atom: %w", a.typ, err)
+		}
+	}
+
+	return nil
+}
+
+// Verify проверяет результат Rewrite: размер файла не изменился, moov стоит перед mdat,
+// а все смещения чанков указывают внутрь файла
+func Verify(r io.ReadSeeker, expectedSize int64) error {
+	atoms, size, err := readAtoms(r)
+	if err != nil {
+		return err
+	}
+
+	if size != expectedSize {
+		return fmt.Errorf("%w: size mismatch %d != %d", ErrVerificationFailed, size, expectedSize)
+	}
+
+	moovIdx, mdatIdx, err := findMoovMdat(atoms)
+	if err != nil {
+		return err
+	}
+
+	if moovIdx > mdatIdx {
+		return fmt.Errorf("%w: moov is still after mdat", ErrVerificationFailed)
+	}
+
+	moov := atoms[moovIdx]
+	if moov.size > maxMoovSize {
+		return ErrMoovTooLarge
+	}
+
+	buf := make([]byte, moov.size)
+	if _, err = r.Seek(moov.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek moov: %w", err)
+	}
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return fmt.Errorf("failed to read moov: %w", err)
+	}
+
+	return walkChunkOffsets(buf, func(offset uint64) (uint64, error) {
+		if offset >= uint64(size) {
+			return 0, fmt.Errorf("%w: chunk offset %d is out of file", ErrVerificationFailed, offset)
+		}
+		return offset, nil
+	})
+}
+
+// readAtoms читает список атомов верхнего уровня и размер файла
+func readAtoms(r io.ReadSeeker) ([]atom, int64, error) {
+	fileSize, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get file size: %w", err)
+	}
+
+	var atoms []atom
+	var pos int64
+	header := make([]byte, 16)
+
+	for pos < fileSize {
+		if fileSize-pos < 8 {
+			return nil, 0, fmt.Errorf("%w: truncated header at %d", ErrInvalidAtom, pos)
+		}
+
+		if _, err = r.Seek(pos, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to seek atom: %w", err)
+		}
+		if _, err = io.ReadFull(r, header[:8]); err != nil {
+			return nil, 0, fmt.Errorf("failed to read atom header: %w", err)
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		typ := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			size = fileSize - pos
+		case 1:
+			if _, err = io.ReadFull(r, header[8:16]); err != nil {
+				return nil, 0, fmt.Errorf("failed to read atom largesize: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if size < headerSize || size > fileSize-pos {
+			return nil, 0, fmt.Errorf("%w: %q at %d has size %d", ErrInvalidAtom, typ, pos, size)
+		}
+
+		atoms = append(atoms, atom{typ: typ, offset: pos, size: size})
+		pos += size
+	}
+
+	return atoms, fileSize, nil
+}
+
+func findMoovMdat(atoms []atom) (int, int, error) {
+	moovIdx, mdatIdx := -1, -1
+	for i, a := range atoms {
+		switch {
+		case a.typ == "moov" && moovIdx < 0:
+			moovIdx = i
+		case a.typ == "mdat" && mdatIdx < 0:
+			mdatIdx = i
+		}
+	}
+
+	if moovIdx < 0 {
+		return 0, 0, ErrMoovNotFound
+	}
+	if mdatIdx < 0 {
+		return 0, 0, ErrMdatNotFound
+	}
+
+	return moovIdx, mdatIdx, nil
+}
+
+// walkChunkOffsets обходит контейнеры moov и применяет fn к каждому смещению в stco и co64.
+// Новые значения записываются обратно в buf.
+func walkChunkOffsets(buf []byte, fn func(uint64) (uint64, error)) error {
+	for pos := 0; pos < len(buf); {
+		if len(buf)-pos < 8 {
+			return fmt.Errorf("%w: truncated child header", ErrInvalidAtom)
+		}
+
+		size := int(binary.BigEndian.Uint32(buf[pos : pos+4]))
+		typ := string(buf[pos+4 : pos+8])
+		headerSize := 8
+
+		switch size {
+		case 0:
+			size = len(buf) - pos
+		case 1:
+			if len(buf)-pos < 16 {
+				return fmt.Errorf("%w: truncated child largesize", ErrInvalidAtom)
+			}
+			size = int(binary.BigEndian.Uint64(buf[pos+8 : pos+16]))
+			headerSize = 16
+		}
+
+		if size < headerSize || size > len(buf)-pos {
+			return fmt.Errorf("%w: child %q has size %d", ErrInvalidAtom, typ, size)
+		}
+
+		body := buf[pos+headerSize : pos+size]
+
+		switch {
+		case typ == "cmov":
+			return ErrCompressedMoov
+		case containerAtoms[typ]:
+			if err := walkChunkOffsets(body, fn); err != nil {
+				return err
+			}
+		case typ == "stco":
+			if err := patchOffsets(body, 4, fn); err != nil {
+				return err
+			}
+		case typ == "co64":
+			if err := patchOffsets(body, 8, fn); err != nil {
+				return err
+			}
+		}
+
+		pos += size
+	}
+
+	return nil
+}
+
+// patchOffsets изменяет таблицу смещений full box с записями шириной width байт
+func patchOffsets(body []byte, width int, fn func(uint64) (uint64, error)) error {
+	if len(body) < 8 {
+		return fmt.Errorf("%w: chunk offset table is too short", ErrInvalidAtom)
+	}
+
+	count := int(binary.BigEndian.Uint32(body[4:8]))
+	entries := body[8:]
+	if len(entries) < count*width {
+		return fmt.Errorf("%w: chunk offset table has %d entries, expected %d", ErrInvalidAtom, len(entries)/width, count)
+	}
+
+	for i := 0; i < count; i++ {
+		entry := entries[i*width : (i+1)*width]
+
+		if width == 4 {
+			offset, err := fn(uint64(binary.BigEndian.Uint32(entry)))
+			if err != nil {
+				return err
+			}
+			if offset > 0xFFFFFFFF {
+				return ErrOffsetOverflow
+			}
+			binary.BigEndian.PutUint32(entry, uint32(offset))
+			continue
+		}
+
+		offset, err := fn(binary.BigEndian.Uint64(entry))
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint64(entry, offset)
+	}
+
+	return nil
+}
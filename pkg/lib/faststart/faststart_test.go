@@ -0,0 +1,150 @@
+package faststart
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// box собирает атом с заданным типом и содержимым
+func box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	buf := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(buf[:4], uint32(8+len(body)))
+	copy(buf[4:8], typ)
+	return append(buf, body...)
+}
+
+// stco собирает таблицу смещений чанков
+func stco(offsets ...uint32) []byte {
+	body := make([]byte, 8+4*len(offsets))
+	binary.BigEndian.PutUint32(body[4:8], uint32(len(offsets)))
+	for i, o := range offsets {
+		binary.BigEndian.PutUint32(body[8+4*i:], o)
+	}
+	return box("stco", body)
+}
+
+// buildFile собирает файл вида ftyp, mdat, moov, где stco указывает на чанки в mdat
+func buildFile() ([]byte, [][]byte) {
+	ftyp := box("ftyp", []byte("isom0000isomiso2"))
+	chunks := [][]byte{[]byte("first-chunk"), []byte("second-chunk")}
+	mdat := box("mdat", chunks...)
+
+	first := uint32(len(ftyp) + 8)
+	second := first + uint32(len(chunks[0]))
+
+	moov := box("moov",
+		box("mvhd", make([]byte, 100)),
+		box("trak", box("mdia", box("minf", box("stbl", stco(first, second))))),
+	)
+
+	return bytes.Join([][]byte{ftyp, mdat, moov}, nil), chunks
+}
+
+// readOffsets возвращает смещения из первой таблицы stco файла
+func readOffsets(t *testing.T, data []byte) []uint32 {
+	idx := bytes.Index(data, []byte("stco"))
+	require.NotEqual(t, -1, idx)
+
+	body := data[idx+4:]
+	count := int(binary.BigEndian.Uint32(body[4:8]))
+	offsets := make([]uint32, count)
+	for i := range offsets {
+		offsets[i] = binary.BigEndian.Uint32(body[8+4*i:])
+	}
+	return offsets
+}
+
+func TestRewrite_MovesMoovAndPatchesOffsets(t *testing.T) {
+	data, chunks := buildFile()
+
+	need, err := NeedsFaststart(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.True(t, need)
+
+	var out bytes.Buffer
+	err = Rewrite(bytes.NewReader(data), &out)
+	require.NoError(t, err)
+
+	result := out.Bytes()
+	assert.Equal(t, len(data), len(result))
+	assert.Less(t, bytes.Index(result, []byte("moov")), bytes.Index(result, []byte("mdat")))
+
+	// Смещения должны указывать на те же чанки в новом файле
+	offsets := readOffsets(t, result)
+	require.Len(t, offsets, len(chunks))
+	for i, chunk := range chunks {
+		assert.Equal(t, chunk, result[offsets[i]:int(offsets[i])+len(chunk)])
+	}
+
+	need, err = NeedsFaststart(bytes.NewReader(result))
+	require.NoError(t, err)
+	assert.False(t, need)
+
+	assert.NoError(t, Verify(bytes.NewReader(result), int64(len(data))))
+}
+
+func TestRewrite_AlreadyOptimized(t *testing.T) {
+	data, _ := buildFile()
+
+	var out bytes.Buffer
+	require.NoError(t, Rewrite(bytes.NewReader(data), &out))
+
+	err := Rewrite(bytes.NewReader(out.Bytes()), &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrAlreadyOptimized)
+}
+
+func TestRewrite_CompressedMoov(t *testing.T) {
+	ftyp := box("ftyp", []byte("isom0000"))
+	mdat := box("mdat", []byte("data"))
+	moov := box("moov", box("cmov", make([]byte, 16)))
+	data := bytes.Join([][]byte{ftyp, mdat, moov}, nil)
+
+	err := Rewrite(bytes.NewReader(data), &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrCompressedMoov)
+}
+
+func TestNeedsFaststart_InvalidFile(t *testing.T) {
+	_, err := NeedsFaststart(bytes.NewReader([]byte("not an mp4 file at all")))
+	assert.ErrorIs(t, err, ErrInvalidAtom)
+
+	data := box("ftyp", []byte("isom0000"))
+	_, err = NeedsFaststart(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrMoovNotFound)
+}
+
+func TestVerify_SizeMismatch(t *testing.T) {
+	data, _ := buildFile()
+
+	var out bytes.Buffer
+	require.NoError(t, Rewrite(bytes.NewReader(data), &out))
+
+	err := Verify(bytes.NewReader(out.Bytes()), int64(len(data))+1)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+}
+
+func TestRewrite_HostileLargeSize(t *testing.T) {
+	// largesize около MaxInt64: pos+size переполняется и не должен проходить проверку границ
+	large := make([]byte, 16)
+	binary.BigEndian.PutUint32(large[:4], 1)
+	copy(large[4:8], "trak")
+	binary.BigEndian.PutUint64(large[8:16], 1<<63-8)
+
+	ftyp := box("ftyp", []byte("isom0000"))
+	mdat := box("mdat", []byte("data"))
+	moov := box("moov", box("mvhd", make([]byte, 8)), large)
+	data := bytes.Join([][]byte{ftyp, mdat, moov}, nil)
+
+	err := Rewrite(bytes.NewReader(data), &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidAtom)
+
+	// То же на верхнем уровне файла
+	copy(large[4:8], "free")
+	data = bytes.Join([][]byte{ftyp, box("free", make([]byte, 8)), large, mdat, moov}, nil)
+	_, err = NeedsFaststart(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrInvalidAtom)
+}
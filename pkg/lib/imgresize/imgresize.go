@@ -0,0 +1,133 @@
+// Package imgresize уменьшает изображения без внешних зависимостей.
+// Каждый пиксель результата — среднее пикселей исходника, которые он покрывает (box filter),
+// что дает приличное качество при уменьшении. Увеличение не выполняется: результат не больше исходника.
+package imgresize
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Режимы вписывания в рамку w×h
+const (
+	FitContain = "contain" // Изображение целиком помещается в рамку, пропорции сохраняются
+	FitCover   = "cover"   // Изображение заполняет рамку, лишнее обрезается по центру
+)
+
+var ErrInvalidFit = errors.New("fit must be contain or cover")
+
+// ParseFit проверяет режим вписывания. Пустое значение означает FitContain.
+func ParseFit(fit string) (string, error) {
+	switch fit {
+	case "", FitContain:
+		return FitContain, nil
+	case FitCover:
+		return FitCover, nil
+	default:
+		return "", ErrInvalidFit
+	}
+}
+
+// Plan рассчитывает область исходника crop и размер результата для рамки w×h.
+// Нулевая ширина или высота рамки вычисляется по пропорциям исходника.
+func Plan(srcW, srcH, w, h int, fit string) (crop image.Rectangle, dstW, dstH int) {
+	crop = image.Rect(0, 0, srcW, srcH)
+	if srcW <= 0 || srcH <= 0 || (w <= 0 && h <= 0) {
+		return crop, srcW, srcH
+	}
+
+	// Обрезка нужна только когда заданы обе стороны рамки
+	if fit == FitCover && w > 0 && h > 0 {
+		if srcW*h > srcH*w {
+			cropW := roundDiv(srcH*w, h)
+			x0 := (srcW - cropW) / 2
+			crop = image.Rect(x0, 0, x0+cropW, srcH)
+		} else {
+			cropH := roundDiv(srcW*h, w)
+			y0 := (srcH - cropH) / 2
+			crop = image.Rect(0, y0, srcW, y0+cropH)
+		}
+	}
+
+	cw, ch := crop.Dx(), crop.Dy()
+
+	scale := 1.0
+	if w > 0 {
+		scale = math.Min(scale, float64(w)/float64(cw))
+	}
+	if h > 0 {
+		scale = math.Min(scale, float64(h)/float64(ch))
+	}
+
+	dstW = max(1, int(math.Round(float64(cw)*scale)))
+	dstH = max(1, int(math.Round(float64(ch)*scale)))
+
+	return crop, dstW, dstH
+}
+
+// Resize уменьшает изображение, чтобы вписать его в рамку w×h в режиме fit
+func Resize(src image.Image, w, h int, fit string) *image.RGBA {
+	b := src.Bounds()
+	crop, dstW, dstH := Plan(b.Dx(), b.Dy(), w, h, fit)
+
+	// В image.RGBA цвета хранятся с премультипликацией альфы, поэтому
+	// прозрачные пиксели не окрашивают края при усреднении
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	xs := spans(crop.Min.X, crop.Dx(), dstW)
+	ys := spans(crop.Min.Y, crop.Dy(), dstH)
+
+	for dy := 0; dy < dstH; dy++ {
+		y0, y1 := ys[dy], ys[dy+1]
+		for dx := 0; dx < dstW; dx++ {
+			x0, x1 := xs[dx], xs[dx+1]
+
+			var r, g, bl, a uint64
+			for y := y0; y < y1; y++ {
+				row := rgba.Pix[y*rgba.Stride+x0*4 : y*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					bl += uint64(row[i+2])
+					a += uint64(row[i+3])
+				}
+			}
+
+			n := uint64((x1 - x0) * (y1 - y0))
+			o := dy*dst.Stride + dx*4
+			dst.Pix[o] = uint8((r + n/2) / n)
+			dst.Pix[o+1] = uint8((g + n/2) / n)
+			dst.Pix[o+2] = uint8((bl + n/2) / n)
+			dst.Pix[o+3] = uint8((a + n/2) / n)
+		}
+	}
+
+	return dst
+}
+
+// spans делит отрезок исходника [start, start+size) на n частей, каждая не меньше одного пикселя
+func spans(start, size, n int) []int {
+	res := make([]int, n+1)
+	for i := 0; i <= n; i++ {
+		res[i] = start + i*size/n
+	}
+	for i := 1; i <= n; i++ {
+		if res[i] <= res[i-1] {
+			res[i] = res[i-1] + 1
+		}
+	}
+
+	return res
+}
+
+func roundDiv(a, b int) int {
+	return (a + b/2) / b
+}
@@ -0,0 +1,65 @@
+package imgresize
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		srcW, srcH int
+		w, h       int
+		fit        string
+		crop       image.Rectangle
+		dstW, dstH int
+	}{
+		{"только ширина", 1200, 800, 300, 0, FitContain, image.Rect(0, 0, 1200, 800), 300, 200},
+		{"только высота", 1200, 800, 0, 400, FitContain, image.Rect(0, 0, 1200, 800), 600, 400},
+		{"contain в квадрат", 1200, 800, 300, 300, FitContain, image.Rect(0, 0, 1200, 800), 300, 200},
+		{"cover в квадрат", 1200, 800, 300, 300, FitCover, image.Rect(200, 0, 1000, 800), 300, 300},
+		{"cover в высокую рамку", 800, 1200, 400, 200, FitCover, image.Rect(0, 400, 800, 800), 400, 200},
+		// Изображение меньше рамки не увеличивается
+		{"без увеличения", 100, 50, 640, 0, FitContain, image.Rect(0, 0, 100, 50), 100, 50},
+		{"cover без увеличения", 100, 50, 640, 640, FitCover, image.Rect(25, 0, 75, 50), 50, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crop, dstW, dstH := Plan(tt.srcW, tt.srcH, tt.w, tt.h, tt.fit)
+			assert.Equal(t, tt.crop, crop)
+			assert.Equal(t, tt.dstW, dstW)
+			assert.Equal(t, tt.dstH, dstH)
+		})
+	}
+}
+
+func TestResize_AveragesPixels(t *testing.T) {
+	// Шахматная доска 4×4 из черных и белых пикселей после уменьшения вдвое становится серой
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if (x+y)%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	dst := Resize(src, 2, 0, FitContain)
+	assert.Equal(t, image.Rect(0, 0, 2, 2), dst.Bounds())
+	assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, dst.RGBAAt(1, 1))
+}
+
+func TestParseFit(t *testing.T) {
+	fit, err := ParseFit("")
+	assert.NoError(t, err)
+	assert.Equal(t, FitContain, fit)
+
+	_, err = ParseFit("stretch")
+	assert.ErrorIs(t, err, ErrInvalidFit)
+}
@@ -0,0 +1,160 @@
+package imgsafe
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	src := `<?xml version="1.0"?>
+<!DOCTYPE svg>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" onload="alert(1)">
+<!-- комментарий -->
+<script>alert(1)</script>
+<style>@import url(https://evil.example/x.css);</style>
+<defs><linearGradient id="g"><stop offset="0"/></linearGradient></defs>
+<rect fill="url(#g)" width="10" height="10" onclick="alert(2)"/>
+<a xlink:href="java&#x09;script:alert(3)"><circle r="5"/></a>
+<image href="https://evil.example/track.png"/>
+<use href="#g"/>
+<set attributeName="xlink:href" to="javascript:alert(4)"/>
+<foreignObject><div xmlns="http://www.w3.org/1999/xhtml">x</div></foreignObject>
+<text style="fill: url(https://evil.example/)">a &amp; b</text>
+</svg>`
+
+	out, err := SanitizeSVG([]byte(src))
+	require.NoError(t, err)
+
+	s := string(out)
+	for _, bad := range []string{"alert", "evil.example", "<script", "<set", "foreignObject", "<!--", "DOCTYPE", "<?xml", "onload", "onclick"} {
+		assert.NotContains(t, s, bad)
+	}
+
+	// Безопасное содержимое сохраняется
+	assert.Contains(t, s, `<rect fill="url(#g)" width="10" height="10"></rect>`)
+	assert.Contains(t, s, `<use href="#g"></use>`)
+	assert.Contains(t, s, `<a><circle r="5"></circle></a>`)
+	assert.Contains(t, s, `<text>a &amp; b</text>`)
+
+	// Результат снова разбирается как SVG
+	_, err = SanitizeSVG(out)
+	assert.NoError(t, err)
+}
+
+func TestSanitizeSVG_CSSBypass(t *testing.T) {
+	// Ссылки, спрятанные за CDATA, комментариями и escape-последовательностями CSS
+	tests := map[string]string{
+		"CDATA в style":          `<svg><style>rect{fill:ur<![CDATA[l(http://evil.example/x)]]>}</style></svg>`,
+		"XML-комментарий":        `<svg><style>@im<!-- -->port "http://evil.example/x.css";</style></svg>`,
+		"CSS-комментарий":        `<svg><style>@im/**/port "http://evil.example/x.css";</style></svg>`,
+		"escape в style":         `<svg><style>rect{fill:u\72l(http://evil.example/x)}</style></svg>`,
+		"escape с пробелом":      `<svg><style>rect{fill:u\000072 l(http://evil.example/x)}</style></svg>`,
+		"escape буквы":           `<svg><style>rect{fill:\url(http://evil.example/x)}</style></svg>`,
+		"image-set":              `<svg><style>rect{fill:image-set("http://evil.example/x.png" 1x)}</style></svg>`,
+		"вложенный элемент":      `<svg><style><g/>rect{fill:url(http://evil.example/x)}</style></svg>`,
+		"escape в атрибуте":      `<svg><rect style="fill:u\72l(http://evil.example/x)"/></svg>`,
+		"image-set в атрибуте":   `<svg><rect style="fill:-webkit-image-set('http://evil.example/x.png' 1x)"/></svg>`,
+		"комментарий в атрибуте": `<svg><rect style="fill:ur/**/l(http://evil.example/x)"/></svg>`,
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := SanitizeSVG([]byte(src))
+			require.NoError(t, err)
+			assert.NotContains(t, string(out), "evil.example")
+		})
+	}
+
+	// Ссылки на элементы документа и встроенные изображения сохраняются
+	out, err := SanitizeSVG([]byte(`<svg><style>rect{fill:u\72l(#g)} circle{fill:image-set("data:image/png;base64,AA" 1x)}</style>` +
+		`<rect style="fill:url('#g')"/></svg>`))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `u\72l(#g)`)
+	assert.Contains(t, string(out), `data:image/png;base64,AA`)
+	assert.Contains(t, string(out), `<rect style="fill:url(&#39;#g&#39;)"></rect>`)
+}
+
+func TestSanitizeSVG_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"не svg":           `<html><body/></html>`,
+		"не закрыт":        `<svg><g></svg>`,
+		"внешняя сущность": `<!DOCTYPE svg [<!ENTITY x SYSTEM "file:///etc/passwd">]><svg>&x;</svg>`,
+		"два корня":        `<svg></svg><svg></svg>`,
+		"пустой документ":  ``,
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := SanitizeSVG([]byte(src))
+			assert.ErrorIs(t, err, ErrInvalidSVG)
+		})
+	}
+}
+
+func TestCheckRaster(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 40, 30))))
+	data := buf.Bytes()
+
+	assert.NoError(t, CheckRaster(data, Limits{MaxWidth: 40, MaxHeight: 30, MaxPixels: 1200}))
+	assert.ErrorIs(t, CheckRaster(data, Limits{MaxWidth: 39}), ErrTooLarge)
+	assert.ErrorIs(t, CheckRaster(data, Limits{MaxPixels: 1000}), ErrTooLarge)
+
+	// Заголовок цел, а сжатые данные обрезаны
+	assert.ErrorIs(t, CheckRaster(data[:len(data)-20], Limits{}), ErrInvalidImage)
+	assert.ErrorIs(t, CheckRaster([]byte("not an image"), Limits{}), ErrInvalidImage)
+}
+
+func TestCheckRaster_WebP(t *testing.T) {
+	// Заголовок VP8X с холстом 100×50
+	data := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00\x63\x00\x00\x31\x00\x00")
+
+	assert.NoError(t, CheckRaster(data, Limits{MaxWidth: 100, MaxHeight: 50}))
+	assert.ErrorIs(t, CheckRaster(data, Limits{MaxWidth: 99}), ErrTooLarge)
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 8)), nil))
+	encoded := buf.Bytes()
+
+	// EXIF little-endian с ориентацией 6 и записью GPS IFD, комментарий и XMP
+	exif := []byte("Exif\x00\x00II*\x00\x08\x00\x00\x00\x02\x00" +
+		"\x12\x01\x03\x00\x01\x00\x00\x00\x06\x00\x00\x00" +
+		"\x25\x88\x04\x00\x01\x00\x00\x00\x26\x00\x00\x00" +
+		"\x00\x00\x00\x00GPSDATA")
+	xmp := []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>secret</x:xmpmeta>")
+	comment := []byte("camera owner")
+
+	var src bytes.Buffer
+	src.Write(encoded[:2])
+	src.Write(segment(markerAPP1, exif))
+	src.Write(segment(markerCOM, comment))
+	src.Write(segment(markerAPP1, xmp))
+	src.Write(encoded[2:])
+
+	out, err := StripJPEGMetadata(src.Bytes())
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(out), "GPSDATA")
+	assert.NotContains(t, string(out), "secret")
+	assert.NotContains(t, string(out), "camera owner")
+
+	// Ориентация перенесена в новый блок EXIF
+	assert.Equal(t, uint16(6), exifOrientation(out[2+4+len(exifHeader):]))
+
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 8), img.Bounds())
+}
+
+func segment(marker byte, payload []byte) []byte {
+	n := len(payload) + 2
+	return append([]byte{0xff, marker, byte(n >> 8), byte(n)}, payload...)
+}
@@ -0,0 +1,164 @@
+package imgsafe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Маркеры JPEG
+const (
+	markerSOI   = 0xd8
+	markerSOS   = 0xda
+	markerEOI   = 0xd9
+	markerAPP0  = 0xe0
+	markerAPP1  = 0xe1
+	markerAPP2  = 0xe2
+	markerAPP14 = 0xee
+	markerAPP15 = 0xef
+	markerCOM   = 0xfe
+)
+
+// tagOrientation тег EXIF с ориентацией снимка
+const tagOrientation = 0x0112
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// StripJPEGMetadata удаляет из JPEG метаданные: EXIF (в том числе GPS), XMP, IPTC и комментарии.
+// Сохраняются JFIF (APP0), цветовой профиль ICC (APP2) и Adobe (APP14). Ориентация снимка
+// переносится в минимальный блок EXIF, чтобы изображение не поворачивалось при показе.
+func StripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != markerSOI {
+		return nil, fmt.Errorf("%w: not a jpeg", ErrInvalidImage)
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(data[:2])
+
+	orientation := uint16(0)
+	var kept [][]byte // Сегменты после APP0, перед которыми встает блок ориентации
+	var head []byte   // APP0 остается сразу после SOI
+
+	pos := 2
+	for {
+		// Перед маркером может стоять любое число байтов-заполнителей 0xff
+		for pos < len(data) && data[pos] == 0xff && pos+1 < len(data) && data[pos+1] == 0xff {
+			pos++
+		}
+		if pos+2 > len(data) || data[pos] != 0xff {
+			return nil, fmt.Errorf("%w: broken jpeg segment at %d", ErrInvalidImage, pos)
+		}
+
+		marker := data[pos+1]
+
+		// Маркеры без длины
+		if marker == markerEOI || marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			kept = append(kept, data[pos:pos+2])
+			pos += 2
+			if marker == markerEOI {
+				break
+			}
+			continue
+		}
+
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("%w: truncated jpeg", ErrInvalidImage)
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, fmt.Errorf("%w: truncated jpeg segment", ErrInvalidImage)
+		}
+		segment, payload := data[pos:end], data[pos+4:end]
+
+		switch {
+		case marker == markerSOS:
+			// После SOS идут сжатые данные, копируем остаток файла целиком
+			kept = append(kept, data[pos:])
+			pos = len(data)
+		case marker == markerAPP0 && head == nil && len(kept) == 0:
+			head = segment
+		case marker == markerAPP1:
+			if bytes.HasPrefix(payload, exifHeader) && orientation == 0 {
+				orientation = exifOrientation(payload[len(exifHeader):])
+			}
+		case marker == markerCOM,
+			marker > markerAPP0 && marker <= markerAPP15 && marker != markerAPP2 && marker != markerAPP14:
+			// Метаданные отбрасываются
+		default:
+			kept = append(kept, segment)
+		}
+
+		if pos >= len(data) {
+			break
+		}
+		pos = end
+	}
+
+	out.Write(head)
+	if orientation > 1 && orientation <= 8 {
+		out.Write(orientationSegment(orientation))
+	}
+	for _, segment := range kept {
+		out.Write(segment)
+	}
+
+	return out.Bytes(), nil
+}
+
+// exifOrientation читает ориентацию из нулевого IFD блока TIFF
+func exifOrientation(tiff []byte) uint16 {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	if order.Uint16(tiff[2:4]) != 42 {
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+
+		// Ориентация — одно значение типа SHORT, лежит прямо в поле значения
+		if order.Uint16(tiff[entry:entry+2]) == tagOrientation && order.Uint16(tiff[entry+2:entry+4]) == 3 {
+			return order.Uint16(tiff[entry+8 : entry+10])
+		}
+	}
+
+	return 0
+}
+
+// orientationSegment собирает APP1 с EXIF, в котором есть только ориентация
+func orientationSegment(orientation uint16) []byte {
+	var seg bytes.Buffer
+
+	seg.Write([]byte{0xff, markerAPP1, 0, 34})
+	seg.Write(exifHeader)
+	seg.Write([]byte{'M', 'M', 0, 42, 0, 0, 0, 8})  // Заголовок TIFF, IFD0 сразу за ним
+	seg.Write([]byte{0, 1})                         // Одна запись
+	seg.Write([]byte{0x01, 0x12, 0, 3, 0, 0, 0, 1}) // Ориентация, SHORT, одно значение
+	seg.Write([]byte{byte(orientation >> 8), byte(orientation), 0, 0})
+	seg.Write([]byte{0, 0, 0, 0}) // Следующего IFD нет
+
+	return seg.Bytes()
+}
@@ -0,0 +1,96 @@
+package imgsafe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+var (
+	ErrInvalidImage = errors.New("invalid image")
+	ErrTooLarge     = errors.New("image dimensions exceed limits")
+)
+
+// Limits ограничения растрового изображения. Нулевое значение поля снимает ограничение.
+type Limits struct {
+	MaxWidth  int
+	MaxHeight int
+	MaxPixels int
+}
+
+func (l Limits) check(width, height int) error {
+	switch {
+	case width <= 0 || height <= 0:
+		return fmt.Errorf("%w: empty image %dx%d", ErrInvalidImage, width, height)
+	case l.MaxWidth > 0 && width > l.MaxWidth,
+		l.MaxHeight > 0 && height > l.MaxHeight,
+		l.MaxPixels > 0 && int64(width)*int64(height) > int64(l.MaxPixels):
+		return fmt.Errorf("%w: %dx%d", ErrTooLarge, width, height)
+	}
+
+	return nil
+}
+
+// CheckRaster проверяет размеры изображения по заголовку и затем полностью декодирует его, поэтому
+// поврежденные файлы и «бомбы» распаковки отклоняются до выделения памяти под пиксели.
+// WEBP проверяется только по заголовку: декодера WEBP в стандартной библиотеке нет.
+func CheckRaster(data []byte, limits Limits) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		width, height, ok := webpSize(data)
+		if !ok {
+			return fmt.Errorf("%w: %v", ErrInvalidImage, err)
+		}
+		return limits.check(width, height)
+	}
+
+	if err = limits.check(cfg.Width, cfg.Height); err != nil {
+		return err
+	}
+
+	if _, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	return nil
+}
+
+// webpSize читает размеры холста из заголовка WEBP (VP8, VP8L или VP8X)
+func webpSize(data []byte) (width, height int, ok bool) {
+	if len(data) < 30 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, false
+	}
+
+	chunk := data[20:]
+
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// Ключевой кадр: 3 байта тега, стартовый код 9d 01 2a, затем 14-битные ширина и высота
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, false
+		}
+		width = int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff)
+		height = int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff)
+	case "VP8L":
+		// Сигнатура 0x2f, затем ширина-1 и высота-1 по 14 бит
+		if chunk[0] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		width = int(bits&0x3fff) + 1
+		height = int(bits>>14&0x3fff) + 1
+	case "VP8X":
+		// 4 байта флагов, затем ширина-1 и высота-1 по 24 бита
+		width = int(uint32(chunk[4])|uint32(chunk[5])<<8|uint32(chunk[6])<<16) + 1
+		height = int(uint32(chunk[7])|uint32(chunk[8])<<8|uint32(chunk[9])<<16) + 1
+	default:
+		return 0, 0, false
+	}
+
+	return width, height, true
+}
@@ -0,0 +1,324 @@
+// Package imgsafe проверяет изображения перед сохранением в библиотеку: удаляет из SVG скрипты
+// и внешние ссылки, ограничивает размеры растровых изображений и убирает метаданные из JPEG.
+package imgsafe
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidSVG = errors.New("invalid svg")
+
+// maxSVGDepth ограничивает вложенность элементов SVG
+const maxSVGDepth = 256
+
+// forbiddenElements элементы, которые удаляются вместе с содержимым
+var forbiddenElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// animationElements элементы анимации, которыми можно подменить ссылку или обработчик события
+var animationElements = map[string]bool{
+	"set":              true,
+	"animate":          true,
+	"animatemotion":    true,
+	"animatetransform": true,
+}
+
+// safeDataImage встроенное растровое изображение, допустимое в href
+var safeDataImage = regexp.MustCompile(`^data:image/(png|jpeg|gif|webp);`)
+
+// cssResource функции CSS, которые загружают ресурс по ссылке
+var cssResource = regexp.MustCompile(`(url|image-set|src)\(`)
+
+// cssString строка в кавычках внутри аргументов функции CSS
+var cssString = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// SanitizeSVG возвращает SVG без скриптов, обработчиков событий и ссылок на внешние ресурсы.
+// Комментарии, DOCTYPE и инструкции обработки удаляются. Документ, который не разбирается
+// как XML или корень которого не svg, отклоняется с ErrInvalidSVG.
+func SanitizeSVG(data []byte) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var out bytes.Buffer
+	var stack []xml.Name
+	var style bytes.Buffer // Текст текущего элемента style, проверяется целиком при его закрытии
+	skip := 0              // Глубина внутри удаляемого элемента
+	inStyle := false
+	root := false
+
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			local := strings.ToLower(t.Name.Local)
+
+			if len(stack) == 0 {
+				if root || local != "svg" {
+					return nil, fmt.Errorf("%w: root element must be svg", ErrInvalidSVG)
+				}
+				root = true
+			}
+
+			stack = append(stack, t.Name)
+			if len(stack) > maxSVGDepth {
+				return nil, fmt.Errorf("%w: nesting too deep", ErrInvalidSVG)
+			}
+
+			// Внутри style допустим только текст
+			if skip > 0 || inStyle || forbiddenElements[local] || unsafeAnimation(local, t.Attr) {
+				skip++
+				continue
+			}
+
+			inStyle = local == "style"
+			style.Reset()
+			writeStart(&out, t)
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1] != t.Name {
+				return nil, fmt.Errorf("%w: unexpected end element %s", ErrInvalidSVG, qualifiedName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			// Текст стиля собирается из всех частей, включая CDATA и куски между комментариями,
+			// и со ссылками на внешние ресурсы удаляется целиком
+			if inStyle && !unsafeCSS(style.String()) {
+				xml.EscapeText(&out, style.Bytes())
+			}
+
+			inStyle = false
+			out.WriteString("</" + qualifiedName(t.Name) + ">")
+		case xml.CharData:
+			if skip > 0 || len(stack) == 0 {
+				continue
+			}
+
+			if inStyle {
+				style.Write(t)
+				continue
+			}
+
+			xml.EscapeText(&out, t)
+		}
+	}
+
+	if !root || len(stack) > 0 {
+		return nil, fmt.Errorf("%w: unexpected end of document", ErrInvalidSVG)
+	}
+
+	return out.Bytes(), nil
+}
+
+func writeStart(out *bytes.Buffer, t xml.StartElement) {
+	out.WriteString("<" + qualifiedName(t.Name))
+
+	for _, attr := range t.Attr {
+		if !safeAttr(attr) {
+			continue
+		}
+
+		out.WriteString(" " + qualifiedName(attr.Name) + `="`)
+		xml.EscapeText(out, []byte(attr.Value))
+		out.WriteString(`"`)
+	}
+
+	out.WriteString(">")
+}
+
+// safeAttr отбрасывает обработчики событий, ссылки на внешние ресурсы и javascript: в любых атрибутах
+func safeAttr(attr xml.Attr) bool {
+	local := strings.ToLower(attr.Name.Local)
+	value := normalizeValue(attr.Value)
+
+	switch {
+	case strings.HasPrefix(local, "on"):
+		return false
+	case local == "href" || local == "src":
+		return strings.HasPrefix(value, "#") || safeDataImage.MatchString(value)
+	case strings.HasPrefix(value, "javascript:"), strings.HasPrefix(value, "vbscript:"), strings.HasPrefix(value, "data:"):
+		return false
+	default:
+		return !unsafeCSS(attr.Value)
+	}
+}
+
+// unsafeAnimation находит анимацию, которая меняет ссылку или обработчик события
+func unsafeAnimation(local string, attrs []xml.Attr) bool {
+	if !animationElements[local] {
+		return false
+	}
+
+	for _, attr := range attrs {
+		if strings.ToLower(attr.Name.Local) != "attributename" {
+			continue
+		}
+
+		target := strings.ToLower(strings.TrimSpace(attr.Value))
+		if _, name, ok := strings.Cut(target, ":"); ok {
+			target = name
+		}
+
+		if target == "href" || target == "src" || strings.HasPrefix(target, "on") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unsafeCSS находит в стиле импорт, выражения и url(), image-set() и src(), ссылающиеся не на элемент
+// документа и не на встроенное изображение. Стиль проверяется после удаления комментариев и escape-
+// последовательностей, которыми обходят проверку («u\72l(», «@im/**/port»).
+func unsafeCSS(css string) bool {
+	value := normalizeValue(decodeCSS(css))
+	if strings.Contains(value, "@import") || strings.Contains(value, "expression(") || strings.Contains(value, "javascript:") {
+		return true
+	}
+
+	for _, loc := range cssResource.FindAllStringSubmatchIndex(value, -1) {
+		args := cssArgs(value[loc[1]:])
+
+		if value[loc[2]:loc[3]] == "image-set" {
+			// Ссылки в image-set задаются строками, вложенные url() проверяются отдельно
+			for _, match := range cssString.FindAllStringSubmatch(args, -1) {
+				if !safeCSSRef(match[1] + match[2]) {
+					return true
+				}
+			}
+			continue
+		}
+
+		if !safeCSSRef(strings.Trim(args, `"'`)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// safeCSSRef пропускает ссылку на элемент документа и встроенное растровое изображение
+func safeCSSRef(ref string) bool {
+	return strings.HasPrefix(ref, "#") || safeDataImage.MatchString(ref)
+}
+
+// cssArgs возвращает аргументы функции CSS до парной закрывающей скобки или до конца стиля
+func cssArgs(css string) string {
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(css); i++ {
+		switch c := css[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return css[:i]
+			}
+			depth--
+		}
+	}
+
+	return css
+}
+
+// decodeCSS удаляет комментарии /* */ и раскрывает escape-последовательности CSS: «\72 » — символ
+// с кодом 0x72, «\» с переводом строки — продолжение строки, «\x» — сам символ x
+func decodeCSS(css string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(css); i++ {
+		switch {
+		case strings.HasPrefix(css[i:], "/*"):
+			end := strings.Index(css[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += 2 + end + 1
+		case css[i] == '\\':
+			i++
+			if i >= len(css) {
+				return b.String()
+			}
+
+			n := 0
+			for n < 6 && i+n < len(css) && isHex(css[i+n]) {
+				n++
+			}
+
+			switch {
+			case n > 0:
+				code, _ := strconv.ParseUint(css[i:i+n], 16, 32)
+				if code == 0 || code > unicode.MaxRune || (code >= 0xd800 && code <= 0xdfff) {
+					code = unicode.ReplacementChar
+				}
+				b.WriteRune(rune(code))
+				i += n - 1
+
+				// Один пробельный символ после кода входит в escape-последовательность
+				if i+1 < len(css) && strings.IndexByte(" \t\n\r\f", css[i+1]) >= 0 {
+					i++
+				}
+			case css[i] == '\n' || css[i] == '\r' || css[i] == '\f':
+			default:
+				b.WriteByte(css[i])
+			}
+		default:
+			b.WriteByte(css[i])
+		}
+	}
+
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// normalizeValue убирает пробелы и управляющие символы, которыми обходят проверку схемы («java\tscript:»)
+func normalizeValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.ToLower(value))
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,208 @@
+// Package mediasign подписывает ссылки на медиафайлы HMAC-SHA256 со сроком действия и привязкой к аккаунту.
+//
+// Подписанная ссылка содержит параметры exp (unix-время окончания), kid (ключ подписи), sig (подпись)
+// и необязательный u — тег аккаунта, для которого выдана ссылка. Тег — HMAC от имени пользователя,
+// само имя в ссылку не попадает. Подписывается ресурс вида "video/имя_файла", а не полный путь,
+// поэтому ссылка не ломается за прокси с префиксом пути и не зависит от параметров вроде w и h у /img.
+//
+// Для ротации ключей ссылки подписываются первым ключом из списка, а проверяются любым ключом из списка.
+package mediasign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Режимы постепенного включения подписи
+const (
+	ModeOff     = "off"     // Ссылки без подписи, проверки нет
+	ModeSign    = "sign"    // Ссылки подписываются, неподписанные запросы еще принимаются
+	ModeEnforce = "enforce" // Запросы без действительной подписи отклоняются
+)
+
+// Параметры подписанной ссылки
+const (
+	ParamExpires   = "exp"
+	ParamKeyID     = "kid"
+	ParamAccount   = "u"
+	ParamSignature = "sig"
+)
+
+var (
+	ErrNoKeys           = errors.New("no signing keys")
+	ErrInvalidKey       = errors.New("signing key must be in id:secret format")
+	ErrMissingSignature = errors.New("signature is missing")
+	ErrInvalidSignature = errors.New("signature is invalid")
+	ErrExpired          = errors.New("signed url has expired")
+	ErrUnknownKey       = errors.New("unknown signing key")
+)
+
+// Key ключ подписи с идентификатором
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// ParseKeys разбирает ключи в формате "id:secret,id:secret". Первый ключ используется для подписи.
+func ParseKeys(value string) ([]Key, error) {
+	var keys []Key
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, secret, ok := strings.Cut(part, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, ErrInvalidKey
+		}
+		keys = append(keys, Key{ID: id, Secret: []byte(secret)})
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	return keys, nil
+}
+
+// Grant результат проверки подписанной ссылки
+type Grant struct {
+	KeyID   string
+	Account string // Тег аккаунта, пустой у ссылки без привязки
+	Expires time.Time
+}
+
+// Signer подписывает и проверяет ссылки
+type Signer struct {
+	keys []Key
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// New создает Signer. Ссылка действует не меньше ttl.
+func New(keys []Key, ttl time.Duration) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl must be positive")
+	}
+
+	return &Signer{keys: keys, ttl: ttl, now: time.Now}, nil
+}
+
+// SignURL добавляет к ссылке на медиафайл срок действия, тег аккаунта и подпись.
+// Срок округляется вверх до часа, чтобы в течение часа ссылка не менялась и кэшировалась клиентом.
+// Пустой username выдает ссылку без привязки к аккаунту.
+func (s *Signer) SignURL(rawURL, username string) string {
+	if rawURL == "" {
+		return ""
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	key := s.keys[0]
+	expires := s.now().Add(s.ttl + time.Hour - 1).Truncate(time.Hour).Unix()
+
+	account := ""
+	if username != "" {
+		account = accountTag(key, username)
+	}
+
+	q := u.Query()
+	q.Set(ParamExpires, strconv.FormatInt(expires, 10))
+	q.Set(ParamKeyID, key.ID)
+	if account != "" {
+		q.Set(ParamAccount, account)
+	} else {
+		q.Del(ParamAccount)
+	}
+	q.Set(ParamSignature, sign(key, Resource(u.Path), expires, account))
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// Verify проверяет подпись ссылки на resource
+func (s *Signer) Verify(resource string, q url.Values) (*Grant, error) {
+	sig := q.Get(ParamSignature)
+	if sig == "" {
+		return nil, ErrMissingSignature
+	}
+
+	key, ok := s.key(q.Get(ParamKeyID))
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	expires, err := strconv.ParseInt(q.Get(ParamExpires), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	account := q.Get(ParamAccount)
+	if !hmac.Equal([]byte(sig), []byte(sign(key, resource, expires, account))) {
+		return nil, ErrInvalidSignature
+	}
+
+	if s.now().Unix() > expires {
+		return nil, ErrExpired
+	}
+
+	return &Grant{KeyID: key.ID, Account: account, Expires: time.Unix(expires, 0)}, nil
+}
+
+// BoundTo проверяет, что ссылка выдана аккаунту username. Ссылка без привязки подходит любому аккаунту.
+func (s *Signer) BoundTo(grant *Grant, username string) bool {
+	if grant.Account == "" {
+		return true
+	}
+
+	key, ok := s.key(grant.KeyID)
+	if !ok || username == "" {
+		return false
+	}
+
+	return hmac.Equal([]byte(grant.Account), []byte(accountTag(key, username)))
+}
+
+func (s *Signer) key(id string) (Key, bool) {
+	for _, key := range s.keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+
+	return Key{}, false
+}
+
+// Resource возвращает подписываемую часть пути: последнюю директорию и имя файла, например "video/neck.mp4"
+func Resource(p string) string {
+	return path.Base(path.Dir(p)) + "/" + path.Base(p)
+}
+
+func sign(key Key, resource string, expires int64, account string) string {
+	mac := hmac.New(sha256.New, key.Secret)
+	fmt.Fprintf(mac, "%s\n%d\n%s", resource, expires, account)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func accountTag(key Key, username string) string {
+	mac := hmac.New(sha256.New, key.Secret)
+	mac.Write([]byte("account\n" + username))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:12])
+}
@@ -0,0 +1,100 @@
+package mediasign
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, keys string, now time.Time) *Signer {
+	t.Helper()
+
+	parsed, err := ParseKeys(keys)
+	require.NoError(t, err)
+
+	s, err := New(parsed, 6*time.Hour)
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	return s
+}
+
+func verifyURL(t *testing.T, s *Signer, signed string) (*Grant, error) {
+	t.Helper()
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+
+	return s.Verify(Resource(u.Path), u.Query())
+}
+
+func TestSignURL_Verify(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 20, 0, 0, time.UTC)
+	s := newTestSigner(t, "k1:secret", now)
+
+	signed := s.SignURL("https://body.7375.org/video/neck.mp4", "patient")
+
+	grant, err := verifyURL(t, s, signed)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", grant.KeyID)
+
+	// Срок округлен вверх до часа и не меньше ttl
+	assert.Equal(t, time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC), grant.Expires.UTC())
+
+	assert.True(t, s.BoundTo(grant, "patient"))
+	assert.False(t, s.BoundTo(grant, "other"))
+	assert.False(t, s.BoundTo(grant, ""))
+	assert.NotContains(t, signed, "patient")
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 20, 0, 0, time.UTC)
+	s := newTestSigner(t, "k1:secret", now)
+
+	signed, err := url.Parse(s.SignURL("https://body.7375.org/video/neck.mp4", "patient"))
+	require.NoError(t, err)
+
+	// Подпись не переносится на другой файл
+	_, err = s.Verify("video/back.mp4", signed.Query())
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Нельзя отвязать ссылку от аккаунта
+	q := signed.Query()
+	q.Del(ParamAccount)
+	_, err = s.Verify("video/neck.mp4", q)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = s.Verify("video/neck.mp4", url.Values{})
+	assert.ErrorIs(t, err, ErrMissingSignature)
+
+	s.now = func() time.Time { return now.Add(8 * time.Hour) }
+	_, err = s.Verify("video/neck.mp4", signed.Query())
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestVerify_KeyRotation(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 20, 0, 0, time.UTC)
+	old := newTestSigner(t, "k1:old", now)
+	signed := old.SignURL("https://body.7375.org/img/neck.jpg?w=320", "")
+
+	// Новый ключ подписывает, старый еще принимается
+	rotated := newTestSigner(t, "k2:new,k1:old", now)
+	grant, err := verifyURL(t, rotated, signed)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", grant.KeyID)
+	assert.True(t, rotated.BoundTo(grant, "anyone"))
+
+	// После удаления старого ключа ссылка перестает действовать
+	_, err = verifyURL(t, newTestSigner(t, "k2:new", now), signed)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestParseKeys_Invalid(t *testing.T) {
+	for _, value := range []string{"", "k1", "k1:", ":secret"} {
+		_, err := ParseKeys(value)
+		assert.Error(t, err, value)
+	}
+}
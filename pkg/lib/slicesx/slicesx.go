@@ -0,0 +1,11 @@
+// Package slicesx дополняет стандартный пакет slices.
+package slicesx
+
+// NonNil заменяет nil на пустой срез: в JSON получается [] вместо null,
+// а pgx записывает пустой массив вместо NULL
+func NonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
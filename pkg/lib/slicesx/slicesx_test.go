@@ -0,0 +1,17 @@
+package slicesx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonNil(t *testing.T) {
+	// nil становится пустым срезом
+	values := NonNil[string](nil)
+	assert.NotNil(t, values)
+	assert.Empty(t, values)
+
+	// Непустой срез возвращается как есть
+	assert.Equal(t, []int{1, 2}, NonNil([]int{1, 2}))
+}
@@ -0,0 +1,155 @@
+// Package webvtt проверяет файлы субтитров WebVTT перед сохранением.
+package webvtt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalid = errors.New("invalid webvtt")
+	ErrNoCues  = errors.New("webvtt has no cues")
+)
+
+// timestampPattern время реплики: [часы:]минуты:секунды.миллисекунды
+var timestampPattern = regexp.MustCompile(`^(?:(\d{2,}):)?([0-5]\d):([0-5]\d)\.(\d{3})$`)
+
+// Validate проверяет заголовок WEBVTT, строки времени реплик и их порядок. Блоки NOTE, STYLE
+// и REGION пропускаются. Возвращает число реплик; файл без реплик отклоняется с ErrNoCues.
+func Validate(data []byte) (int, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+		return 0, fmt.Errorf("%w: file must be UTF-8 text", ErrInvalid)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	lines := strings.Split(text, "\n")
+
+	if !blockKeyword(lines[0], "WEBVTT") {
+		return 0, fmt.Errorf("%w: missing WEBVTT header", ErrInvalid)
+	}
+
+	// Заголовок продолжается до первой пустой строки
+	i := 1
+	for i < len(lines) && lines[i] != "" {
+		if strings.Contains(lines[i], "-->") {
+			return 0, fmt.Errorf("%w: line %d: cue must be separated from header by a blank line", ErrInvalid, i+1)
+		}
+		i++
+	}
+
+	cues := 0
+	var lastStart time.Duration
+
+	for i < len(lines) {
+		if lines[i] == "" {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(lines) && lines[i] != "" {
+			i++
+		}
+		block := lines[start:i]
+
+		if blockKeyword(block[0], "NOTE") || blockKeyword(block[0], "STYLE") || blockKeyword(block[0], "REGION") {
+			continue
+		}
+
+		// Необязательный идентификатор реплики перед строкой времени
+		timing := 0
+		if !strings.Contains(block[0], "-->") {
+			timing = 1
+		}
+		if timing >= len(block) || !strings.Contains(block[timing], "-->") {
+			return 0, fmt.Errorf("%w: line %d: expected cue timings", ErrInvalid, start+timing+1)
+		}
+
+		cueStart, cueEnd, err := parseTimings(block[timing])
+		if err != nil {
+			return 0, fmt.Errorf("%w: line %d: %v", ErrInvalid, start+timing+1, err)
+		}
+
+		if cueEnd <= cueStart {
+			return 0, fmt.Errorf("%w: line %d: cue ends before it starts", ErrInvalid, start+timing+1)
+		}
+		if cueStart < lastStart {
+			return 0, fmt.Errorf("%w: line %d: cues must be ordered by start time", ErrInvalid, start+timing+1)
+		}
+
+		for j, line := range block[timing+1:] {
+			if strings.Contains(line, "-->") {
+				return 0, fmt.Errorf("%w: line %d: cue text cannot contain -->", ErrInvalid, start+timing+j+2)
+			}
+		}
+
+		lastStart = cueStart
+		cues++
+	}
+
+	if cues == 0 {
+		return 0, ErrNoCues
+	}
+
+	return cues, nil
+}
+
+// parseTimings разбирает строку «начало --> конец [настройки]»
+func parseTimings(line string) (time.Duration, time.Duration, error) {
+	left, right, _ := strings.Cut(line, "-->")
+
+	start, err := parseTimestamp(strings.TrimSpace(left))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(right)
+	if len(fields) == 0 {
+		return 0, 0, errors.New("missing cue end time")
+	}
+
+	end, err := parseTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
+
+func parseTimestamp(value string) (time.Duration, error) {
+	m := timestampPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	var hours int
+	if m[1] != "" {
+		var err error
+		if hours, err = strconv.Atoi(m[1]); err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q", value)
+		}
+	}
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	millis, _ := strconv.Atoi(m[4])
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(millis)*time.Millisecond, nil
+}
+
+// blockKeyword проверяет, что строка — ключевое слово, за которым идет конец строки, пробел или табуляция
+func blockKeyword(line, keyword string) bool {
+	rest, ok := strings.CutPrefix(line, keyword)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
@@ -0,0 +1,55 @@
+package webvtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	src := "\xef\xbb\xbfWEBVTT - упражнения для колена\r\n" +
+		"Kind: captions\r\n" +
+		"\r\n" +
+		"NOTE проверено врачом\r\n" +
+		"\r\n" +
+		"STYLE\r\n" +
+		"::cue { color: yellow }\r\n" +
+		"\r\n" +
+		"1\r\n" +
+		"00:00.000 --> 00:04.500 line:90%\r\n" +
+		"Сядьте на край стула\r\n" +
+		"\r\n" +
+		"00:00:04.500 --> 00:00:09.000\r\n" +
+		"<v Врач>Медленно выпрямите ногу</v>\r\n" +
+		"и задержите на 5 секунд\r\n"
+
+	cues, err := Validate([]byte(src))
+	require.NoError(t, err)
+	assert.Equal(t, 2, cues)
+}
+
+func TestValidate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  error
+	}{
+		{"нет заголовка", "00:00.000 --> 00:01.000\nтекст\n", ErrInvalid},
+		{"SRT вместо WebVTT", "WEBVTT\n\n1\n00:00:00,000 --> 00:00:01,000\nтекст\n", ErrInvalid},
+		{"заголовок без пробела", "WEBVTTX\n\n00:00.000 --> 00:01.000\nтекст\n", ErrInvalid},
+		{"конец раньше начала", "WEBVTT\n\n00:02.000 --> 00:01.000\nтекст\n", ErrInvalid},
+		{"обратный порядок", "WEBVTT\n\n00:05.000 --> 00:06.000\nа\n\n00:01.000 --> 00:02.000\nб\n", ErrInvalid},
+		{"минуты больше 59", "WEBVTT\n\n00:60.000 --> 01:61.000\nтекст\n", ErrInvalid},
+		{"идентификатор без времени", "WEBVTT\n\nintro\nтекст\n", ErrInvalid},
+		{"не UTF-8", "WEBVTT\n\n00:00.000 --> 00:01.000\n\xff\xfe\n", ErrInvalid},
+		{"без реплик", "WEBVTT\n\nNOTE пусто\n", ErrNoCues},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Validate([]byte(tt.src))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package weekly рассчитывает срабатывания еженедельного расписания: дни недели и местное время
+// в часовом поясе пользователя. Переходы на летнее время учитываются правилами пакета time:
+// несуществующее местное время сдвигается вперед.
+package weekly
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidDays     = errors.New("days must contain ISO weekdays from 1 to 7")
+	ErrInvalidClock    = errors.New("time must be in HH:MM format")
+	ErrInvalidTimezone = errors.New("unknown timezone")
+)
+
+// Schedule еженедельное расписание
+type Schedule struct {
+	Days     []int // ISO дни недели по возрастанию: 1 — понедельник, 7 — воскресенье
+	Hour     int
+	Minute   int
+	Location *time.Location
+}
+
+// New проверяет и собирает расписание из дней недели, времени "HH:MM" и названия часового пояса IANA
+func New(days []int, clock, timezone string) (*Schedule, error) {
+	if len(days) == 0 {
+		return nil, ErrInvalidDays
+	}
+
+	normalized := make([]int, 0, len(days))
+	for _, day := range days {
+		if day < 1 || day > 7 {
+			return nil, ErrInvalidDays
+		}
+		if !slices.Contains(normalized, day) {
+			normalized = append(normalized, day)
+		}
+	}
+	slices.Sort(normalized)
+
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return nil, ErrInvalidClock
+	}
+
+	// LoadLocation принимает пустую строку как UTC, а "Local" — как пояс сервера
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" || timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	return &Schedule{
+		Days:     normalized,
+		Hour:     t.Hour(),
+		Minute:   t.Minute(),
+		Location: loc,
+	}, nil
+}
+
+// Clock возвращает время срабатывания в формате "HH:MM"
+func (s *Schedule) Clock() string {
+	return time.Date(0, 1, 1, s.Hour, s.Minute, 0, 0, time.UTC).Format("15:04")
+}
+
+// Next возвращает первое срабатывание строго после after
+func (s *Schedule) Next(after time.Time) time.Time {
+	local := after.In(s.Location)
+
+	// Через неделю расписание гарантированно повторится, восьмой день нужен для срабатывания
+	// в тот же день недели, если время сегодня уже прошло
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), s.Hour, s.Minute, 0, 0, s.Location)
+
+		if candidate.After(after) && slices.Contains(s.Days, isoWeekday(candidate)) {
+			return candidate
+		}
+	}
+
+	// Недостижимо для расписания из New
+	return time.Time{}
+}
+
+// isoWeekday возвращает день недели по ISO: 1 — понедельник, 7 — воскресенье
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
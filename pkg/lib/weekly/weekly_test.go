@@ -0,0 +1,92 @@
+package weekly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	s, err := New([]int{5, 1, 3, 1}, "07:05", "Europe/Moscow")
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 3, 5}, s.Days)
+	assert.Equal(t, "07:05", s.Clock())
+	assert.Equal(t, "Europe/Moscow", s.Location.String())
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     []int
+		clock    string
+		timezone string
+		err      error
+	}{
+		{name: "без дней", days: nil, clock: "07:00", timezone: "UTC", err: ErrInvalidDays},
+		{name: "день вне диапазона", days: []int{0}, clock: "07:00", timezone: "UTC", err: ErrInvalidDays},
+		{name: "неверное время", days: []int{1}, clock: "25:00", timezone: "UTC", err: ErrInvalidClock},
+		{name: "неизвестный пояс", days: []int{1}, clock: "07:00", timezone: "Mars/Olympus", err: ErrInvalidTimezone},
+		{name: "пустой пояс", days: []int{1}, clock: "07:00", timezone: "", err: ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.days, tt.clock, tt.timezone)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Понедельник, среда и пятница в 08:30 по Москве (UTC+3)
+	s, err := New([]int{1, 3, 5}, "08:30", "Europe/Moscow")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "сегодня позже",
+			after: time.Date(2024, 3, 4, 5, 0, 0, 0, time.UTC), // понедельник 08:00 МСК
+			want:  time.Date(2024, 3, 4, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "ровно во время срабатывания",
+			after: time.Date(2024, 3, 4, 5, 30, 0, 0, time.UTC),
+			want:  time.Date(2024, 3, 6, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "через выходные",
+			after: time.Date(2024, 3, 8, 6, 0, 0, 0, time.UTC), // пятница 09:00 МСК
+			want:  time.Date(2024, 3, 11, 5, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(s.Next(tt.after)), "got %s", s.Next(tt.after).UTC())
+		})
+	}
+}
+
+func TestNext_SameWeekdayNextWeek(t *testing.T) {
+	s, err := New([]int{1}, "08:00", "UTC")
+	require.NoError(t, err)
+
+	after := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC) // понедельник после срабатывания
+	assert.Equal(t, time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC), s.Next(after))
+}
+
+func TestNext_DaylightSaving(t *testing.T) {
+	// В Берлине 31 марта 2024 часы переводятся с 02:00 на 03:00
+	s, err := New([]int{7}, "09:00", "Europe/Berlin")
+	require.NoError(t, err)
+
+	after := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC).Equal(s.Next(after)))
+}